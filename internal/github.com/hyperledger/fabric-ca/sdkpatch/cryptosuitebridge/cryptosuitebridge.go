@@ -107,11 +107,21 @@ func GetECDSAP384KeyGenOpts(ephemeral bool) core.KeyGenOpts {
 	return &bccsp.ECDSAP384KeyGenOpts{Temporary: ephemeral}
 }
 
+//GetEd25519KeyGenOpts options for Ed25519 key generation.
+func GetEd25519KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return cryptosuite.GetEd25519KeyGenOpts(ephemeral)
+}
+
 //GetX509PublicKeyImportOpts options for importing public keys from an x509 certificate
 func GetX509PublicKeyImportOpts(ephemeral bool) core.KeyImportOpts {
 	return &bccsp.X509PublicKeyImportOpts{Temporary: ephemeral}
 }
 
+//GetEd25519PrivateKeyImportOpts options for Ed25519 secret key importation in PKCS#8 format.
+func GetEd25519PrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
+	return cryptosuite.GetEd25519PrivateKeyImportOpts(ephemeral)
+}
+
 //GetECDSAPrivateKeyImportOpts options for ECDSA secret key importation in DER format
 // or PKCS#8 format.
 func GetECDSAPrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
@@ -23,6 +23,8 @@ package util
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -30,6 +32,8 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/mail"
 	"strings"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -42,7 +46,7 @@ import (
 )
 
 // getBCCSPKeyOpts generates a key as specified in the request.
-// This supports ECDSA and RSA.
+// This supports ECDSA, RSA and Ed25519.
 func getBCCSPKeyOpts(kr csr.KeyRequest, ephemeral bool) (opts core.KeyGenOpts, err error) {
 	if kr == nil {
 		return factory.GetECDSAKeyGenOpts(ephemeral), nil
@@ -74,6 +78,8 @@ func getBCCSPKeyOpts(kr csr.KeyRequest, ephemeral bool) (opts core.KeyGenOpts, e
 		default:
 			return nil, errors.Errorf("Invalid ECDSA key size: %d", kr.Size())
 		}
+	case "ed25519":
+		return factory.GetEd25519KeyGenOpts(ephemeral), nil
 	default:
 		return nil, errors.Errorf("Invalid algorithm: %s", kr.Algo())
 	}
@@ -144,6 +150,34 @@ func BCCSPKeyRequestGenerate(req *csr.CertificateRequest, myCSP core.CryptoSuite
 	return key, cspSigner, nil
 }
 
+// GenerateCSR generates a PEM encoded CSR signed by the given signer.
+// cfssl doesn't support Ed25519 keys, so the CSR of an Ed25519 key is created directly.
+func GenerateCSR(signer crypto.Signer, req *csr.CertificateRequest) ([]byte, error) {
+	if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+		return csr.Generate(signer, req)
+	}
+
+	tpl := x509.CertificateRequest{
+		Subject:            req.Name(),
+		SignatureAlgorithm: x509.PureEd25519,
+	}
+	for _, host := range req.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			tpl.IPAddresses = append(tpl.IPAddresses, ip)
+		} else if email, err := mail.ParseAddress(host); err == nil && email != nil {
+			tpl.EmailAddresses = append(tpl.EmailAddresses, email.Address)
+		} else {
+			tpl.DNSNames = append(tpl.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &tpl, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CSR")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
 // ImportBCCSPKeyFromPEM attempts to create a private BCCSP key from a pem file keyFile
 func ImportBCCSPKeyFromPEM(keyFile string, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
 	keyBuff, err := ioutil.ReadFile(keyFile)
@@ -161,6 +195,18 @@ func ImportBCCSPKeyFromPEM(keyFile string, myCSP core.CryptoSuite, temporary boo
 func ImportBCCSPKeyFromPEMBytes(keyBuff []byte, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
 	keyFile := "pem bytes"
 
+	if block, _ := pem.Decode(keyBuff); block != nil {
+		if pk, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			if _, ok := pk.(ed25519.PrivateKey); ok {
+				sk, err := myCSP.KeyImport(block.Bytes, factory.GetEd25519PrivateKeyImportOpts(temporary))
+				if err != nil {
+					return nil, errors.WithMessage(err, fmt.Sprintf("Failed to import Ed25519 private key for '%s'", keyFile))
+				}
+				return sk, nil
+			}
+		}
+	}
+
 	key, err := factory.PEMtoPrivateKey(keyBuff, nil)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("Failed parsing private key from %s", keyFile))
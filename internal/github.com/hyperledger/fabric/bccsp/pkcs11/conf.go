@@ -16,6 +16,7 @@ import (
 	"encoding/asn1"
 	"fmt"
 	"hash"
+	"time"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -92,6 +93,20 @@ type PKCS11Opts struct {
 	Pin        string `mapstructure:"pin" json:"pin"`
 	SoftVerify bool   `mapstructure:"softwareverify,omitempty" json:"softwareverify,omitempty"`
 	Immutable  bool   `mapstructure:"immutable,omitempty" json:"immutable,omitempty"`
+
+	// Session pool and failover options
+	SessionCacheSize    int               `mapstructure:"sessioncachesize,omitempty" json:"sessioncachesize,omitempty"`
+	HealthCheckInterval time.Duration     `mapstructure:"healthcheckinterval,omitempty" json:"healthcheckinterval,omitempty"`
+	FailoverTokens      []PKCS11TokenOpts `mapstructure:"failovertokens,omitempty" json:"failovertokens,omitempty"`
+}
+
+// PKCS11TokenOpts are the options of an additional token which holds the same keys as the token
+// of the PKCS11Opts, usually on another HSM. The library and the pin default to the ones of the PKCS11Opts.
+type PKCS11TokenOpts struct {
+	Library          string `mapstructure:"library" json:"library"`
+	Label            string `mapstructure:"label" json:"label"`
+	Pin              string `mapstructure:"pin" json:"pin"`
+	SessionCacheSize int    `mapstructure:"sessioncachesize,omitempty" json:"sessioncachesize,omitempty"`
 }
 
 // FileKeystoreOpts currently only ECDSA operations go to PKCS11, need a keystore still
@@ -15,6 +15,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"os"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
@@ -49,26 +50,71 @@ func New(opts PKCS11Opts, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
 	}
 
 	//Load PKCS11 context handle
-	pkcs11Ctx, err := sdkp11.LoadContextAndLogin(opts.Library, opts.Pin, opts.Label)
+	pkcs11Ctx, err := sdkp11.LoadContextAndLogin(opts.Library, opts.Pin, opts.Label, ctxOpts(opts.SessionCacheSize, opts.HealthCheckInterval)...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed initializing PKCS11 context")
 	}
-	csp := &impl{BCCSP: swCSP, conf: conf, ks: keyStore, softVerify: opts.SoftVerify, pkcs11Ctx: pkcs11Ctx}
+
+	//Load PKCS11 context handles of the tokens holding the same keys
+	handles := []*sdkp11.ContextHandle{pkcs11Ctx}
+	for _, token := range opts.FailoverTokens {
+		lib, pin, sessionCacheSize := token.Library, token.Pin, token.SessionCacheSize
+		if lib == "" {
+			lib = opts.Library
+		}
+		if pin == "" {
+			pin = opts.Pin
+		}
+		if sessionCacheSize == 0 {
+			sessionCacheSize = opts.SessionCacheSize
+		}
+		handle, err := sdkp11.LoadContextAndLogin(lib, pin, token.Label, ctxOpts(sessionCacheSize, opts.HealthCheckInterval)...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed initializing PKCS11 context of failover token [%s]", token.Label)
+		}
+		handles = append(handles, handle)
+	}
+
+	csp := &impl{BCCSP: swCSP, conf: conf, ks: keyStore, softVerify: opts.SoftVerify, pkcs11Ctx: pkcs11Ctx, tokens: sdkp11.NewTokenGroup(handles...)}
 	return csp, nil
 }
 
+// ctxOpts returns the options of the PKCS11 context handle of a token
+func ctxOpts(sessionCacheSize int, healthCheckInterval time.Duration) []sdkp11.Options {
+	var opts []sdkp11.Options
+	if sessionCacheSize > 0 {
+		opts = append(opts, sdkp11.WithSessionCacheSize(sessionCacheSize))
+	}
+	if healthCheckInterval > 0 {
+		opts = append(opts, sdkp11.WithHealthCheckInterval(healthCheckInterval))
+	}
+	return opts
+}
+
 type impl struct {
 	bccsp.BCCSP
 
 	conf *config
 	ks   bccsp.KeyStore
 
+	// pkcs11Ctx is the token which generates keys, tokens are all the tokens which hold the keys
 	pkcs11Ctx  *sdkp11.ContextHandle
+	tokens     *sdkp11.TokenGroup
 	softVerify bool
 	//Immutable flag makes object immutable
 	immutable bool
 }
 
+// ContextHandles returns the PKCS11 context handles of the tokens which hold the keys
+func (csp *impl) ContextHandles() []*sdkp11.ContextHandle {
+	return csp.tokens.Handles()
+}
+
+// SetPKCS11Metrics sets the metrics that record the session pools, the signing latency and the errors of the tokens
+func (csp *impl) SetPKCS11Metrics(m sdkp11.Metrics) {
+	csp.tokens.SetMetrics(m)
+}
+
 // KeyGen generates a key using opts.
 func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 	// Validate arguments
@@ -28,25 +28,48 @@ import (
 	"github.com/miekg/pkcs11"
 )
 
+// Look for an EC key by SKI, stored in CKA_ID, in the tokens that hold the keys.
+// The tokens are searched for the private key first, so that a token which is missing
+// the private key doesn't hide it in the other tokens
+func (csp *impl) getECKey(ski []byte) (pubKey *ecdsa.PublicKey, isPriv bool, err error) {
+	err = csp.tokens.Do(func(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle) error {
+		var e error
+		pubKey, isPriv, e = getECKeyFromToken(p11lib, session, ski, true)
+		return e
+	})
+	if err == nil {
+		return pubKey, isPriv, nil
+	}
+	logger.Debugf("Private key not found [%s] for SKI [%s], looking for Public key", err, hex.EncodeToString(ski))
+
+	err = csp.tokens.Do(func(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle) error {
+		var e error
+		pubKey, isPriv, e = getECKeyFromToken(p11lib, session, ski, false)
+		return e
+	})
+	return pubKey, isPriv, err
+}
+
 // Look for an EC key by SKI, stored in CKA_ID
+// An error is returned if requirePriv is true and the token doesn't hold the private key
 // This function can probably be adapted for both EC and RSA keys.
-func (csp *impl) getECKey(ski []byte) (pubKey *ecdsa.PublicKey, isPriv bool, err error) {
+func getECKeyFromToken(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle, ski []byte, requirePriv bool) (pubKey *ecdsa.PublicKey, isPriv bool, err error) {
 
-	session := csp.pkcs11Ctx.GetSession()
-	defer csp.pkcs11Ctx.ReturnSession(session)
 	isPriv = true
-	_, err = csp.pkcs11Ctx.FindKeyPairFromSKI(session, ski, privateKeyFlag)
+	_, err = p11lib.FindKeyPairFromSKI(session, ski, privateKeyFlag)
 	if err != nil {
+		if requirePriv {
+			return nil, false, fmt.Errorf("Private key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
+		}
 		isPriv = false
-		logger.Debugf("Private key not found [%s] for SKI [%s], looking for Public key", err, hex.EncodeToString(ski))
 	}
 
-	publicKey, err := csp.pkcs11Ctx.FindKeyPairFromSKI(session, ski, publicKeyFlag)
+	publicKey, err := p11lib.FindKeyPairFromSKI(session, ski, publicKeyFlag)
 	if err != nil {
 		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
 	}
 
-	ecpt, marshaledOid, err := ecPoint(csp.pkcs11Ctx, session, *publicKey)
+	ecpt, marshaledOid, err := ecPoint(p11lib, session, *publicKey)
 	if err != nil {
 		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
 	}
@@ -223,25 +246,32 @@ func (csp *impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool) (ski
 	return ski, pubGoKey, nil
 }
 
+// signP11ECDSA signs with any of the tokens that hold the private key
 func (csp *impl) signP11ECDSA(ski []byte, msg []byte) (R, S *big.Int, err error) {
+	err = csp.tokens.Sign(func(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle) error {
+		var e error
+		R, S, e = signP11ECDSAWithToken(p11lib, session, ski, msg)
+		return e
+	})
+	return R, S, err
+}
 
-	session := csp.pkcs11Ctx.GetSession()
-	defer csp.pkcs11Ctx.ReturnSession(session)
+func signP11ECDSAWithToken(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle, ski []byte, msg []byte) (R, S *big.Int, err error) {
 
-	privateKey, err := csp.pkcs11Ctx.FindKeyPairFromSKI(session, ski, privateKeyFlag)
+	privateKey, err := p11lib.FindKeyPairFromSKI(session, ski, privateKeyFlag)
 	defer timeTrack(time.Now(), fmt.Sprintf("signing [session: %d]", session))
 	if err != nil {
 		return nil, nil, fmt.Errorf("Private key not found [%s]", err)
 	}
 
-	err = csp.pkcs11Ctx.SignInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, *privateKey)
+	err = p11lib.SignInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, *privateKey)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Sign-initialize  failed [%s]", err)
 	}
 
 	var sig []byte
 
-	sig, err = csp.pkcs11Ctx.Sign(session, msg)
+	sig, err = p11lib.Sign(session, msg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("P11: sign failed [%s]", err)
 	}
@@ -254,14 +284,21 @@ func (csp *impl) signP11ECDSA(ski []byte, msg []byte) (R, S *big.Int, err error)
 	return R, S, nil
 }
 
-func (csp *impl) verifyP11ECDSA(ski []byte, msg []byte, R, S *big.Int, byteSize int) (bool, error) {
+// verifyP11ECDSA verifies with any of the tokens that hold the public key
+func (csp *impl) verifyP11ECDSA(ski []byte, msg []byte, R, S *big.Int, byteSize int) (valid bool, err error) {
+	err = csp.tokens.Do(func(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle) error {
+		var e error
+		valid, e = verifyP11ECDSAWithToken(p11lib, session, ski, msg, R, S, byteSize)
+		return e
+	})
+	return valid, err
+}
 
-	session := csp.pkcs11Ctx.GetSession()
-	defer csp.pkcs11Ctx.ReturnSession(session)
+func verifyP11ECDSAWithToken(p11lib *sdkp11.ContextHandle, session pkcs11.SessionHandle, ski []byte, msg []byte, R, S *big.Int, byteSize int) (bool, error) {
 
 	logger.Debugf("Verify ECDSA\n")
 
-	publicKey, err := csp.pkcs11Ctx.FindKeyPairFromSKI(session, ski, publicKeyFlag)
+	publicKey, err := p11lib.FindKeyPairFromSKI(session, ski, publicKeyFlag)
 	if err != nil {
 		return false, fmt.Errorf("Public key not found [%s]", err)
 	}
@@ -274,12 +311,12 @@ func (csp *impl) verifyP11ECDSA(ski []byte, msg []byte, R, S *big.Int, byteSize
 	copy(sig[byteSize-len(r):byteSize], r)
 	copy(sig[2*byteSize-len(s):], s)
 
-	err = csp.pkcs11Ctx.VerifyInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)},
+	err = p11lib.VerifyInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)},
 		*publicKey)
 	if err != nil {
 		return false, fmt.Errorf("PKCS11: Verify-initialize [%s]", err)
 	}
-	err = csp.pkcs11Ctx.Verify(session, msg, sig)
+	err = p11lib.Verify(session, msg, sig)
 	if err == pkcs11.Error(pkcs11.CKR_SIGNATURE_INVALID) {
 		return false, nil
 	}
@@ -45,6 +45,23 @@ func NewFileBasedKeyStore(pwd []byte, path string, readOnly bool) (bccsp.KeyStor
 	return ks, ks.Init(pwd, path, readOnly)
 }
 
+// FileCipher encrypts and decrypts the content of the key files of a file-based key store.
+// The name of the key file is passed so that the content can be bound to it.
+type FileCipher interface {
+	Encrypt(name string, plaintext []byte) ([]byte, error)
+	Decrypt(name string, ciphertext []byte) ([]byte, error)
+}
+
+// NewFileBasedKeyStoreWithCipher instantiates a file-based key store at a given position
+// whose key files are encrypted with the given cipher (rather than with a password).
+func NewFileBasedKeyStoreWithCipher(cipher FileCipher, path string, readOnly bool) (bccsp.KeyStore, error) {
+	if cipher == nil {
+		return nil, errors.New("Invalid cipher. It must be different from nil.")
+	}
+	ks := &fileBasedKeyStore{cipher: cipher}
+	return ks, ks.Init(nil, path, readOnly)
+}
+
 // fileBasedKeyStore is a folder-based KeyStore.
 // Each key is stored in a separated file whose name contains the key's SKI
 // and flags to identity the key's type. All the keys are stored in
@@ -58,7 +75,8 @@ type fileBasedKeyStore struct {
 	readOnly bool
 	isOpen   bool
 
-	pwd []byte
+	pwd    []byte
+	cipher FileCipher
 
 	// Sync
 	m sync.Mutex
@@ -226,7 +244,7 @@ func (ks *fileBasedKeyStore) searchKeystoreForSKI(ski []byte) (k bccsp.Key, err
 			continue
 		}
 
-		raw, err := ioutil.ReadFile(filepath.Join(ks.path, f.Name()))
+		raw, err := ks.readKeyFile(filepath.Join(ks.path, f.Name()))
 		if err != nil {
 			continue
 		}
@@ -280,7 +298,7 @@ func (ks *fileBasedKeyStore) storePrivateKey(alias string, privateKey interface{
 		return err
 	}
 
-	err = ioutil.WriteFile(ks.getPathForAlias(alias, "sk"), rawKey, 0600)
+	err = ks.writeKeyFile(ks.getPathForAlias(alias, "sk"), rawKey)
 	if err != nil {
 		logger.Errorf("Failed storing private key [%s]: [%s]", alias, err)
 		return err
@@ -296,7 +314,7 @@ func (ks *fileBasedKeyStore) storePublicKey(alias string, publicKey interface{})
 		return err
 	}
 
-	err = ioutil.WriteFile(ks.getPathForAlias(alias, "pk"), rawKey, 0600)
+	err = ks.writeKeyFile(ks.getPathForAlias(alias, "pk"), rawKey)
 	if err != nil {
 		logger.Errorf("Failed storing private key [%s]: [%s]", alias, err)
 		return err
@@ -312,7 +330,7 @@ func (ks *fileBasedKeyStore) storeKey(alias string, key []byte) error {
 		return err
 	}
 
-	err = ioutil.WriteFile(ks.getPathForAlias(alias, "key"), pem, 0600)
+	err = ks.writeKeyFile(ks.getPathForAlias(alias, "key"), pem)
 	if err != nil {
 		logger.Errorf("Failed storing key [%s]: [%s]", alias, err)
 		return err
@@ -325,7 +343,7 @@ func (ks *fileBasedKeyStore) loadPrivateKey(alias string) (interface{}, error) {
 	path := ks.getPathForAlias(alias, "sk")
 	logger.Debugf("Loading private key [%s] at [%s]...", alias, path)
 
-	raw, err := ioutil.ReadFile(path)
+	raw, err := ks.readKeyFile(path)
 	if err != nil {
 		logger.Errorf("Failed loading private key [%s]: [%s].", alias, err.Error())
 
@@ -346,7 +364,7 @@ func (ks *fileBasedKeyStore) loadPublicKey(alias string) (interface{}, error) {
 	path := ks.getPathForAlias(alias, "pk")
 	logger.Debugf("Loading public key [%s] at [%s]...", alias, path)
 
-	raw, err := ioutil.ReadFile(path)
+	raw, err := ks.readKeyFile(path)
 	if err != nil {
 		logger.Errorf("Failed loading public key [%s]: [%s].", alias, err.Error())
 
@@ -367,7 +385,7 @@ func (ks *fileBasedKeyStore) loadKey(alias string) ([]byte, error) {
 	path := ks.getPathForAlias(alias, "key")
 	logger.Debugf("Loading key [%s] at [%s]...", alias, path)
 
-	pem, err := ioutil.ReadFile(path)
+	pem, err := ks.readKeyFile(path)
 	if err != nil {
 		logger.Errorf("Failed loading key [%s]: [%s].", alias, err.Error())
 
@@ -426,3 +444,24 @@ func (ks *fileBasedKeyStore) openKeyStore() error {
 func (ks *fileBasedKeyStore) getPathForAlias(alias, suffix string) string {
 	return filepath.Join(ks.path, alias+"_"+suffix)
 }
+
+// readKeyFile reads the key file, decrypting it with the cipher (if any)
+func (ks *fileBasedKeyStore) readKeyFile(path string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil || ks.cipher == nil {
+		return raw, err
+	}
+	return ks.cipher.Decrypt(filepath.Base(path), raw)
+}
+
+// writeKeyFile writes the key file, encrypting it with the cipher (if any)
+func (ks *fileBasedKeyStore) writeKeyFile(path string, raw []byte) error {
+	if ks.cipher != nil {
+		var err error
+		raw, err = ks.cipher.Encrypt(filepath.Base(path), raw)
+		if err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path, raw, 0600)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/hex"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/pkg/errors"
+)
+
+// ErrKeyNotFound is returned by a KeyStorage if it doesn't hold the key
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyStorage persists the PEM-encoded keys of a key store. The keys are named
+// <ski>_<suffix> (sk, pk or key) like the files of the file-based key store.
+type KeyStorage interface {
+	// Load returns the key with the given name or ErrKeyNotFound
+	Load(name string) ([]byte, error)
+	// Store stores the key with the given name
+	Store(name string, raw []byte) error
+}
+
+// NewStorageKeyStore instantiates a key store that persists the keys in the given storage,
+// for example a database. It can be set as read only, in which case any store operation
+// will be forbidden.
+func NewStorageKeyStore(storage KeyStorage, readOnly bool) (bccsp.KeyStore, error) {
+	if storage == nil {
+		return nil, errors.New("Invalid storage. It must be different from nil.")
+	}
+	return &storageKeyStore{storage: storage, readOnly: readOnly}, nil
+}
+
+type storageKeyStore struct {
+	storage  KeyStorage
+	readOnly bool
+}
+
+// ReadOnly returns true if this KeyStore is read only, false otherwise.
+func (ks *storageKeyStore) ReadOnly() bool {
+	return ks.readOnly
+}
+
+// GetKey returns a key object whose SKI is the one passed.
+func (ks *storageKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("Invalid SKI. Cannot be of zero length.")
+	}
+	alias := hex.EncodeToString(ski)
+
+	for _, suffix := range []string{"sk", "pk", "key"} {
+		raw, err := ks.storage.Load(alias + "_" + suffix)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Failed loading key [%x]", ski)
+		}
+
+		key, err := decodeStoredKey(suffix, raw)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Failed loading key [%x]", ski)
+		}
+		return key, nil
+	}
+	return nil, errors.Errorf("Key with SKI %s not found", alias)
+}
+
+// StoreKey stores the key k in this KeyStore.
+// If this KeyStore is read only then the method will fail.
+func (ks *storageKeyStore) StoreKey(k bccsp.Key) error {
+	if ks.readOnly {
+		return errors.New("Read only KeyStore.")
+	}
+	if k == nil {
+		return errors.New("Invalid key. It must be different from nil.")
+	}
+
+	var raw []byte
+	var suffix string
+	var err error
+	switch kk := k.(type) {
+	case *ecdsaPrivateKey:
+		suffix = "sk"
+		raw, err = utils.PrivateKeyToPEM(kk.privKey, nil)
+	case *ecdsaPublicKey:
+		suffix = "pk"
+		raw, err = utils.PublicKeyToPEM(kk.pubKey, nil)
+	case *rsaPrivateKey:
+		suffix = "sk"
+		raw, err = utils.PrivateKeyToPEM(kk.privKey, nil)
+	case *rsaPublicKey:
+		suffix = "pk"
+		raw, err = utils.PublicKeyToPEM(kk.pubKey, nil)
+	case *aesPrivateKey:
+		suffix = "key"
+		raw = utils.AEStoPEM(kk.privKey)
+	default:
+		return errors.Errorf("Key type not recognized [%s]", k)
+	}
+	if err != nil {
+		return errors.WithMessage(err, "Failed converting key to PEM")
+	}
+
+	if err := ks.storage.Store(hex.EncodeToString(k.SKI())+"_"+suffix, raw); err != nil {
+		return errors.WithMessage(err, "Failed storing key")
+	}
+	return nil
+}
+
+func decodeStoredKey(suffix string, raw []byte) (bccsp.Key, error) {
+	switch suffix {
+	case "key":
+		key, err := utils.PEMtoAES(raw, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &aesPrivateKey{key, false}, nil
+	case "sk":
+		key, err := utils.PEMtoPrivateKey(raw, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			return &ecdsaPrivateKey{k}, nil
+		case *rsa.PrivateKey:
+			return &rsaPrivateKey{k}, nil
+		default:
+			return nil, errors.New("Secret key type not recognized")
+		}
+	default:
+		key, err := utils.PEMtoPublicKey(raw, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			return &ecdsaPublicKey{k}, nil
+		case *rsa.PublicKey:
+			return &rsaPublicKey{k}, nil
+		default:
+			return nil, errors.New("Public key type not recognized")
+		}
+	}
+}
@@ -146,6 +146,12 @@ func (s *System) RegisterChecker(component string, checker healthz.HealthChecker
 	return s.healthHandler.RegisterChecker(component, checker)
 }
 
+// RegisterHandler serves the given handler on the given path. The handler requires a client
+// certificate if secure is true and TLS is enabled.
+func (s *System) RegisterHandler(path string, handler http.Handler, secure bool) {
+	s.mux.Handle(path, s.handlerChain(handler, secure && s.options.TLS.Enabled))
+}
+
 func (s *System) initializeServer() {
 	s.mux = http.NewServeMux()
 	s.httpServer = &http.Server{
@@ -12,6 +12,7 @@ package msp
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/hex"
@@ -136,15 +137,18 @@ func (id *identity) Anonymous() bool {
 func (id *identity) Verify(msg []byte, sig []byte) error {
 	// mspIdentityLogger.Infof("Verifying signature")
 
-	// Compute Hash
-	hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
-	if err != nil {
-		return errors.WithMessage(err, "failed getting hash function options")
-	}
-
-	digest, err := id.msp.bccsp.Hash(msg, hashOpt)
-	if err != nil {
-		return errors.WithMessage(err, "failed computing digest")
+	// Compute Hash (Ed25519 signatures are over the message itself)
+	digest := msg
+	if _, ok := id.cert.PublicKey.(ed25519.PublicKey); !ok {
+		hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
+		if err != nil {
+			return errors.WithMessage(err, "failed getting hash function options")
+		}
+
+		digest, err = id.msp.bccsp.Hash(msg, hashOpt)
+		if err != nil {
+			return errors.WithMessage(err, "failed computing digest")
+		}
 	}
 
 	if mspIdentityLogger.IsEnabledFor(logging.DEBUG) {
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -29,6 +30,9 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
 	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
 	CCFilter      invoke.CCFilter
+	Consensus     *invoke.ConsensusOpts
+	Budget        *invoke.BudgetOpts
+	Identity      msp.SigningIdentity // signs the request instead of the identity of the client's context
 }
 
 // RequestOption func for each Opts argument
@@ -60,6 +64,14 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	AgreedEndorsers  []string // URLs of the endorsers whose responses matched (consensus queries only)
+}
+
+// DryRunResponse contains the endorsement responses of a dry run along with
+// the decoded simulation results (read/write set and chaincode event) of each endorser
+type DryRunResponse struct {
+	Response
+	Simulations []*invoke.Simulation
 }
 
 //WithTargets allows overriding of the target peers for the request
@@ -156,6 +168,39 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 	}
 }
 
+// WithDeadlineBudget enables the end-to-end deadline mode, in which the deadline of the parent context
+// (see WithParentContext) or, if the parent context has no deadline, the Execute timeout is the budget
+// of the entire request. Retries and all sub-steps consume the budget, and the endorsement, broadcast and
+// commit phases are each allotted the given fraction of it. Per-hop timeouts (e.g. PeerResponse) still
+// apply but are cut short at the end of the current phase. If a phase runs out of time then the request
+// fails with a timeout error that names the phase.
+func WithDeadlineBudget(budget invoke.BudgetOpts) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if budget.Endorsement < 0 || budget.Broadcast < 0 || budget.Commit < 0 {
+			return errors.New("deadline budget fractions must not be negative")
+		}
+		if budget.Endorsement+budget.Broadcast+budget.Commit > 1 {
+			return errors.New("deadline budget fractions must not add up to more than 1")
+		}
+		o.Budget = &budget
+		return nil
+	}
+}
+
+// WithSigningIdentity signs the request (proposal and transaction) with the given identity instead of
+// the identity that the client was created with. The client's discovery, selection and event services
+// are shared by all identities; in particular, commit events are received over the event service
+// connection of the client's identity.
+func WithSigningIdentity(identity msp.SigningIdentity) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if identity == nil {
+			return errors.New("signing identity is nil")
+		}
+		o.Identity = identity
+		return nil
+	}
+}
+
 //WithChaincodeFilter adds a chaincode filter for figuring out additional endorsers
 func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -163,3 +208,43 @@ func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 		return nil
 	}
 }
+
+// WithQueryStrategy enables consensus for a query and specifies whether the query returns as soon as
+// the consensus criteria are met (invoke.FastestWins) or only after all targets have responded (invoke.Quorum)
+func WithQueryStrategy(strategy invoke.QueryStrategy) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		consensusOpts(o).Strategy = strategy
+		return nil
+	}
+}
+
+// WithMinMatchingResponses enables consensus for a query and specifies the minimum number
+// of identical responses that must be received
+func WithMinMatchingResponses(n int) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if n < 1 {
+			return errors.New("minimum number of matching responses must be greater than zero")
+		}
+		consensusOpts(o).MinMatching = n
+		return nil
+	}
+}
+
+// WithMinMSPs enables consensus for a query and specifies the minimum number of distinct MSPs
+// that must be represented among the identical responses
+func WithMinMSPs(n int) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if n < 1 {
+			return errors.New("minimum number of MSPs must be greater than zero")
+		}
+		consensusOpts(o).MinMSPs = n
+		return nil
+	}
+}
+
+func consensusOpts(o *requestOptions) *invoke.ConsensusOpts {
+	if o.Consensus == nil {
+		o.Consensus = &invoke.ConsensusOpts{}
+	}
+	return o.Consensus
+}
@@ -11,6 +11,7 @@ import (
 
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -117,3 +118,16 @@ func TestWithPeerSorter(t *testing.T) {
 	assert.NoError(t, err, "WithPeerSorter should not return error")
 	assert.Equal(t, opts.TargetSorter, &sorter, "sorter option should have been set")
 }
+
+func TestWithConsensusOpts(t *testing.T) {
+	ctx := setupMockTestContext("test", "Org1MSP")
+
+	opts := requestOptions{}
+	assert.NoError(t, WithQueryStrategy(invoke.Quorum)(ctx, &opts))
+	assert.NoError(t, WithMinMatchingResponses(3)(ctx, &opts))
+	assert.NoError(t, WithMinMSPs(2)(ctx, &opts))
+	assert.Equal(t, &invoke.ConsensusOpts{Strategy: invoke.Quorum, MinMatching: 3, MinMSPs: 2}, opts.Consensus)
+
+	assert.Error(t, WithMinMatchingResponses(0)(ctx, &opts))
+	assert.Error(t, WithMinMSPs(-1)(ctx, &opts))
+}
@@ -25,9 +25,12 @@ import (
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
@@ -42,12 +45,30 @@ type Client struct {
 	membership   fab.ChannelMembership
 	eventService fab.EventService
 	greylist     *greylist.Filter
+	breakers     *comm.Breakers
 	metrics      *metrics.ClientMetrics
+	invocChains  *invoke.InvocationChainCache
 }
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
+// WithInvocationChainCache enables caching of the invocation chains (chaincode-to-chaincode calls and
+// private data collections) that are discovered from the read/write sets of endorsements. On subsequent
+// invocations of the same chaincode function, endorsers are selected for the entire invocation chain up
+// front, which avoids a second round of endorsements. Cached entries expire after the given time-to-live
+// (a value of zero means that entries never expire) and are replaced whenever an endorsement reveals a
+// chaincode or collection that was not previously known.
+func WithInvocationChainCache(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return errors.New("invocation chain cache time-to-live must not be negative")
+		}
+		c.invocChains = invoke.NewInvocationChainCache(ttl)
+		return nil
+	}
+}
+
 // New returns a Client instance. Channel client can query chaincode, execute chaincode and register/unregister for chaincode events on specific channel.
 func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
 
@@ -56,7 +77,8 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "failed to create channel context")
 	}
 
-	greylistProvider := greylist.New(channelContext.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry))
+	greylistProvider := greylist.New(channelContext.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry),
+		greylist.WithMetrics(channelContext.ChannelID(), channelContext.GetMetrics().Discovery()))
 
 	if channelContext.ChannelService() == nil {
 		return nil, errors.New("channel service not initialized")
@@ -73,6 +95,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	}
 
 	channelClient := newClient(channelContext, membership, eventService, greylistProvider)
+	channelClient.breakers = comm.CircuitBreakers(channelContext)
 
 	for _, param := range opts {
 		err := param(&channelClient)
@@ -84,7 +107,9 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	return &channelClient, nil
 }
 
-// Query chaincode using request and optional request options
+// Query chaincode using request and optional request options.
+// Consensus among the responses of multiple peers may be required using the
+// WithQueryStrategy, WithMinMatchingResponses and WithMinMSPs options.
 //  Parameters:
 //  request holds info about mandatory chaincode ID and function
 //  options holds optional request options
@@ -113,6 +138,36 @@ func (cc *Client) Execute(request Request, options ...RequestOption) (Response,
 	return callExecute(cc, request, options...)
 }
 
+// DryRun endorses a transaction without sending it to the orderer. The read/write set and
+// chaincode event produced by each endorser are decoded and returned so that the effects of
+// the transaction may be reviewed before it is submitted with Execute.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  options holds optional request options
+//
+//  Returns:
+//  the proposal responses from peer(s) along with the decoded simulation results of each endorser
+func (cc *Client) DryRun(request Request, options ...RequestOption) (DryRunResponse, error) {
+	options = append(options, addDefaultTimeout(fab.Execute))
+	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+
+	response, err := cc.InvokeHandler(invoke.NewDryRunHandler(), request, options...)
+	if err != nil {
+		return DryRunResponse{Response: response}, err
+	}
+
+	dryRunResponse := DryRunResponse{Response: response}
+	for _, r := range response.Responses {
+		simulation, err := invoke.DecodeSimulation(r)
+		if err != nil {
+			return dryRunResponse, errors.WithMessage(err, "failed to decode simulation results")
+		}
+		dryRunResponse.Simulations = append(dryRunResponse.Simulations, simulation)
+	}
+
+	return dryRunResponse, nil
+}
+
 // addDefaultTargetFilter adds default target filter if target filter is not specified
 func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -151,6 +206,28 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	reqCtx, cancel := cc.createReqContext(&txnOpts)
 	defer cancel()
 
+	// The root span is a child of the span in the parent context (if any) so that
+	// the SDK spans are part of the caller's trace
+	reqCtx, span := tracing.StartSpan(reqCtx, "channel.InvokeHandler",
+		tracing.Attr(tracing.ChannelKey, cc.context.ChannelID()),
+		tracing.Attr(tracing.ChaincodeKey, request.ChaincodeID),
+		tracing.Attr(tracing.FunctionKey, request.Fcn))
+
+	// Log entries of the handlers include the channel and chaincode of the request
+	reqCtx = logging.WithFields(reqCtx,
+		logging.KV(logging.ChannelKey, cc.context.ChannelID()),
+		logging.KV(logging.ChaincodeKey, request.ChaincodeID))
+
+	response, err := cc.invokeHandler(reqCtx, handler, request, txnOpts)
+	if response.TransactionID != "" {
+		span.SetAttributes(tracing.Attr(tracing.TxIDKey, string(response.TransactionID)))
+	}
+	tracing.EndSpan(span, err)
+
+	return response, err
+}
+
+func (cc *Client) invokeHandler(reqCtx reqContext.Context, handler invoke.Handler, request Request, txnOpts requestOptions) (Response, error) {
 	//Prepare context objects for handler
 	requestContext, clientContext, err := cc.prepareHandlerContexts(reqCtx, request, txnOpts)
 	if err != nil {
@@ -188,6 +265,9 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	case <-complete:
 		return Response(requestContext.Response), requestContext.Error
 	case <-reqCtx.Done():
+		if budget, ok := contextImpl.RequestBudget(reqCtx); ok && reqCtx.Err() == reqContext.DeadlineExceeded {
+			return Response{}, invoke.NewBudgetExhaustedError(budget, budget.Phase())
+		}
 		return Response{}, status.New(status.ClientStatus, status.Timeout.ToInt32(),
 			"request timed out or been cancelled", nil)
 	}
@@ -205,14 +285,36 @@ func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context,
 		txnOpts.Timeouts[fab.Execute] = cc.context.EndpointConfig().Timeout(fab.Execute)
 	}
 
-	reqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeout(txnOpts.Timeouts[fab.Execute]),
+	timeout := txnOpts.Timeouts[fab.Execute]
+	if txnOpts.Budget != nil && txnOpts.ParentContext != nil {
+		//in deadline mode the deadline of the parent context is the budget
+		if deadline, ok := txnOpts.ParentContext.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(cc.requestClient(txnOpts), contextImpl.WithTimeout(timeout),
 		contextImpl.WithParent(txnOpts.ParentContext))
 	//Add timeout overrides here as a value so that it can be used by immediate child contexts (in handlers/transactors)
 	reqCtx = reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, txnOpts.Timeouts)
 
+	if txnOpts.Budget != nil {
+		deadline, _ := reqCtx.Deadline()
+		reqCtx = contextImpl.WithBudget(reqCtx, contextImpl.NewBudget(deadline, txnOpts.Budget.Fractions()))
+	}
+
 	return reqCtx, cancel
 }
 
+// requestClient returns the client context that signs the request: the client's context with
+// the identity that was provided with the WithSigningIdentity option (if any)
+func (cc *Client) requestClient(txnOpts *requestOptions) context.Client {
+	if txnOpts.Identity == nil {
+		return cc.context
+	}
+	return &contextImpl.Client{Providers: cc.context, SigningIdentity: txnOpts.Identity}
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Request, o requestOptions) (*invoke.RequestContext, *invoke.ClientContext, error) {
 
@@ -236,7 +338,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	}
 
 	peerFilter := func(peer fab.Peer) bool {
-		if !cc.greylist.Accept(peer) {
+		if !cc.greylist.Accept(peer) || !cc.breakers.Accept(peer) {
 			return false
 		}
 		if o.TargetFilter != nil && !o.TargetFilter.Accept(peer) {
@@ -258,13 +360,15 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		Membership:   cc.membership,
 		Transactor:   transactor,
 		EventService: cc.eventService,
+
+		InvocationChainCache: cc.invocChains,
 	}
 
 	requestContext := &invoke.RequestContext{
 		Request:         invoke.Request(request),
 		Opts:            invoke.Opts(o),
 		Response:        invoke.Response{},
-		RetryHandler:    retry.New(o.Retry),
+		RetryHandler:    newRetryHandler(reqCtx, o),
 		Ctx:             reqCtx,
 		SelectionFilter: peerFilter,
 		PeerSorter:      peerSorter,
@@ -273,6 +377,29 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	return requestContext, clientContext, nil
 }
 
+// newRetryHandler returns the retry handler for the request. In deadline mode, retries
+// are not attempted once the budget is exhausted.
+func newRetryHandler(reqCtx reqContext.Context, o requestOptions) retry.Handler {
+	handler := retry.New(o.Retry)
+	if o.Budget == nil {
+		return handler
+	}
+	return &budgetRetryHandler{Handler: handler, reqCtx: reqCtx}
+}
+
+type budgetRetryHandler struct {
+	retry.Handler
+	reqCtx reqContext.Context
+}
+
+// Required returns false if the request context is done; otherwise the decision is delegated
+func (h *budgetRetryHandler) Required(err error) bool {
+	if h.reqCtx.Err() != nil {
+		return false
+	}
+	return h.Handler.Required(err)
+}
+
 //prepareOptsFromOptions Reads apitxn.Opts from Option array
 func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
 	txnOpts := requestOptions{}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
@@ -22,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
@@ -114,6 +117,44 @@ func TestQuery(t *testing.T) {
 
 }
 
+func TestQueryTracing(t *testing.T) {
+	tracer := tracing.NewMockTracer()
+
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockSelectionService(nil), txnmocks.NewMockDiscoveryService(nil), nil)
+	client, err := fabCtx()
+	require.NoError(t, err)
+	client.(*fcmocks.MockContext).SetTracer(tracer)
+
+	chClient, err := New(createChannelContext(fabCtx, channelID))
+	require.NoError(t, err)
+
+	parentCtx, callerSpan := tracing.StartSpan(tracing.ContextWithTracer(reqContext.Background(), tracer), "caller")
+	response, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithParentContext(parentCtx))
+	require.NoError(t, err)
+	callerSpan.End()
+
+	rootSpans := tracer.SpansNamed("channel.InvokeHandler")
+	require.Len(t, rootSpans, 1)
+	root := rootSpans[0]
+	assert.Equal(t, callerSpan, root.Parent, "root span should be a child of the span in the parent context")
+	assert.True(t, root.Ended())
+	assert.Equal(t, channelID, root.Attribute(tracing.ChannelKey))
+	assert.Equal(t, "testCC", root.Attribute(tracing.ChaincodeKey))
+	assert.Equal(t, "invoke", root.Attribute(tracing.FunctionKey))
+	assert.Equal(t, string(response.TransactionID), root.Attribute(tracing.TxIDKey))
+
+	handlerSpans := tracer.SpansNamed("invoke.ProposalProcessorHandler")
+	require.Len(t, handlerSpans, 1)
+	assert.Equal(t, root, handlerSpans[0].Parent, "handler spans should be children of the root span")
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC"})
+	require.Error(t, err)
+	rootSpans = tracer.SpansNamed("channel.InvokeHandler")
+	require.Len(t, rootSpans, 2)
+	assert.Error(t, rootSpans[1].Err(), "error should be recorded on the root span")
+}
+
 func TestQuerySelectionError(t *testing.T) {
 	chClient := setupChannelClientWithError(nil, errors.New("Test Error"), nil, t)
 
@@ -228,6 +269,30 @@ func TestExecuteTx(t *testing.T) {
 
 }
 
+func TestDryRun(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.SetRwSets(fcmocks.NewRwSet("testCC"))
+	testOrderer1 := fcmocks.NewMockOrderer("", make(chan *fab.SignedEnvelope, 1))
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer1}, []fab.Orderer{testOrderer1}, t)
+
+	response, err := chClient.DryRun(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	require.NoError(t, err)
+	require.Len(t, response.Simulations, 1)
+	assert.Equal(t, "http://peer1.com", response.Simulations[0].Endorser)
+	require.Len(t, response.Simulations[0].Namespaces, 1)
+	assert.Equal(t, "testCC", response.Simulations[0].Namespaces[0].Namespace)
+	assert.Equal(t, pb.TxValidationCode(0), response.TxValidationCode)
+
+	select {
+	case <-testOrderer1.BroadcastQueue:
+		t.Fatal("dry run should not send the transaction to the orderer")
+	default:
+	}
+
+	_, err = chClient.DryRun(Request{ChaincodeID: "testCC"})
+	assert.Error(t, err, "Should have failed for empty function")
+}
+
 type customHandler struct {
 	expectedPayload []byte
 }
@@ -251,6 +316,44 @@ func TestInvokeHandler(t *testing.T) {
 	}
 }
 
+// identityHandler records the identity of the request context and the event service of each invocation
+type identityHandler struct {
+	identities    []string
+	eventServices []fab.EventService
+}
+
+func (h *identityHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	ctx, ok := contextImpl.RequestClientContext(requestContext.Ctx)
+	if !ok {
+		requestContext.Error = errors.New("client context not found in request context")
+		return
+	}
+	h.identities = append(h.identities, ctx.Identifier().ID)
+	h.eventServices = append(h.eventServices, clientContext.EventService)
+}
+
+func TestInvokeHandlerWithSigningIdentity(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	handler := &identityHandler{}
+	request := Request{ChaincodeID: "testCC", Fcn: "move"}
+
+	_, err := chClient.InvokeHandler(handler, request)
+	require.NoError(t, err)
+
+	_, err = chClient.InvokeHandler(handler, request, WithSigningIdentity(mspmocks.NewMockSigningIdentity("user2", "Org2MSP")))
+	require.NoError(t, err)
+
+	_, err = chClient.InvokeHandler(handler, request)
+	require.NoError(t, err)
+
+	_, err = chClient.InvokeHandler(handler, request, WithSigningIdentity(nil))
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"test", "user2", "test"}, handler.identities, "only the request with the signing identity option should be signed by that identity")
+	require.Len(t, handler.eventServices, 3)
+	assert.True(t, handler.eventServices[0] == handler.eventServices[1], "event service should be shared by all identities")
+}
+
 // customEndorsementHandler ignores the channel in the ClientContext
 // and instead sends the proposal to the given channel
 type customEndorsementHandler struct {
@@ -401,6 +504,35 @@ func TestTransactionTimeout(t *testing.T) {
 	assert.EqualValues(t, statusError.Code, status.Timeout)
 }
 
+func TestTransactionDeadlineBudget(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	mockEventService.Timeout = true
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peers := []fab.Peer{testPeer1}
+
+	chClient := setupChannelClient(peers, t)
+	chClient.eventService = mockEventService
+
+	parent, cancel := reqContext.WithTimeout(reqContext.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithParentContext(parent), WithDeadlineBudget(invoke.BudgetOpts{Commit: 0.1}))
+	require.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second, "commit wait should be limited to its fraction of the budget")
+
+	statusError, ok := status.FromError(err)
+	require.True(t, ok, "Expected status error got %+v", err)
+	assert.EqualValues(t, status.Timeout, statusError.Code)
+	assert.Contains(t, err.Error(), "deadline budget exhausted in commit phase")
+
+	_, err = chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke"},
+		WithDeadlineBudget(invoke.BudgetOpts{Endorsement: 0.5, Broadcast: 0.5, Commit: 0.5}))
+	assert.Error(t, err, "fractions that add up to more than 1 should be rejected")
+}
+
 func TestExecuteTxWithRetries(t *testing.T) {
 	testStatus := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
 	testResp := []byte("test")
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCCID         = "assets"
+	testMetadataPath = "testdata/metadata.json"
+)
+
+type owner struct {
+	Name string `json:"name"`
+	Org  string `json:"org,omitempty"`
+}
+
+type asset struct {
+	ID    string `json:"ID"`
+	Color string `json:"Color"`
+	Size  int32  `json:"Size"`
+	Owner *owner `json:"Owner"`
+}
+
+// mockChannelClient records the requests and returns a preset payload
+type mockChannelClient struct {
+	queries    []channel.Request
+	executions []channel.Request
+	payload    []byte
+	err        error
+}
+
+func (c *mockChannelClient) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.queries = append(c.queries, request)
+	return channel.Response{Payload: c.payload}, c.err
+}
+
+func (c *mockChannelClient) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.executions = append(c.executions, request)
+	return channel.Response{Payload: c.payload}, c.err
+}
+
+func TestLoadMetadata(t *testing.T) {
+	md, err := LoadMetadata(testMetadataPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"AssetContract", "AuditContract"}, md.ContractNames())
+
+	c, ok := md.DefaultContract()
+	require.True(t, ok)
+	assert.Equal(t, "AssetContract", c.Name)
+
+	_, tx, err := md.Transaction("GetAllAssets")
+	require.NoError(t, err)
+	assert.False(t, tx.IsSubmit())
+	require.NotNil(t, tx.Returns)
+	assert.Equal(t, "array", tx.Returns.Schema.Type, "array form of returns should be supported")
+
+	_, tx, err = md.Transaction("AssetContract:TransferAsset")
+	require.NoError(t, err)
+	assert.True(t, tx.IsSubmit(), "untagged transactions should be submitted")
+
+	_, _, err = md.Transaction("Unknown:History")
+	assert.Error(t, err)
+	_, _, err = md.Transaction("History")
+	assert.Error(t, err, "History is not in the default contract")
+
+	_, err = ParseMetadata([]byte(`{"contracts":{}}`))
+	assert.Error(t, err)
+	_, err = LoadMetadata("testdata/invalid.json")
+	assert.Error(t, err)
+}
+
+func TestQueryMetadata(t *testing.T) {
+	md, err := LoadMetadata(testMetadataPath)
+	require.NoError(t, err)
+	mdBytes, err := json.Marshal(md)
+	require.NoError(t, err)
+
+	client := &mockChannelClient{payload: mdBytes}
+	queried, err := QueryMetadata(client, testCCID)
+	require.NoError(t, err)
+	assert.Equal(t, md.ContractNames(), queried.ContractNames())
+	require.Len(t, client.queries, 1)
+	assert.Equal(t, channel.Request{ChaincodeID: testCCID, Fcn: GetMetadataFcn}, client.queries[0])
+
+	client = &mockChannelClient{err: errors.New("query failed")}
+	_, err = QueryMetadata(client, testCCID)
+	assert.Error(t, err)
+}
+
+func TestInvoker(t *testing.T) {
+	md, err := LoadMetadata(testMetadataPath)
+	require.NoError(t, err)
+
+	client := &mockChannelClient{}
+	invoker, err := NewInvoker(client, testCCID, md)
+	require.NoError(t, err)
+
+	t.Run("Submit", func(t *testing.T) {
+		_, err := invoker.Invoke("CreateAsset", []interface{}{"asset1", "red", 5, &owner{Name: "Tom"}}, nil)
+		require.NoError(t, err)
+		require.Len(t, client.executions, 1)
+		request := client.executions[0]
+		assert.Equal(t, "AssetContract:CreateAsset", request.Fcn)
+		assert.Equal(t, [][]byte{[]byte("asset1"), []byte("red"), []byte("5"), []byte(`{"name":"Tom"}`)}, request.Args)
+	})
+
+	t.Run("Evaluate", func(t *testing.T) {
+		client.payload = []byte(`{"ID":"asset1","Color":"red","Size":5,"Owner":{"name":"Tom"}}`)
+		result := &asset{}
+		_, err := invoker.Invoke("ReadAsset", []interface{}{"asset1"}, result)
+		require.NoError(t, err)
+		require.Len(t, client.queries, 1)
+		assert.Equal(t, &asset{ID: "asset1", Color: "red", Size: 5, Owner: &owner{Name: "Tom"}}, result)
+
+		var exists bool
+		client.payload = []byte("true")
+		_, err = invoker.Invoke("AssetExists", []interface{}{"asset1"}, &exists)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("String result", func(t *testing.T) {
+		client.payload = []byte("Tom")
+		var previousOwner string
+		_, err := invoker.Invoke("TransferAsset", []interface{}{"asset1", &owner{Name: "Jerry"}}, &previousOwner)
+		require.NoError(t, err)
+		assert.Equal(t, "Tom", previousOwner)
+	})
+
+	t.Run("Invalid result", func(t *testing.T) {
+		client.payload = []byte(`{"ID":"asset1"}`)
+		_, err := invoker.Invoke("ReadAsset", []interface{}{"asset1"}, &asset{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is required")
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			args []interface{}
+			msg  string
+		}{
+			{"wrong count", []interface{}{"asset1"}, "expects 4 argument(s)"},
+			{"empty string", []interface{}{"", "red", 5, &owner{Name: "Tom"}}, "at least 1 characters"},
+			{"enum", []interface{}{"asset1", "purple", 5, &owner{Name: "Tom"}}, "must be one of"},
+			{"integer", []interface{}{"asset1", "red", 1.5, &owner{Name: "Tom"}}, "must be an integer"},
+			{"minimum", []interface{}{"asset1", "red", 0, &owner{Name: "Tom"}}, "greater than or equal to"},
+			{"type", []interface{}{"asset1", "red", "5", &owner{Name: "Tom"}}, "must be a number"},
+			{"required", []interface{}{"asset1", "red", 5, map[string]string{"org": "Org1"}}, "value.name is required"},
+			{"additional property", []interface{}{"asset1", "red", 5, map[string]string{"name": "Tom", "age": "3"}}, "value.age is not allowed"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := invoker.Invoke("CreateAsset", tc.args, nil)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.msg)
+			})
+		}
+	})
+
+	t.Run("Explicit submit and evaluate", func(t *testing.T) {
+		client.queries = nil
+		client.executions = nil
+		client.payload = []byte(`["a","b"]`)
+		var history []string
+		_, err := invoker.Submit("AuditContract:History", []interface{}{"asset"}, &history)
+		require.NoError(t, err)
+		assert.Len(t, client.executions, 1)
+		assert.Equal(t, []string{"a", "b"}, history)
+
+		_, err = invoker.Evaluate("AuditContract:History", []interface{}{"asset"}, nil)
+		require.NoError(t, err)
+		assert.Len(t, client.queries, 1)
+	})
+
+	_, err = NewInvoker(nil, testCCID, md)
+	assert.Error(t, err)
+	_, err = NewInvoker(client, "", md)
+	assert.Error(t, err)
+	_, err = NewInvoker(client, testCCID, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// contractgen generates strongly typed Go client stubs from the metadata of a contract API chaincode.
+//
+//  Usage:
+//  contractgen -metadata metadata.json -package assets -out assets_client.go
+//
+// The metadata may be retrieved from a running chaincode by querying the
+// org.hyperledger.fabric:GetMetadata transaction (see contract.QueryMetadata).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract/gen"
+)
+
+func main() {
+	metadataPath := flag.String("metadata", "", "path to the contract metadata JSON file (required)")
+	pkg := flag.String("package", "", "name of the Go package of the generated source (required)")
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	if *metadataPath == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*metadataPath, *pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "contractgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(metadataPath, pkg, out string) error {
+	md, err := contract.LoadMetadata(metadataPath)
+	if err != nil {
+		return err
+	}
+
+	src, err := gen.Generate(md, gen.Options{Package: pkg})
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gen generates strongly typed Go client stubs from contract API metadata.
+// For each contract a client type is generated with one method per transaction. Submit
+// transactions are invoked with Execute and evaluate transactions with Query, using
+// contract.Invoker to validate and serialize the arguments. A Go struct is generated
+// for each schema in the components section of the metadata.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/pkg/errors"
+)
+
+// Options contains the options for generating client stubs
+type Options struct {
+	// Package is the name of the Go package of the generated source (required)
+	Package string
+	// Generator is the name of the generator that is recorded in the header of the generated source
+	Generator string
+}
+
+type structModel struct {
+	Name        string
+	Description string
+	Fields      []fieldModel
+}
+
+type fieldModel struct {
+	Name     string
+	Type     string
+	JSONName string
+	Optional bool
+}
+
+type clientModel struct {
+	Name         string
+	ContractName string
+	Transactions []txModel
+}
+
+type txModel struct {
+	Method     string
+	Name       string
+	Submit     bool
+	Params     []paramModel
+	ResultType string
+}
+
+type paramModel struct {
+	Name string
+	Type string
+}
+
+type fileModel struct {
+	Generator string
+	Package   string
+	Metadata  string
+	Structs   []structModel
+	Clients   []clientModel
+}
+
+// Generate generates the Go source of typed client stubs for the contracts in the given metadata
+func Generate(md *contract.Metadata, opts Options) ([]byte, error) {
+	if md == nil {
+		return nil, errors.New("contract metadata is required")
+	}
+	if opts.Package == "" {
+		return nil, errors.New("package name is required")
+	}
+	if opts.Generator == "" {
+		opts.Generator = "contractgen"
+	}
+
+	mdBytes, err := json.Marshal(md)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal contract metadata")
+	}
+
+	model := fileModel{
+		Generator: opts.Generator,
+		Package:   opts.Package,
+		Metadata:  strconv.Quote(string(mdBytes)),
+		Structs:   newStructModels(md),
+	}
+
+	for _, name := range md.ContractNames() {
+		client, err := newClientModel(md.Contracts[name])
+		if err != nil {
+			return nil, err
+		}
+		model.Clients = append(model.Clients, client)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := fileTemplate.Execute(buf, model); err != nil {
+		return nil, errors.Wrap(err, "failed to execute template")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format generated source")
+	}
+
+	return src, nil
+}
+
+func newStructModels(md *contract.Metadata) []structModel {
+	var names []string
+	for name := range md.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var structs []structModel
+	for _, name := range names {
+		schema := md.Components.Schemas[name]
+
+		var propNames []string
+		for propName := range schema.Properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+
+		s := structModel{Name: exportedName(name), Description: schema.Description}
+		for _, propName := range propNames {
+			s.Fields = append(s.Fields, fieldModel{
+				Name:     exportedName(propName),
+				Type:     goType(schema.Properties[propName]),
+				JSONName: propName,
+				Optional: !contains(schema.Required, propName),
+			})
+		}
+		structs = append(structs, s)
+	}
+
+	return structs
+}
+
+func newClientModel(c *contract.ContractMetadata) (clientModel, error) {
+	client := clientModel{Name: exportedName(c.Name) + "Client", ContractName: c.Name}
+
+	methods := make(map[string]bool)
+	for _, tx := range c.Transactions {
+		method := exportedName(tx.Name)
+		if methods[method] {
+			return clientModel{}, errors.Errorf("transactions in contract [%s] map to duplicate method name [%s]", c.Name, method)
+		}
+		methods[method] = true
+
+		t := txModel{Method: method, Name: tx.Name, Submit: tx.IsSubmit()}
+		if tx.Returns != nil && tx.Returns.Schema != nil {
+			t.ResultType = goType(tx.Returns.Schema)
+		}
+
+		used := map[string]bool{"c": true, "opts": true, "result": true, "resp": true, "err": true}
+		for i, p := range tx.Parameters {
+			name := paramName(p.Name, i, used)
+			used[name] = true
+			t.Params = append(t.Params, paramModel{Name: name, Type: goType(p.Schema)})
+		}
+		client.Transactions = append(client.Transactions, t)
+	}
+
+	return client, nil
+}
+
+// goType returns the Go type that corresponds to the given schema
+func goType(s *contract.Schema) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if name := s.RefName(); name != "" {
+		return "*" + exportedName(name)
+	}
+
+	switch s.Type {
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "integer":
+		switch s.Format {
+		case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+			return s.Format
+		}
+		return "int"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+// exportedName converts the given name into an exported Go identifier
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	id := b.String()
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// paramName converts the given parameter name into an unexported Go identifier
+// that does not clash with keywords or other identifiers in the generated method
+func paramName(name string, index int, used map[string]bool) string {
+	id := exportedName(name)
+	runes := []rune(id)
+	runes[0] = unicode.ToLower(runes[0])
+	id = string(runes)
+
+	if id == "x" && name == "" {
+		id = "arg" + strconv.Itoa(index)
+	}
+	if isKeyword(id) || used[id] {
+		id += "Arg"
+	}
+	for used[id] {
+		id += strconv.Itoa(index)
+	}
+	return id
+}
+
+func isKeyword(id string) bool {
+	switch id {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var", "channel", "contract":
+		return true
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var fileTemplate = template.Must(template.New("contract").Parse(`// Code generated by {{.Generator}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+)
+
+// metadataJSON contains the contract metadata from which this file was generated
+const metadataJSON = {{.Metadata}}
+{{range .Structs}}
+// {{.Name}} {{if .Description}}{{.Description}}{{else}}is generated from the {{.Name}} schema component{{end}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSONName}}{{if .Optional}},omitempty{{end}}"` + "`" + `
+{{- end}}
+}
+{{end}}
+{{- range $client := .Clients}}
+// {{.Name}} is a typed client for the {{.ContractName}} contract
+type {{.Name}} struct {
+	invoker *contract.Invoker
+}
+
+// New{{.Name}} returns a typed client for the {{.ContractName}} contract of the given chaincode
+func New{{.Name}}(client contract.ChannelClient, chaincodeID string) (*{{.Name}}, error) {
+	md, err := contract.ParseMetadata([]byte(metadataJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	invoker, err := contract.NewInvoker(client, chaincodeID, md)
+	if err != nil {
+		return nil, err
+	}
+
+	return &{{.Name}}{invoker: invoker}, nil
+}
+{{range .Transactions}}
+// {{.Method}} {{if .Submit}}submits{{else}}evaluates{{end}} the {{.Name}} transaction
+func (c *{{$client.Name}}) {{.Method}}({{range .Params}}{{.Name}} {{.Type}}, {{end}}opts ...channel.RequestOption) ({{if .ResultType}}{{.ResultType}}, {{end}}channel.Response, error) {
+	args := []interface{}{ {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}}{{end -}} }
+{{- if .ResultType}}
+
+	var result {{.ResultType}}
+	resp, err := c.invoker.{{if .Submit}}Submit{{else}}Evaluate{{end}}("{{$client.ContractName}}:{{.Name}}", args, &result, opts...)
+	return result, resp, err
+{{- else}}
+	return c.invoker.{{if .Submit}}Submit{{else}}Evaluate{{end}}("{{$client.ContractName}}:{{.Name}}", args, nil, opts...)
+{{- end}}
+}
+{{end}}
+{{- end}}`))
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	md, err := contract.LoadMetadata("../testdata/metadata.json")
+	require.NoError(t, err)
+
+	src, err := Generate(md, Options{Package: "assets"})
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "assets.go", src, parser.ParseComments)
+	require.NoError(t, err, "generated source should be valid Go")
+	assert.Equal(t, "assets", file.Name.Name)
+
+	decls := make(map[string]string)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					decls[ts.Name.Name] = "type"
+				}
+			}
+		case *ast.FuncDecl:
+			decls[d.Name.Name] = "func"
+		}
+	}
+
+	for _, name := range []string{"Asset", "Owner", "AssetContractClient", "AuditContractClient"} {
+		assert.Equal(t, "type", decls[name], "expecting type %s", name)
+	}
+	for _, name := range []string{"NewAssetContractClient", "NewAuditContractClient", "CreateAsset", "ReadAsset", "GetAllAssets", "AssetExists", "TransferAsset", "History"} {
+		assert.Equal(t, "func", decls[name], "expecting func %s", name)
+	}
+
+	assert.Contains(t, string(src), "func (c *AssetContractClient) CreateAsset(id string, color string, size int32, owner *Owner, opts ...channel.RequestOption) (channel.Response, error)")
+	assert.Contains(t, string(src), "func (c *AssetContractClient) ReadAsset(id string, opts ...channel.RequestOption) (*Asset, channel.Response, error)")
+	assert.Contains(t, string(src), "func (c *AuditContractClient) History(typeArg string, opts ...channel.RequestOption) ([]string, channel.Response, error)")
+	assert.Contains(t, string(src), "c.invoker.Submit(\"AssetContract:CreateAsset\"")
+	assert.Contains(t, string(src), "c.invoker.Evaluate(\"AssetContract:ReadAsset\"")
+	assert.Contains(t, string(src), "Org  string `json:\"org,omitempty\"`")
+
+	// The generated source must also build against the real packages. It's written into a directory
+	// of this module (ignored by ./... patterns since its name starts with an underscore).
+	dir, err := ioutil.TempDir(".", "_assets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "assets.go"), src, 0600))
+
+	out, err := exec.Command("go", "build", "./"+dir).CombinedOutput() // nolint: gas
+	require.NoError(t, err, "generated source should build: %s", out)
+
+	_, err = Generate(md, Options{})
+	assert.Error(t, err, "package name is required")
+	_, err = Generate(nil, Options{Package: "assets"})
+	assert.Error(t, err, "metadata is required")
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "AssetID", exportedName("asset_ID"))
+	assert.Equal(t, "X1st", exportedName("1st"))
+	assert.Equal(t, "GetValue", exportedName("get-value"))
+
+	used := map[string]bool{"opts": true}
+	assert.Equal(t, "rangeArg", paramName("range", 0, used))
+	assert.Equal(t, "optsArg", paramName("opts", 1, used))
+	assert.Equal(t, "arg2", paramName("", 2, used))
+
+	assert.Equal(t, "[]*Asset", goType(&contract.Schema{Type: "array", Items: &contract.Schema{Ref: "#/components/schemas/Asset"}}))
+	assert.Equal(t, "int64", goType(&contract.Schema{Type: "integer", Format: "int64"}))
+	assert.Equal(t, "float64", goType(&contract.Schema{Type: "number", Format: "double"}))
+	assert.Equal(t, "interface{}", goType(nil))
+}
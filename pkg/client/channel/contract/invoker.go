@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contract
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+)
+
+// Invoker invokes the transactions of a contract API chaincode. Arguments are validated
+// against the parameter schemas in the metadata and serialized according to the conventions
+// of the contract API: strings are passed as is and all other values are encoded as JSON.
+type Invoker struct {
+	client      ChannelClient
+	chaincodeID string
+	metadata    *Metadata
+}
+
+// NewInvoker returns a new invoker for the given chaincode
+func NewInvoker(client ChannelClient, chaincodeID string, metadata *Metadata) (*Invoker, error) {
+	if client == nil {
+		return nil, errors.New("channel client is required")
+	}
+	if chaincodeID == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+	if metadata == nil {
+		return nil, errors.New("contract metadata is required")
+	}
+
+	return &Invoker{client: client, chaincodeID: chaincodeID, metadata: metadata}, nil
+}
+
+// Metadata returns the contract metadata
+func (inv *Invoker) Metadata() *Metadata {
+	return inv.metadata
+}
+
+// Invoke invokes the given transaction. The transaction name may be qualified with the
+// contract name (contract:transaction), otherwise the default contract is assumed.
+// Submit transactions are executed (endorsed and committed) and evaluate transactions
+// are queried. If result is not nil then the payload is validated against the return
+// schema and decoded into result.
+func (inv *Invoker) Invoke(txName string, args []interface{}, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	_, tx, err := inv.metadata.Transaction(txName)
+	if err != nil {
+		return channel.Response{}, err
+	}
+
+	if tx.IsSubmit() {
+		return inv.invokeWith(inv.client.Execute, txName, args, result, options...)
+	}
+	return inv.invokeWith(inv.client.Query, txName, args, result, options...)
+}
+
+// Submit invokes a transaction using Execute regardless of how it is tagged in the metadata
+func (inv *Invoker) Submit(txName string, args []interface{}, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	return inv.invokeWith(inv.client.Execute, txName, args, result, options...)
+}
+
+// Evaluate invokes a transaction using Query regardless of how it is tagged in the metadata
+func (inv *Invoker) Evaluate(txName string, args []interface{}, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	return inv.invokeWith(inv.client.Query, txName, args, result, options...)
+}
+
+type invokeFunc func(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+
+func (inv *Invoker) invokeWith(invoke invokeFunc, txName string, args []interface{}, result interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	contract, tx, err := inv.metadata.Transaction(txName)
+	if err != nil {
+		return channel.Response{}, err
+	}
+
+	request, err := inv.newRequest(contract, tx, args)
+	if err != nil {
+		return channel.Response{}, err
+	}
+
+	response, err := invoke(request, options...)
+	if err != nil {
+		return response, err
+	}
+
+	if result != nil {
+		if err := inv.decodeResult(tx, response.Payload, result); err != nil {
+			return response, errors.WithMessagef(err, "failed to decode result of transaction [%s]", request.Fcn)
+		}
+	}
+
+	return response, nil
+}
+
+// newRequest validates and serializes the arguments and returns the channel request
+func (inv *Invoker) newRequest(contract *ContractMetadata, tx *TransactionMetadata, args []interface{}) (channel.Request, error) {
+	fcn := contract.Name + ":" + tx.Name
+
+	if len(args) != len(tx.Parameters) {
+		return channel.Request{}, errors.Errorf("transaction [%s] expects %d argument(s) but %d were provided", fcn, len(tx.Parameters), len(args))
+	}
+
+	request := channel.Request{ChaincodeID: inv.chaincodeID, Fcn: fcn}
+	for i, param := range tx.Parameters {
+		arg, err := inv.serialize(param, args[i])
+		if err != nil {
+			return channel.Request{}, errors.WithMessagef(err, "invalid argument [%s] for transaction [%s]", param.Name, fcn)
+		}
+		request.Args = append(request.Args, arg)
+	}
+
+	return request, nil
+}
+
+func (inv *Invoker) serialize(param *ParameterMetadata, arg interface{}) ([]byte, error) {
+	value, bytes, err := toJSONValue(arg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal argument")
+	}
+
+	if err := inv.metadata.Components.Validate(param.Schema, value); err != nil {
+		return nil, err
+	}
+
+	if str, ok := value.(string); ok {
+		return []byte(str), nil
+	}
+
+	return bytes, nil
+}
+
+func (inv *Invoker) decodeResult(tx *TransactionMetadata, payload []byte, result interface{}) error {
+	if len(payload) == 0 {
+		return nil
+	}
+
+	var schema *Schema
+	if tx.Returns != nil {
+		var err error
+		schema, err = inv.metadata.Components.Resolve(tx.Returns.Schema)
+		if err != nil {
+			return err
+		}
+	}
+
+	if schema != nil && schema.Type == "string" {
+		str, ok := result.(*string)
+		if !ok {
+			return errors.New("result must be a *string for a transaction that returns a string")
+		}
+		*str = string(payload)
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return errors.Wrap(err, "payload is not valid JSON")
+	}
+	if err := inv.metadata.Components.Validate(schema, value); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, result)
+}
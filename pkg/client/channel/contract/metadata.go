@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package contract enables typed access to chaincodes written with the Fabric contract API.
+// Contract API chaincodes describe their contracts, transactions, parameters and return values
+// in metadata which is returned by the org.hyperledger.fabric:GetMetadata system transaction.
+// The metadata is used to validate and serialize transaction arguments and to route each
+// transaction to either Execute (submit) or Query (evaluate) on the channel client.
+//
+//  Basic Flow:
+//  1) Query (or load) the contract metadata
+//  2) Create an invoker for the chaincode
+//  3) Submit or evaluate transactions using Go values as arguments
+package contract
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+)
+
+// GetMetadataFcn is the name of the system transaction that returns the contract metadata
+const GetMetadataFcn = "org.hyperledger.fabric:GetMetadata"
+
+const (
+	submitTag   = "submit"
+	evaluateTag = "evaluate"
+)
+
+// ChannelClient is the subset of channel.Client that is used to invoke contracts
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// Metadata describes the contracts implemented by a chaincode
+type Metadata struct {
+	Info       *Info                        `json:"info,omitempty"`
+	Contracts  map[string]*ContractMetadata `json:"contracts"`
+	Components Components                   `json:"components"`
+}
+
+// Info contains general information about a chaincode or contract
+type Info struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// Components contains the schemas that are referenced by the transactions
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// ContractMetadata describes a single contract
+type ContractMetadata struct {
+	Name         string                 `json:"name"`
+	Info         *Info                  `json:"info,omitempty"`
+	Transactions []*TransactionMetadata `json:"transactions"`
+	Default      bool                   `json:"default,omitempty"`
+}
+
+// TransactionMetadata describes a transaction of a contract
+type TransactionMetadata struct {
+	Name       string               `json:"name"`
+	Tag        []string             `json:"tag,omitempty"`
+	Parameters []*ParameterMetadata `json:"parameters,omitempty"`
+	Returns    *ReturnsMetadata     `json:"returns,omitempty"`
+}
+
+// ParameterMetadata describes a transaction parameter
+type ParameterMetadata struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+// ReturnsMetadata describes the value returned by a transaction
+type ReturnsMetadata struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// UnmarshalJSON accepts both the single object form ({"schema": ...}) used by Go
+// contracts and the array form ([{"name": ..., "schema": ...}]) used by Node and Java contracts
+func (r *ReturnsMetadata) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var returns []*ParameterMetadata
+		if err := json.Unmarshal(data, &returns); err != nil {
+			return err
+		}
+		if len(returns) > 0 {
+			r.Schema = returns[0].Schema
+		}
+		return nil
+	}
+
+	type returnsMetadata ReturnsMetadata
+	return json.Unmarshal(data, (*returnsMetadata)(r))
+}
+
+// IsSubmit returns true if the transaction updates the ledger and must therefore be submitted
+// for ordering. Transactions that are not explicitly tagged as evaluate are considered to be submit.
+func (t *TransactionMetadata) IsSubmit() bool {
+	submit := true
+	for _, tag := range t.Tag {
+		switch strings.ToLower(tag) {
+		case submitTag:
+			return true
+		case evaluateTag:
+			submit = false
+		}
+	}
+	return submit
+}
+
+// ParseMetadata parses contract metadata from JSON
+func ParseMetadata(data []byte) (*Metadata, error) {
+	md := &Metadata{}
+	if err := json.Unmarshal(data, md); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal contract metadata")
+	}
+
+	if len(md.Contracts) == 0 {
+		return nil, errors.New("contract metadata does not contain any contracts")
+	}
+
+	for name, c := range md.Contracts {
+		if c.Name == "" {
+			c.Name = name
+		}
+	}
+
+	return md, nil
+}
+
+// LoadMetadata loads contract metadata from a JSON file
+func LoadMetadata(path string) (*Metadata, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read contract metadata from %s", path)
+	}
+	return ParseMetadata(data)
+}
+
+// QueryMetadata queries the contract metadata of the given chaincode
+func QueryMetadata(client ChannelClient, chaincodeID string, options ...channel.RequestOption) (*Metadata, error) {
+	response, err := client.Query(channel.Request{ChaincodeID: chaincodeID, Fcn: GetMetadataFcn}, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query contract metadata")
+	}
+	return ParseMetadata(response.Payload)
+}
+
+// ContractNames returns the names of the contracts in sorted order
+func (md *Metadata) ContractNames() []string {
+	var names []string
+	for name := range md.Contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultContract returns the default contract. If no contract is marked as the default
+// and there is exactly one contract then that contract is returned.
+func (md *Metadata) DefaultContract() (*ContractMetadata, bool) {
+	for _, c := range md.Contracts {
+		if c.Default {
+			return c, true
+		}
+	}
+	if len(md.Contracts) == 1 {
+		for _, c := range md.Contracts {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Transaction returns the metadata for the given transaction. The name may be qualified
+// with the contract name (contract:transaction), otherwise the default contract is used.
+func (md *Metadata) Transaction(name string) (*ContractMetadata, *TransactionMetadata, error) {
+	var contract *ContractMetadata
+	txName := name
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		var ok bool
+		contract, ok = md.Contracts[name[:i]]
+		if !ok {
+			return nil, nil, errors.Errorf("contract [%s] not found in metadata", name[:i])
+		}
+		txName = name[i+1:]
+	} else {
+		var ok bool
+		contract, ok = md.DefaultContract()
+		if !ok {
+			return nil, nil, errors.Errorf("transaction [%s] must be qualified with a contract name since there is no default contract", name)
+		}
+	}
+
+	for _, tx := range contract.Transactions {
+		if tx.Name == txName {
+			return contract, tx, nil
+		}
+	}
+
+	return nil, nil, errors.Errorf("transaction [%s] not found in contract [%s]", txName, contract.Name)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const componentsSchemaPrefix = "#/components/schemas/"
+
+// Schema is the subset of JSON schema that is used by the contract API to describe
+// parameters, return values and components
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// RefName returns the name of the component referenced by the schema or
+// an empty string if the schema is not a reference
+func (s *Schema) RefName() string {
+	if strings.HasPrefix(s.Ref, componentsSchemaPrefix) {
+		return strings.TrimPrefix(s.Ref, componentsSchemaPrefix)
+	}
+	return ""
+}
+
+// Resolve returns the schema referenced by the given schema (if it is a reference)
+func (c Components) Resolve(s *Schema) (*Schema, error) {
+	for s != nil && s.Ref != "" {
+		name := s.RefName()
+		if name == "" {
+			return nil, errors.Errorf("unsupported schema reference [%s]", s.Ref)
+		}
+		resolved, ok := c.Schemas[name]
+		if !ok {
+			return nil, errors.Errorf("schema component [%s] not found", name)
+		}
+		s = resolved
+	}
+	return s, nil
+}
+
+// Validate validates a JSON-decoded value (as produced by json.Unmarshal into an interface{})
+// against the given schema
+func (c Components) Validate(s *Schema, value interface{}) error {
+	return c.validate(s, value, "value")
+}
+
+func (c Components) validate(s *Schema, value interface{}, path string) error {
+	s, err := c.Resolve(s)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		return errors.Errorf("%s must be one of %v", path, s.Enum)
+	}
+
+	switch s.Type {
+	case "string":
+		return validateString(s, value, path)
+	case "number", "integer":
+		return validateNumber(s, value, path)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return errors.Errorf("%s must be a boolean", path)
+		}
+	case "array":
+		return c.validateArray(s, value, path)
+	case "object":
+		return c.validateObject(s, value, path)
+	case "":
+		if len(s.Properties) > 0 {
+			return c.validateObject(s, value, path)
+		}
+	default:
+		return errors.Errorf("unsupported schema type [%s] for %s", s.Type, path)
+	}
+
+	return nil
+}
+
+func validateString(s *Schema, value interface{}, path string) error {
+	str, ok := value.(string)
+	if !ok {
+		return errors.Errorf("%s must be a string", path)
+	}
+	length := len([]rune(str))
+	if s.MinLength != nil && length < *s.MinLength {
+		return errors.Errorf("%s must be at least %d characters long", path, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return errors.Errorf("%s must be at most %d characters long", path, *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		matched, err := regexp.MatchString(s.Pattern, str)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern for %s", path)
+		}
+		if !matched {
+			return errors.Errorf("%s must match pattern %s", path, s.Pattern)
+		}
+	}
+	return nil
+}
+
+func validateNumber(s *Schema, value interface{}, path string) error {
+	n, ok := value.(float64)
+	if !ok {
+		return errors.Errorf("%s must be a number", path)
+	}
+	if s.Type == "integer" && n != math.Trunc(n) {
+		return errors.Errorf("%s must be an integer", path)
+	}
+	if s.Minimum != nil && n < *s.Minimum {
+		return errors.Errorf("%s must be greater than or equal to %v", path, *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return errors.Errorf("%s must be less than or equal to %v", path, *s.Maximum)
+	}
+	return nil
+}
+
+func (c Components) validateArray(s *Schema, value interface{}, path string) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		return errors.Errorf("%s must be an array", path)
+	}
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		return errors.Errorf("%s must contain at least %d items", path, *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		return errors.Errorf("%s must contain at most %d items", path, *s.MaxItems)
+	}
+	for i, item := range items {
+		if err := c.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Components) validateObject(s *Schema, value interface{}, path string) error {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return errors.Errorf("%s must be an object", path)
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return errors.Errorf("%s.%s is required", path, name)
+		}
+	}
+	for name, v := range obj {
+		propSchema, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return errors.Errorf("%s.%s is not allowed", path, name)
+			}
+			continue
+		}
+		if err := c.validate(propSchema, v, path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// toJSONValue converts a Go value to its generic JSON representation
+func toJSONValue(value interface{}) (interface{}, []byte, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(bytes, &generic); err != nil {
+		return nil, nil, err
+	}
+	return generic, bytes, nil
+}
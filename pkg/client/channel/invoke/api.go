@@ -16,12 +16,46 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 )
 
 // CCFilter returns true if the given chaincode should be included
 // in the invocation chain when computing endorsers.
 type CCFilter func(ccID string) bool
 
+// QueryStrategy determines how the responses of a consensus query are evaluated
+type QueryStrategy int
+
+const (
+	// FastestWins returns as soon as enough matching responses have been received
+	// to satisfy the consensus criteria. Responses from the remaining targets are ignored.
+	FastestWins QueryStrategy = iota
+
+	// Quorum waits for all targets to respond and requires that the matching responses
+	// satisfy the consensus criteria and also form a majority of the successful responses.
+	Quorum
+)
+
+// String returns the name of the query strategy
+func (s QueryStrategy) String() string {
+	switch s {
+	case FastestWins:
+		return "fastest-wins"
+	case Quorum:
+		return "quorum"
+	default:
+		return "unknown"
+	}
+}
+
+// ConsensusOpts contains the criteria that the responses of a query must satisfy
+// in order for the query to succeed
+type ConsensusOpts struct {
+	Strategy    QueryStrategy
+	MinMatching int // minimum number of identical responses (defaults to 1)
+	MinMSPs     int // minimum number of distinct MSPs among the identical responses (defaults to 1)
+}
+
 // Opts allows the user to specify more advanced options
 type Opts struct {
 	Targets       []fab.Peer // targets
@@ -32,6 +66,9 @@ type Opts struct {
 	Timeouts      map[fab.TimeoutType]time.Duration
 	ParentContext reqContext.Context //parent grpc context
 	CCFilter      CCFilter
+	Consensus     *ConsensusOpts
+	Budget        *BudgetOpts
+	Identity      msp.SigningIdentity // signs the request instead of the identity of the client's context
 }
 
 // Request contains the parameters to execute transaction
@@ -60,6 +97,7 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	AgreedEndorsers  []string // URLs of the endorsers whose responses matched (consensus queries only)
 }
 
 //Handler for chaining transaction executions
@@ -75,6 +113,9 @@ type ClientContext struct {
 	Membership   fab.ChannelMembership
 	Transactor   fab.Transactor
 	EventService fab.EventService
+
+	// InvocationChainCache (optional) holds the invocation chains learned from previous endorsements
+	InvocationChainCache *InvocationChainCache
 }
 
 //RequestContext contains request, opts, response parameters for handler execution
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+)
+
+// Phases of an invocation that consume the deadline budget
+const (
+	PhaseEndorsement = "endorsement"
+	PhaseBroadcast   = "broadcast"
+	PhaseCommit      = "commit"
+)
+
+// BudgetOpts enables the end-to-end deadline mode, in which the deadline of the parent context is the
+// budget of the entire invocation (including retries). Each phase is allotted the given fraction of the
+// budget. A fraction of zero means that the phase is only limited by the remaining budget.
+type BudgetOpts struct {
+	Endorsement float64
+	Broadcast   float64
+	Commit      float64
+}
+
+// Fractions returns the fraction of the budget of each phase
+func (o *BudgetOpts) Fractions() map[string]float64 {
+	return map[string]float64{
+		PhaseEndorsement: o.Endorsement,
+		PhaseBroadcast:   o.Broadcast,
+		PhaseCommit:      o.Commit,
+	}
+}
+
+// startPhase starts the given phase of the deadline budget (if the request has one). The returned function
+// ends the phase and, if the phase ran out of time, replaces the given error with an error that names the phase.
+func startPhase(requestContext *RequestContext, phase string) func(err error) error {
+	budget, ok := contextImpl.RequestBudget(requestContext.Ctx)
+	if !ok {
+		return func(err error) error { return err }
+	}
+
+	end := budget.StartPhase(phase)
+	return func(err error) error {
+		if end() && err != nil {
+			return NewBudgetExhaustedError(budget, phase)
+		}
+		return err
+	}
+}
+
+// NewBudgetExhaustedError returns a timeout error indicating that the given phase exhausted the deadline budget
+func NewBudgetExhaustedError(budget *contextImpl.Budget, phase string) error {
+	if phase == "" {
+		return status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			fmt.Sprintf("deadline budget of %s exhausted", budget.Total()), nil)
+	}
+	return status.New(status.ClientStatus, status.Timeout.ToInt32(),
+		fmt.Sprintf("deadline budget exhausted in %s phase (allotted %s of %s)", phase, budget.Allotted(phase), budget.Total()), nil)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// ConsensusEndorsementHandler sends a query proposal to each of the targets (or, if no targets
+// were provided, to all of the peers returned by discovery) and groups identical responses together.
+// The query succeeds if a group of identical responses satisfies the consensus options of the request.
+// Only the responses in the agreeing group are returned.
+type ConsensusEndorsementHandler struct {
+	next Handler
+}
+
+// NewConsensusQueryHandler returns query handler with chain of ConsensusEndorsementHandler and SignatureValidationHandler
+func NewConsensusQueryHandler(next ...Handler) Handler {
+	return NewConsensusEndorsementHandler(
+		NewSignatureValidationHandler(next...),
+	)
+}
+
+// NewConsensusEndorsementHandler returns a handler that requires consensus among the query responses
+func NewConsensusEndorsementHandler(next ...Handler) *ConsensusEndorsementHandler {
+	return &ConsensusEndorsementHandler{next: getNext(next)}
+}
+
+type proposalResult struct {
+	target   fab.Peer
+	response *fab.TransactionProposalResponse
+	err      error
+}
+
+// Handle sends the proposal to the targets and evaluates the responses
+func (h *ConsensusEndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.ConsensusEndorsementHandler")()
+
+	opts := consensusOptsOrDefault(requestContext.Opts.Consensus)
+
+	targets, err := getConsensusTargets(requestContext, clientContext)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	if len(targets) < opts.MinMatching {
+		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(),
+			fmt.Sprintf("%d target(s) available but %d matching responses are required", len(targets), opts.MinMatching), nil)
+		return
+	}
+
+	proposal, err := createTransactionProposal(clientContext.Transactor, &requestContext.Request)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	requestContext.Opts.Targets = targets
+	requestContext.Response.Proposal = proposal
+	requestContext.Response.TransactionID = proposal.TxnID
+	withTxIDField(requestContext, proposal.TxnID)
+
+	agreed, err := collectConsensus(requestContext.Ctx, clientContext.Transactor, proposal, targets, opts)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	requestContext.Response.Responses = agreed
+	requestContext.Response.Payload = agreed[0].ProposalResponse.GetResponse().Payload
+	requestContext.Response.ChaincodeStatus = agreed[0].ChaincodeStatus
+	for _, r := range agreed {
+		requestContext.Response.AgreedEndorsers = append(requestContext.Response.AgreedEndorsers, r.Endorser)
+	}
+
+	//Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+func consensusOptsOrDefault(o *ConsensusOpts) ConsensusOpts {
+	opts := ConsensusOpts{Strategy: FastestWins, MinMatching: 1, MinMSPs: 1}
+	if o == nil {
+		return opts
+	}
+	opts.Strategy = o.Strategy
+	if o.MinMatching > 0 {
+		opts.MinMatching = o.MinMatching
+	}
+	if o.MinMSPs > 0 {
+		opts.MinMSPs = o.MinMSPs
+	}
+	return opts
+}
+
+// getConsensusTargets returns the targets provided in the request or, if none were provided,
+// all of the peers on the channel that are accepted by the selection filter. Unlike the selection
+// service (which only returns enough endorsers to satisfy the endorsement policy), discovery
+// allows the query to be sent to as many independent peers as possible.
+func getConsensusTargets(requestContext *RequestContext, clientContext *ClientContext) ([]fab.Peer, error) {
+	if len(requestContext.Opts.Targets) > 0 {
+		return requestContext.Opts.Targets, nil
+	}
+
+	peers, err := clientContext.Discovery.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get peers from discovery service")
+	}
+
+	var targets []fab.Peer
+	for _, p := range peers {
+		if requestContext.SelectionFilter == nil || requestContext.SelectionFilter(p) {
+			targets = append(targets, p)
+		}
+	}
+
+	if requestContext.PeerSorter != nil {
+		targets = requestContext.PeerSorter(targets)
+	}
+
+	return targets, nil
+}
+
+// collectConsensus sends the proposal to each target concurrently and returns the group
+// of identical responses that satisfies the consensus options
+func collectConsensus(ctx reqContext.Context, sender fab.ProposalSender, proposal *fab.TransactionProposal, targets []fab.Peer, opts ConsensusOpts) ([]*fab.TransactionProposalResponse, error) {
+	resultCh := make(chan proposalResult, len(targets))
+	for _, target := range targets {
+		go func(target fab.Peer) {
+			responses, err := sender.SendTransactionProposal(proposal, []fab.ProposalProcessor{target})
+			if err == nil && len(responses) == 0 {
+				err = errors.Errorf("no response received from [%s]", target.URL())
+			}
+			result := proposalResult{target: target, err: err}
+			if err == nil {
+				result.response = responses[0]
+			}
+			resultCh <- result
+		}(target)
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	tally := newResponseTally()
+	var errs multi.Errors
+	for i := 0; i < len(targets); i++ {
+		var result proposalResult
+		select {
+		case result = <-resultCh:
+		case <-done:
+			// the outstanding responses are dropped into the buffered channel
+			return nil, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				fmt.Sprintf("consensus query timed out or been cancelled after %d of %d response(s)", i, len(targets)), nil)
+		}
+		if result.err != nil {
+			logger.Debugw(ctx, "Query failed", logging.KV(logging.PeerKey, result.target.URL()), logging.KV(logging.ErrorKey, result.err))
+			errs = append(errs, result.err)
+			continue
+		}
+		if err := validateResponseStatus(result.response); err != nil {
+			logger.Debugw(ctx, "Query returned an error response", logging.KV(logging.PeerKey, result.target.URL()), logging.KV(logging.ErrorKey, err))
+			errs = append(errs, err)
+			continue
+		}
+
+		group := tally.add(result.target, result.response)
+		if opts.Strategy == FastestWins && group.satisfies(opts) {
+			logger.Debugw(ctx, "Consensus reached", logging.KV("responses", i+1), logging.KV("targets", len(targets)))
+			return group.responses, nil
+		}
+	}
+
+	if tally.total == 0 {
+		return nil, errs.ToError()
+	}
+
+	return tally.consensus(opts)
+}
+
+func validateResponseStatus(r *fab.TransactionProposalResponse) error {
+	response := r.ProposalResponse.GetResponse()
+	if response.Status < int32(common.Status_SUCCESS) || response.Status >= int32(common.Status_BAD_REQUEST) {
+		return status.NewFromProposalResponse(r.ProposalResponse, r.Endorser)
+	}
+	return nil
+}
+
+// responseGroup contains identical proposal responses
+type responseGroup struct {
+	responses []*fab.TransactionProposalResponse
+	mspIDs    map[string]struct{}
+}
+
+func (g *responseGroup) satisfies(opts ConsensusOpts) bool {
+	return len(g.responses) >= opts.MinMatching && len(g.mspIDs) >= opts.MinMSPs
+}
+
+// responseTally groups proposal responses by their content
+type responseTally struct {
+	groups map[string]*responseGroup
+	order  []string
+	total  int
+}
+
+func newResponseTally() *responseTally {
+	return &responseTally{groups: make(map[string]*responseGroup)}
+}
+
+func (t *responseTally) add(target fab.Peer, r *fab.TransactionProposalResponse) *responseGroup {
+	key := responseKey(r)
+
+	group, ok := t.groups[key]
+	if !ok {
+		group = &responseGroup{mspIDs: make(map[string]struct{})}
+		t.groups[key] = group
+		t.order = append(t.order, key)
+	}
+
+	group.responses = append(group.responses, r)
+	group.mspIDs[target.MSPID()] = struct{}{}
+	t.total++
+
+	return group
+}
+
+// responseKey returns the key by which the response is grouped: a hash of the proposal response
+// payload and the chaincode response payload, each prefixed by its length so that different pairs
+// of payloads can't produce the same key
+func responseKey(r *fab.TransactionProposalResponse) string {
+	var data []byte
+	for _, field := range [][]byte{r.ProposalResponse.Payload, r.ProposalResponse.GetResponse().Payload} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		data = append(append(data, length[:]...), field...)
+	}
+	sum := sha256.Sum256(data)
+	return string(sum[:])
+}
+
+// consensus returns the largest group of identical responses provided that it satisfies the consensus
+// options and, for the Quorum strategy, that it constitutes a majority of the successful responses.
+func (t *responseTally) consensus(opts ConsensusOpts) ([]*fab.TransactionProposalResponse, error) {
+	var largest *responseGroup
+	for _, key := range t.order {
+		group := t.groups[key]
+		if largest == nil || len(group.responses) > len(largest.responses) {
+			largest = group
+		}
+	}
+
+	if !largest.satisfies(opts) {
+		return nil, status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(),
+			fmt.Sprintf("consensus not reached: largest group of matching responses has %d response(s) from %d MSP(s) but %d response(s) from %d MSP(s) are required",
+				len(largest.responses), len(largest.mspIDs), opts.MinMatching, opts.MinMSPs), nil)
+	}
+
+	if opts.Strategy == Quorum && 2*len(largest.responses) <= t.total {
+		return nil, status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(),
+			fmt.Sprintf("consensus not reached: %d of %d response(s) match which is not a majority", len(largest.responses), t.total), nil)
+	}
+
+	return largest.responses, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusQueryHandler(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value"), RWLock: &sync.RWMutex{}}
+	peer2 := &fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value"), RWLock: &sync.RWMutex{}}
+	peer3 := &fcmocks.MockPeer{MockName: "Peer3", MockURL: "http://peer3.com", MockMSP: "Org2MSP", Status: 200, Payload: []byte("value"), RWLock: &sync.RWMutex{}}
+	peer4 := &fcmocks.MockPeer{MockName: "Peer4", MockURL: "http://peer4.com", MockMSP: "Org2MSP", Status: 200, Payload: []byte("other"), RWLock: &sync.RWMutex{}}
+	peer5 := &fcmocks.MockPeer{MockName: "Peer5", MockURL: "http://peer5.com", MockMSP: "Org3MSP", Status: 500, Payload: []byte("error"), RWLock: &sync.RWMutex{}}
+
+	t.Run("Quorum", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{Strategy: Quorum, MinMatching: 3, MinMSPs: 2}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3, peer4, peer5)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+		assert.Len(t, requestContext.Response.Responses, 3)
+		assert.ElementsMatch(t, []string{peer1.URL(), peer2.URL(), peer3.URL()}, requestContext.Response.AgreedEndorsers)
+	})
+
+	t.Run("Quorum not a majority", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{Strategy: Quorum, MinMatching: 1}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer4)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.EndorsementMismatch.ToInt32(), s.Code)
+	})
+
+	t.Run("Insufficient MSPs", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{Strategy: FastestWins, MinMatching: 2, MinMSPs: 2}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer4)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		assert.Contains(t, requestContext.Error.Error(), "consensus not reached")
+	})
+
+	t.Run("Fastest wins", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{Strategy: FastestWins, MinMatching: 2}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3, peer4, peer5)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+		assert.Len(t, requestContext.Response.AgreedEndorsers, 2)
+	})
+
+	t.Run("Explicit targets", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{peer3, peer4}, Consensus: &ConsensusOpts{}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.NoError(t, requestContext.Error)
+		assert.Len(t, requestContext.Response.AgreedEndorsers, 1)
+	})
+
+	t.Run("Not enough targets", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{MinMatching: 3}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, peer2)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.NoPeersFound.ToInt32(), s.Code)
+	})
+
+	t.Run("All failed", func(t *testing.T) {
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{}}, t)
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer5)
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		// the lock of the slow peer is held until the test returns so that it never responds
+		lock := &sync.RWMutex{}
+		lock.Lock()
+		defer lock.Unlock()
+		slowPeer := &fcmocks.MockPeer{MockName: "Peer6", MockURL: "http://peer6.com", MockMSP: "Org3MSP", Status: 200, Payload: []byte("value"), RWLock: lock}
+
+		requestContext := prepareRequestContext(request, Opts{Consensus: &ConsensusOpts{Strategy: Quorum, MinMatching: 2}}, t)
+		ctx, cancel := reqContext.WithCancel(requestContext.Ctx)
+		requestContext.Ctx = ctx
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		clientContext.Discovery = txnmocks.NewMockDiscoveryService(nil, peer1, slowPeer)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		NewQueryHandler().Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.Timeout.ToInt32(), s.Code)
+	})
+}
+
+func TestResponseTallyKey(t *testing.T) {
+	newResponse := func(payload, responsePayload string) *fab.TransactionProposalResponse {
+		return &fab.TransactionProposalResponse{
+			ProposalResponse: &pb.ProposalResponse{
+				Payload:  []byte(payload),
+				Response: &pb.Response{Status: 200, Payload: []byte(responsePayload)},
+			},
+		}
+	}
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP"}
+	peer2 := &fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockMSP: "Org2MSP"}
+
+	// The concatenations of the payloads are the same but the responses differ
+	tally := newResponseTally()
+	tally.add(peer1, newResponse("ab", "c"))
+	group := tally.add(peer2, newResponse("a", "bc"))
+	assert.Len(t, group.responses, 1)
+	assert.Len(t, tally.groups, 2)
+
+	group = tally.add(peer2, newResponse("ab", "c"))
+	assert.Len(t, group.responses, 2)
+}
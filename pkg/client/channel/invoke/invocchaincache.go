@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// InvocationChainCache holds the invocation chains (i.e. the chaincodes and private data collections)
+// that were learned from the read/write sets of previous endorsements, keyed by chaincode and function.
+// The SelectAndEndorseHandler uses the cached invocation chain to select endorsers for all of the
+// chaincodes up front, which avoids a second round of endorsements on subsequent invocations.
+type InvocationChainCache struct {
+	// entries contains a map of chaincode/function keys and invocChainEntry values
+	entries sync.Map
+	ttl     time.Duration
+}
+
+type invocChainEntry struct {
+	invocChain []*fab.ChaincodeCall
+	timeAdded  time.Time
+}
+
+// NewInvocationChainCache returns a new invocation chain cache. Entries expire after the given time-to-live.
+func NewInvocationChainCache(ttl time.Duration) *InvocationChainCache {
+	return &InvocationChainCache{ttl: ttl}
+}
+
+// Get returns a copy of the cached invocation chain for the given chaincode and function or
+// false if there is no entry or if the entry has expired
+func (c *InvocationChainCache) Get(ccID, fcn string) ([]*fab.ChaincodeCall, bool) {
+	key := invocChainKey(ccID, fcn)
+	value, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry := value.(*invocChainEntry)
+	if c.ttl > 0 && entry.timeAdded.Add(c.ttl).Before(time.Now()) {
+		logger.Debugf("Invocation chain for [%s] has expired", key)
+		c.entries.Delete(key)
+		return nil, false
+	}
+
+	return copyInvocationChain(entry.invocChain), true
+}
+
+// Put caches a copy of the invocation chain for the given chaincode and function
+func (c *InvocationChainCache) Put(ccID, fcn string, invocChain []*fab.ChaincodeCall) {
+	c.entries.Store(invocChainKey(ccID, fcn), &invocChainEntry{invocChain: copyInvocationChain(invocChain), timeAdded: time.Now()})
+}
+
+// Invalidate removes the cached invocation chain for the given chaincode and function
+func (c *InvocationChainCache) Invalidate(ccID, fcn string) {
+	c.entries.Delete(invocChainKey(ccID, fcn))
+}
+
+// learn updates the cache with the invocation chain from a proposal response. The cached entry is replaced
+// if the response contains a chaincode or collection that was not previously known, otherwise the existing
+// entry (and its expiry) is retained.
+func (c *InvocationChainCache) learn(ccID, fcn string, respInvocChain []*fab.ChaincodeCall) {
+	var learned []*fab.ChaincodeCall
+	for _, ccCall := range respInvocChain {
+		if lsccFilter(ccCall.ID) {
+			learned = append(learned, ccCall)
+		}
+	}
+
+	cached, ok := c.Get(ccID, fcn)
+	if !ok {
+		logger.Debugf("Caching invocation chain for chaincode [%s], function [%s]", ccID, fcn)
+		c.Put(ccID, fcn, learned)
+		return
+	}
+
+	if _, changed := mergeInvocationChains(cached, learned, acceptAllCCFilter); changed {
+		logger.Debugf("Invocation chain for chaincode [%s], function [%s] has changed. Replacing cached entry.", ccID, fcn)
+		c.Put(ccID, fcn, learned)
+	}
+}
+
+// copyInvocationChain returns a deep copy of the invocation chain so that the cached chaincode calls
+// (and their collections) are never shared with, and modified by, concurrent requests
+func copyInvocationChain(invocChain []*fab.ChaincodeCall) []*fab.ChaincodeCall {
+	if invocChain == nil {
+		return nil
+	}
+	chainCopy := make([]*fab.ChaincodeCall, len(invocChain))
+	for i, ccCall := range invocChain {
+		ccCallCopy := &fab.ChaincodeCall{ID: ccCall.ID}
+		if ccCall.Collections != nil {
+			ccCallCopy.Collections = append([]string(nil), ccCall.Collections...)
+		}
+		chainCopy[i] = ccCallCopy
+	}
+	return chainCopy
+}
+
+func invocChainKey(ccID, fcn string) string {
+	return ccID + "/" + fcn
+}
+
+var acceptAllCCFilter = func(ccID string) bool {
+	return true
+}
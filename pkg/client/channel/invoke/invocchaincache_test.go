@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvocationChainCache(t *testing.T) {
+	cache := NewInvocationChainCache(50 * time.Millisecond)
+
+	_, ok := cache.Get("cc1", "fcn")
+	assert.False(t, ok)
+
+	cache.learn("cc1", "fcn", []*fab.ChaincodeCall{{ID: "cc1"}, {ID: "lscc"}, {ID: "cc2"}})
+	invocChain, ok := cache.Get("cc1", "fcn")
+	require.True(t, ok)
+	assert.Equal(t, []*fab.ChaincodeCall{{ID: "cc1"}, {ID: "cc2"}}, invocChain)
+
+	_, ok = cache.Get("cc1", "otherfcn")
+	assert.False(t, ok, "entries should be cached per function")
+
+	// Learning a subset of the cached chain should not replace the entry
+	cache.learn("cc1", "fcn", []*fab.ChaincodeCall{{ID: "cc1"}})
+	invocChain, ok = cache.Get("cc1", "fcn")
+	require.True(t, ok)
+	assert.Len(t, invocChain, 2)
+
+	// A new collection should replace the entry
+	cache.learn("cc1", "fcn", []*fab.ChaincodeCall{{ID: "cc1", Collections: []string{"coll1"}}, {ID: "cc2"}})
+	invocChain, ok = cache.Get("cc1", "fcn")
+	require.True(t, ok)
+	assert.Equal(t, []string{"coll1"}, invocChain[0].Collections)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok = cache.Get("cc1", "fcn")
+	assert.False(t, ok, "entry should have expired")
+
+	cache.Put("cc1", "fcn", []*fab.ChaincodeCall{{ID: "cc1"}})
+	cache.Invalidate("cc1", "fcn")
+	_, ok = cache.Get("cc1", "fcn")
+	assert.False(t, ok, "entry should have been invalidated")
+}
+
+func TestInvocationChainCacheCopies(t *testing.T) {
+	cache := NewInvocationChainCache(time.Minute)
+
+	invocChain := []*fab.ChaincodeCall{{ID: "cc1", Collections: make([]string, 1, 4)}}
+	invocChain[0].Collections[0] = "coll1"
+	cache.Put("cc1", "fcn", invocChain)
+
+	// Modifying the chain that was put must not modify the cached chain
+	invocChain[0].Collections = append(invocChain[0].Collections, "coll2")
+	invocChain[0].ID = "other"
+
+	cached, ok := cache.Get("cc1", "fcn")
+	require.True(t, ok)
+	assert.Equal(t, []*fab.ChaincodeCall{{ID: "cc1", Collections: []string{"coll1"}}}, cached)
+
+	// Neither must modifying a chain that was returned
+	cached[0].Collections[0] = "changed"
+	cached[0].Collections = append(cached[0].Collections, "coll3")
+
+	cached, ok = cache.Get("cc1", "fcn")
+	require.True(t, ok)
+	assert.Equal(t, []string{"coll1"}, cached[0].Collections)
+}
+
+func TestSelectAndEndorseWithInvocationChainCache(t *testing.T) {
+	ccID1 := "cc1"
+	ccID2 := "cc2"
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	peer1.SetRwSets(fcmocks.NewRwSet(ccID1), fcmocks.NewRwSet(ccID2))
+	peer2 := &fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockMSP: "Org2MSP", Status: 200, Payload: []byte("value")}
+	peer2.SetRwSets(peer1.RwSets...)
+
+	selection := &recordingSelectionService{
+		endorsers: map[string][]fab.Peer{
+			ccID1:               {peer1},
+			ccID1 + "," + ccID2: {peer1, peer2},
+		},
+	}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.Selection = selection
+	clientContext.InvocationChainCache = NewInvocationChainCache(time.Minute)
+
+	request := Request{ChaincodeID: ccID1, Fcn: "invoke"}
+
+	// The first invocation discovers cc2 and requires a second round of endorsements
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	NewSelectAndEndorseHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []string{ccID1, ccID1 + "," + ccID2}, selection.calls)
+	assert.Len(t, requestContext.Response.Responses, 2)
+
+	// The second invocation selects endorsers for cc1 and cc2 up front
+	selection.calls = nil
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewSelectAndEndorseHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []string{ccID1 + "," + ccID2}, selection.calls)
+	assert.Len(t, requestContext.Response.Responses, 2)
+}
+
+// recordingSelectionService records the invocation chains that are passed to it
+type recordingSelectionService struct {
+	endorsers map[string][]fab.Peer
+	calls     []string
+}
+
+func (s *recordingSelectionService) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts ...options.Opt) ([]fab.Peer, error) {
+	key := ""
+	for i, cc := range chaincodes {
+		if i > 0 {
+			key += ","
+		}
+		key += cc.ID
+	}
+	s.calls = append(s.calls, key)
+	return s.endorsers[key], nil
+}
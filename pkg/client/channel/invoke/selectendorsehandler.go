@@ -45,9 +45,27 @@ func NewSelectAndEndorseHandler(next ...Handler) Handler {
 
 // Handle selects endorsers and sends proposals to the endorsers
 func (e *SelectAndEndorseHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.SelectAndEndorseHandler")()
+
+	endPhase := startPhase(requestContext, PhaseEndorsement)
+	e.selectAndEndorse(requestContext, clientContext)
+	requestContext.Error = endPhase(requestContext.Error)
+
+	if requestContext.Error != nil {
+		return
+	}
+
+	if e.next != nil {
+		e.next.Handle(requestContext, clientContext)
+	}
+}
+
+func (e *SelectAndEndorseHandler) selectAndEndorse(requestContext *RequestContext, clientContext *ClientContext) {
 	var ccCalls []*fab.ChaincodeCall
 	targets := requestContext.Opts.Targets
 	if len(targets) == 0 {
+		addCachedInvocationChain(requestContext, clientContext)
+
 		var err error
 		ccCalls, requestContext.Opts.Targets, err = getEndorsers(requestContext, clientContext)
 		if err != nil {
@@ -67,11 +85,11 @@ func (e *SelectAndEndorseHandler) Handle(requestContext *RequestContext, clientC
 		if err != nil {
 			// Log a warning. No need to fail the endorsement. Use the responses collected so far,
 			// which may be sufficient to satisfy the chaincode policy.
-			logger.Warnf("error getting additional endorsers: %s", err)
+			logger.Warnw(requestContext.Ctx, "Error getting additional endorsers", logging.KV(logging.ErrorKey, err))
 		} else {
 			if len(additionalEndorsers) > 0 {
 				requestContext.Opts.Targets = additionalEndorsers
-				logger.Debugf("...getting additional endorsements from %d target(s)", len(additionalEndorsers))
+				logger.Debugw(requestContext.Ctx, "...getting additional endorsements", logging.KV("targets", len(additionalEndorsers)))
 				additionalResponses, err := clientContext.Transactor.SendTransactionProposal(requestContext.Response.Proposal, peer.PeersToTxnProcessors(additionalEndorsers))
 				if err != nil {
 					requestContext.Error = errors.WithMessage(err, "error sending transaction proposal")
@@ -81,14 +99,10 @@ func (e *SelectAndEndorseHandler) Handle(requestContext *RequestContext, clientC
 				// Add the new endorsements to the list of responses
 				requestContext.Response.Responses = append(requestContext.Response.Responses, additionalResponses...)
 			} else {
-				logger.Debugf("...no additional endorsements are required.")
+				logger.Debugw(requestContext.Ctx, "...no additional endorsements are required.")
 			}
 		}
 	}
-
-	if e.next != nil {
-		e.next.Handle(requestContext, clientContext)
-	}
 }
 
 //NewChainedCCFilter returns a chaincode filter that chains
@@ -126,6 +140,10 @@ func getAdditionalEndorsers(requestContext *RequestContext, clientContext *Clien
 		return nil, errors.WithMessage(err, "error getting invocation chain from proposal response")
 	}
 
+	if clientContext.InvocationChainCache != nil {
+		clientContext.InvocationChainCache.learn(requestContext.Request.ChaincodeID, requestContext.Request.Fcn, invocationChainFromResponse)
+	}
+
 	invocationChain, foundAdditional := mergeInvocationChains(invocationChain, invocationChainFromResponse, getCCFilter(requestContext))
 	if !foundAdditional {
 		return nil, nil
@@ -133,7 +151,7 @@ func getAdditionalEndorsers(requestContext *RequestContext, clientContext *Clien
 
 	requestContext.Request.InvocationChain = invocationChain
 
-	logger.Debugf("Found additional chaincodes/collections. Checking if additional endorsements are required...")
+	logger.Debugw(requestContext.Ctx, "Found additional chaincodes/collections. Checking if additional endorsements are required...")
 
 	// If using Fabric selection then disable retries. We don't want to keep retrying if the endorsement query returns an error.
 	// Also, add a priority selector that gives priority to peers from which we already have endorsements. This way, we don't
@@ -149,7 +167,7 @@ func getAdditionalEndorsers(requestContext *RequestContext, clientContext *Clien
 	var additionalEndorsers []fab.Peer
 	for _, endorser := range endorsers {
 		if !containsMSP(requestContext.Opts.Targets, endorser.MSPID()) {
-			logger.Debugf("... will ask for additional endorsement from [%s] in order to satisfy the chaincode policy", endorser.URL())
+			logger.Debugw(requestContext.Ctx, "... will ask for additional endorsement in order to satisfy the chaincode policy", logging.KV(logging.PeerKey, endorser.URL()))
 			additionalEndorsers = append(additionalEndorsers, endorser)
 		}
 	}
@@ -157,6 +175,45 @@ func getAdditionalEndorsers(requestContext *RequestContext, clientContext *Clien
 	return additionalEndorsers, nil
 }
 
+// addCachedInvocationChain adds the chaincodes and collections from the cached invocation chain
+// (if any) to the invocation chain of the request so that endorsers may be selected for all of
+// the chaincodes up front
+func addCachedInvocationChain(requestContext *RequestContext, clientContext *ClientContext) {
+	if clientContext.InvocationChainCache == nil {
+		return
+	}
+
+	cachedInvocChain, ok := clientContext.InvocationChainCache.Get(requestContext.Request.ChaincodeID, requestContext.Request.Fcn)
+	if !ok {
+		return
+	}
+
+	logger.Debugw(requestContext.Ctx, "Using cached invocation chain", logging.KV("function", requestContext.Request.Fcn))
+
+	filter := getCCFilter(requestContext)
+	invocChain := append([]*fab.ChaincodeCall{}, requestContext.Request.InvocationChain...)
+	for _, cachedCCCall := range cachedInvocChain {
+		if !filter(cachedCCCall.ID) {
+			continue
+		}
+		if i := indexOfCCCall(invocChain, cachedCCCall.ID); i >= 0 {
+			invocChain[i], _ = merge(invocChain[i], cachedCCCall)
+		} else {
+			invocChain = append(invocChain, cachedCCCall)
+		}
+	}
+	requestContext.Request.InvocationChain = invocChain
+}
+
+func indexOfCCCall(invocChain []*fab.ChaincodeCall, ccID string) int {
+	for i, ccCall := range invocChain {
+		if ccCall.ID == ccID {
+			return i
+		}
+	}
+	return -1
+}
+
 func getCCFilter(requestContext *RequestContext) CCFilter {
 	if requestContext.Opts.CCFilter != nil {
 		return NewChainedCCFilter(lsccFilter, requestContext.Opts.CCFilter)
@@ -193,6 +250,24 @@ func getInvocationChainFromResponse(response *fab.TransactionProposalResponse) (
 }
 
 func getRWSetsFromProposalResponse(response *pb.ProposalResponse) ([]*rwsetutil.NsRwSet, error) {
+	chaincodeAction, err := getChaincodeAction(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if chaincodeAction == nil || len(chaincodeAction.Results) == 0 {
+		return nil, nil
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(chaincodeAction.Results); err != nil {
+		return nil, err
+	}
+
+	return txRWSet.NsRwSets, nil
+}
+
+func getChaincodeAction(response *pb.ProposalResponse) (*pb.ChaincodeAction, error) {
 	if response == nil {
 		return nil, nil
 	}
@@ -209,16 +284,7 @@ func getRWSetsFromProposalResponse(response *pb.ProposalResponse) ([]*rwsetutil.
 		return nil, err
 	}
 
-	if len(chaincodeAction.Results) == 0 {
-		return nil, nil
-	}
-
-	txRWSet := &rwsetutil.TxRwSet{}
-	if err := txRWSet.FromProtoBytes(chaincodeAction.Results); err != nil {
-		return nil, err
-	}
-
-	return txRWSet.NsRwSets, nil
+	return chaincodeAction, nil
 }
 
 func mergeInvocationChains(invocChain []*fab.ChaincodeCall, respInvocChain []*fab.ChaincodeCall, filter CCFilter) ([]*fab.ChaincodeCall, bool) {
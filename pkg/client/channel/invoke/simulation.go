@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// Simulation contains the decoded results of a transaction simulation performed by a single endorser
+type Simulation struct {
+	Endorser       string
+	Namespaces     []*NsSimulation
+	ChaincodeEvent *pb.ChaincodeEvent
+}
+
+// NsSimulation contains the read/write set of a single namespace (chaincode)
+type NsSimulation struct {
+	Namespace      string
+	Reads          []*KeyRead
+	Writes         []*KeyWrite
+	Deletes        []string
+	MetadataWrites []*kvrwset.KVMetadataWrite
+	RangeQueries   []*RangeQuery
+	Collections    []*CollectionSimulation
+}
+
+// KeyRead is a key that was read during simulation. Version is nil if the key did not exist.
+type KeyRead struct {
+	Key     string
+	Version *Version
+}
+
+// KeyWrite is a key that was written during simulation
+type KeyWrite struct {
+	Key   string
+	Value []byte
+}
+
+// Version is the height (block and transaction number) at which a key was last committed
+type Version struct {
+	BlockNum uint64
+	TxNum    uint64
+}
+
+// RangeQuery is a range query that was executed during simulation. Either Reads or
+// ReadsMerkleSummary is set, depending on the number of keys returned by the query.
+type RangeQuery struct {
+	StartKey           string
+	EndKey             string
+	ItrExhausted       bool
+	Reads              []*KeyRead
+	ReadsMerkleSummary *kvrwset.QueryReadsMerkleSummary
+}
+
+// CollectionSimulation contains the hashed read/write set of a private data collection
+type CollectionSimulation struct {
+	Collection   string
+	PvtRwSetHash []byte
+	HashedReads  []*HashedKeyRead
+	HashedWrites []*HashedKeyWrite
+}
+
+// HashedKeyRead is a private data key (hash) that was read during simulation
+type HashedKeyRead struct {
+	KeyHash []byte
+	Version *Version
+}
+
+// HashedKeyWrite is a private data key (hash) that was written or deleted during simulation
+type HashedKeyWrite struct {
+	KeyHash   []byte
+	ValueHash []byte
+	IsDelete  bool
+}
+
+// NewDryRunHandler returns dry run handler with chain of SelectAndEndorseHandler, EndorsementValidationHandler and SignatureValidationHandler.
+// The transaction is endorsed but is never sent to the orderer.
+func NewDryRunHandler(next ...Handler) Handler {
+	return NewSelectAndEndorseHandler(
+		NewEndorsementValidationHandler(
+			NewSignatureValidationHandler(next...),
+		),
+	)
+}
+
+// DecodeSimulation decodes the read/write set and chaincode event from the given proposal response
+func DecodeSimulation(response *fab.TransactionProposalResponse) (*Simulation, error) {
+	chaincodeAction, err := getChaincodeAction(response.ProposalResponse)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error extracting chaincode action from proposal response")
+	}
+
+	simulation := &Simulation{Endorser: response.Endorser}
+	if chaincodeAction == nil {
+		return simulation, nil
+	}
+
+	if len(chaincodeAction.Events) > 0 {
+		event := &pb.ChaincodeEvent{}
+		if err := proto.Unmarshal(chaincodeAction.Events, event); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode event")
+		}
+		simulation.ChaincodeEvent = event
+	}
+
+	if len(chaincodeAction.Results) == 0 {
+		return simulation, nil
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(chaincodeAction.Results); err != nil {
+		return nil, errors.WithMessage(err, "error unmarshalling read/write set")
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		simulation.Namespaces = append(simulation.Namespaces, newNsSimulation(nsRWSet))
+	}
+
+	return simulation, nil
+}
+
+func newNsSimulation(nsRWSet *rwsetutil.NsRwSet) *NsSimulation {
+	ns := &NsSimulation{Namespace: nsRWSet.NameSpace}
+
+	if kvRWSet := nsRWSet.KvRwSet; kvRWSet != nil {
+		ns.Reads = newKeyReads(kvRWSet.Reads)
+		for _, w := range kvRWSet.Writes {
+			if w.IsDelete {
+				ns.Deletes = append(ns.Deletes, w.Key)
+			} else {
+				ns.Writes = append(ns.Writes, &KeyWrite{Key: w.Key, Value: w.Value})
+			}
+		}
+		ns.MetadataWrites = kvRWSet.MetadataWrites
+		for _, rq := range kvRWSet.RangeQueriesInfo {
+			ns.RangeQueries = append(ns.RangeQueries, &RangeQuery{
+				StartKey:           rq.StartKey,
+				EndKey:             rq.EndKey,
+				ItrExhausted:       rq.ItrExhausted,
+				Reads:              newKeyReads(rq.GetRawReads().GetKvReads()),
+				ReadsMerkleSummary: rq.GetReadsMerkleHashes(),
+			})
+		}
+	}
+
+	for _, collRWSet := range nsRWSet.CollHashedRwSets {
+		ns.Collections = append(ns.Collections, newCollectionSimulation(collRWSet))
+	}
+
+	return ns
+}
+
+func newCollectionSimulation(collRWSet *rwsetutil.CollHashedRwSet) *CollectionSimulation {
+	coll := &CollectionSimulation{
+		Collection:   collRWSet.CollectionName,
+		PvtRwSetHash: collRWSet.PvtRwSetHash,
+	}
+
+	if collRWSet.HashedRwSet == nil {
+		return coll
+	}
+
+	for _, r := range collRWSet.HashedRwSet.HashedReads {
+		coll.HashedReads = append(coll.HashedReads, &HashedKeyRead{KeyHash: r.KeyHash, Version: newVersion(r.Version)})
+	}
+	for _, w := range collRWSet.HashedRwSet.HashedWrites {
+		coll.HashedWrites = append(coll.HashedWrites, &HashedKeyWrite{KeyHash: w.KeyHash, ValueHash: w.ValueHash, IsDelete: w.IsDelete})
+	}
+
+	return coll
+}
+
+func newKeyReads(kvReads []*kvrwset.KVRead) []*KeyRead {
+	var reads []*KeyRead
+	for _, r := range kvReads {
+		reads = append(reads, &KeyRead{Key: r.Key, Version: newVersion(r.Version)})
+	}
+	return reads
+}
+
+func newVersion(v *kvrwset.Version) *Version {
+	if v == nil {
+		return nil
+	}
+	return &Version{BlockNum: v.BlockNum, TxNum: v.TxNum}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSimulation(t *testing.T) {
+	txRWSet := &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{
+			{
+				NameSpace: "testCC",
+				KvRwSet: &kvrwset.KVRWSet{
+					Reads: []*kvrwset.KVRead{
+						{Key: "a", Version: &kvrwset.Version{BlockNum: 10, TxNum: 2}},
+						{Key: "new"},
+					},
+					Writes: []*kvrwset.KVWrite{
+						{Key: "a", Value: []byte("90")},
+						{Key: "b", IsDelete: true},
+					},
+					RangeQueriesInfo: []*kvrwset.RangeQueryInfo{
+						{
+							StartKey:     "k1",
+							EndKey:       "k9",
+							ItrExhausted: true,
+							ReadsInfo: &kvrwset.RangeQueryInfo_RawReads{
+								RawReads: &kvrwset.QueryReads{KvReads: []*kvrwset.KVRead{{Key: "k2", Version: &kvrwset.Version{BlockNum: 3}}}},
+							},
+						},
+					},
+				},
+				CollHashedRwSets: []*rwsetutil.CollHashedRwSet{
+					{
+						CollectionName: "coll1",
+						PvtRwSetHash:   []byte("pvthash"),
+						HashedRwSet: &kvrwset.HashedRWSet{
+							HashedReads:  []*kvrwset.KVReadHash{{KeyHash: []byte("kh1"), Version: &kvrwset.Version{BlockNum: 5}}},
+							HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("kh2"), ValueHash: []byte("vh2")}},
+						},
+					},
+				},
+			},
+		},
+	}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	event := &pb.ChaincodeEvent{ChaincodeId: "testCC", EventName: "moved", Payload: []byte("payload")}
+	eventBytes, err := proto.Marshal(event)
+	require.NoError(t, err)
+
+	response := newTestProposalResponse(t, &pb.ChaincodeAction{Results: results, Events: eventBytes})
+
+	simulation, err := DecodeSimulation(response)
+	require.NoError(t, err)
+	assert.Equal(t, "http://peer1.com", simulation.Endorser)
+	require.NotNil(t, simulation.ChaincodeEvent)
+	assert.Equal(t, "moved", simulation.ChaincodeEvent.EventName)
+
+	require.Len(t, simulation.Namespaces, 1)
+	ns := simulation.Namespaces[0]
+	assert.Equal(t, "testCC", ns.Namespace)
+	assert.Equal(t, []*KeyRead{{Key: "a", Version: &Version{BlockNum: 10, TxNum: 2}}, {Key: "new"}}, ns.Reads)
+	assert.Equal(t, []*KeyWrite{{Key: "a", Value: []byte("90")}}, ns.Writes)
+	assert.Equal(t, []string{"b"}, ns.Deletes)
+
+	require.Len(t, ns.RangeQueries, 1)
+	assert.Equal(t, "k1", ns.RangeQueries[0].StartKey)
+	assert.True(t, ns.RangeQueries[0].ItrExhausted)
+	assert.Equal(t, []*KeyRead{{Key: "k2", Version: &Version{BlockNum: 3}}}, ns.RangeQueries[0].Reads)
+	assert.Nil(t, ns.RangeQueries[0].ReadsMerkleSummary)
+
+	require.Len(t, ns.Collections, 1)
+	coll := ns.Collections[0]
+	assert.Equal(t, "coll1", coll.Collection)
+	assert.Equal(t, []byte("pvthash"), coll.PvtRwSetHash)
+	assert.Equal(t, []*HashedKeyRead{{KeyHash: []byte("kh1"), Version: &Version{BlockNum: 5}}}, coll.HashedReads)
+	assert.Equal(t, []*HashedKeyWrite{{KeyHash: []byte("kh2"), ValueHash: []byte("vh2")}}, coll.HashedWrites)
+}
+
+func TestDecodeSimulationNoResults(t *testing.T) {
+	simulation, err := DecodeSimulation(newTestProposalResponse(t, &pb.ChaincodeAction{}))
+	require.NoError(t, err)
+	assert.Empty(t, simulation.Namespaces)
+	assert.Nil(t, simulation.ChaincodeEvent)
+
+	_, err = DecodeSimulation(newTestProposalResponse(t, &pb.ChaincodeAction{Results: []byte("invalid")}))
+	assert.Error(t, err)
+}
+
+func newTestProposalResponse(t *testing.T, action *pb.ChaincodeAction) *fab.TransactionProposalResponse {
+	actionBytes, err := proto.Marshal(action)
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: actionBytes})
+	require.NoError(t, err)
+
+	return &fab.TransactionProposalResponse{
+		Endorser:         "http://peer1.com",
+		ProposalResponse: &pb.ProposalResponse{Payload: payload, Response: &pb.Response{Status: 200}},
+	}
+}
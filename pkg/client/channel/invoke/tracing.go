@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
+)
+
+// startSpan starts a span for a step of the handler chain. The request context is replaced
+// with the context of the span (so that subsequent handlers create child spans) until the
+// returned function is invoked, which ends the span and restores the original context.
+func startSpan(requestContext *RequestContext, name string) func() {
+	parent := requestContext.Ctx
+	ctx, span := tracing.StartSpan(parent, name,
+		tracing.Attr(tracing.ChaincodeKey, requestContext.Request.ChaincodeID),
+		tracing.Attr(tracing.FunctionKey, requestContext.Request.Fcn))
+	requestContext.Ctx = ctx
+
+	return func() {
+		if txnID := requestContext.Response.TransactionID; txnID != "" {
+			span.SetAttributes(tracing.Attr(tracing.TxIDKey, string(txnID)))
+		}
+		tracing.EndSpan(span, requestContext.Error)
+		requestContext.Ctx = parent
+	}
+}
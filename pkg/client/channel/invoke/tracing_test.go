@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteHandlerTracing(t *testing.T) {
+	tracer := tracing.NewMockTracer()
+
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	requestContext.Ctx = tracing.ContextWithTracer(requestContext.Ctx, tracer)
+	ctx := requestContext.Ctx
+
+	mockPeer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer}, t)
+	// the transactor creates its own request contexts for the endorsers
+	clientContext.Transactor.(*txnmocks.MockTransactor).Ctx.(*fcmocks.MockContext).SetTracer(tracer)
+
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
+
+	go func() {
+		select {
+		case txStatusReg := <-mockEventService.TxStatusRegCh:
+			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+		case <-time.After(requestContext.Opts.Timeouts[fab.Execute]):
+			panic("Execute handler : time out not expected")
+		}
+	}()
+
+	NewExecuteHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, ctx, requestContext.Ctx, "request context should be restored")
+
+	txnID := string(requestContext.Response.TransactionID)
+
+	for _, name := range []string{"invoke.SelectAndEndorseHandler", "invoke.EndorsementHandler", "invoke.EndorsementValidationHandler",
+		"invoke.SignatureValidationHandler", "invoke.CommitTxHandler"} {
+		spans := tracer.SpansNamed(name)
+		require.Len(t, spans, 1, "expecting span %s", name)
+		assert.True(t, spans[0].Ended())
+		assert.Equal(t, "test", spans[0].Attribute(tracing.ChaincodeKey))
+		assert.Equal(t, "invoke", spans[0].Attribute(tracing.FunctionKey))
+	}
+
+	selectAndEndorse := tracer.SpansNamed("invoke.SelectAndEndorseHandler")[0]
+	endorsement := tracer.SpansNamed("invoke.EndorsementHandler")[0]
+	assert.Equal(t, selectAndEndorse, endorsement.Parent, "handler spans should be nested")
+	assert.Equal(t, txnID, endorsement.Attribute(tracing.TxIDKey))
+
+	waitSpans := tracer.SpansNamed("invoke.WaitForTxStatus")
+	require.Len(t, waitSpans, 1)
+	assert.Equal(t, txnID, waitSpans[0].Attribute(tracing.TxIDKey))
+	assert.Equal(t, pb.TxValidationCode_VALID.String(), waitSpans[0].Attribute(tracing.TxValidationCodeKey))
+	assert.Equal(t, "invoke.CommitTxHandler", waitSpans[0].Parent.Name)
+
+	endorserSpans := tracer.SpansNamed("txn.ProcessTransactionProposal")
+	require.Len(t, endorserSpans, 1)
+	assert.Equal(t, "http://peer1.com", endorserSpans[0].Attribute(tracing.PeerURLKey))
+	assert.Equal(t, txnID, endorserSpans[0].Attribute(tracing.TxIDKey))
+}
@@ -8,15 +8,19 @@ package invoke
 
 import (
 	"bytes"
+	reqContext "context"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 )
@@ -33,6 +37,7 @@ type EndorsementHandler struct {
 
 //Handle for endorsing transactions
 func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.EndorsementHandler")()
 
 	if len(requestContext.Opts.Targets) == 0 {
 		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "targets were not provided", nil)
@@ -45,21 +50,26 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 		TxnHeaderOpts = e.headerOptsProvider()
 	}
 
+	endPhase := startPhase(requestContext, PhaseEndorsement)
 	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(
 		clientContext.Transactor,
 		&requestContext.Request,
 		peer.PeersToTxnProcessors(requestContext.Opts.Targets),
 		TxnHeaderOpts...,
 	)
+	err = endPhase(err)
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
+	withTxIDField(requestContext, proposal.TxnID)
 
 	if err != nil {
 		requestContext.Error = err
 		return
 	}
 
+	logger.Debugw(requestContext.Ctx, "Received endorsements", logging.KV("responses", len(transactionProposalResponses)))
+
 	requestContext.Response.Responses = transactionProposalResponses
 	if len(transactionProposalResponses) > 0 {
 		requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
@@ -79,6 +89,8 @@ type ProposalProcessorHandler struct {
 
 //Handle selects proposal processors
 func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.ProposalProcessorHandler")()
+
 	//Get proposal processor, if not supplied then use selection service to get available peers as endorser
 	if len(requestContext.Opts.Targets) == 0 {
 		var selectionOpts []options.Opt
@@ -94,6 +106,7 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 			requestContext.Error = errors.WithMessage(err, "Failed to get endorsing peers")
 			return
 		}
+		logger.Debugw(requestContext.Ctx, "Selected endorsers", logging.KV("endorsers", len(endorsers)))
 		requestContext.Opts.Targets = endorsers
 	}
 
@@ -122,6 +135,7 @@ type EndorsementValidationHandler struct {
 
 //Handle for Filtering proposal response
 func (f *EndorsementValidationHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.EndorsementValidationHandler")()
 
 	//Filter tx proposal responses
 	err := f.validate(requestContext.Response.Responses)
@@ -165,6 +179,8 @@ type CommitTxHandler struct {
 
 //Handle handles commit tx
 func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	defer startSpan(requestContext, "invoke.CommitTxHandler")()
+
 	txnID := requestContext.Response.TransactionID
 
 	//Register Tx event
@@ -175,42 +191,98 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 	defer clientContext.EventService.Unregister(reg)
 
+	endPhase := startPhase(requestContext, PhaseBroadcast)
 	_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
-	if err != nil {
+	if err = endPhase(err); err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
 	}
 
+	logger.Debugw(requestContext.Ctx, "Transaction sent to orderer. Waiting for commit...")
+
+	endPhase = startPhase(requestContext, PhaseCommit)
+	if err := endPhase(waitForTxStatus(requestContext, txnID, statusNotifier)); err != nil {
+		logger.Debugw(requestContext.Ctx, "Transaction was not committed", logging.KV(logging.ErrorKey, err))
+		requestContext.Error = err
+		return
+	}
+
+	logger.Debugw(requestContext.Ctx, "Transaction committed")
+
+	//Delegate to next step if any
+	if c.next != nil {
+		c.next.Handle(requestContext, clientContext)
+	}
+}
+
+// withTxIDField attaches the transaction ID to the request context as a log field. The field is
+// removed along with the span of the handler.
+func withTxIDField(requestContext *RequestContext, txnID fab.TransactionID) {
+	if txnID != "" {
+		requestContext.Ctx = logging.WithFields(requestContext.Ctx, logging.KV(logging.TxIDKey, string(txnID)))
+	}
+}
+
+// waitForTxStatus waits for the transaction status event of the given transaction
+func waitForTxStatus(requestContext *RequestContext, txnID fab.TransactionID, statusNotifier <-chan *fab.TxStatusEvent) error {
+	_, span := tracing.StartSpan(requestContext.Ctx, "invoke.WaitForTxStatus", tracing.Attr(tracing.TxIDKey, string(txnID)))
+
+	done := requestContext.Ctx.Done()
+	if budget, ok := contextImpl.RequestBudget(requestContext.Ctx); ok {
+		// wait no longer than the commit phase of the deadline budget
+		ctx, cancel := reqContext.WithDeadline(requestContext.Ctx, budget.Deadline())
+		defer cancel()
+		done = ctx.Done()
+	}
+
+	var err error
 	select {
 	case txStatus := <-statusNotifier:
 		requestContext.Response.TxValidationCode = txStatus.TxValidationCode
+		span.SetAttributes(tracing.Attr(tracing.TxValidationCodeKey, txStatus.TxValidationCode.String()))
 
 		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
-			requestContext.Error = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
+			err = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
 				"received invalid transaction", nil)
-			return
 		}
-	case <-requestContext.Ctx.Done():
-		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(),
+	case <-done:
+		err = status.New(status.ClientStatus, status.Timeout.ToInt32(),
 			"Execute didn't receive block event", nil)
-		return
 	}
 
-	//Delegate to next step if any
-	if c.next != nil {
-		c.next.Handle(requestContext, clientContext)
-	}
+	tracing.EndSpan(span, err)
+	return err
 }
 
-//NewQueryHandler returns query handler with chain of ProposalProcessorHandler, EndorsementHandler, EndorsementValidationHandler and SignatureValidationHandler
+// NewQueryHandler returns query handler with chain of ProposalProcessorHandler, EndorsementHandler, EndorsementValidationHandler and SignatureValidationHandler.
+// If consensus options were provided in the request then the chain of ConsensusEndorsementHandler and SignatureValidationHandler is used instead.
 func NewQueryHandler(next ...Handler) Handler {
-	return NewProposalProcessorHandler(
-		NewEndorsementHandler(
-			NewEndorsementValidationHandler(
-				NewSignatureValidationHandler(next...),
+	return &queryHandler{
+		standard: NewProposalProcessorHandler(
+			NewEndorsementHandler(
+				NewEndorsementValidationHandler(
+					NewSignatureValidationHandler(next...),
+				),
 			),
 		),
-	)
+		consensus: NewConsensusQueryHandler(next...),
+	}
+}
+
+// queryHandler delegates to either the standard query chain or the consensus query chain
+// depending on the request options
+type queryHandler struct {
+	standard  Handler
+	consensus Handler
+}
+
+// Handle delegates to the appropriate query handler chain
+func (q *queryHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if requestContext.Opts.Consensus != nil {
+		q.consensus.Handle(requestContext, clientContext)
+		return
+	}
+	q.standard.Handle(requestContext, clientContext)
 }
 
 //NewExecuteHandler returns execute handler with chain of SelectAndEndorseHandler, EndorsementValidationHandler, SignatureValidationHandler and CommitHandler
@@ -276,6 +348,17 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 }
 
 func createAndSendTransactionProposal(transactor fab.ProposalSender, chrequest *Request, targets []fab.ProposalProcessor, opts ...fab.TxnHeaderOpt) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+	proposal, err := createTransactionProposal(transactor, chrequest, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+
+	return transactionProposalResponses, proposal, err
+}
+
+func createTransactionProposal(transactor fab.ProposalSender, chrequest *Request, opts ...fab.TxnHeaderOpt) (*fab.TransactionProposal, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
@@ -285,15 +368,13 @@ func createAndSendTransactionProposal(transactor fab.ProposalSender, chrequest *
 
 	txh, err := transactor.CreateTransactionHeader(opts...)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction header failed")
+		return nil, errors.WithMessage(err, "creating transaction header failed")
 	}
 
 	proposal, err := txn.CreateChaincodeInvokeProposal(txh, request)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
 
-	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
-
-	return transactionProposalResponses, proposal, err
+	return proposal, nil
 }
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package dynamicdiscovery
 
 import (
+	"sync"
+	"time"
+
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/random"
 	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
@@ -14,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -24,6 +28,10 @@ type ChannelService struct {
 	*service
 	channelID  string
 	membership fab.ChannelMembership
+
+	refreshLock    sync.RWMutex
+	lastRefresh    time.Time
+	lastRefreshErr error
 }
 
 // NewChannelService creates a Discovery Service to query the list of member peers on a given channel.
@@ -42,6 +50,20 @@ func NewChannelService(ctx contextAPI.Client, membership fab.ChannelMembership,
 	return s, nil
 }
 
+// LastRefresh returns the time of the last successful refresh of the peers along with the
+// error of the most recent refresh (nil if the most recent refresh succeeded)
+func (s *ChannelService) LastRefresh() (time.Time, error) {
+	s.refreshLock.RLock()
+	defer s.refreshLock.RUnlock()
+
+	return s.lastRefresh, s.lastRefreshErr
+}
+
+// Closed returns true if the discovery service has been closed
+func (s *ChannelService) Closed() bool {
+	return s.peersRef.IsClosed()
+}
+
 // Close releases resources
 func (s *ChannelService) Close() {
 	logger.Debugf("Closing discovery service for channel [%s]", s.channelID)
@@ -49,7 +71,10 @@ func (s *ChannelService) Close() {
 }
 
 func (s *ChannelService) queryPeers() ([]fab.Peer, error) {
+	startTime := time.Now()
 	peers, err := s.doQueryPeers()
+	s.context().GetMetrics().Discovery().ObserveRefresh(s.channelID, metrics.DiscoveryService, time.Since(startTime).Seconds(), err)
+	s.setLastRefresh(startTime, err)
 
 	if err != nil && s.ErrHandler != nil {
 		logger.Infof("[%s] Got error from discovery query: %s. Invoking error handler", s.channelID, err)
@@ -59,6 +84,16 @@ func (s *ChannelService) queryPeers() ([]fab.Peer, error) {
 	return peers, err
 }
 
+func (s *ChannelService) setLastRefresh(refreshTime time.Time, err error) {
+	s.refreshLock.Lock()
+	defer s.refreshLock.Unlock()
+
+	if err == nil {
+		s.lastRefresh = refreshTime
+	}
+	s.lastRefreshErr = err
+}
+
 func (s *ChannelService) doQueryPeers() ([]fab.Peer, error) {
 	logger.Debugf("Refreshing peers of channel [%s] from discovery service...", s.channelID)
 
@@ -86,6 +86,10 @@ func TestDiscoveryService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(peers))
 
+	lastRefresh, refreshErr := service.LastRefresh()
+	assert.NoError(t, refreshErr)
+	assert.False(t, lastRefresh.IsZero())
+
 	discClient.SetResponses(
 		&clientmocks.MockDiscoverEndpointResponse{
 			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
@@ -146,6 +150,11 @@ func TestDiscoveryService(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equalf(t, 2, len(peers), "Expected 2 peers")
 
+	// The error of the last refresh should be reported along with the time of the last successful refresh
+	lastFailedRefresh, refreshErr := service.LastRefresh()
+	assert.Error(t, refreshErr)
+	assert.True(t, lastFailedRefresh.After(lastRefresh))
+
 	// Fatal error (access denied can be due due a user being revoked)
 	discClient.SetResponses(
 		&clientmocks.MockDiscoverEndpointResponse{
@@ -159,6 +168,7 @@ func TestDiscoveryService(t *testing.T) {
 	_, err = service.GetPeers()
 	require.Error(t, err)
 	assert.Equal(t, "Discovery client has been closed", err.Error())
+	assert.True(t, service.Closed())
 }
 
 func TestDiscoveryServiceWithNewOrgJoined(t *testing.T) {
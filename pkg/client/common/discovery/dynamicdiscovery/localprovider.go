@@ -53,3 +53,10 @@ func (p *LocalProvider) CloseContext(ctx fab.ClientContext) {
 	logger.Debugf("Closing local discovery service for MSP [%s]", mspID)
 	p.cache.Delete(lazycache.NewStringKey(mspID))
 }
+
+// Invalidate closes all of the local discovery services so that they are re-created
+// (with the current configuration) the next time they are requested.
+func (p *LocalProvider) Invalidate() {
+	logger.Debug("Invalidating local discovery services")
+	p.cache.DeleteAll()
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
 var logger = logging.NewLogger("fabsdk/client")
@@ -25,11 +26,28 @@ type Filter struct {
 	// peers are expired from the greylist based on these timestamps
 	greylistURLs   sync.Map
 	expiryInterval time.Duration
+	channelID      string
+	metrics        *metrics.DiscoveryMetrics
+}
+
+// Opt is a greylist filter option
+type Opt func(f *Filter)
+
+// WithMetrics sets the metrics that record the peers that are greylisted on the given channel
+func WithMetrics(channelID string, m *metrics.DiscoveryMetrics) Opt {
+	return func(f *Filter) {
+		f.channelID = channelID
+		f.metrics = m
+	}
 }
 
 // New creates a new greylist filter with the given expiry interval
-func New(expire time.Duration) *Filter {
-	return &Filter{expiryInterval: expire}
+func New(expire time.Duration, opts ...Opt) *Filter {
+	f := &Filter{expiryInterval: expire, metrics: (&metrics.ClientMetrics{}).Discovery()}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // Accept returns whether or not to Accept a peer as a canditate for endorsement
@@ -57,6 +75,7 @@ func (b *Filter) Greylist(err error) {
 	if ok, peerURL := required(s); ok && peerURL != "" {
 		logger.Infof("Greylisting peer %s", peerURL)
 		b.greylistURLs.Store(peerURL, time.Now())
+		b.metrics.GreylistedPeers.With(metrics.ChannelLabel, b.channelID, metrics.PeerLabel, peerURL).Add(1)
 	}
 }
 
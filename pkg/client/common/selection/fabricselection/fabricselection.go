@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
@@ -214,6 +215,13 @@ func (s *Service) getChannelResponse(chaincodes []*fab.ChaincodeCall, retryOpts
 }
 
 func (s *Service) queryEndorsers(chaincodes []*fab.ChaincodeCall, retryOpts retry.Opts) (discclient.ChannelResponse, error) {
+	startTime := time.Now()
+	chResponse, err := s.doQueryEndorsers(chaincodes, retryOpts)
+	s.ctx.GetMetrics().Discovery().ObserveRefresh(s.channelID, metrics.SelectionService, time.Since(startTime).Seconds(), err)
+	return chResponse, err
+}
+
+func (s *Service) doQueryEndorsers(chaincodes []*fab.ChaincodeCall, retryOpts retry.Opts) (discclient.ChannelResponse, error) {
 	logger.Debugf("Querying discovery service for endorsers for chaincodes: %#v", chaincodes)
 
 	targets, err := s.getTargets(s.ctx)
@@ -124,7 +124,8 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 //
 //  Returns:
 //  blockchain information
-func (c *Client) QueryInfo(options ...RequestOption) (*fab.BlockchainInfoResponse, error) {
+func (c *Client) QueryInfo(options ...RequestOption) (resp *fab.BlockchainInfoResponse, err error) {
+	defer c.observe("QueryInfo", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -167,7 +168,8 @@ func (c *Client) QueryInfo(options ...RequestOption) (*fab.BlockchainInfoRespons
 //
 //  Returns:
 //  block information
-func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*common.Block, error) {
+func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (block *common.Block, err error) {
+	defer c.observe("QueryBlockByHash", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -191,7 +193,8 @@ func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*
 //
 //  Returns:
 //  block information
-func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOption) (*common.Block, error) {
+func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOption) (block *common.Block, err error) {
+	defer c.observe("QueryBlockByTxID", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -215,7 +218,8 @@ func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOpti
 //
 //  Returns:
 //  block information
-func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*common.Block, error) {
+func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (block *common.Block, err error) {
+	defer c.observe("QueryBlock", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -274,7 +278,8 @@ func matchBlockData(responses []*common.Block, minTargets int) (*common.Block, e
 //
 //  Returns:
 //  processed transaction information
-func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...RequestOption) (*pb.ProcessedTransaction, error) {
+func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...RequestOption) (tx *pb.ProcessedTransaction, err error) {
+	defer c.observe("QueryTransaction", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -313,7 +318,8 @@ func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...Re
 //
 //  Returns:
 //  channel configuration information
-func (c *Client) QueryConfig(options ...RequestOption) (fab.ChannelCfg, error) {
+func (c *Client) QueryConfig(options ...RequestOption) (cfg fab.ChannelCfg, err error) {
+	defer c.observe("QueryConfig", c.ctx.ChannelID(), time.Now(), &err)
 
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
@@ -331,7 +337,8 @@ func (c *Client) QueryConfig(options ...RequestOption) (fab.ChannelCfg, error) {
 }
 
 // QueryConfigBlock returns the current configuration block for the specified channel.
-func (c *Client) QueryConfigBlock(options ...RequestOption) (*common.Block, error) {
+func (c *Client) QueryConfigBlock(options ...RequestOption) (block *common.Block, err error) {
+	defer c.observe("QueryConfigBlock", c.ctx.ChannelID(), time.Now(), &err)
 	targets, opts, err := c.prepareRequestParams(options...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "QueryConfigBlock failed to prepare request parameters")
@@ -428,7 +435,12 @@ func (c *Client) createRequestContext(opts *requestOptions) (reqContext.Context,
 		opts.Timeouts[fab.PeerResponse] = c.ctx.EndpointConfig().Timeout(fab.PeerResponse)
 	}
 
-	return contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.PeerResponse]), contextImpl.WithParent(opts.ParentContext))
+	var client context.Client = c.ctx
+	if opts.Identity != nil {
+		client = &contextImpl.Client{Providers: c.ctx, SigningIdentity: opts.Identity}
+	}
+
+	return contextImpl.NewRequest(client, contextImpl.WithTimeout(opts.Timeouts[fab.PeerResponse]), contextImpl.WithParent(opts.ParentContext))
 }
 
 // filterTargets is helper method to filter peers
@@ -464,3 +476,9 @@ func shuffle(a []fab.Peer) {
 		a[i], a[j] = a[j], a[i]
 	}
 }
+
+// observe records the metrics of a ledger operation. It is deferred by the operation
+// with a pointer to its error result.
+func (c *Client) observe(operation, channelID string, startTime time.Time, err *error) {
+	c.ctx.GetMetrics().Ledger().Observe(channelID, operation, time.Since(startTime).Seconds(), *err)
+}
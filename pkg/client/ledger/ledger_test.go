@@ -219,6 +219,32 @@ func TestQueryInfo(t *testing.T) {
 	}
 }
 
+func TestQueryInfoWithSigningIdentity(t *testing.T) {
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	identity := mspmocks.NewMockSigningIdentity("user2", "Org2MSP")
+	_, err := lc.QueryInfo(WithSigningIdentity(identity))
+	if err != nil {
+		t.Fatalf("Test ledger query info failed: %s", err)
+	}
+
+	_, err = lc.QueryInfo(WithSigningIdentity(nil))
+	assert.Error(t, err, "expected error for nil signing identity")
+
+	reqCtx, cancel := lc.createRequestContext(&requestOptions{Identity: identity})
+	defer cancel()
+	ctx, ok := contextImpl.RequestClientContext(reqCtx)
+	assert.True(t, ok)
+	assert.Equal(t, "user2", ctx.Identifier().ID)
+
+	reqCtx, cancel = lc.createRequestContext(&requestOptions{})
+	defer cancel()
+	ctx, ok = contextImpl.RequestClientContext(reqCtx)
+	assert.True(t, ok)
+	assert.Equal(t, lc.ctx.Identifier().ID, ctx.Identifier().ID)
+}
+
 func TestQueryTransaction(t *testing.T) {
 
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
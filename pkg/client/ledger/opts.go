@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -43,6 +44,7 @@ type requestOptions struct {
 	MinTargets    int                               // min number of targets that have to respond with no error (or agree on result)
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for ledger query operations
 	ParentContext reqContext.Context                //parent grpc context for ledger operations
+	Identity      msp.SigningIdentity               // signs the request instead of the identity of the client's context
 }
 
 //WithTargets allows for overriding of the target peers per request.
@@ -132,3 +134,15 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 		return nil
 	}
 }
+
+// WithSigningIdentity signs the ledger query with the given identity instead of the identity
+// that the client was created with
+func WithSigningIdentity(identity msp.SigningIdentity) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if identity == nil {
+			return errors.New("signing identity is nil")
+		}
+		o.Identity = identity
+		return nil
+	}
+}
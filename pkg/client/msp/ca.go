@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	"time"
+)
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
@@ -73,6 +77,61 @@ type RevokedCert struct {
 	AKI string
 }
 
+// GetCertificatesRequest is a request to list the certificates issued by the CA.
+// All criteria are optional; certificates must match all criteria that are specified.
+type GetCertificatesRequest struct {
+	// ID returns the certificates of the given enrollment ID
+	ID string
+	// AKI returns the certificates with the given AKI (Authority Key Identifier)
+	AKI string
+	// Serial returns the certificate with the given serial number
+	Serial string
+	// RevokedStart and RevokedEnd return the certificates that were revoked within the time window
+	RevokedStart time.Time
+	RevokedEnd   time.Time
+	// ExpiredStart and ExpiredEnd return the certificates that expire within the time window
+	ExpiredStart time.Time
+	ExpiredEnd   time.Time
+	// NotRevoked excludes revoked certificates
+	NotRevoked bool
+	// NotExpired excludes expired certificates
+	NotExpired bool
+	// CAName is the name of the CA to connect to
+	CAName string
+	// Offset is the number of matching certificates to skip
+	Offset int
+	// Limit is the maximum number of certificates to return (zero means no limit)
+	Limit int
+}
+
+// GetCertificatesResponse contains the certificates that match a GetCertificatesRequest
+type GetCertificatesResponse struct {
+	// Certificates are the PEM-encoded certificates
+	Certificates [][]byte
+	// More is true if more certificates match the request than were returned (see Limit).
+	// The next page starts at Offset + len(Certificates).
+	More bool
+}
+
+// GenCRLRequest is a request to generate a CRL (certificate revocation list).
+// All time criteria are optional.
+type GenCRLRequest struct {
+	// CAName is the name of the CA to connect to
+	CAName string
+	// RevokedAfter and RevokedBefore include the certificates that were revoked within the time window
+	RevokedAfter  time.Time
+	RevokedBefore time.Time
+	// ExpireAfter and ExpireBefore include the certificates that expire within the time window
+	ExpireAfter  time.Time
+	ExpireBefore time.Time
+}
+
+// GenCRLResponse is the response to a GenCRLRequest
+type GenCRLResponse struct {
+	// CRL is the PEM-encoded certificate revocation list (CRL) that contains the requested revoked certificates
+	CRL []byte
+}
+
 // IdentityRequest represents the request to add/update identity to the fabric-ca-server
 type IdentityRequest struct {
 
@@ -314,6 +314,8 @@ func getIdentityResponses(responses []*mspapi.IdentityResponse) []*IdentityRespo
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
 // They can be retrieved by calling IdentityManager.GetSigningIdentity().
+// With the WithTLSProfile option, a TLS certificate is enrolled instead; it's stored in a separate
+// TLS credential store and used as the client certificate for mutual TLS.
 //  Parameters:
 //  enrollmentID enrollment ID of a registered user
 //  opts are optional enrollment options
@@ -330,18 +332,14 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		}
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
-	if err != nil {
-		return err
-	}
-
 	req := &mspapi.EnrollmentRequest{
-		Name:    enrollmentID,
-		Secret:  eo.secret,
-		CAName:  c.caName,
-		Profile: eo.profile,
-		Type:    eo.typ,
-		Label:   eo.label,
+		Name:       enrollmentID,
+		Secret:     eo.secret,
+		CAName:     c.caName,
+		Profile:    eo.profile,
+		Type:       eo.typ,
+		Label:      eo.label,
+		KeyRequest: eo.keyReq,
 	}
 
 	if req.CAName == "" {
@@ -356,6 +354,19 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		req.AttrReqs = attrs
 	}
 
+	if eo.tls {
+		ca, err := msp.NewCAClient(c.orgName, c.ctx, msp.WithCAInstance(c.caID))
+		if err != nil {
+			return errors.WithMessage(err, "failed to create CA Client")
+		}
+		return ca.EnrollTLS(req)
+	}
+
+	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
+	if err != nil {
+		return err
+	}
+
 	return ca.Enroll(req)
 }
 
@@ -380,10 +391,11 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 	}
 
 	req := &mspapi.ReenrollmentRequest{
-		Name:    enrollmentID,
-		Profile: eo.profile,
-		Label:   eo.label,
-		CAName:  c.caName,
+		Name:       enrollmentID,
+		Profile:    eo.profile,
+		Label:      eo.label,
+		CAName:     c.caName,
+		KeyRequest: eo.keyReq,
 	}
 	if req.CAName == "" {
 		req.CAName = c.caName
@@ -459,6 +471,58 @@ func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error)
 	}, nil
 }
 
+// GetCertificates returns the certificates issued by the CA that match the request. Large result
+// sets may be retrieved in pages using the Offset and Limit of the request.
+//  Parameters:
+//  request holds the search criteria
+//
+//  Returns:
+//  the matching certificates
+func (c *Client) GetCertificates(request *GetCertificatesRequest) (*GetCertificatesResponse, error) {
+	if request == nil {
+		return nil, errors.New("must provide certificates request")
+	}
+
+	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := mspapi.GetCertificatesRequest(*request)
+	resp, err := ca.GetCertificates(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCertificatesResponse{Certificates: resp.Certificates, More: resp.More}, nil
+}
+
+// GenCRL generates a CRL (certificate revocation list) that contains the revoked certificates that
+// match the request. The CRL may be added to the revocation list of a channel MSP.
+//  Parameters:
+//  request holds the revocation and expiry time windows
+//
+//  Returns:
+//  the PEM-encoded CRL
+func (c *Client) GenCRL(request *GenCRLRequest) (*GenCRLResponse, error) {
+	if request == nil {
+		return nil, errors.New("must provide CRL request")
+	}
+
+	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := mspapi.GenCRLRequest(*request)
+	resp, err := ca.GenCRL(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenCRLResponse{CRL: resp.CRL}, nil
+}
+
 // GetCAInfo returns generic CA information
 func (c *Client) GetCAInfo() (*GetCAInfoResponse, error) {
 	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
@@ -127,6 +127,35 @@ func TestEnrollWithProfile(t *testing.T) {
 	}
 }
 
+func TestEnrollWithTLSProfile(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer sdk.Close()
+
+	ctxProvider := sdk.Context()
+	msp, err := New(ctxProvider)
+	require.NoError(t, err)
+
+	enrollUsername := randomUsername()
+	err = msp.Enroll(enrollUsername, WithSecret("enrollmentSecret"), WithTLSProfile())
+	require.NoError(t, err)
+
+	// The TLS certificate isn't stored with the enrollment certificates
+	_, err = msp.GetSigningIdentity(enrollUsername)
+	assert.Equal(t, ErrUserNotFound, err)
+
+	ctx, err := ctxProvider()
+	require.NoError(t, err)
+	assert.NotEmpty(t, ctx.EndpointConfig().TLSClientCerts(), "enrolled TLS certificate should be used for mutual TLS")
+
+	require.NoError(t, msp.UseTLSIdentity(enrollUsername))
+	assert.Error(t, msp.UseTLSIdentity("unknown"))
+
+	mgr, err := msp.NewTLSRenewalManager()
+	require.NoError(t, err)
+	require.NoError(t, mgr.Renew(enrollUsername))
+}
+
 func TestEnrollWithType(t *testing.T) {
 	f := testFixture{}
 	sdk := f.setup()
@@ -343,6 +372,49 @@ func TestRevoke(t *testing.T) {
 
 }
 
+func TestGetCertificates(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	if err != nil {
+		t.Fatalf("failed to create CA client: %s", err)
+	}
+
+	_, err = msp.GetCertificates(nil)
+	if err == nil {
+		t.Fatal("GetCertificates should have failed with nil request")
+	}
+
+	resp, err := msp.GetCertificates(&GetCertificatesRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %s", err)
+	}
+	if len(resp.Certificates) != 2 || !resp.More {
+		t.Fatalf("expecting %d certificates with more, got %d (more: %t)", 2, len(resp.Certificates), resp.More)
+	}
+}
+
+func TestGenCRL(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	if err != nil {
+		t.Fatalf("failed to create CA client: %s", err)
+	}
+
+	resp, err := msp.GenCRL(&GenCRLRequest{})
+	if err != nil {
+		t.Fatalf("GenCRL return error %s", err)
+	}
+	if len(resp.CRL) == 0 {
+		t.Fatal("expecting CRL")
+	}
+}
+
 // TestCreateIdentityFailure tests failures in CreateIdentity
 func TestCreateIdentityFailure(t *testing.T) {
 
@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+)
+
 // client options collector
 type clientOptions struct {
 	orgName string
@@ -24,6 +28,8 @@ type enrollmentOptions struct {
 	label    string
 	typ      string
 	attrReqs []*AttributeRequest
+	tls      bool
+	keyReq   *mspapi.KeyRequest
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -83,6 +89,16 @@ func WithType(typ string) EnrollmentOption {
 	}
 }
 
+// WithTLSProfile enrollment option. The user is enrolled with the TLS profile of the CA (unless another
+// profile is specified with WithProfile) and the TLS certificate is stored in the TLS credential store.
+// The TLS certificate is subsequently used as the client certificate for mutual TLS.
+func WithTLSProfile() EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.tls = true
+		return nil
+	}
+}
+
 // WithLabel enrollment option
 func WithLabel(label string) EnrollmentOption {
 	return func(o *enrollmentOptions) error {
@@ -98,3 +114,13 @@ func WithAttributeRequests(attrReqs []*AttributeRequest) EnrollmentOption {
 		return nil
 	}
 }
+
+// WithKeyRequest enrollment option. Specifies the key pair that's generated for the identity:
+// algorithm "ecdsa" with size 256 (the default) or 384, or algorithm "ed25519" (the size is ignored).
+// On re-enrollment, a key pair of the same type as the existing key is generated by default.
+func WithKeyRequest(algorithm string, size int) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.keyReq = &mspapi.KeyRequest{Algorithm: algorithm, Size: size}
+		return nil
+	}
+}
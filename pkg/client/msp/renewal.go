@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+type contextCloseable interface {
+	CloseContext(ctxt fab.ClientContext)
+}
+
+// NewRenewalManager returns a manager that renews the enrollment certificates of enrolled users
+// before they expire. The users to be renewed are added with Watch and the periodic checks are
+// started with Start. After a certificate is renewed, the SDK caches (channel services, local
+// discovery) that were created for the previous identity are closed so that contexts created for
+// the user subsequently use the renewed identity.
+//  Parameters:
+//  opts are optional renewal options (threshold, check interval, key rotation and handlers)
+//
+//  Returns:
+//  the renewal manager
+func (c *Client) NewRenewalManager(opts ...msp.RenewalOption) (*msp.RenewalManager, error) {
+	ca, err := msp.NewCAClient(c.orgName, c.ctx, msp.WithCAInstance(c.caID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create CA Client")
+	}
+
+	mgr, err := msp.NewRenewalManager(ca, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create renewal manager")
+	}
+	mgr.OnRenew(c.closeRenewedContext)
+
+	return mgr, nil
+}
+
+// closeRenewedContext closes the SDK caches of the identity that was replaced by a renewal
+func (c *Client) closeRenewedContext(event *msp.RenewalEvent) {
+	if event.Err != nil {
+		return
+	}
+
+	identityManager, ok := c.ctx.IdentityManager(c.orgName)
+	if !ok {
+		logger.Warnf("Identity manager not found for organization [%s] - unable to close context of renewed user [%s]", c.orgName, event.ID)
+		return
+	}
+
+	previous, err := identityManager.CreateSigningIdentity(mspctx.WithCert(event.Previous.EnrollmentCertificate))
+	if err != nil {
+		logger.Warnf("Unable to close context of renewed user [%s]: %s", event.ID, err)
+		return
+	}
+
+	ctx := &contextImpl.Client{Providers: c.ctx, SigningIdentity: previous}
+	if pvdr, ok := c.ctx.LocalDiscoveryProvider().(contextCloseable); ok {
+		pvdr.CloseContext(ctx)
+	}
+	if pvdr, ok := c.ctx.ChannelProvider().(contextCloseable); ok {
+		pvdr.CloseContext(ctx)
+	}
+}
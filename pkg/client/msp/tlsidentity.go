@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/pkg/errors"
+)
+
+// UseTLSIdentity uses the TLS certificate of a user, which was enrolled with the WithTLSProfile
+// option, as the client certificate for mutual TLS connections to peers, orderers and CAs.
+// This is typically called on start-up to reuse a TLS certificate that was enrolled previously.
+//  Parameters:
+//  enrollmentID enrollment ID of the user
+//
+//  Returns:
+//  an error if the TLS certificate isn't found or can't be used
+func (c *Client) UseTLSIdentity(enrollmentID string) error {
+	ca, err := msp.NewCAClient(c.orgName, c.ctx, msp.WithCAInstance(c.caID))
+	if err != nil {
+		return errors.WithMessage(err, "failed to create CA Client")
+	}
+
+	return ca.UseTLSIdentity(enrollmentID)
+}
+
+// NewTLSRenewalManager returns a manager that renews the TLS certificates that were enrolled with the
+// WithTLSProfile option before they expire. If the renewed certificate is in use for mutual TLS then
+// it's replaced by the renewed certificate and the cached connections are closed.
+//  Parameters:
+//  opts are optional renewal options (threshold, check interval, key rotation and handlers)
+//
+//  Returns:
+//  the renewal manager
+func (c *Client) NewTLSRenewalManager(opts ...msp.RenewalOption) (*msp.RenewalManager, error) {
+	ca, err := msp.NewCAClient(c.orgName, c.ctx, msp.WithCAInstance(c.caID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create CA Client")
+	}
+
+	mgr, err := msp.NewTLSRenewalManager(ca, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create TLS renewal manager")
+	}
+
+	return mgr, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -149,3 +150,17 @@ func WithRetry(retryOpt retry.Opts) RequestOption {
 		return nil
 	}
 }
+
+// WithSigningIdentity signs the request with the given identity instead of the identity that the client
+// was created with. For SaveChannel, the identity also signs the channel configuration if no signing
+// identities or signatures are provided. Commit events (for instantiate and upgrade) are received over
+// the event service connection of the client's identity.
+func WithSigningIdentity(identity msp.SigningIdentity) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if identity == nil {
+			return errors.New("signing identity is nil")
+		}
+		o.Identity = identity
+		return nil
+	}
+}
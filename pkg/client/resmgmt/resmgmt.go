@@ -107,6 +107,8 @@ type requestOptions struct {
 	Retry         retry.Opts
 	// signatures for channel configurations, if set, this option will take precedence over signatures of SaveChannelRequest.SigningIdentities
 	Signatures []*common.ConfigSignature
+	// identity that signs the request instead of the identity of the client's context
+	Identity msp.SigningIdentity
 }
 
 //SaveChannelRequest holds parameters for save channel request
@@ -200,7 +202,8 @@ func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, err
 //
 //  Returns:
 //  an error if join fails
-func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error {
+func (rc *Client) JoinChannel(channelID string, options ...RequestOption) (err error) {
+	defer rc.observe("JoinChannel", channelID, time.Now(), &err)
 
 	if channelID == "" {
 		return errors.New("must provide channel ID")
@@ -215,7 +218,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -233,7 +236,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 		return errors.WithMessage(err, "failed to find orderer for request")
 	}
 
-	ordrReqCtx, ordrReqCtxCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(parentReqCtx))
+	ordrReqCtx, ordrReqCtxCancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(parentReqCtx))
 	defer ordrReqCtxCancel()
 
 	genesisBlock, err := resource.GenesisBlockFromOrderer(ordrReqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
@@ -245,7 +248,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 		GenesisBlock: genesisBlock,
 	}
 
-	peerReqCtx, peerReqCtxCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
+	peerReqCtx, peerReqCtxCancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
 	defer peerReqCtxCancel()
 	err = resource.JoinChannel(peerReqCtx, joinChannelRequest, peersToTxnProcessors(targets), resource.WithRetry(opts.Retry))
 	if err != nil {
@@ -364,11 +367,12 @@ func (rc *Client) isChaincodeInstalled(reqCtx reqContext.Context, req InstallCCR
 //
 //  Returns:
 //  install chaincode proposal responses from peer(s)
-func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]InstallCCResponse, error) {
+func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) (resp []InstallCCResponse, err error) {
+	defer rc.observe("InstallCC", "", time.Now(), &err)
 	// For each peer query if chaincode installed. If cc is installed treat as success with message 'already installed'.
 	// If cc is not installed try to install, and if that fails add to the list with error and peer name.
 
-	err := checkRequiredInstallCCParams(req)
+	err = checkRequiredInstallCCParams(req)
 	if err != nil {
 		return nil, err
 	}
@@ -382,7 +386,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -401,7 +405,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
 	}
 
-	responses, newTargets, errs := rc.adjustTargets(targets, req, opts.Retry, parentReqCtx)
+	responses, newTargets, errs := rc.adjustTargets(targets, req, opts, parentReqCtx)
 
 	if len(newTargets) == 0 {
 		// CC is already installed on all targets and/or
@@ -409,7 +413,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 		return responses, errs.ToError()
 	}
 
-	reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
+	reqCtx, cancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
 	defer cancel()
 
 	responses, err = rc.sendInstallCCRequest(req, reqCtx, newTargets, responses)
@@ -442,7 +446,7 @@ func (rc *Client) sendInstallCCRequest(req InstallCCRequest, reqCtx reqContext.C
 	return responses, nil
 }
 
-func (rc *Client) adjustTargets(targets []fab.Peer, req InstallCCRequest, retry retry.Opts, parentReqCtx reqContext.Context) ([]InstallCCResponse, []fab.Peer, multi.Errors) {
+func (rc *Client) adjustTargets(targets []fab.Peer, req InstallCCRequest, opts requestOptions, parentReqCtx reqContext.Context) ([]InstallCCResponse, []fab.Peer, multi.Errors) {
 	errs := multi.Errors{}
 
 	responses := make([]InstallCCResponse, 0)
@@ -450,10 +454,10 @@ func (rc *Client) adjustTargets(targets []fab.Peer, req InstallCCRequest, retry
 	// Targets will be adjusted if cc has already been installed
 	newTargets := make([]fab.Peer, 0)
 	for _, target := range targets {
-		reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(parentReqCtx))
+		reqCtx, cancel := contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(parentReqCtx))
 		defer cancel()
 
-		installed, err1 := rc.isChaincodeInstalled(reqCtx, req, target, retry)
+		installed, err1 := rc.isChaincodeInstalled(reqCtx, req, target, opts.Retry)
 		if err1 != nil {
 			// Add to errors with unable to verify error message
 			errs = append(errs, errors.Errorf("unable to verify if cc is installed on %s. Got error: %s", target.URL(), err1))
@@ -489,7 +493,8 @@ func checkRequiredInstallCCParams(req InstallCCRequest) error {
 //
 //  Returns:
 //  instantiate chaincode response with transaction ID
-func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, options ...RequestOption) (InstantiateCCResponse, error) {
+func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, options ...RequestOption) (resp InstantiateCCResponse, err error) {
+	defer rc.observe("InstantiateCC", channelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -512,7 +517,8 @@ func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, opti
 //
 //  Returns:
 //  upgrade chaincode response with transaction ID
-func (rc *Client) UpgradeCC(channelID string, req UpgradeCCRequest, options ...RequestOption) (UpgradeCCResponse, error) {
+func (rc *Client) UpgradeCC(channelID string, req UpgradeCCRequest, options ...RequestOption) (resp UpgradeCCResponse, err error) {
+	defer rc.observe("UpgradeCC", channelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -533,7 +539,8 @@ func (rc *Client) UpgradeCC(channelID string, req UpgradeCCRequest, options ...R
 //
 //  Returns:
 //  list of installed chaincodes on specified peer
-func (rc *Client) QueryInstalledChaincodes(options ...RequestOption) (*pb.ChaincodeQueryResponse, error) {
+func (rc *Client) QueryInstalledChaincodes(options ...RequestOption) (resp *pb.ChaincodeQueryResponse, err error) {
+	defer rc.observe("QueryInstalledChaincodes", "", time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -557,7 +564,8 @@ func (rc *Client) QueryInstalledChaincodes(options ...RequestOption) (*pb.Chainc
 //
 //  Returns:
 //  list of instantiated chaincodes
-func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...RequestOption) (*pb.ChaincodeQueryResponse, error) {
+func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...RequestOption) (resp *pb.ChaincodeQueryResponse, err error) {
+	defer rc.observe("QueryInstantiatedChaincodes", channelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -618,7 +626,8 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 //
 // Returns:
 // list of collections config
-func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string, options ...RequestOption) (*common.CollectionConfigPackage, error) {
+func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string, options ...RequestOption) (resp *common.CollectionConfigPackage, err error) {
+	defer rc.observe("QueryCollectionsConfig", channelID, time.Now(), &err)
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
 		return nil, err
@@ -701,7 +710,8 @@ func (rc *Client) selectRandomChannelPeer(ctx context.Channel) (fab.ProposalProc
 //
 //  Returns:
 //  all channels that peer has joined
-func (rc *Client) QueryChannels(options ...RequestOption) (*pb.ChannelQueryResponse, error) {
+func (rc *Client) QueryChannels(options ...RequestOption) (resp *pb.ChannelQueryResponse, err error) {
+	defer rc.observe("QueryChannels", "", time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -758,10 +768,10 @@ func (rc *Client) getCCProposalTargets(channelID string, opts requestOptions) ([
 }
 
 // createTP
-func (rc *Client) createTP(req InstantiateCCRequest, channelID string, ccProposalType chaincodeProposalType) (*fab.TransactionProposal, fab.TransactionID, error) {
+func (rc *Client) createTP(ctx context.Client, req InstantiateCCRequest, channelID string, ccProposalType chaincodeProposalType) (*fab.TransactionProposal, fab.TransactionID, error) {
 	deployProposal := chaincodeDeployRequest(req)
 
-	txID, err := txn.NewHeader(rc.ctx, channelID)
+	txID, err := txn.NewHeader(ctx, channelID)
 	if err != nil {
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "create transaction ID failed")
 	}
@@ -811,7 +821,7 @@ func (rc *Client) sendCCProposal(reqCtx reqContext.Context, ccProposalType chain
 	}
 
 	// create a transaction proposal for chaincode deployment
-	tp, txnID, err := rc.createTP(req, channelID, ccProposalType)
+	tp, txnID, err := rc.createTP(rc.requestClient(opts), req, channelID, ccProposalType)
 	if err != nil {
 		return txnID, err
 	}
@@ -914,7 +924,8 @@ func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
 //
 //  Returns:
 //  save channel response with transaction ID
-func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption) (SaveChannelResponse, error) {
+func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption) (resp SaveChannelResponse, err error) {
+	defer rc.observe("SaveChannel", req.ChannelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -967,7 +978,7 @@ func (rc *Client) signAndSubmitChannelConfigTx(channelID string, signingIdentiti
 	if opts.Signatures != nil {
 		configSignatures = opts.Signatures
 	} else {
-		configSignatures, err = rc.getConfigSignatures(signingIdentities, chConfigTx)
+		configSignatures, err = rc.getConfigSignatures(signingIdentities, opts.Identity, chConfigTx)
 		if err != nil {
 			return "", err
 		}
@@ -1017,9 +1028,10 @@ func (rc *Client) validateSaveChannelRequest(req SaveChannelRequest) error {
 	return nil
 }
 
-func (rc *Client) getConfigSignatures(signingIdentities []msp.SigningIdentity, chConfig []byte) ([]*common.ConfigSignature, error) {
+func (rc *Client) getConfigSignatures(signingIdentities []msp.SigningIdentity, requestIdentity msp.SigningIdentity, chConfig []byte) ([]*common.ConfigSignature, error) {
 	// Signing user has to belong to one of configured channel organisations
 	// In case that order org is one of channel orgs we can use context user
+	// (or the identity of the request, if provided)
 	var signers []msp.SigningIdentity
 
 	if len(signingIdentities) > 0 {
@@ -1028,6 +1040,8 @@ func (rc *Client) getConfigSignatures(signingIdentities []msp.SigningIdentity, c
 				signers = append(signers, id)
 			}
 		}
+	} else if requestIdentity != nil {
+		signers = append(signers, requestIdentity)
 	} else if rc.ctx != nil {
 		signers = append(signers, rc.ctx)
 	} else {
@@ -1237,7 +1251,8 @@ func (rc *Client) getChannelConfig(channelID string, opts requestOptions) (*chco
 //
 //  Returns:
 //  channel configuration block
-func (rc *Client) QueryConfigBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+func (rc *Client) QueryConfigBlockFromOrderer(channelID string, options ...RequestOption) (block *common.Block, err error) {
+	defer rc.observe("QueryConfigBlockFromOrderer", channelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -1263,7 +1278,8 @@ func (rc *Client) QueryConfigBlockFromOrderer(channelID string, options ...Reque
 //
 //  Returns:
 //  channel configuration
-func (rc *Client) QueryConfigFromOrderer(channelID string, options ...RequestOption) (fab.ChannelCfg, error) {
+func (rc *Client) QueryConfigFromOrderer(channelID string, options ...RequestOption) (cfg fab.ChannelCfg, err error) {
+	defer rc.observe("QueryConfigFromOrderer", channelID, time.Now(), &err)
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -1343,7 +1359,16 @@ func (rc *Client) createRequestContext(opts requestOptions, defaultTimeoutType f
 		opts.Timeouts[defaultTimeoutType] = rc.ctx.EndpointConfig().Timeout(defaultTimeoutType)
 	}
 
-	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+	return contextImpl.NewRequest(rc.requestClient(opts), contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+}
+
+// requestClient returns the client context that signs the request: the client's context with
+// the identity that was provided with the WithSigningIdentity option (if any)
+func (rc *Client) requestClient(opts requestOptions) context.Client {
+	if opts.Identity == nil {
+		return rc.ctx
+	}
+	return &contextImpl.Client{Providers: rc.ctx, SigningIdentity: opts.Identity}
 }
 
 //resolveTimeouts sets default for timeouts from config if not provided through opts
@@ -1365,3 +1390,9 @@ func (rc *Client) resolveTimeouts(opts *requestOptions) {
 		opts.Timeouts[fab.PeerResponse] = rc.ctx.EndpointConfig().Timeout(fab.PeerResponse)
 	}
 }
+
+// observe records the metrics of a resource management operation. It is deferred by the
+// operation with a pointer to its error result.
+func (rc *Client) observe(operation, channelID string, startTime time.Time, err *error) {
+	rc.ctx.GetMetrics().ResMgmt().Observe(channelID, operation, time.Since(startTime).Seconds(), *err)
+}
@@ -47,6 +47,7 @@ import (
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -1612,6 +1613,43 @@ func TestGetConfigSignaturesFromIdentities(t *testing.T) {
 	assert.NotNil(t, signature, "signatures must not be empty")
 }
 
+func TestRequestWithSigningIdentity(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	rc := setupResMgmtClient(t, ctx)
+
+	_, err := rc.prepareRequestOpts(WithSigningIdentity(nil))
+	assert.Error(t, err, "expected error for nil signing identity")
+
+	identity := mspmocks.NewMockSigningIdentity("user2", "Org2MSP")
+	opts, err := rc.prepareRequestOpts(WithSigningIdentity(identity))
+	require.NoError(t, err)
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
+	defer cancel()
+	client, ok := contextImpl.RequestClientContext(reqCtx)
+	require.True(t, ok)
+	assert.Equal(t, "user2", client.Identifier().ID)
+
+	reqCtx, cancel = rc.createRequestContext(requestOptions{}, fab.ResMgmt)
+	defer cancel()
+	client, ok = contextImpl.RequestClientContext(reqCtx)
+	require.True(t, ok)
+	assert.Equal(t, "test", client.Identifier().ID)
+
+	chConfig := []byte("channel config update")
+
+	// The identity of the request signs the channel config if no signing identities are provided
+	signatures, err := rc.getConfigSignatures(nil, identity, chConfig)
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	assert.True(t, bytes.Contains(signatures[0].SignatureHeader, []byte("user2Org2MSP")))
+
+	signatures, err = rc.getConfigSignatures([]msp.SigningIdentity{ctx.SigningIdentity}, identity, chConfig)
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	assert.False(t, bytes.Contains(signatures[0].SignatureHeader, []byte("user2Org2MSP")))
+}
+
 func createClientContext(fabCtx context.Client) context.ClientProvider {
 	return func() (context.Client, error) {
 		return fabCtx, nil
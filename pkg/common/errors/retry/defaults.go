@@ -131,7 +131,7 @@ var ResMgmtDefaultRetryableCodes = map[status.Group][]status.Code{
 // transient by fabric-sdk-go/pkg/client/channel.Client
 var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 	status.EndorserClientStatus: {
-		status.ConnectionFailed, status.EndorsementMismatch,
+		status.ConnectionFailed, status.EndorsementMismatch, status.CircuitOpen,
 		status.PrematureChaincodeExecution,
 		status.Code(pb.TxValidationCode_MVCC_READ_CONFLICT),
 		status.ChaincodeAlreadyLaunching,
@@ -142,7 +142,7 @@ var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 		status.Code(common.Status_INTERNAL_SERVER_ERROR),
 	},
 	status.OrdererClientStatus: {
-		status.ConnectionFailed,
+		status.ConnectionFailed, status.CircuitOpen,
 	},
 	status.OrdererServerStatus: {
 		status.Code(common.Status_SERVICE_UNAVAILABLE),
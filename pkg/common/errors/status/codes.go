@@ -55,6 +55,10 @@ const (
 	// GenericTransient is generally used by tests to indicate that a retry is possible
 	GenericTransient Code = 12
 
+	// CircuitOpen is returned when a request isn't sent to an endpoint because the circuit breaker
+	// of the endpoint is open
+	CircuitOpen Code = 13
+
 	// PrematureChaincodeExecution indicates that an attempt was made to invoke a chaincode that's
 	// in the process of being launched.
 	PrematureChaincodeExecution Code = 21
@@ -80,6 +84,7 @@ var CodeName = map[int32]string{
 	9:  "MISSING_ENDORSEMENT",
 	11: "QUERY_ENDORSERS",
 	12: "GENERIC_TRANSIENT",
+	13: "CIRCUIT_OPEN",
 	21: "PREMATURE_CHAINCODE_EXECUTION",
 	22: "CHAINCODE_ALREADY_LAUNCHING",
 	23: "CHAINCODE_NAME_NOT_FOUND",
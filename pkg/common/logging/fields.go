@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+)
+
+// Keys of the fields that the SDK attaches to its structured log entries
+const (
+	TxIDKey      = "txID"
+	ChannelKey   = "channel"
+	ChaincodeKey = "chaincode"
+	PeerKey      = "peer"
+	OrdererKey   = "orderer"
+	OrgKey       = "org"
+	ErrorKey     = "error"
+)
+
+type fieldsKey struct{}
+
+// KV returns a key-value field
+func KV(key string, value interface{}) api.Field {
+	return api.Field{Key: key, Value: value}
+}
+
+// WithFields returns a copy of the context with the given fields attached. The fields are
+// added to every entry that is logged with the returned context (see Logger.Debugw).
+// A field replaces a field with the same key that is already attached to the context.
+func WithFields(ctx context.Context, fields ...api.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, merge(FieldsFromContext(ctx), fields))
+}
+
+// FieldsFromContext returns the fields that are attached to the context
+func FieldsFromContext(ctx context.Context) []api.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]api.Field)
+	return fields
+}
+
+// Debugw logs a message at debug level along with the fields attached to the context and the given fields
+func (l *Logger) Debugw(ctx context.Context, msg string, fields ...api.Field) {
+	l.logw(ctx, api.DEBUG, msg, fields)
+}
+
+// Infow logs a message at info level along with the fields attached to the context and the given fields
+func (l *Logger) Infow(ctx context.Context, msg string, fields ...api.Field) {
+	l.logw(ctx, api.INFO, msg, fields)
+}
+
+// Warnw logs a message at warning level along with the fields attached to the context and the given fields
+func (l *Logger) Warnw(ctx context.Context, msg string, fields ...api.Field) {
+	l.logw(ctx, api.WARNING, msg, fields)
+}
+
+// Errorw logs a message at error level along with the fields attached to the context and the given fields
+func (l *Logger) Errorw(ctx context.Context, msg string, fields ...api.Field) {
+	l.logw(ctx, api.ERROR, msg, fields)
+}
+
+func (l *Logger) logw(ctx context.Context, level api.Level, msg string, fields []api.Field) {
+	// don't merge and format the fields of entries that aren't logged
+	if !l.IsEnabledFor(Level(level)) {
+		return
+	}
+
+	allFields := merge(FieldsFromContext(ctx), fields)
+
+	logger := l.logger()
+	if sl, ok := logger.(api.StructuredLogger); ok {
+		sl.LogFields(level, msg, allFields...)
+		return
+	}
+
+	// The logger doesn't support fields so append them to the message
+	text := FormatFields(msg, allFields...)
+	switch level {
+	case api.DEBUG:
+		logger.Debug(text)
+	case api.INFO:
+		logger.Info(text)
+	case api.WARNING:
+		logger.Warn(text)
+	default:
+		logger.Error(text)
+	}
+}
+
+// FormatFields appends the fields to the message as key=value pairs
+func FormatFields(msg string, fields ...api.Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteString(" ")
+		b.WriteString(f.Key)
+		b.WriteString("=")
+		fmt.Fprint(&b, f.Value)
+	}
+	return b.String()
+}
+
+// merge returns a new slice which contains the given fields followed by the additional fields.
+// An additional field replaces a field with the same key.
+func merge(fields []api.Field, additional []api.Field) []api.Field {
+	if len(additional) == 0 {
+		return fields
+	}
+
+	merged := make([]api.Field, 0, len(fields)+len(additional))
+	merged = append(merged, fields...)
+
+	for _, f := range additional {
+		replaced := false
+		for i := range merged {
+			if merged[i].Key == f.Key {
+				merged[i] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FieldsFromContext(ctx))
+	assert.Equal(t, ctx, WithFields(ctx))
+
+	ctx1 := WithFields(ctx, KV(ChannelKey, "mychannel"), KV(ChaincodeKey, "mycc"))
+	ctx2 := WithFields(ctx1, KV(TxIDKey, "tx1"), KV(ChaincodeKey, "othercc"))
+
+	assert.Equal(t, []api.Field{KV(ChannelKey, "mychannel"), KV(ChaincodeKey, "mycc")}, FieldsFromContext(ctx1))
+	assert.Equal(t, []api.Field{KV(ChannelKey, "mychannel"), KV(ChaincodeKey, "othercc"), KV(TxIDKey, "tx1")}, FieldsFromContext(ctx2))
+}
+
+func TestFormatFields(t *testing.T) {
+	assert.Equal(t, "message", FormatFields("message"))
+	assert.Equal(t, "message channel=mychannel block=10", FormatFields("message", KV(ChannelKey, "mychannel"), KV("block", 10)))
+}
+
+type structuredLogger struct {
+	api.Logger
+	level  api.Level
+	msg    string
+	fields []api.Field
+}
+
+func (l *structuredLogger) LogFields(level api.Level, msg string, fields ...api.Field) {
+	l.level = level
+	l.msg = msg
+	l.fields = fields
+}
+
+type structuredProvider struct {
+	logger *structuredLogger
+}
+
+func (p *structuredProvider) GetLogger(module string) api.Logger {
+	return p.logger
+}
+
+func TestLogWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), KV(ChannelKey, "mychannel"))
+
+	t.Run("Structured", func(t *testing.T) {
+		var out bytes.Buffer
+		sl := &structuredLogger{Logger: testdata.GetSampleLoggingProvider(&out).GetLogger(moduleName)}
+		resetLoggerInstance()
+		Initialize(&structuredProvider{logger: sl})
+
+		logger := NewLogger(moduleName)
+		logger.Warnw(ctx, "endorsement failed", KV(PeerKey, "peer0"))
+
+		assert.Equal(t, api.WARNING, sl.level)
+		assert.Equal(t, "endorsement failed", sl.msg)
+		assert.Equal(t, []api.Field{KV(ChannelKey, "mychannel"), KV(PeerKey, "peer0")}, sl.fields)
+	})
+
+	t.Run("Unstructured", func(t *testing.T) {
+		var out bytes.Buffer
+		resetLoggerInstance()
+		Initialize(modlog.LoggerProvider())
+
+		logger := NewLogger(moduleName)
+		logger.logger().(*modlog.Log).ChangeOutput(&out)
+		logger.Infow(ctx, "endorsement succeeded", KV(PeerKey, "peer0"))
+		require.Contains(t, out.String(), "INFO endorsement succeeded channel=mychannel peer=peer0")
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		var out bytes.Buffer
+		sl := &structuredLogger{Logger: testdata.GetSampleLoggingProvider(&out).GetLogger(moduleName)}
+		resetLoggerInstance()
+		Initialize(&structuredProvider{logger: sl})
+
+		SetLevel(moduleName, INFO)
+		logger := NewLogger(moduleName)
+		value := &countingStringer{}
+		logger.Debugw(ctx, "block received", KV("block", value))
+
+		assert.Empty(t, sl.msg, "debug entries should not be logged at info level")
+		assert.Equal(t, 0, value.count, "fields of debug entries should not be formatted at info level")
+	})
+}
+
+type countingStringer struct {
+	count int
+}
+
+func (s *countingStringer) String() string {
+	s.count++
+	return "value"
+}
@@ -230,6 +230,11 @@ func (l *Logger) Errorln(args ...interface{}) {
 	l.logger().Errorln(args...)
 }
 
+// IsEnabledFor returns true if the given log level is enabled for the module of the logger
+func (l *Logger) IsEnabledFor(level Level) bool {
+	return IsEnabledFor(l.module, level)
+}
+
 func (l *Logger) logger() api.Logger {
 	l.once.Do(func() {
 		l.instance = loggerProvider().GetLogger(l.module)
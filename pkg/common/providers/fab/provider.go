@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"google.golang.org/grpc"
 )
@@ -155,6 +156,7 @@ type Providers interface {
 	InfraProvider() InfraProvider
 	EndpointConfig() EndpointConfig
 	MetricsProvider
+	TracerProvider
 }
 
 // CertPool is a thread safe wrapper around the x509 standard library
@@ -171,3 +173,9 @@ type CertPool interface {
 type MetricsProvider interface {
 	GetMetrics() *metrics.ClientMetrics
 }
+
+// TracerProvider represents a provider of the tracer that records the spans of SDK requests.
+type TracerProvider interface {
+	// GetTracer returns the tracer or nil if tracing isn't enabled
+	GetTracer() tracing.Tracer
+}
@@ -11,6 +11,7 @@ import (
 	core "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	msp "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	tracing "github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	metrics "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -93,6 +94,20 @@ func (mr *MockProvidersMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockProviders)(nil).GetMetrics))
 }
 
+// GetTracer mocks base method
+func (m *MockProviders) GetTracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// GetTracer indicates an expected call of GetTracer
+func (mr *MockProvidersMockRecorder) GetTracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracer", reflect.TypeOf((*MockProviders)(nil).GetTracer))
+}
+
 // IdentityConfig mocks base method
 func (m *MockProviders) IdentityConfig() msp.IdentityConfig {
 	m.ctrl.T.Helper()
@@ -271,6 +286,20 @@ func (mr *MockClientMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockClient)(nil).GetMetrics))
 }
 
+// GetTracer mocks base method
+func (m *MockClient) GetTracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// GetTracer indicates an expected call of GetTracer
+func (mr *MockClientMockRecorder) GetTracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracer", reflect.TypeOf((*MockClient)(nil).GetTracer))
+}
+
 // Identifier mocks base method
 func (m *MockClient) Identifier() *msp.IdentityIdentifier {
 	m.ctrl.T.Helper()
@@ -12,6 +12,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	tracing "github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	metrics "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -326,6 +327,20 @@ func (mr *MockProvidersMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockProviders)(nil).GetMetrics))
 }
 
+// GetTracer mocks base method
+func (m *MockProviders) GetTracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// GetTracer indicates an expected call of GetTracer
+func (mr *MockProvidersMockRecorder) GetTracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracer", reflect.TypeOf((*MockProviders)(nil).GetTracer))
+}
+
 // InfraProvider mocks base method
 func (m *MockProviders) InfraProvider() fab.InfraProvider {
 	m.ctrl.T.Helper()
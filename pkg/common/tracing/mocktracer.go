@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// MockTraceHeader is the metadata key into which MockTracer injects the span name
+const MockTraceHeader = "mock-trace-span"
+
+type mockSpanKey struct{}
+
+// MockTracer records the spans that it creates (for testing)
+type MockTracer struct {
+	mutex sync.RWMutex
+	spans []*MockSpan
+}
+
+// NewMockTracer returns a new mock tracer
+func NewMockTracer() *MockTracer {
+	return &MockTracer{}
+}
+
+// Start creates and records a new span
+func (t *MockTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	span := &MockSpan{Name: name, attrs: make(map[string]interface{})}
+	if parent, ok := ctx.Value(mockSpanKey{}).(*MockSpan); ok {
+		span.Parent = parent
+	}
+	span.SetAttributes(attrs...)
+
+	t.mutex.Lock()
+	t.spans = append(t.spans, span)
+	t.mutex.Unlock()
+
+	return context.WithValue(ctx, mockSpanKey{}, span), span
+}
+
+// Inject sets the name of the span in the context into the carrier
+func (t *MockTracer) Inject(ctx context.Context, carrier Carrier) {
+	if span, ok := ctx.Value(mockSpanKey{}).(*MockSpan); ok {
+		carrier.Set(MockTraceHeader, span.Name)
+	}
+}
+
+// Spans returns the recorded spans in the order in which they were started
+func (t *MockTracer) Spans() []*MockSpan {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	spans := make([]*MockSpan, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// SpansNamed returns the recorded spans with the given name
+func (t *MockTracer) SpansNamed(name string) []*MockSpan {
+	var spans []*MockSpan
+	for _, s := range t.Spans() {
+		if s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// MockSpan is a span recorded by MockTracer
+type MockSpan struct {
+	Name   string
+	Parent *MockSpan
+
+	mutex sync.RWMutex
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+// SetAttributes adds the given attributes to the span
+func (s *MockSpan) SetAttributes(attrs ...Attribute) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+// RecordError records the error
+func (s *MockSpan) RecordError(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.err = err
+}
+
+// End ends the span
+func (s *MockSpan) End() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.ended = true
+}
+
+// Attribute returns the value of the given attribute
+func (s *MockSpan) Attribute(key string) interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.attrs[key]
+}
+
+// Err returns the error recorded on the span
+func (s *MockSpan) Err() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.err
+}
+
+// Ended returns true if the span was ended
+func (s *MockSpan) Ended() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.ended
+}
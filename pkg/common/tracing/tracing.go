@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing enables setting a custom tracer implementation (for example, one backed by
+// OpenTelemetry) that records spans along the SDK request path. Tracing is disabled (no-op) by default.
+// The tracer of an SDK instance is carried by the request contexts of its clients, so SDK instances
+// with different tracers don't interfere with each other.
+//
+//  Basic Flow:
+//  1) Attach the tracer to the context
+//  2) Start a span (as a child of the span in the given context, if any)
+//  3) Inject the trace context into outgoing gRPC metadata
+//  4) End the span
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Attribute keys used to tag SDK spans
+const (
+	TxIDKey             = "fabric.tx_id"
+	TxValidationCodeKey = "fabric.tx_validation_code"
+	ChannelKey          = "fabric.channel"
+	ChaincodeKey        = "fabric.chaincode"
+	FunctionKey         = "fabric.function"
+	PeerURLKey          = "fabric.peer.url"
+	OrdererURLKey       = "fabric.orderer.url"
+)
+
+// Attribute is a key/value pair that is attached to a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr returns a new attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a single operation within a trace
+type Span interface {
+	// SetAttributes adds the given attributes to the span
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the given error on the span and marks the span as failed
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// Carrier holds propagated trace context fields. It is compatible with the
+// OpenTelemetry TextMapCarrier interface.
+type Carrier interface {
+	Get(key string) string
+	Set(key string, value string)
+	Keys() []string
+}
+
+// Tracer creates spans and propagates trace context
+type Tracer interface {
+	// Start creates a span that is a child of the span in the given context (if any) and
+	// returns a context containing the new span
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	// Inject writes the trace context of the span in the given context into the carrier
+	Inject(ctx context.Context, carrier Carrier)
+}
+
+type tracerKey struct{}
+
+// ContextWithTracer returns a context that carries the tracer. The spans that are started with
+// the returned context (or a context derived from it) are recorded by the tracer.
+// The context is returned unchanged if the tracer is nil.
+func ContextWithTracer(ctx context.Context, t Tracer) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// FromContext returns the tracer carried by the context or the no-op tracer if there is none
+func FromContext(ctx context.Context) Tracer {
+	if t, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return t
+	}
+	return noopTracer{}
+}
+
+// StartSpan starts a span using the tracer carried by the context
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return FromContext(ctx).Start(ctx, name, attrs...)
+}
+
+// EndSpan records the given error (if any) on the span and ends the span
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// InjectGRPC returns a context whose outgoing gRPC metadata contains the trace context of the span in ctx
+func InjectGRPC(ctx context.Context) context.Context {
+	tracer := FromContext(ctx)
+	if _, ok := tracer.(noopTracer); ok {
+		return ctx
+	}
+
+	carrier := &MetadataCarrier{MD: metadata.MD{}}
+	tracer.Inject(ctx, carrier)
+	if len(carrier.MD) == 0 {
+		return ctx
+	}
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = metadata.Join(md, carrier.MD)
+	} else {
+		md = carrier.MD
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// MetadataCarrier adapts gRPC metadata to the Carrier interface
+type MetadataCarrier struct {
+	MD metadata.MD
+}
+
+// Get returns the first value for the given key
+func (c *MetadataCarrier) Get(key string) string {
+	values := c.MD.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set sets the value for the given key
+func (c *MetadataCarrier) Set(key string, value string) {
+	c.MD.Set(key, value)
+}
+
+// Keys returns the keys in the metadata
+func (c *MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.MD))
+	for k := range c.MD {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopTracer) Inject(ctx context.Context, carrier Carrier) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNoopTracer(t *testing.T) {
+	ctx := context.Background()
+
+	spanCtx, span := StartSpan(ctx, "noop", Attr(TxIDKey, "tx1"))
+	assert.Equal(t, ctx, spanCtx, "no-op tracer should not modify the context")
+	EndSpan(span, errors.New("some error"))
+
+	assert.Equal(t, ctx, InjectGRPC(ctx), "no-op tracer should not inject metadata")
+}
+
+func TestTracer(t *testing.T) {
+	tracer := NewMockTracer()
+	ctx := ContextWithTracer(context.Background(), tracer)
+	assert.Equal(t, tracer, FromContext(ctx))
+
+	ctx, parent := StartSpan(ctx, "parent", Attr(ChaincodeKey, "cc1"))
+	ctx, child := StartSpan(ctx, "child")
+	child.SetAttributes(Attr(PeerURLKey, "peer1:7051"))
+	EndSpan(child, errors.New("some error"))
+	EndSpan(parent, nil)
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "parent", spans[0].Name)
+	assert.Equal(t, "cc1", spans[0].Attribute(ChaincodeKey))
+	assert.NoError(t, spans[0].Err())
+	assert.True(t, spans[0].Ended())
+	assert.Equal(t, spans[0], spans[1].Parent)
+	assert.Equal(t, "peer1:7051", spans[1].Attribute(PeerURLKey))
+	assert.EqualError(t, spans[1].Err(), "some error")
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "existing", "value")
+	md, ok := metadata.FromOutgoingContext(InjectGRPC(ctx))
+	require.True(t, ok)
+	assert.Equal(t, []string{"child"}, md.Get(MockTraceHeader))
+	assert.Equal(t, []string{"value"}, md.Get("existing"), "existing metadata should be preserved")
+
+	_, span := StartSpan(context.Background(), "noop")
+	assert.Equal(t, noopSpan{}, span, "a context without a tracer should use the no-op tracer")
+	assert.Equal(t, context.Background(), ContextWithTracer(context.Background(), nil))
+}
+
+func TestMetadataCarrier(t *testing.T) {
+	carrier := &MetadataCarrier{MD: metadata.MD{}}
+	assert.Equal(t, "", carrier.Get("traceparent"))
+
+	carrier.Set("traceparent", "00-1-2-01")
+	assert.Equal(t, "00-1-2-01", carrier.Get("traceparent"))
+	assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package context
+
+import (
+	reqContext "context"
+	"sync"
+	"time"
+)
+
+var reqContextBudget = reqContextKey("deadline-budget")
+
+// Budget is the end-to-end deadline of a request. Each phase of the request (e.g. endorsement, broadcast
+// and commit) may be allotted a fraction of the budget. The time spent in a phase, including retries, is
+// deducted from the allotment of the phase. Request contexts created by NewRequest from a context that
+// carries a budget time out no later than the end of the current phase.
+type Budget struct {
+	total     time.Duration
+	deadline  time.Time
+	fractions map[string]float64
+
+	lock          sync.Mutex
+	phase         string
+	lastPhase     string
+	phaseStart    time.Time
+	phaseDeadline time.Time
+	spent         map[string]time.Duration
+}
+
+// NewBudget returns a budget that ends at the given deadline. The fractions map each phase to the fraction
+// of the budget that's allotted to the phase. A phase without a fraction is only limited by the deadline.
+func NewBudget(deadline time.Time, fractions map[string]float64) *Budget {
+	return &Budget{
+		total:     time.Until(deadline),
+		deadline:  deadline,
+		fractions: fractions,
+		spent:     make(map[string]time.Duration),
+	}
+}
+
+// WithBudget returns a copy of the given context that carries the given budget
+func WithBudget(ctx reqContext.Context, budget *Budget) reqContext.Context {
+	return reqContext.WithValue(ctx, reqContextBudget, budget)
+}
+
+// RequestBudget extracts the deadline budget from the request-scoped context.
+func RequestBudget(ctx reqContext.Context) (*Budget, bool) {
+	budget, ok := ctx.Value(reqContextBudget).(*Budget)
+	return budget, ok
+}
+
+// Total returns the total budget
+func (b *Budget) Total() time.Duration {
+	return b.total
+}
+
+// Allotted returns the time allotted to the given phase
+func (b *Budget) Allotted(phase string) time.Duration {
+	fraction, ok := b.fractions[phase]
+	if !ok || fraction <= 0 {
+		return b.total
+	}
+	return time.Duration(fraction * float64(b.total))
+}
+
+// Phase returns the current phase or, if no phase is in progress, the most recent phase
+// (an empty string is returned if no phase was started)
+func (b *Budget) Phase() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.phase == "" {
+		return b.lastPhase
+	}
+	return b.phase
+}
+
+// StartPhase starts the given phase. The phase ends at the earlier of the request deadline and
+// the time at which the allotment of the phase is used up. The returned function ends the phase
+// and returns true if the phase ran out of time. If the given phase is already in progress (e.g.
+// a handler of the phase invokes another handler of the same phase) then the returned function
+// doesn't end the phase.
+func (b *Budget) StartPhase(phase string) func() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.phase == phase {
+		return b.Exhausted
+	}
+
+	now := time.Now()
+	b.endPhase(now)
+
+	b.phase = phase
+	b.phaseStart = now
+	b.phaseDeadline = now.Add(b.Allotted(phase) - b.spent[phase])
+	if b.phaseDeadline.After(b.deadline) {
+		b.phaseDeadline = b.deadline
+	}
+
+	return func() bool {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+
+		exhausted := !time.Now().Before(b.phaseDeadline)
+		if b.phase == phase {
+			b.endPhase(time.Now())
+		}
+		return exhausted
+	}
+}
+
+// Remaining returns the time that remains in the current phase or, if no phase was started,
+// the time that remains until the request deadline
+func (b *Budget) Remaining() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return time.Until(b.currentDeadline())
+}
+
+// Deadline returns the end of the current phase or, if no phase was started, the request deadline
+func (b *Budget) Deadline() time.Time {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.currentDeadline()
+}
+
+// Exhausted returns true if the current phase (or the request) ran out of time
+func (b *Budget) Exhausted() bool {
+	return b.Remaining() <= 0
+}
+
+func (b *Budget) currentDeadline() time.Time {
+	if b.phase == "" {
+		return b.deadline
+	}
+	return b.phaseDeadline
+}
+
+func (b *Budget) endPhase(now time.Time) {
+	if b.phase == "" {
+		return
+	}
+	b.spent[b.phase] += now.Sub(b.phaseStart)
+	b.lastPhase = b.phase
+	b.phase = ""
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package context
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	budget := NewBudget(time.Now().Add(time.Second), map[string]float64{"endorsement": 0.1})
+
+	assert.Equal(t, "", budget.Phase())
+	assert.True(t, budget.Remaining() > 900*time.Millisecond)
+
+	end := budget.StartPhase("endorsement")
+	assert.Equal(t, "endorsement", budget.Phase())
+	assert.True(t, budget.Remaining() <= 100*time.Millisecond, "phase should be limited to its allotment")
+
+	nestedEnd := budget.StartPhase("endorsement")
+	assert.False(t, nestedEnd())
+	assert.Equal(t, "endorsement", budget.Phase(), "nested phase should not end the phase")
+
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, end())
+
+	// the time spent in the first attempt is deducted from the allotment of the retry
+	end = budget.StartPhase("endorsement")
+	assert.True(t, budget.Remaining() <= 40*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, budget.Exhausted())
+	assert.True(t, end())
+	assert.Equal(t, "endorsement", budget.Phase(), "most recent phase should be returned")
+
+	// a phase without a fraction is only limited by the deadline
+	end = budget.StartPhase("commit")
+	assert.True(t, budget.Remaining() > 500*time.Millisecond)
+	assert.False(t, end())
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -75,6 +76,7 @@ type Provider struct {
 	infraProvider          fab.InfraProvider
 	channelProvider        fab.ChannelProvider
 	clientMetrics          *metrics.ClientMetrics
+	tracer                 tracing.Tracer
 }
 
 // CryptoSuite returns the BCCSP provider of sdk.
@@ -127,6 +129,11 @@ func (c *Provider) GetMetrics() *metrics.ClientMetrics {
 	return c.clientMetrics
 }
 
+// GetTracer returns the tracer of the SDK or nil if tracing isn't enabled
+func (c *Provider) GetTracer() tracing.Tracer {
+	return c.tracer
+}
+
 //SDKContextParams parameter for creating FabContext
 type SDKContextParams func(opts *Provider)
 
@@ -207,6 +214,13 @@ func WithClientMetrics(cm *metrics.ClientMetrics) SDKContextParams {
 	}
 }
 
+//WithTracer sets the tracer to Context Provider
+func WithTracer(tracer tracing.Tracer) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.tracer = tracer
+	}
+}
+
 //NewProvider creates new context client provider
 // Not be used by end developers, fabsdk package use only
 func NewProvider(params ...SDKContextParams) *Provider {
@@ -344,8 +358,16 @@ func NewRequest(client context.Client, options ...ReqContextOptions) (reqContext
 		timeout = client.EndpointConfig().Timeout(reqCtxOpts.timeoutType)
 	}
 
+	//the timeout may not exceed the current phase of the deadline budget
+	if budget, ok := RequestBudget(parentContext); ok {
+		if remaining := budget.Remaining(); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
 	ctx := reqContext.WithValue(parentContext, reqContextCommManager, client.InfraProvider().CommManager())
 	ctx = reqContext.WithValue(ctx, reqContextClient, client)
+	ctx = tracing.ContextWithTracer(ctx, client.GetTracer())
 	ctx, cancel := reqContext.WithTimeout(ctx, timeout)
 
 	return ctx, cancel
@@ -14,9 +14,13 @@ import (
 
 	"regexp"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/secret"
 	"github.com/pkg/errors"
 )
 
+var logger = logging.NewLogger("fabsdk/core")
+
 // IsTLSEnabled is a generic function that expects a URL and verifies if it has
 // a prefix HTTPS or GRPCS to return true for TLS Enabled URLs or false otherwise
 func IsTLSEnabled(url string) bool {
@@ -82,18 +86,45 @@ type TLSConfig struct {
 	Pem string
 	//bytes from Pem/Path
 	bytes []byte
+	//secret reference of Pem/Path, if any
+	ref string
 }
 
 // Bytes returns the tls certificate as a byte array
+// If the config is a secret reference, the secret is resolved on each call so that a rotated secret
+// is picked up. The bytes that were loaded last are returned if the secret can't be resolved.
 func (cfg *TLSConfig) Bytes() []byte {
+	if cfg.ref != "" {
+		resolved, err := secret.Resolve(cfg.ref)
+		if err == nil {
+			return resolved
+		}
+		logger.Warnf("Failed to resolve TLS config secret, using the secret that was loaded last: %s", err)
+	}
 	return cfg.bytes
 }
 
+// IsSecret returns true if the loaded Pem/Path is a secret reference (see package secret)
+func (cfg *TLSConfig) IsSecret() bool {
+	return cfg.ref != ""
+}
+
 //LoadBytes preloads bytes from Pem/Path
 //Pem takes precedence over Path
+//Either may be a secret reference (see package secret), in which case the bytes are the resolved secret
 func (cfg *TLSConfig) LoadBytes() error {
 	var err error
-	if cfg.Pem != "" {
+	if secret.IsReference(cfg.Pem) || (cfg.Pem == "" && secret.IsReference(cfg.Path)) {
+		ref := cfg.Pem
+		if ref == "" {
+			ref = cfg.Path
+		}
+		cfg.bytes, err = secret.Resolve(ref)
+		if err != nil {
+			return err
+		}
+		cfg.ref = ref
+	} else if cfg.Pem != "" {
 		cfg.bytes = []byte(cfg.Pem)
 	} else if cfg.Path != "" {
 		cfg.bytes, err = ioutil.ReadFile(cfg.Path)
@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// resolveEnv resolves env://NAME references
+func resolveEnv(ref Reference) ([]byte, error) {
+	value, ok := os.LookupEnv(ref.Path)
+	if !ok {
+		return nil, errors.Errorf("environment variable [%s] is not set", ref.Path)
+	}
+	return []byte(value), nil
+}
+
+// resolveFile resolves file:///path references. Trailing newlines are removed from the contents of the file.
+func resolveFile(ref Reference) ([]byte, error) {
+	value, err := ioutil.ReadFile(ref.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret file [%s]", ref.Path)
+	}
+	return bytes.TrimRight(value, "\r\n"), nil
+}
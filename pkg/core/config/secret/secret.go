@@ -0,0 +1,358 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package secret resolves references to secrets (private keys, PINs, enrollment secrets, etc.) that
+// appear in the SDK configuration, so that the secrets themselves don't have to be stored in the
+// configuration. The following forms of reference are supported:
+//
+//  env://NAME                 the value of environment variable NAME
+//  file:///path/to/file       the contents of the given file
+//  secret://provider/path     the secret at the given path of a registered provider (e.g. vault)
+//
+// Any reference may end with #field, in which case the secret is expected to be a JSON object and
+// the value of the given field is used. The field starts at the first '#' after the scheme; a file path
+// that contains '#' has to be percent-encoded (%23). Values that aren't references are used as-is.
+//
+// Secrets are resolved lazily (when the config value is used) and are cached. The secrets cached by
+// the default manager are resolved again after five minutes (see SetCacheTTL). Resolvers for additional
+// providers are plugged in using Register.
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	envScheme    = "env"
+	fileScheme   = "file"
+	secretScheme = "secret"
+
+	schemeSeparator = "://"
+)
+
+// Reference is a parsed secret reference
+type Reference struct {
+	// Provider is the name of the resolver of the secret: env, file or, for secret://provider/path, the provider
+	Provider string
+	// Path identifies the secret within the provider: the variable name for env, the file path for file
+	Path string
+	// Field is the (optional) field of the secret to use
+	Field string
+
+	raw string
+}
+
+// String returns the reference in its original form
+func (r Reference) String() string {
+	return r.raw
+}
+
+// Resolver resolves secrets of a provider
+type Resolver interface {
+	Resolve(ref Reference) ([]byte, error)
+}
+
+// ResolverFunc is a function that implements Resolver
+type ResolverFunc func(ref Reference) ([]byte, error)
+
+// Resolve resolves the given reference
+func (f ResolverFunc) Resolve(ref Reference) ([]byte, error) {
+	return f(ref)
+}
+
+// RotationHandler is invoked with the new value of a secret when the value changes
+type RotationHandler func(ref string, value []byte)
+
+// IsReference returns true if the given config value is a secret reference
+func IsReference(value string) bool {
+	for _, scheme := range []string{envScheme, fileScheme, secretScheme} {
+		if strings.HasPrefix(value, scheme+schemeSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseReference parses the given secret reference
+func ParseReference(value string) (Reference, error) {
+	i := strings.Index(value, schemeSeparator)
+	if i < 0 || !IsReference(value) {
+		return Reference{}, errors.Errorf("invalid secret reference [%s]", value)
+	}
+
+	ref := Reference{raw: value}
+	scheme, rest := value[:i], value[i+len(schemeSeparator):]
+	rest, ref.Field = splitField(rest)
+
+	switch scheme {
+	case secretScheme:
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return Reference{}, errors.Errorf("invalid secret reference [%s]: expecting secret://provider/path", value)
+		}
+		ref.Provider, ref.Path = parts[0], parts[1]
+	case fileScheme:
+		path, err := url.PathUnescape(rest)
+		if err != nil {
+			return Reference{}, errors.Wrapf(err, "invalid secret reference [%s]", value)
+		}
+		ref.Provider, ref.Path = scheme, path
+	default:
+		ref.Provider, ref.Path = scheme, rest
+	}
+
+	if ref.Path == "" {
+		return Reference{}, errors.Errorf("invalid secret reference [%s]: path is required", value)
+	}
+
+	return ref, nil
+}
+
+// splitField splits the (optional) #field off the part of a reference that follows the scheme
+func splitField(rest string) (string, string) {
+	if i := strings.Index(rest, "#"); i >= 0 {
+		return rest[:i], rest[i+1:]
+	}
+	return rest, ""
+}
+
+type cacheEntry struct {
+	ref     Reference
+	value   []byte
+	expires time.Time
+}
+
+// Manager resolves secret references using the registered resolvers and caches the resolved values
+type Manager struct {
+	ttl       time.Duration
+	lock      sync.RWMutex
+	resolvers map[string]Resolver
+	cache     map[string]*cacheEntry
+	handlers  []RotationHandler
+}
+
+// Option configures a Manager
+type Option func(m *Manager)
+
+// WithCacheTTL sets how long a resolved secret is cached before it's resolved again. If the value
+// has changed, the rotation handlers are invoked. By default, secrets are cached until Refresh is called.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// SetCacheTTL sets how long a resolved secret is cached before it's resolved again (see WithCacheTTL).
+// A TTL of zero caches the secrets until Refresh is called.
+func (m *Manager) SetCacheTTL(ttl time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ttl = ttl
+}
+
+// WithResolver registers the resolver of the given provider
+func WithResolver(provider string, resolver Resolver) Option {
+	return func(m *Manager) {
+		m.resolvers[provider] = resolver
+	}
+}
+
+// NewManager returns a new Manager with the env and file resolvers registered
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
+		resolvers: map[string]Resolver{
+			envScheme:  ResolverFunc(resolveEnv),
+			fileScheme: ResolverFunc(resolveFile),
+		},
+		cache: make(map[string]*cacheEntry),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Register registers (or replaces) the resolver of the given provider. The resolver is used for references
+// of the form secret://provider/path. Cached secrets of the provider are evicted.
+func (m *Manager) Register(provider string, resolver Resolver) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.resolvers[provider] = resolver
+	for key, entry := range m.cache {
+		if entry.ref.Provider == provider {
+			delete(m.cache, key)
+		}
+	}
+}
+
+// OnRotate registers a handler that's invoked when the value of a previously resolved secret changes
+func (m *Manager) OnRotate(handler RotationHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// Resolve returns the secret for the given config value if it's a reference; otherwise the value is returned as-is
+func (m *Manager) Resolve(value string) ([]byte, error) {
+	if !IsReference(value) {
+		return []byte(value), nil
+	}
+
+	m.lock.RLock()
+	entry, ok := m.cache[value]
+	m.lock.RUnlock()
+
+	if ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		return entry.value, nil
+	}
+
+	ref, err := ParseReference(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.resolve(ref)
+}
+
+// ResolveString returns the secret for the given config value as a string (see Resolve)
+func (m *Manager) ResolveString(value string) (string, error) {
+	secret, err := m.Resolve(value)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// Refresh resolves all of the cached secrets again and invokes the rotation handlers for the secrets that changed
+func (m *Manager) Refresh() error {
+	m.lock.RLock()
+	refs := make([]Reference, 0, len(m.cache))
+	for _, entry := range m.cache {
+		refs = append(refs, entry.ref)
+	}
+	m.lock.RUnlock()
+
+	var errs []string
+	for _, ref := range refs {
+		if _, err := m.resolve(ref); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("failed to refresh secrets: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (m *Manager) resolve(ref Reference) ([]byte, error) {
+	m.lock.RLock()
+	resolver, ok := m.resolvers[ref.Provider]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("no secret resolver registered for provider [%s]", ref.Provider)
+	}
+
+	value, err := resolver.Resolve(ref)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to resolve secret ["+ref.String()+"]")
+	}
+
+	if ref.Field != "" {
+		value, err = extractField(value, ref.Field)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to resolve secret ["+ref.String()+"]")
+		}
+	}
+
+	entry := &cacheEntry{ref: ref, value: value}
+
+	m.lock.Lock()
+	if m.ttl > 0 {
+		entry.expires = time.Now().Add(m.ttl)
+	}
+	previous, cached := m.cache[ref.String()]
+	m.cache[ref.String()] = entry
+	handlers := append([]RotationHandler{}, m.handlers...)
+	m.lock.Unlock()
+
+	if cached && !bytes.Equal(previous.value, value) {
+		logger.Debugf("Secret [%s] was rotated", ref)
+		for _, handler := range handlers {
+			handler(ref.String(), value)
+		}
+	}
+
+	return value, nil
+}
+
+// extractField returns the value of the given field of the given JSON object
+func extractField(value []byte, field string) ([]byte, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return nil, errors.Wrapf(err, "secret is not a JSON object, unable to extract field [%s]", field)
+	}
+
+	v, ok := fields[field]
+	if !ok {
+		return nil, errors.Errorf("field [%s] not found in secret", field)
+	}
+
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
+
+// defaultCacheTTL is how long the default manager caches a secret, so that rotated
+// secrets are picked up without an explicit Refresh
+const defaultCacheTTL = 5 * time.Minute
+
+var defaultManager = NewManager(WithCacheTTL(defaultCacheTTL))
+
+// SetCacheTTL sets how long the default manager caches a resolved secret
+func SetCacheTTL(ttl time.Duration) {
+	defaultManager.SetCacheTTL(ttl)
+}
+
+// Register registers the resolver of the given provider with the default manager
+func Register(provider string, resolver Resolver) {
+	defaultManager.Register(provider, resolver)
+}
+
+// OnRotate registers a rotation handler with the default manager
+func OnRotate(handler RotationHandler) {
+	defaultManager.OnRotate(handler)
+}
+
+// Resolve resolves the given config value using the default manager
+func Resolve(value string) ([]byte, error) {
+	return defaultManager.Resolve(value)
+}
+
+// ResolveString resolves the given config value using the default manager
+func ResolveString(value string) (string, error) {
+	return defaultManager.ResolveString(value)
+}
+
+// Refresh refreshes the secrets cached by the default manager
+func Refresh() error {
+	return defaultManager.Refresh()
+}
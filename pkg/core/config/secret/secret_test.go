@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	ref, err := ParseReference("secret://vault/fabric/org1#pin")
+	require.NoError(t, err)
+	assert.Equal(t, "vault", ref.Provider)
+	assert.Equal(t, "fabric/org1", ref.Path)
+	assert.Equal(t, "pin", ref.Field)
+	assert.Equal(t, "secret://vault/fabric/org1#pin", ref.String())
+
+	ref, err = ParseReference("file:///run/secrets/key.pem")
+	require.NoError(t, err)
+	assert.Equal(t, "file", ref.Provider)
+	assert.Equal(t, "/run/secrets/key.pem", ref.Path)
+	assert.Empty(t, ref.Field)
+
+	ref, err = ParseReference("env://HSM_PIN")
+	require.NoError(t, err)
+	assert.Equal(t, "env", ref.Provider)
+	assert.Equal(t, "HSM_PIN", ref.Path)
+
+	ref, err = ParseReference("secret://vault/fabric/org1#credentials#pin")
+	require.NoError(t, err)
+	assert.Equal(t, "fabric/org1", ref.Path)
+	assert.Equal(t, "credentials#pin", ref.Field, "the field should start at the first '#'")
+
+	ref, err = ParseReference("file:///run/secrets/org%231/ca.json#enrollSecret")
+	require.NoError(t, err)
+	assert.Equal(t, "/run/secrets/org#1/ca.json", ref.Path, "file paths should be percent-decoded")
+	assert.Equal(t, "enrollSecret", ref.Field)
+
+	for _, invalid := range []string{"1234", "secret://vault", "secret:///path", "env://", "env://#field", "file:///run/%zz", "https://example.com"} {
+		_, err = ParseReference(invalid)
+		assert.Error(t, err, "expected error for [%s]", invalid)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m := NewManager()
+
+	value, err := m.ResolveString("1234")
+	require.NoError(t, err)
+	assert.Equal(t, "1234", value, "values that aren't references should be returned as-is")
+
+	require.NoError(t, os.Setenv("SECRET_TEST_PIN", "5678"))
+	defer os.Unsetenv("SECRET_TEST_PIN")
+
+	value, err = m.ResolveString("env://SECRET_TEST_PIN")
+	require.NoError(t, err)
+	assert.Equal(t, "5678", value)
+
+	_, err = m.Resolve("env://SECRET_TEST_UNDEFINED")
+	assert.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "secret")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ca.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"enrollSecret":"adminpw","attempts":3}`+"\n"), 0600))
+
+	value, err = m.ResolveString("file://" + path + "#enrollSecret")
+	require.NoError(t, err)
+	assert.Equal(t, "adminpw", value)
+
+	value, err = m.ResolveString("file://" + path + "#attempts")
+	require.NoError(t, err)
+	assert.Equal(t, "3", value)
+
+	_, err = m.Resolve("file://" + path + "#missing")
+	assert.Error(t, err)
+
+	_, err = m.Resolve("secret://vault/fabric#pin")
+	assert.Error(t, err, "provider that isn't registered should fail")
+}
+
+func TestCustomResolverAndRotation(t *testing.T) {
+	current := "v1"
+	calls := 0
+	m := NewManager(WithResolver("vault", ResolverFunc(func(ref Reference) ([]byte, error) {
+		calls++
+		if ref.Path != "fabric/org1" {
+			return nil, errors.Errorf("secret [%s] not found", ref.Path)
+		}
+		return []byte(`{"pin":"` + current + `"}`), nil
+	})))
+
+	var rotated []string
+	m.OnRotate(func(ref string, value []byte) {
+		rotated = append(rotated, ref+"="+string(value))
+	})
+
+	value, err := m.ResolveString("secret://vault/fabric/org1#pin")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	_, err = m.ResolveString("secret://vault/fabric/org1#pin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "secret should be cached")
+
+	require.NoError(t, m.Refresh())
+	assert.Empty(t, rotated, "rotation handler should not be invoked if the secret didn't change")
+
+	current = "v2"
+	require.NoError(t, m.Refresh())
+	assert.Equal(t, []string{"secret://vault/fabric/org1#pin=v2"}, rotated)
+
+	value, err = m.ResolveString("secret://vault/fabric/org1#pin")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", value)
+}
+
+func TestCacheTTL(t *testing.T) {
+	current := "v1"
+	m := NewManager(WithCacheTTL(10*time.Millisecond), WithResolver("test", ResolverFunc(func(ref Reference) ([]byte, error) {
+		return []byte(current), nil
+	})))
+
+	rotated := make(chan string, 1)
+	m.OnRotate(func(ref string, value []byte) {
+		rotated <- string(value)
+	})
+
+	value, err := m.ResolveString("secret://test/key")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	current = "v2"
+	time.Sleep(20 * time.Millisecond)
+
+	value, err = m.ResolveString("secret://test/key")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", value, "secret should be resolved again once the TTL expires")
+	assert.Equal(t, "v2", <-rotated)
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	assert.Equal(t, defaultCacheTTL, defaultManager.ttl, "the default manager should resolve cached secrets again")
+
+	SetCacheTTL(time.Minute)
+	defer SetCacheTTL(defaultCacheTTL)
+	assert.Equal(t, time.Minute, defaultManager.ttl)
+}
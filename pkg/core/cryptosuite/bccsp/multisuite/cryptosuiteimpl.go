@@ -9,6 +9,7 @@ package multisuite
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/pkcs11"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/remote"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/pkg/errors"
 )
@@ -20,6 +21,8 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return sw.GetSuiteByConfig(config)
 	case "pkcs11":
 		return pkcs11.GetSuiteByConfig(config)
+	case "remote":
+		return remote.GetSuiteByConfig(config)
 	}
 
 	return nil, errors.Errorf("Unsupported security provider requested: %s", config.SecurityProvider())
@@ -8,6 +8,7 @@ package multisuite
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -80,6 +81,20 @@ func TestCryptoSuiteByConfigPKCS11(t *testing.T) {
 	verifySuiteType(t, c, "*pkcs11.impl")
 }
 
+func TestCryptoSuiteByConfigRemote(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("remote").Times(2)
+
+	// The mock config doesn't configure a signer
+	_, err := GetSuiteByConfig(mockConfig)
+	if err == nil || !strings.Contains(err.Error(), "remote signer configuration") {
+		t.Fatalf("Expected remote signer configuration error, but got: %v", err)
+	}
+}
+
 func verifySuiteType(t *testing.T, c core.CryptoSuite, expectedType string) {
 	w, ok := c.(*wrapper.CryptoSuite)
 	if !ok {
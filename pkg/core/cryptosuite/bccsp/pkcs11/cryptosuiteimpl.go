@@ -7,17 +7,58 @@ SPDX-License-Identifier: Apache-2.0
 package pkcs11
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	bccspPkcs11 "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/factory/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
+	sdkp11 "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/common/pkcs11"
 	"github.com/pkg/errors"
 )
 
 var logger = logging.NewLogger("fabsdk/core")
 
+//tokenConfig is implemented by configs which configure the session pools, the health checks and the failover tokens
+type tokenConfig interface {
+	SecurityProviderSessionCacheSize() int
+	SecurityProviderHealthCheckInterval() time.Duration
+	SecurityProviderFailoverTokens() ([]cryptosuite.PKCS11TokenConfig, error)
+}
+
+//contextHandles is implemented by the PKCS11 BCCSP
+type contextHandles interface {
+	ContextHandles() []*sdkp11.ContextHandle
+}
+
+//metricsSetter is implemented by the PKCS11 BCCSP
+type metricsSetter interface {
+	SetPKCS11Metrics(m sdkp11.Metrics)
+}
+
+//CryptoSuite is the PKCS11 crypto suite
+type CryptoSuite struct {
+	*wrapper.CryptoSuite
+}
+
+//ContextHandles returns the PKCS11 context handles of the tokens which hold the keys
+func (c *CryptoSuite) ContextHandles() []*sdkp11.ContextHandle {
+	if handles, ok := c.BCCSP.(contextHandles); ok {
+		return handles.ContextHandles()
+	}
+	return nil
+}
+
+//SetPKCS11Metrics sets the metrics that record the session pools, the signing latency and the errors of the tokens
+func (c *CryptoSuite) SetPKCS11Metrics(m sdkp11.Metrics) {
+	if setter, ok := c.BCCSP.(metricsSetter); ok {
+		setter.SetPKCS11Metrics(m)
+	}
+}
+
 //GetSuiteByConfig returns cryptosuite adaptor for bccsp loaded according to given config
 func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	// TODO: delete this check?
@@ -25,13 +66,16 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
 	}
 
-	opts := getOptsByConfig(config)
-	bccsp, err := getBCCSPFromOpts(opts)
+	opts, err := getOptsByConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
+	bccsp, err := getBCCSPFromOpts(opts)
 	if err != nil {
 		return nil, err
 	}
-	return &wrapper.CryptoSuite{BCCSP: bccsp}, nil
+	return &CryptoSuite{CryptoSuite: &wrapper.CryptoSuite{BCCSP: bccsp}}, nil
 }
 
 func getBCCSPFromOpts(config *pkcs11.PKCS11Opts) (bccsp.BCCSP, error) {
@@ -45,7 +89,7 @@ func getBCCSPFromOpts(config *pkcs11.PKCS11Opts) (bccsp.BCCSP, error) {
 }
 
 //getOptsByConfig Returns Factory opts for given SDK config
-func getOptsByConfig(c core.CryptoSuiteConfig) *pkcs11.PKCS11Opts {
+func getOptsByConfig(c core.CryptoSuiteConfig) (*pkcs11.PKCS11Opts, error) {
 	pkks := pkcs11.FileKeystoreOpts{KeyStorePath: c.KeyStorePath()}
 	opts := &pkcs11.PKCS11Opts{
 		SecLevel:     c.SecurityLevel(),
@@ -56,7 +100,24 @@ func getOptsByConfig(c core.CryptoSuiteConfig) *pkcs11.PKCS11Opts {
 		Label:        c.SecurityProviderLabel(),
 		SoftVerify:   c.SoftVerify(),
 	}
+
+	if tc, ok := c.(tokenConfig); ok {
+		opts.SessionCacheSize = tc.SecurityProviderSessionCacheSize()
+		opts.HealthCheckInterval = tc.SecurityProviderHealthCheckInterval()
+		tokens, err := tc.SecurityProviderFailoverTokens()
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			opts.FailoverTokens = append(opts.FailoverTokens, pkcs11.PKCS11TokenOpts{
+				Library:          token.Library,
+				Label:            token.Label,
+				Pin:              token.Pin,
+				SessionCacheSize: token.SessionCacheSize,
+			})
+		}
+	}
 	logger.Debug("Initialized PKCS11 cryptosuite")
 
-	return opts
+	return opts, nil
 }
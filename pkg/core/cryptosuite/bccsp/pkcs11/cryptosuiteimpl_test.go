@@ -14,18 +14,22 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	pkcsFactory "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/factory/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
 var securityLevel = 256
 
 const (
 	providerTypePKCS11 = "PKCS11"
+	failoverTokenLabel = "ForFabric1"
 )
 
 func TestBadConfig(t *testing.T) {
@@ -140,6 +144,45 @@ func TestPKCS11CSPConfigWithEmptyProviderName(t *testing.T) {
 	}
 }
 
+func TestPKCS11FailoverTokens(t *testing.T) {
+	//generate a key on the failover token only
+	opts := configurePKCS11Options("SHA2", securityLevel)
+	opts.Label = failoverTokenLabel
+	csp, err := getBCCSPFromOpts(opts)
+	require.NoError(t, err)
+	key, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	//the key is used with the failover token, since the token of the provider doesn't hold it
+	opts = configurePKCS11Options("SHA2", securityLevel)
+	opts.SessionCacheSize = 5
+	opts.FailoverTokens = []pkcs11.PKCS11TokenOpts{{Label: failoverTokenLabel, SessionCacheSize: 2}}
+	csp, err = getBCCSPFromOpts(opts)
+	require.NoError(t, err)
+
+	suite := &CryptoSuite{CryptoSuite: &wrapper.CryptoSuite{BCCSP: csp}}
+	handles := suite.ContextHandles()
+	require.Len(t, handles, 2)
+	assert.Equal(t, opts.Label, handles[0].Stats().Label)
+	assert.Equal(t, failoverTokenLabel, handles[1].Stats().Label)
+
+	k, err := suite.GetKey(key.SKI())
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+
+	digest := sha256.Sum256([]byte("Hello"))
+	signature, err := suite.Sign(k, digest[:], nil)
+	require.NoError(t, err)
+	valid, err := suite.Verify(k, signature, digest[:], nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, uint64(0), handles[0].Stats().Signatures)
+	assert.Equal(t, uint64(1), handles[1].Stats().Signatures)
+
+	//should not panic
+	suite.SetPKCS11Metrics((&metrics.ClientMetrics{}).PKCS11())
+}
+
 func configurePKCS11Options(hashFamily string, securityLevel int) *pkcs11.PKCS11Opts {
 	providerLib, softHSMPin, softHSMTokenLabel := pkcs11.FindPKCS11Lib()
 
@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// batcher collects concurrent signing requests and sends them to the signer in batches.
+// A batch is sent when it's full or when the batch window after its first request has elapsed.
+type batcher struct {
+	sign      func([]*signRequest) ([]*signResult, error)
+	maxSize   int
+	window    time.Duration
+	requests  chan *pendingSignature
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type pendingSignature struct {
+	request *signRequest
+	result  chan signOutcome
+}
+
+type signOutcome struct {
+	signature []byte
+	err       error
+}
+
+func newBatcher(sign func([]*signRequest) ([]*signResult, error), maxSize int, window time.Duration) *batcher {
+	b := &batcher{
+		sign:     sign,
+		maxSize:  maxSize,
+		window:   window,
+		requests: make(chan *pendingSignature),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Sign queues the request and waits for its signature
+func (b *batcher) Sign(request *signRequest) ([]byte, error) {
+	p := &pendingSignature{request: request, result: make(chan signOutcome, 1)}
+	select {
+	case b.requests <- p:
+	case <-b.done:
+		return nil, errors.New("remote signer crypto suite is closed")
+	}
+	outcome := <-p.result
+	return outcome.signature, outcome.err
+}
+
+// Close stops batching. Batches that were already sent are completed.
+func (b *batcher) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+}
+
+func (b *batcher) run() {
+	for {
+		var batch []*pendingSignature
+		select {
+		case p := <-b.requests:
+			batch = append(batch, p)
+		case <-b.done:
+			return
+		}
+
+		timer := time.NewTimer(b.window)
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case p := <-b.requests:
+				batch = append(batch, p)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		go b.dispatch(batch)
+	}
+}
+
+func (b *batcher) dispatch(batch []*pendingSignature) {
+	requests := make([]*signRequest, len(batch))
+	for i, p := range batch {
+		requests[i] = p.request
+	}
+
+	logger.Debugf("Sending batch of %d signing requests to remote signer", len(requests))
+	results, err := b.sign(requests)
+
+	for i, p := range batch {
+		switch {
+		case err != nil:
+			p.result <- signOutcome{err: errors.WithMessage(err, "remote signing failed")}
+		case results[i].Error != "":
+			p.result <- signOutcome{err: errors.Errorf("remote signer failed to sign with key [%s]: %s", p.request.SKI, results[i].Error)}
+		default:
+			p.result <- signOutcome{signature: results[i].Signature}
+		}
+	}
+}
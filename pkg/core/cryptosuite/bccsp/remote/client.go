@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+)
+
+// maxResponseSize limits the size of the responses read from the signer
+const maxResponseSize = 16 << 20
+
+// client sends the requests of the signing protocol. Each attempt of a request is
+// bounded by the timeout and failed attempts are retried according to the retry options.
+type client struct {
+	url        string
+	httpClient *http.Client
+	timeout    time.Duration
+	retryOpts  retry.Opts
+}
+
+func (c *client) getKey(request *keyRequest) (*keyResponse, error) {
+	response := &keyResponse{}
+	if err := c.post(keysPath, request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *client) sign(requests []*signRequest) ([]*signResult, error) {
+	response := &signBatchResponse{}
+	if err := c.post(signPath, &signBatchRequest{Requests: requests}, response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(requests) {
+		return nil, errors.Errorf("remote signer returned %d results for %d requests", len(response.Results), len(requests))
+	}
+	for _, result := range response.Results {
+		if result == nil {
+			return nil, errors.New("remote signer returned an empty result")
+		}
+	}
+	return response.Results, nil
+}
+
+func (c *client) post(path string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	_, err = retry.NewInvoker(retry.New(c.retryOpts)).Invoke(
+		func() (interface{}, error) {
+			return nil, c.postOnce(path, body, response)
+		},
+	)
+	return err
+}
+
+func (c *client) postOnce(path string, body []byte, response interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(c.url, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return c.transportError(ctx, err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return c.transportError(ctx, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &errorResponse{}
+		if err := json.Unmarshal(respBody, errResp); err != nil || errResp.Error == "" {
+			errResp.Error = http.StatusText(resp.StatusCode)
+		}
+		return status.New(status.HTTPTransportStatus, int32(resp.StatusCode), fmt.Sprintf("remote signer returned an error: %s", errResp.Error), nil)
+	}
+
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response of remote signer")
+	}
+	return nil
+}
+
+// transportError returns the (retryable) status of a request that failed before a response was received
+func (c *client) transportError(ctx context.Context, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return status.New(status.ClientStatus, status.Timeout.ToInt32(), fmt.Sprintf("request to remote signer timed out after %s", c.timeout), nil)
+	}
+	return status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), fmt.Sprintf("request to remote signer failed: %s", err), nil)
+}
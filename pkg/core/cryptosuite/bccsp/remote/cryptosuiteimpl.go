@@ -0,0 +1,376 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	bccspSw "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	defaultTimeout      = 5 * time.Second
+	defaultMaxBatchSize = 100
+	defaultBatchWindow  = 5 * time.Millisecond
+	defaultLabelExpiry  = 5 * time.Minute
+)
+
+// DefaultRetryOpts are the default retry options of requests to the signer
+var DefaultRetryOpts = retry.Opts{
+	Attempts:       2,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	BackoffFactor:  2.0,
+	RetryableCodes: map[status.Group][]status.Code{
+		status.ClientStatus: {status.ConnectionFailed, status.Timeout},
+		status.HTTPTransportStatus: {
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	},
+}
+
+// Opts are the options of the remote signer crypto suite
+type Opts struct {
+	// URL of the signer, mandatory
+	URL string
+	// Optional. TLS configuration with the root CAs of the signer and the client certificate for mutual TLS.
+	TLSConfig *tls.Config
+	// Optional. Timeout of each attempt of a request. If not provided, 5s is used.
+	Timeout time.Duration
+	// Optional. Retries of failed requests. If not provided, DefaultRetryOpts is used.
+	Retry *retry.Opts
+	// Optional. Maximum number of signatures requested at once. If not provided, 100 is used.
+	MaxBatchSize int
+	// Optional. How long concurrent signing requests are collected into a batch. If not provided, 5ms is used.
+	BatchWindow time.Duration
+	// Optional. How long the key of a label is cached before the label is looked up again, so that a label
+	// may be moved to another key. If not provided, 5m is used. Keys looked up by SKI are cached until the
+	// suite is closed.
+	LabelExpiry time.Duration
+	// Optional. Security level and hash family of the local suite that hashes and verifies.
+	// If not provided, 256 and SHA2 are used.
+	SecurityLevel int
+	HashFamily    string
+}
+
+// remoteSignerConfig is implemented by crypto suite configs that configure the signer (such as cryptosuite.Config)
+type remoteSignerConfig interface {
+	RemoteSignerURL() string
+	RemoteSignerTimeout() time.Duration
+	RemoteSignerRetryAttempts() int
+	RemoteSignerTLSCACertPath() string
+	RemoteSignerTLSClientCertPath() string
+	RemoteSignerTLSClientKeyPath() string
+}
+
+// CryptoSuite delegates signing to a remote signer. The private keys are referenced by SKI or label and never
+// leave the signer; their public keys are fetched and cached. Hashing, verification and the import of
+// public keys are performed locally. Key generation and the import of private keys aren't supported.
+// Only ECDSA and Ed25519 keys of the signer are supported.
+type CryptoSuite struct {
+	local   core.CryptoSuite
+	client  *client
+	batcher *batcher
+	keys    *lazycache.Cache
+	labels  *lazycache.Cache
+}
+
+//GetSuiteByConfig returns cryptosuite adaptor for the remote signer configured in the given config
+func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "remote" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+
+	remoteConfig, ok := config.(remoteSignerConfig)
+	if !ok {
+		return nil, errors.New("remote signer configuration isn't supported by the crypto suite config")
+	}
+
+	opts := &Opts{
+		URL:           remoteConfig.RemoteSignerURL(),
+		Timeout:       remoteConfig.RemoteSignerTimeout(),
+		SecurityLevel: config.SecurityLevel(),
+		HashFamily:    config.SecurityAlgorithm(),
+	}
+
+	if attempts := remoteConfig.RemoteSignerRetryAttempts(); attempts >= 0 {
+		retryOpts := DefaultRetryOpts
+		retryOpts.Attempts = attempts
+		opts.Retry = &retryOpts
+	}
+
+	tlsConfig, err := tlsConfigFromFiles(remoteConfig.RemoteSignerTLSCACertPath(), remoteConfig.RemoteSignerTLSClientCertPath(), remoteConfig.RemoteSignerTLSClientKeyPath())
+	if err != nil {
+		return nil, err
+	}
+	opts.TLSConfig = tlsConfig
+
+	return New(opts)
+}
+
+// New returns a new remote signer crypto suite
+func New(opts *Opts) (*CryptoSuite, error) {
+	if opts == nil || opts.URL == "" {
+		return nil, errors.New("remote signer URL is required")
+	}
+
+	level, family := opts.SecurityLevel, opts.HashFamily
+	if level == 0 {
+		level = 256
+	}
+	if family == "" {
+		family = "SHA2"
+	}
+	local, err := sw.GetSuite(level, family, bccspSw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create local crypto suite")
+	}
+
+	c := &client{
+		url:        opts.URL,
+		httpClient: &http.Client{Transport: &http.Transport{TLSClientConfig: opts.TLSConfig}},
+		timeout:    opts.Timeout,
+		retryOpts:  DefaultRetryOpts,
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	if opts.Retry != nil {
+		c.retryOpts = *opts.Retry
+	}
+
+	maxBatchSize, batchWindow, labelExpiry := opts.MaxBatchSize, opts.BatchWindow, opts.LabelExpiry
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxBatchSize
+	}
+	if batchWindow <= 0 {
+		batchWindow = defaultBatchWindow
+	}
+	if labelExpiry <= 0 {
+		labelExpiry = defaultLabelExpiry
+	}
+
+	s := &CryptoSuite{
+		local:   local,
+		client:  c,
+		batcher: newBatcher(c.sign, maxBatchSize, batchWindow),
+	}
+	fetchKey := func(k lazycache.Key) (interface{}, error) {
+		return s.fetchKey(k.(*keyRef))
+	}
+	s.keys = lazycache.New("Remote_Signer_Key_Cache", fetchKey)
+	s.labels = lazycache.New("Remote_Signer_Label_Cache", fetchKey, lazyref.WithAbsoluteExpiration(labelExpiry))
+
+	logger.Debugf("Initialized remote signer cryptosuite [%s]", opts.URL)
+	return s, nil
+}
+
+// Close releases the resources of the suite. Signing fails after the suite is closed.
+func (s *CryptoSuite) Close() {
+	s.batcher.Close()
+	s.keys.Close()
+	s.labels.Close()
+}
+
+// KeyGen isn't supported: the keys are managed by the signer
+func (s *CryptoSuite) KeyGen(opts core.KeyGenOpts) (core.Key, error) {
+	return nil, errors.New("key generation isn't supported by the remote signer crypto suite")
+}
+
+// KeyImport imports a public key (e.g. from a certificate). Private keys can't be imported.
+func (s *CryptoSuite) KeyImport(raw interface{}, opts core.KeyImportOpts) (core.Key, error) {
+	k, err := s.local.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if k.Private() {
+		return nil, errors.New("private keys can't be imported into the remote signer crypto suite")
+	}
+	return k, nil
+}
+
+// GetKey returns the signer's private key with the given SKI
+func (s *CryptoSuite) GetKey(ski []byte) (core.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("SKI is required")
+	}
+	return s.getKey(&keyRef{SKI: hex.EncodeToString(ski)})
+}
+
+// GetKeyByLabel returns the signer's private key with the given label
+func (s *CryptoSuite) GetKeyByLabel(label string) (core.Key, error) {
+	if label == "" {
+		return nil, errors.New("label is required")
+	}
+	return s.getKey(&keyRef{Label: label})
+}
+
+func (s *CryptoSuite) getKey(ref *keyRef) (core.Key, error) {
+	cache := s.keys
+	if ref.Label != "" {
+		cache = s.labels
+	}
+	k, err := cache.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	return k.(*key), nil
+}
+
+// Hash hashes the message locally
+func (s *CryptoSuite) Hash(msg []byte, opts core.HashOpts) ([]byte, error) {
+	return s.local.Hash(msg, opts)
+}
+
+// GetHash returns a local hash function
+func (s *CryptoSuite) GetHash(opts core.HashOpts) (hash.Hash, error) {
+	return s.local.GetHash(opts)
+}
+
+// Sign signs the digest with a key of the signer. Concurrent requests are sent to the signer in batches.
+// The signer options are ignored.
+func (s *CryptoSuite) Sign(k core.Key, digest []byte, opts core.SignerOpts) ([]byte, error) {
+	rk, ok := k.(*key)
+	if !ok {
+		return nil, errors.New("key isn't a key of the remote signer")
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("digest is required")
+	}
+	return s.batcher.Sign(&signRequest{SKI: hex.EncodeToString(rk.ski), Digest: digest})
+}
+
+// Verify verifies the signature locally
+func (s *CryptoSuite) Verify(k core.Key, signature, digest []byte, opts core.SignerOpts) (bool, error) {
+	if rk, ok := k.(*key); ok {
+		k = rk.pub
+	}
+	return s.local.Verify(k, signature, digest, opts)
+}
+
+// fetchKey fetches the public key of a private key from the signer
+func (s *CryptoSuite) fetchKey(ref *keyRef) (*key, error) {
+	resp, err := s.client.getKey(&keyRequest{SKI: ref.SKI, Label: ref.Label})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get key [%s] from remote signer", ref)
+	}
+
+	pub, err := s.importPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid public key of key [%s]", ref)
+	}
+
+	// The SKI of the key is derived from its public key, so that the signer can't substitute another key
+	ski, err := hex.DecodeString(resp.SKI)
+	if err != nil || !bytes.Equal(ski, pub.SKI()) || (ref.SKI != "" && ref.SKI != resp.SKI) {
+		return nil, errors.Errorf("remote signer returned a key that doesn't match key [%s]", ref)
+	}
+
+	return &key{ski: ski, label: resp.Label, pub: pub}, nil
+}
+
+func (s *CryptoSuite) importPublicKey(der []byte) (core.Key, error) {
+	pk, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse public key")
+	}
+
+	switch pk := pk.(type) {
+	case *ecdsa.PublicKey:
+		return s.local.KeyImport(pk, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	case ed25519.PublicKey:
+		return s.local.KeyImport(pk, &sw.Ed25519PublicKeyImportOpts{Temporary: true})
+	default:
+		return nil, errors.Errorf("unsupported public key type %T", pk)
+	}
+}
+
+// keyRef references a key of the signer by SKI or label
+type keyRef keyRequest
+
+func (r *keyRef) String() string {
+	if r.SKI != "" {
+		return "ski:" + r.SKI
+	}
+	return "label:" + r.Label
+}
+
+// key is a private key of the signer
+type key struct {
+	ski   []byte
+	label string
+	pub   core.Key
+}
+
+func (k *key) Bytes() ([]byte, error) {
+	return nil, errors.New("private key material isn't available from the remote signer")
+}
+
+func (k *key) SKI() []byte {
+	return k.ski
+}
+
+func (k *key) Symmetric() bool {
+	return false
+}
+
+func (k *key) Private() bool {
+	return true
+}
+
+func (k *key) PublicKey() (core.Key, error) {
+	return k.pub, nil
+}
+
+// tlsConfigFromFiles returns the TLS configuration of the given PEM files (all of which are optional)
+func tlsConfigFromFiles(caCertPath, clientCertPath, clientKeyPath string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caCertPath != "" {
+		caCerts, err := ioutil.ReadFile(caCertPath) // nolint: gas
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read remote signer CA certificates [%s]", caCertPath)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, errors.Errorf("no remote signer CA certificates found in [%s]", caCertPath)
+		}
+	}
+
+	if clientCertPath != "" || clientKeyPath != "" {
+		cert, err := tls.LoadX509KeyPair(clientCertPath, clientKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load remote signer client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
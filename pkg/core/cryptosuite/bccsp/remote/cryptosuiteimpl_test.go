@@ -0,0 +1,445 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMsg = []byte("hello world")
+
+func TestRemoteSign(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	suite := f.newSuite(t, nil)
+	defer suite.Close()
+
+	for _, opts := range []core.KeyGenOpts{cryptosuite.GetECDSAP256KeyGenOpts(false), cryptosuite.GetEd25519KeyGenOpts(false)} {
+		signerKey, err := f.signerSuite.KeyGen(opts)
+		require.NoError(t, err)
+
+		k, err := suite.GetKey(signerKey.SKI())
+		require.NoError(t, err)
+		assert.True(t, k.Private())
+		assert.Equal(t, signerKey.SKI(), k.SKI())
+		assert.Equal(t, cryptosuite.KeyAlgorithm(signerKey), cryptosuite.KeyAlgorithm(k))
+
+		digest := testMsg
+		if hashOpts := cryptosuite.GetSignatureHashOpts(k); hashOpts != nil {
+			digest, err = suite.Hash(testMsg, hashOpts)
+			require.NoError(t, err)
+		}
+
+		signature, err := suite.Sign(k, digest, nil)
+		require.NoError(t, err)
+
+		valid, err := suite.Verify(k, signature, digest, nil)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		valid, err = f.signerSuite.Verify(signerKey, signature, digest, nil)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+}
+
+func TestRemoteKeyByLabel(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	suite := f.newSuite(t, nil)
+	defer suite.Close()
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	f.server.AddLabel("org1-signer", signerKey.SKI())
+
+	k, err := suite.GetKeyByLabel("org1-signer")
+	require.NoError(t, err)
+	assert.Equal(t, signerKey.SKI(), k.SKI())
+
+	// The key is cached
+	requests := f.requests(keysPath)
+	_, err = suite.GetKeyByLabel("org1-signer")
+	require.NoError(t, err)
+	assert.Equal(t, requests, f.requests(keysPath))
+
+	_, err = suite.GetKeyByLabel("unknown")
+	assert.Error(t, err)
+
+	_, err = suite.GetKey([]byte("unknown"))
+	assert.Error(t, err)
+}
+
+func TestRemoteKeyByLabelExpiry(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	const labelExpiry = 100 * time.Millisecond
+	suite := f.newSuite(t, &Opts{LabelExpiry: labelExpiry})
+	defer suite.Close()
+
+	key1, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	key2, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+
+	f.server.AddLabel("org1-signer", key1.SKI())
+	k, err := suite.GetKeyByLabel("org1-signer")
+	require.NoError(t, err)
+	assert.Equal(t, key1.SKI(), k.SKI())
+
+	// The label is moved to another key, which is used once the cached key expires
+	f.server.AddLabel("org1-signer", key2.SKI())
+	k, err = suite.GetKeyByLabel("org1-signer")
+	require.NoError(t, err)
+	assert.Equal(t, key1.SKI(), k.SKI())
+
+	time.Sleep(2 * labelExpiry)
+	k, err = suite.GetKeyByLabel("org1-signer")
+	require.NoError(t, err)
+	assert.Equal(t, key2.SKI(), k.SKI())
+}
+
+func TestRemoteSignBatching(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	suite := f.newSuite(t, &Opts{MaxBatchSize: 10, BatchWindow: 100 * time.Millisecond})
+	defer suite.Close()
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	k, err := suite.GetKey(signerKey.SKI())
+	require.NoError(t, err)
+	digest, err := suite.Hash(testMsg, cryptosuite.GetSHAOpts())
+	require.NoError(t, err)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			signature, err := suite.Sign(k, digest, nil)
+			if err == nil {
+				var valid bool
+				valid, err = suite.Verify(k, signature, digest, nil)
+				if err == nil && !valid {
+					err = status.New(status.TestStatus, status.SignatureVerificationFailed.ToInt32(), "invalid signature", nil)
+				}
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.True(t, f.requests(signPath) < n, "signing requests should be batched")
+}
+
+func TestRemoteSignerMutualTLS(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	tlsConfig := f.clientTLSConfig.Clone()
+	tlsConfig.Certificates = nil
+	suite := f.newSuite(t, &Opts{TLSConfig: tlsConfig, Retry: &retry.Opts{}})
+	defer suite.Close()
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	_, err = suite.GetKey(signerKey.SKI())
+	assert.Error(t, err, "client without certificate should be rejected")
+}
+
+func TestRemoteSignerRetry(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	// The signer is unavailable for the first two requests
+	var unavailable int32 = 2
+	f.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if atomic.AddInt32(&unavailable, -1) >= 0 {
+			writeError(w, http.StatusServiceUnavailable, errTest)
+			return true
+		}
+		return false
+	}
+
+	retryOpts := DefaultRetryOpts
+	retryOpts.InitialBackoff = time.Millisecond
+	suite := f.newSuite(t, &Opts{Retry: &retryOpts})
+	defer suite.Close()
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	_, err = suite.GetKey(signerKey.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.requests(keysPath))
+
+	// Requests that fail with other errors aren't retried
+	_, err = suite.GetKey([]byte("unknown"))
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.HTTPTransportStatus, s.Group)
+	assert.Equal(t, int32(http.StatusNotFound), s.Code)
+	assert.Equal(t, 4, f.requests(keysPath))
+}
+
+func TestRemoteSignerTimeout(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	f.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		time.Sleep(200 * time.Millisecond)
+		return false
+	}
+
+	suite := f.newSuite(t, &Opts{Timeout: 20 * time.Millisecond, Retry: &retry.Opts{Attempts: 1, RetryableCodes: DefaultRetryOpts.RetryableCodes}})
+	defer suite.Close()
+
+	_, err := suite.GetKey([]byte("ski"))
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.ClientStatus, s.Group)
+	assert.Equal(t, status.Timeout.ToInt32(), s.Code)
+	assert.Equal(t, 2, f.requests(keysPath), "timed out request should be retried once")
+}
+
+func TestRemoteUnsupportedOperations(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	suite := f.newSuite(t, nil)
+
+	_, err := suite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+	assert.Error(t, err)
+
+	localKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+	require.NoError(t, err)
+	_, err = suite.Sign(localKey, testMsg, nil)
+	assert.Error(t, err, "only keys of the signer are supported")
+
+	// Public keys may be imported, private keys may not
+	_, err = suite.KeyImport(f.clientCert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(f.clientKey)
+	require.NoError(t, err)
+	_, err = suite.KeyImport(der, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	k, err := suite.GetKey(signerKey.SKI())
+	require.NoError(t, err)
+	_, err = k.Bytes()
+	assert.Error(t, err, "private key material shouldn't be available")
+
+	suite.Close()
+	_, err = suite.Sign(k, testMsg, nil)
+	assert.Error(t, err, "closed suite shouldn't sign")
+
+	_, err = New(&Opts{})
+	assert.Error(t, err, "URL is required")
+
+	// RSA keys aren't supported since the signer options (hash and padding) aren't sent to the signer
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	_, err = suite.importPublicKey(der)
+	assert.Error(t, err)
+}
+
+func TestGetSuiteByConfig(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.close()
+
+	dir, err := ioutil.TempDir("", "remotesigner")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	keyDER, err := x509.MarshalECPrivateKey(f.clientKey)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.ts.Certificate().Raw}), 0600))
+	require.NoError(t, ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.clientCert.Raw}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("remote").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SHA2").AnyTimes()
+	mockConfig.EXPECT().SecurityLevel().Return(256).AnyTimes()
+
+	_, err = GetSuiteByConfig(mockConfig)
+	assert.Error(t, err, "remote signer config is required")
+
+	config := &remoteConfig{CryptoSuiteConfig: mockConfig, url: f.ts.URL, retryAttempts: -1, caPath: caPath, certPath: certPath, keyPath: keyPath}
+	suite, err := GetSuiteByConfig(config)
+	require.NoError(t, err)
+	defer suite.(*CryptoSuite).Close()
+
+	signerKey, err := f.signerSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	k, err := suite.GetKey(signerKey.SKI())
+	require.NoError(t, err)
+	_, err = suite.Sign(k, testMsg, nil)
+	require.NoError(t, err)
+
+	config.caPath = filepath.Join(dir, "missing.pem")
+	_, err = GetSuiteByConfig(config)
+	assert.Error(t, err)
+}
+
+type remoteConfig struct {
+	core.CryptoSuiteConfig
+	url           string
+	retryAttempts int
+	caPath        string
+	certPath      string
+	keyPath       string
+}
+
+func (c *remoteConfig) RemoteSignerURL() string               { return c.url }
+func (c *remoteConfig) RemoteSignerTimeout() time.Duration    { return 0 }
+func (c *remoteConfig) RemoteSignerRetryAttempts() int        { return c.retryAttempts }
+func (c *remoteConfig) RemoteSignerTLSCACertPath() string     { return c.caPath }
+func (c *remoteConfig) RemoteSignerTLSClientCertPath() string { return c.certPath }
+func (c *remoteConfig) RemoteSignerTLSClientKeyPath() string  { return c.keyPath }
+
+var errTest = status.New(status.TestStatus, status.GenericTransient.ToInt32(), "test error", nil)
+
+// testFixture is a reference signer that requires mutual TLS, with the TLS configuration of its clients
+type testFixture struct {
+	ts              *httptest.Server
+	server          *Server
+	signerSuite     core.CryptoSuite
+	keyStorePath    string
+	clientTLSConfig *tls.Config
+	clientCert      *x509.Certificate
+	clientKey       *ecdsa.PrivateKey
+
+	mutex     sync.Mutex
+	counts    map[string]int
+	intercept func(w http.ResponseWriter, r *http.Request) bool
+}
+
+func newTestFixture(t *testing.T) *testFixture {
+	keyStorePath, err := ioutil.TempDir("", "remotesigner")
+	require.NoError(t, err)
+
+	kek := make([]byte, 32)
+	ks, err := sw.NewEncryptedFileKeyStore(keyStorePath, sw.KEKProviderFunc(func(salt []byte) ([]byte, error) { return kek, nil }))
+	require.NoError(t, err)
+	signerSuite, err := sw.GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	f := &testFixture{
+		server:       NewServer(signerSuite),
+		signerSuite:  signerSuite,
+		keyStorePath: keyStorePath,
+		counts:       make(map[string]int),
+	}
+
+	ca, err := testcert.New(testcert.WithCommonName("remote signer test CA"), testcert.WithCA())
+	require.NoError(t, err)
+	client, err := testcert.New(testcert.WithCommonName("remote signer test"), testcert.WithParent(ca),
+		testcert.WithKeyUsage(x509.KeyUsageDigitalSignature), testcert.WithExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	require.NoError(t, err)
+	f.clientCert, f.clientKey = client.Cert, client.Key.(*ecdsa.PrivateKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Cert)
+
+	f.ts = httptest.NewUnstartedServer(http.HandlerFunc(f.serveHTTP))
+	f.ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	f.ts.StartTLS()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(f.ts.Certificate())
+	f.clientTLSConfig = &tls.Config{
+		RootCAs:      rootCAs,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{f.clientCert.Raw}, PrivateKey: f.clientKey}},
+	}
+
+	return f
+}
+
+func (f *testFixture) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	f.counts[r.URL.Path]++
+	intercept := f.intercept
+	f.mutex.Unlock()
+
+	if intercept != nil && intercept(w, r) {
+		return
+	}
+	f.server.ServeHTTP(w, r)
+}
+
+func (f *testFixture) requests(path string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.counts[path]
+}
+
+func (f *testFixture) newSuite(t *testing.T, opts *Opts) *CryptoSuite {
+	if opts == nil {
+		opts = &Opts{}
+	}
+	opts.URL = f.ts.URL
+	if opts.TLSConfig == nil {
+		opts.TLSConfig = f.clientTLSConfig
+	}
+	suite, err := New(opts)
+	require.NoError(t, err)
+	return suite
+}
+
+func (f *testFixture) close() {
+	f.ts.Close()
+	os.RemoveAll(f.keyStorePath)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remote provides a crypto suite that delegates signing to a remote signing service, so that the
+// private keys of an organization can live in one hardened place and be shared by multiple applications.
+//
+// Signing protocol
+//
+// The suite communicates with the signer over HTTPS; mutual TLS should be used so that the signer can
+// authenticate its clients. Requests and responses are JSON: SKIs are hex encoded and binary values
+// (digests, signatures and public keys) are base64 encoded.
+//
+//  POST <url>/v1/keys
+//  Request:  {"ski": "<SKI>"} or {"label": "<label>"}
+//  Response: {"ski": "<SKI>", "label": "<label>", "publicKey": "<PKIX DER public key>"}
+//
+//  POST <url>/v1/sign
+//  Request:  {"requests": [{"ski": "<SKI>", "digest": "<digest>"}, ...]}
+//  Response: {"results": [{"signature": "<signature>"} or {"error": "<message>"}, ...]}
+//
+// The sign results are in the order of the requests. ECDSA signatures are ASN.1 DER encoded (with a low S
+// value) and Ed25519 signatures are over the message itself, which is sent as the digest. Only ECDSA and
+// Ed25519 keys are supported: the requests don't carry signer options, such as the hash and padding of RSA.
+//
+// Failed requests return a non-200 status (404 if a key isn't found) with a body of {"error": "<message>"}.
+// The client retries requests that fail with a connection error, a timeout or a status of 429, 502, 503 or 504.
+package remote
+
+const (
+	keysPath = "/v1/keys"
+	signPath = "/v1/sign"
+)
+
+// keyRequest references a key by SKI or by label
+type keyRequest struct {
+	SKI   string `json:"ski,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+type keyResponse struct {
+	SKI       string `json:"ski"`
+	Label     string `json:"label,omitempty"`
+	PublicKey []byte `json:"publicKey"`
+}
+
+type signRequest struct {
+	SKI    string `json:"ski"`
+	Digest []byte `json:"digest"`
+}
+
+type signBatchRequest struct {
+	Requests []*signRequest `json:"requests"`
+}
+
+type signResult struct {
+	Signature []byte `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type signBatchResponse struct {
+	Results []*signResult `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
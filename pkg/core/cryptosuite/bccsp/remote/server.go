@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxRequestSize limits the size of the requests read by the server
+	maxRequestSize = 4 << 20
+	// defaultServerMaxBatchSize is the default maximum number of signing requests of a batch
+	defaultServerMaxBatchSize = 1000
+)
+
+// Server is a reference implementation of the signer. It signs with the ECDSA and Ed25519 private keys of
+// a crypto suite, for example a SW suite with an encrypted key store or a PKCS#11 suite, which are referenced
+// by SKI or by the labels that are added to the server.
+//
+// The server is an http.Handler. It should be served over TLS with client authentication
+// (tls.RequireAndVerifyClientCert), so that only the applications of the organization can sign.
+type Server struct {
+	suite        core.CryptoSuite
+	maxBatchSize int
+	mutex        sync.RWMutex
+	labels       map[string][]byte
+	mux          *http.ServeMux
+}
+
+// NewServer returns a signer that signs with the private keys of the given crypto suite
+func NewServer(suite core.CryptoSuite) *Server {
+	s := &Server{
+		suite:        suite,
+		maxBatchSize: defaultServerMaxBatchSize,
+		labels:       make(map[string][]byte),
+		mux:          http.NewServeMux(),
+	}
+	s.mux.HandleFunc(keysPath, s.handleKey)
+	s.mux.HandleFunc(signPath, s.handleSign)
+	return s
+}
+
+// AddLabel makes the key with the given SKI available by label
+func (s *Server) AddLabel(label string, ski []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.labels[label] = ski
+}
+
+// ServeHTTP handles the requests of the signing protocol
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleKey(w http.ResponseWriter, r *http.Request) {
+	request := &keyRequest{}
+	if !readRequest(w, r, request) {
+		return
+	}
+
+	ski, label, err := s.resolve(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	k, err := s.privateKey(ski)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	pub, err := k.PublicKey()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to get public key"))
+		return
+	}
+	raw, err := pub.Bytes()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to marshal public key"))
+		return
+	}
+
+	writeResponse(w, &keyResponse{SKI: hex.EncodeToString(k.SKI()), Label: label, PublicKey: raw})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	request := &signBatchRequest{}
+	if !readRequest(w, r, request) {
+		return
+	}
+	if len(request.Requests) > s.maxBatchSize {
+		writeError(w, http.StatusBadRequest, errors.Errorf("batch of %d requests exceeds the maximum of %d", len(request.Requests), s.maxBatchSize))
+		return
+	}
+
+	response := &signBatchResponse{Results: make([]*signResult, len(request.Requests))}
+	for i, req := range request.Requests {
+		signature, err := s.sign(req)
+		if err != nil {
+			response.Results[i] = &signResult{Error: err.Error()}
+			continue
+		}
+		response.Results[i] = &signResult{Signature: signature}
+	}
+
+	writeResponse(w, response)
+}
+
+func (s *Server) sign(request *signRequest) ([]byte, error) {
+	if request == nil {
+		return nil, errors.New("empty request")
+	}
+	ski, err := hex.DecodeString(request.SKI)
+	if err != nil {
+		return nil, errors.New("invalid SKI")
+	}
+	k, err := s.privateKey(ski)
+	if err != nil {
+		return nil, err
+	}
+	return s.suite.Sign(k, request.Digest, nil)
+}
+
+// resolve returns the SKI and label of the referenced key
+func (s *Server) resolve(request *keyRequest) ([]byte, string, error) {
+	if request.SKI != "" {
+		ski, err := hex.DecodeString(request.SKI)
+		if err != nil {
+			return nil, "", errors.New("invalid SKI")
+		}
+		return ski, "", nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	ski, ok := s.labels[request.Label]
+	if !ok {
+		return nil, "", errors.Errorf("key with label [%s] not found", request.Label)
+	}
+	return ski, request.Label, nil
+}
+
+func (s *Server) privateKey(ski []byte) (core.Key, error) {
+	k, err := s.suite.GetKey(ski)
+	if err != nil || k == nil || !k.Private() {
+		return nil, errors.Errorf("private key [%s] not found", hex.EncodeToString(ski))
+	}
+	if cryptosuite.KeyAlgorithm(k) == cryptosuite.KeyAlgorithmRSA {
+		return nil, errors.Errorf("key [%s] is an RSA key, which isn't supported", hex.EncodeToString(ski))
+	}
+	return k, nil
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s isn't allowed", r.Method))
+		return false
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+		return false
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Warnf("Failed to write response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(&errorResponse{Error: err.Error()}); err != nil {
+		logger.Warnf("Failed to write error response: %s", err)
+	}
+}
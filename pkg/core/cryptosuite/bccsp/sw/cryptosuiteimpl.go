@@ -18,6 +18,34 @@ import (
 
 var logger = logging.NewLogger("fabsdk/core")
 
+// keyStoreEncryptionConfig is implemented by crypto suite configs that may select the encrypted keystore
+type keyStoreEncryptionConfig interface {
+	KeyStoreEncryption() string
+	KeyStorePassphrase() (string, error)
+}
+
+// KeyStoreKEKProvider returns the provider of the key-encryption key of the keystore that's selected by
+// the given config, or nil if the keys of the keystore aren't encrypted
+func KeyStoreKEKProvider(config core.CryptoSuiteConfig) (KEKProvider, error) {
+	encConfig, ok := config.(keyStoreEncryptionConfig)
+	if !ok {
+		return nil, nil
+	}
+
+	switch encConfig.KeyStoreEncryption() {
+	case "", KeyStoreEncryptionNone:
+		return nil, nil
+	case KeyStoreEncryptionPassphrase:
+		passphrase, err := encConfig.KeyStorePassphrase()
+		if err != nil {
+			return nil, err
+		}
+		return NewPassphraseKEKProvider([]byte(passphrase))
+	default:
+		return nil, errors.Errorf("Unsupported key store encryption: %s", encConfig.KeyStoreEncryption())
+	}
+}
+
 //GetSuiteByConfig returns cryptosuite adaptor for bccsp loaded according to given config
 func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	// TODO: delete this check?
@@ -25,6 +53,14 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
 	}
 
+	provider, err := KeyStoreKEKProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		return GetSuiteByConfigWithKEK(config, provider)
+	}
+
 	opts := getOptsByConfig(config)
 	bccsp, err := getBCCSPFromOpts(opts)
 	if err != nil {
@@ -33,6 +69,43 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	return wrapper.NewCryptoSuite(bccsp), nil
 }
 
+//GetSuiteByConfigWithKEK returns cryptosuite adaptor for bccsp loaded according to given config, whose keystore
+//is encrypted with the key-encryption key of the given provider
+func GetSuiteByConfigWithKEK(config core.CryptoSuiteConfig, provider KEKProvider) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "sw" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+
+	keyStore, err := NewEncryptedFileKeyStore(config.KeyStorePath(), provider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Could not initialize encrypted key store")
+	}
+	logger.Debug("Initialized SW cryptosuite with encrypted key store")
+
+	return GetSuite(config.SecurityLevel(), config.SecurityAlgorithm(), keyStore)
+}
+
+//GetSuiteByConfigWithKVStore returns cryptosuite adaptor for bccsp loaded according to given config, whose
+//keys are stored in the given key-value store (see NewKVKeyStore) rather than in the keystore directory
+func GetSuiteByConfigWithKVStore(config core.CryptoSuiteConfig, store core.KVStore) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "sw" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+	if encConfig, ok := config.(keyStoreEncryptionConfig); ok {
+		if enc := encConfig.KeyStoreEncryption(); enc != "" && enc != KeyStoreEncryptionNone {
+			return nil, errors.Errorf("Key store encryption [%s] is only supported by the file key store", enc)
+		}
+	}
+
+	keyStore, err := NewKVKeyStore(store)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Could not initialize key-value key store")
+	}
+	logger.Debug("Initialized SW cryptosuite with key-value key store")
+
+	return GetSuite(config.SecurityLevel(), config.SecurityAlgorithm(), keyStore)
+}
+
 //GetSuiteWithDefaultEphemeral returns cryptosuite adaptor for bccsp with default ephemeral options (intended to aid testing)
 func GetSuiteWithDefaultEphemeral() (core.CryptoSuite, error) {
 	opts := getEphemeralOpts()
@@ -45,25 +118,51 @@ func GetSuiteWithDefaultEphemeral() (core.CryptoSuite, error) {
 }
 
 func getBCCSPFromOpts(config *bccspSw.SwOpts) (bccsp.BCCSP, error) {
+	if config.FileKeystore != nil && !config.Ephemeral {
+		// The file-based keystore is wrapped so that it also stores Ed25519 keys
+		path := config.FileKeystore.KeyStorePath
+		ks, err := sw.NewFileBasedKeyStore(nil, path, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to initialize software key store")
+		}
+		return newBCCSP(config.SecLevel, config.HashFamily, newEd25519KeyStore(ks, &fileKeyStorage{path: path}))
+	}
+
 	f := &bccspSw.SWFactory{}
 
 	csp, err := f.Get(config)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Could not initialize BCCSP %s", f.Name())
 	}
+	if err := addEd25519Support(csp); err != nil {
+		return nil, err
+	}
 	return csp, nil
 }
 
 // GetSuite returns a new instance of the software-based BCCSP
 // set at the passed security level, hash family and KeyStore.
 func GetSuite(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (core.CryptoSuite, error) {
-	bccsp, err := sw.NewWithParams(securityLevel, hashFamily, keyStore)
+	bccsp, err := newBCCSP(securityLevel, hashFamily, keyStore)
 	if err != nil {
 		return nil, err
 	}
 	return wrapper.NewCryptoSuite(bccsp), nil
 }
 
+// newBCCSP returns a new instance of the software-based BCCSP, which supports Ed25519 in addition to the
+// algorithms of the Fabric BCCSP
+func newBCCSP(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	csp, err := sw.NewWithParams(securityLevel, hashFamily, keyStore)
+	if err != nil {
+		return nil, err
+	}
+	if err := addEd25519Support(csp); err != nil {
+		return nil, err
+	}
+	return csp, nil
+}
+
 //GetOptsByConfig Returns Factory opts for given SDK config
 func getOptsByConfig(c core.CryptoSuiteConfig) *bccspSw.SwOpts {
 	opts := &bccspSw.SwOpts{
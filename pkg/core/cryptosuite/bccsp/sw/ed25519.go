@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+// ED25519 is the algorithm of Ed25519 keys
+const ED25519 = "ED25519"
+
+// ed25519KeyFileSuffix is the suffix of the files that hold Ed25519 private keys in a file keystore
+const ed25519KeyFileSuffix = "_ed25519_sk"
+
+// Ed25519KeyGenOpts contains options for Ed25519 key generation
+type Ed25519KeyGenOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (opts *Ed25519KeyGenOpts) Algorithm() string {
+	return ED25519
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral, false otherwise.
+func (opts *Ed25519KeyGenOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// Ed25519PrivateKeyImportOpts contains options for importing Ed25519 private keys
+// (as an ed25519.PrivateKey or in PKCS#8 DER format)
+type Ed25519PrivateKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *Ed25519PrivateKeyImportOpts) Algorithm() string {
+	return ED25519
+}
+
+// Ephemeral returns true if the key generated has to be ephemeral, false otherwise.
+func (opts *Ed25519PrivateKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// Ed25519PublicKeyImportOpts contains options for importing Ed25519 public keys
+// (as an ed25519.PublicKey or in PKIX DER format)
+type Ed25519PublicKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *Ed25519PublicKeyImportOpts) Algorithm() string {
+	return ED25519
+}
+
+// Ephemeral returns true if the key generated has to be ephemeral, false otherwise.
+func (opts *Ed25519PublicKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+type ed25519PrivateKey struct {
+	privKey ed25519.PrivateKey
+}
+
+// Bytes converts this key to its byte representation, if this operation is allowed.
+func (k *ed25519PrivateKey) Bytes() ([]byte, error) {
+	return nil, errors.New("Not supported.")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *ed25519PrivateKey) SKI() []byte {
+	return ed25519SKI(k.privKey.Public().(ed25519.PublicKey))
+}
+
+// Symmetric returns true if this key is a symmetric key, false otherwise.
+func (k *ed25519PrivateKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key, false otherwise.
+func (k *ed25519PrivateKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *ed25519PrivateKey) PublicKey() (bccsp.Key, error) {
+	return &ed25519PublicKey{pubKey: k.privKey.Public().(ed25519.PublicKey)}, nil
+}
+
+type ed25519PublicKey struct {
+	pubKey ed25519.PublicKey
+}
+
+// Bytes returns the public key in PKIX DER format
+func (k *ed25519PublicKey) Bytes() ([]byte, error) {
+	raw, err := x509.MarshalPKIXPublicKey(k.pubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed marshalling key")
+	}
+	return raw, nil
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *ed25519PublicKey) SKI() []byte {
+	return ed25519SKI(k.pubKey)
+}
+
+// Symmetric returns true if this key is a symmetric key, false otherwise.
+func (k *ed25519PublicKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key, false otherwise.
+func (k *ed25519PublicKey) Private() bool {
+	return false
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+func (k *ed25519PublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
+
+func ed25519SKI(pubKey ed25519.PublicKey) []byte {
+	hash := sha256.Sum256(pubKey)
+	return hash[:]
+}
+
+type ed25519KeyGenerator struct{}
+
+func (kg *ed25519KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed generating Ed25519 key")
+	}
+	return &ed25519PrivateKey{privKey: privKey}, nil
+}
+
+// ed25519Signer signs the message itself: Ed25519 signatures are computed over the message rather than over its hash
+type ed25519Signer struct{}
+
+func (s *ed25519Signer) Sign(k bccsp.Key, msg []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	return ed25519.Sign(k.(*ed25519PrivateKey).privKey, msg), nil
+}
+
+type ed25519PrivateKeyVerifier struct{}
+
+func (v *ed25519PrivateKeyVerifier) Verify(k bccsp.Key, signature, msg []byte, opts bccsp.SignerOpts) (bool, error) {
+	return ed25519.Verify(k.(*ed25519PrivateKey).privKey.Public().(ed25519.PublicKey), msg, signature), nil
+}
+
+type ed25519PublicKeyVerifier struct{}
+
+func (v *ed25519PublicKeyVerifier) Verify(k bccsp.Key, signature, msg []byte, opts bccsp.SignerOpts) (bool, error) {
+	return ed25519.Verify(k.(*ed25519PublicKey).pubKey, msg, signature), nil
+}
+
+type ed25519PrivateKeyImporter struct{}
+
+func (ki *ed25519PrivateKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	switch key := raw.(type) {
+	case ed25519.PrivateKey:
+		return &ed25519PrivateKey{privKey: key}, nil
+	case []byte:
+		privKey, err := x509.ParsePKCS8PrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed parsing Ed25519 private key")
+		}
+		edKey, ok := privKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("Failed casting to Ed25519 private key. Invalid raw material.")
+		}
+		return &ed25519PrivateKey{privKey: edKey}, nil
+	default:
+		return nil, errors.New("Invalid raw material. Expected ed25519.PrivateKey or PKCS#8 DER.")
+	}
+}
+
+type ed25519PublicKeyImporter struct{}
+
+func (ki *ed25519PublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	switch key := raw.(type) {
+	case ed25519.PublicKey:
+		return &ed25519PublicKey{pubKey: key}, nil
+	case []byte:
+		pubKey, err := x509.ParsePKIXPublicKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed parsing Ed25519 public key")
+		}
+		edKey, ok := pubKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("Failed casting to Ed25519 public key. Invalid raw material.")
+		}
+		return &ed25519PublicKey{pubKey: edKey}, nil
+	default:
+		return nil, errors.New("Invalid raw material. Expected ed25519.PublicKey or PKIX DER.")
+	}
+}
+
+// x509PublicKeyImporter imports the Ed25519 public keys of certificates and delegates other keys to the default importer
+type x509PublicKeyImporter struct {
+	next sw.KeyImporter
+}
+
+func (ki *x509PublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	if cert, ok := raw.(*x509.Certificate); ok {
+		if pubKey, ok := cert.PublicKey.(ed25519.PublicKey); ok {
+			return &ed25519PublicKey{pubKey: pubKey}, nil
+		}
+	}
+	return ki.next.KeyImport(raw, opts)
+}
+
+// addEd25519Support registers the Ed25519 key generator, signer, verifiers and importers with the software BCCSP
+func addEd25519Support(csp bccsp.BCCSP) error {
+	swCSP, ok := csp.(*sw.CSP)
+	if !ok {
+		return errors.New("Ed25519 is only supported by the software BCCSP")
+	}
+
+	x509ImportOpts := reflect.TypeOf(&bccsp.X509PublicKeyImportOpts{})
+	next, ok := swCSP.KeyImporters[x509ImportOpts]
+	if !ok {
+		return errors.New("X509 public key importer not found")
+	}
+
+	wrappers := []struct {
+		t reflect.Type
+		w interface{}
+	}{
+		{reflect.TypeOf(&Ed25519KeyGenOpts{}), &ed25519KeyGenerator{}},
+		{reflect.TypeOf(&ed25519PrivateKey{}), &ed25519Signer{}},
+		{reflect.TypeOf(&ed25519PrivateKey{}), &ed25519PrivateKeyVerifier{}},
+		{reflect.TypeOf(&ed25519PublicKey{}), &ed25519PublicKeyVerifier{}},
+		{reflect.TypeOf(&Ed25519PrivateKeyImportOpts{}), &ed25519PrivateKeyImporter{}},
+		{reflect.TypeOf(&Ed25519PublicKeyImportOpts{}), &ed25519PublicKeyImporter{}},
+		{x509ImportOpts, &x509PublicKeyImporter{next: next}},
+	}
+	for _, wrapper := range wrappers {
+		if err := swCSP.AddWrapper(wrapper.t, wrapper.w); err != nil {
+			return errors.Wrap(err, "Failed adding Ed25519 support")
+		}
+	}
+	return nil
+}
+
+// ed25519KeyStore stores the Ed25519 private keys, which aren't supported by the Fabric keystores,
+// in the storage of the keystore (named <ski>_ed25519_sk). Other keys are delegated.
+type ed25519KeyStore struct {
+	bccsp.KeyStore
+	storage sw.KeyStorage
+}
+
+func newEd25519KeyStore(keyStore bccsp.KeyStore, storage sw.KeyStorage) bccsp.KeyStore {
+	return &ed25519KeyStore{KeyStore: keyStore, storage: storage}
+}
+
+// GetKey returns the key with the given SKI
+func (ks *ed25519KeyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	raw, err := ks.storage.Load(hex.EncodeToString(ski) + ed25519KeyFileSuffix)
+	if err != nil {
+		if err == sw.ErrKeyNotFound {
+			return ks.KeyStore.GetKey(ski)
+		}
+		return nil, errors.WithMessagef(err, "Failed loading key [%x]", ski)
+	}
+
+	privKey, err := pemToEd25519PrivateKey(raw)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed loading key [%x]", ski)
+	}
+	return &ed25519PrivateKey{privKey: privKey}, nil
+}
+
+// StoreKey stores the key
+func (ks *ed25519KeyStore) StoreKey(k bccsp.Key) error {
+	edKey, ok := k.(*ed25519PrivateKey)
+	if !ok {
+		return ks.KeyStore.StoreKey(k)
+	}
+	if ks.ReadOnly() {
+		return errors.New("Read only KeyStore.")
+	}
+
+	raw, err := ed25519PrivateKeyToPEM(edKey.privKey)
+	if err != nil {
+		return err
+	}
+	if err := ks.storage.Store(hex.EncodeToString(k.SKI())+ed25519KeyFileSuffix, raw); err != nil {
+		return errors.WithMessage(err, "Failed storing Ed25519 private key")
+	}
+	return nil
+}
+
+// fileKeyStorage stores keys in the files of a keystore directory, encrypted with the cipher (if not nil)
+type fileKeyStorage struct {
+	path   string
+	cipher sw.FileCipher
+}
+
+// Load returns the key stored in the named file
+func (s *fileKeyStorage) Load(name string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(s.path, name)) // nolint: gas
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, sw.ErrKeyNotFound
+		}
+		return nil, errors.Wrapf(err, "Failed reading key [%s]", name)
+	}
+	if s.cipher == nil {
+		return raw, nil
+	}
+	return s.cipher.Decrypt(name, raw)
+}
+
+// Store stores the key in the named file
+func (s *fileKeyStorage) Store(name string, raw []byte) error {
+	if s.cipher != nil {
+		var err error
+		if raw, err = s.cipher.Encrypt(name, raw); err != nil {
+			return err
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.path, name), raw, 0600); err != nil {
+		return errors.Wrapf(err, "Failed writing key [%s]", name)
+	}
+	return nil
+}
+
+// ed25519PrivateKeyToPEM encodes the key in PKCS#8 PEM format
+func ed25519PrivateKeyToPEM(privKey ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed marshalling Ed25519 private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// pemToEd25519PrivateKey decodes a key that was encoded with ed25519PrivateKeyToPEM
+func pemToEd25519PrivateKey(raw []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("Failed decoding PEM")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed parsing Ed25519 private key")
+	}
+	privKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("Key is not an Ed25519 private key")
+	}
+	return privKey, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMsg = []byte("hello world")
+
+func TestEd25519SignVerify(t *testing.T) {
+	suite, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	key, err := suite.KeyGen(&Ed25519KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+	assert.False(t, key.Symmetric())
+
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.SKI(), pubKey.SKI())
+
+	sig, err := suite.Sign(key, testMsg, nil)
+	require.NoError(t, err)
+
+	// Signatures are standard Ed25519 signatures of the message
+	raw, err := pubKey.Bytes()
+	require.NoError(t, err)
+	pub, err := x509.ParsePKIXPublicKey(raw)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(pub.(ed25519.PublicKey), testMsg, sig))
+
+	valid, err := suite.Verify(key, sig, testMsg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = suite.Verify(pubKey, sig, []byte("tampered"), nil)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestEd25519KeyImport(t *testing.T) {
+	suite, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	key, err := suite.KeyImport(der, &Ed25519PrivateKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+
+	sig, err := suite.Sign(key, testMsg, nil)
+	require.NoError(t, err)
+
+	// Import the public key from a certificate
+	cert, err := testcert.New(testcert.WithCommonName("user1"), testcert.WithKey(priv))
+	require.NoError(t, err)
+
+	certKey, err := suite.KeyImport(cert.Cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.False(t, certKey.Private())
+	assert.Equal(t, key.SKI(), certKey.SKI())
+
+	valid, err := suite.Verify(certKey, sig, testMsg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubKey, err := suite.KeyImport(pubDER, &Ed25519PublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, key.SKI(), pubKey.SKI())
+
+	_, err = suite.KeyImport([]byte("invalid"), &Ed25519PrivateKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+}
+
+func TestEd25519FileKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "ed25519ks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	ks, err := sw.NewFileBasedKeyStore(nil, path, false)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", newEd25519KeyStore(ks, &fileKeyStorage{path: path}))
+	require.NoError(t, err)
+
+	key, err := suite.KeyGen(&Ed25519KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(path, hex.EncodeToString(key.SKI())+ed25519KeyFileSuffix))
+	require.NoError(t, err, "Ed25519 key should be stored")
+
+	verifyStoredKey(t, path, nil, key)
+}
+
+func TestEd25519EncryptedKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "ed25519ks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	key, err := suite.KeyGen(&Ed25519KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	raw, err := ioutil.ReadFile(filepath.Join(path, hex.EncodeToString(key.SKI())+ed25519KeyFileSuffix))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), sealedKeyPEMType, "private key should be encrypted at rest")
+
+	verifyStoredKey(t, path, provider, key)
+}
+
+// verifyStoredKey reopens the key store and checks that the stored key signs like the given key
+func verifyStoredKey(t *testing.T, path string, provider KEKProvider, key core.Key) {
+	var ks bccsp.KeyStore
+	var err error
+	if provider != nil {
+		ks, err = NewEncryptedFileKeyStore(path, provider)
+	} else {
+		var fks bccsp.KeyStore
+		fks, err = sw.NewFileBasedKeyStore(nil, path, false)
+		ks = newEd25519KeyStore(fks, &fileKeyStorage{path: path})
+	}
+	require.NoError(t, err)
+
+	suite, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+
+	stored, err := suite.GetKey(key.SKI())
+	require.NoError(t, err)
+	assert.True(t, stored.Private())
+
+	sig, err := suite.Sign(stored, testMsg, nil)
+	require.NoError(t, err)
+	valid, err := suite.Verify(key, sig, testMsg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -0,0 +1,505 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/aesgcm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/fileutil"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KeyStoreEncryptionNone stores the private keys of the keystore in plaintext (the default)
+	KeyStoreEncryptionNone = "none"
+	// KeyStoreEncryptionPassphrase encrypts the private keys of the keystore with a key derived from a passphrase
+	KeyStoreEncryptionPassphrase = "passphrase"
+
+	// keyStoreMetadataFile holds the salt of the keystore and the check value of its KEK. The name
+	// doesn't start with a hex digit so that the file is never mistaken for a key (keys are named <ski>_<suffix>).
+	keyStoreMetadataFile        = ".kek.json"
+	pendingKeyStoreMetadataFile = ".kek.json.pending"
+
+	kekSize  = 32
+	saltSize = 16
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	// sealedKeyPEMType is the PEM type of the key files of an encrypted keystore
+	sealedKeyPEMType = "SEALED KEY"
+
+	keyFileMode = 0600
+)
+
+var (
+	kekCheckLabel   = []byte("fabric-sdk-go keystore KEK")
+	fileKeyLabel    = []byte("fabric-sdk-go keystore file key")
+	errNotSealedKey = errors.New("key is not encrypted with AES-GCM; use ImportPlaintextKeyStore to encrypt the keys of the key store")
+)
+
+// KEKProvider supplies the key-encryption key (KEK) of an encrypted keystore
+type KEKProvider interface {
+	// KEK returns the key-encryption key for the given (random) salt of the keystore. Providers
+	// that derive the key from a secret, such as a passphrase, must use the salt; providers
+	// that retrieve the key from a key management service may ignore it.
+	KEK(salt []byte) ([]byte, error)
+}
+
+// KEKProviderFunc is a function that implements KEKProvider
+type KEKProviderFunc func(salt []byte) ([]byte, error)
+
+// KEK returns the key-encryption key for the given salt
+func (f KEKProviderFunc) KEK(salt []byte) ([]byte, error) {
+	return f(salt)
+}
+
+type passphraseKEKProvider struct {
+	passphrase []byte
+}
+
+// NewPassphraseKEKProvider returns a KEKProvider that derives the key-encryption key from a passphrase with scrypt
+func NewPassphraseKEKProvider(passphrase []byte) (KEKProvider, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("keystore passphrase is empty")
+	}
+	return &passphraseKEKProvider{passphrase: passphrase}, nil
+}
+
+// KEK derives the key-encryption key from the passphrase and the salt
+func (p *passphraseKEKProvider) KEK(salt []byte) ([]byte, error) {
+	kek, err := scrypt.Key(p.passphrase, salt, scryptN, scryptR, scryptP, kekSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key-encryption key")
+	}
+	return kek, nil
+}
+
+// keyStoreMetadata is stored in the keystore directory
+type keyStoreMetadata struct {
+	Salt  []byte `json:"salt"`
+	Check []byte `json:"check"`
+}
+
+// NewEncryptedFileKeyStore returns a file-based keystore at the given path whose keys are encrypted (with AES-GCM)
+// under the key-encryption key of the given provider. The first time the keystore is opened a random salt is
+// generated for it; subsequently the key-encryption key is verified before the keystore is used.
+//
+// Keys that were stored in the directory in plaintext are rejected; use ImportPlaintextKeyStore to encrypt them.
+func NewEncryptedFileKeyStore(path string, provider KEKProvider) (bccsp.KeyStore, error) {
+	kek, err := openKEK(path, provider)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := newKeyFileCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	ks, err := sw.NewFileBasedKeyStoreWithCipher(cipher, path, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize encrypted key store")
+	}
+	return newEd25519KeyStore(ks, &fileKeyStorage{path: path, cipher: cipher}), nil
+}
+
+// ImportPlaintextKeyStore encrypts the keys of the plaintext keystore at srcPath into the encrypted keystore
+// at path (the paths may be the same, in which case the keystore is encrypted in place).
+// Returns the number of imported keys.
+func ImportPlaintextKeyStore(srcPath string, path string, provider KEKProvider) (int, error) {
+	kek, err := openKEK(path, provider)
+	if err != nil {
+		return 0, err
+	}
+	cipher, err := newKeyFileCipher(kek)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = forEachKeyFile(srcPath, func(name string, raw []byte) error {
+		if _, err := cipher.Decrypt(name, raw); err == nil {
+			// Already encrypted
+			return nil
+		}
+		encrypted, err := transcodeKey(name, raw, nil, cipher)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to import key [%s]", name)
+		}
+		if err := fileutil.WriteFileAtomic(filepath.Join(path, name), encrypted, keyFileMode); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// RekeyKeyStore re-encrypts the keys of the encrypted keystore at the given path with the key-encryption
+// key of the next provider (and a new salt). The keystore mustn't be in use while it's re-keyed.
+// If the re-keying is interrupted, calling RekeyKeyStore again with the same providers completes it.
+// Returns the number of re-encrypted keys.
+func RekeyKeyStore(path string, current KEKProvider, next KEKProvider) (int, error) {
+	if current == nil || next == nil {
+		return 0, errors.New("current and next KEK providers are required")
+	}
+
+	metadata, err := readKeyStoreMetadata(filepath.Join(path, keyStoreMetadataFile))
+	if err != nil {
+		return 0, err
+	}
+	if metadata == nil {
+		return 0, errors.Errorf("key store [%s] is not encrypted", path)
+	}
+	currentKEK, err := verifiedKEK(current, metadata)
+	if err != nil {
+		return 0, err
+	}
+	currentCipher, err := newKeyFileCipher(currentKEK)
+	if err != nil {
+		return 0, err
+	}
+
+	// The new salt is persisted before any key is re-encrypted so that an interrupted re-keying
+	// can be completed with the same key-encryption key
+	pendingPath := filepath.Join(path, pendingKeyStoreMetadataFile)
+	pending, err := readKeyStoreMetadata(pendingPath)
+	if err != nil {
+		return 0, err
+	}
+	var nextKEK []byte
+	if pending == nil {
+		pending, nextKEK, err = newKeyStoreMetadata(next)
+		if err != nil {
+			return 0, err
+		}
+		if err := writeKeyStoreMetadata(pendingPath, pending); err != nil {
+			return 0, err
+		}
+	} else if nextKEK, err = verifiedKEK(next, pending); err != nil {
+		return 0, err
+	}
+	nextCipher, err := newKeyFileCipher(nextKEK)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = forEachKeyFile(path, func(name string, raw []byte) error {
+		rekeyed, err := transcodeKey(name, raw, currentCipher, nextCipher)
+		if err != nil {
+			if _, nextErr := nextCipher.Decrypt(name, raw); nextErr == nil {
+				// Already re-keyed
+				return nil
+			}
+			return errors.WithMessagef(err, "failed to re-key key [%s]", name)
+		}
+		if err := fileutil.WriteFileAtomic(filepath.Join(path, name), rekeyed, keyFileMode); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := os.Rename(pendingPath, filepath.Join(path, keyStoreMetadataFile)); err != nil {
+		return count, errors.Wrap(err, "failed to update key store metadata")
+	}
+	return count, nil
+}
+
+// EncryptedKeyFiles reads and writes the key files of encrypted keystores outside of a crypto suite, such
+// as the keystores of the users' MSP directories. The key-encryption key of each keystore directory is
+// derived once.
+type EncryptedKeyFiles struct {
+	provider KEKProvider
+	lock     sync.Mutex
+	ciphers  map[string]*keyFileCipher
+}
+
+// NewEncryptedKeyFiles returns the key files of the keystores that are encrypted under the key-encryption
+// key of the given provider
+func NewEncryptedKeyFiles(provider KEKProvider) (*EncryptedKeyFiles, error) {
+	if provider == nil {
+		return nil, errors.New("KEK provider is required")
+	}
+	return &EncryptedKeyFiles{provider: provider, ciphers: make(map[string]*keyFileCipher)}, nil
+}
+
+// Decrypt returns the plaintext key of the key file at the given path. Keys that aren't encrypted are returned as-is.
+func (f *EncryptedKeyFiles) Decrypt(path string, raw []byte) ([]byte, error) {
+	if block, _ := pem.Decode(raw); block == nil || block.Type != sealedKeyPEMType {
+		return raw, nil
+	}
+
+	cipher, err := f.cipher(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if cipher == nil {
+		return nil, errors.Errorf("failed to decrypt key [%s]: key store metadata not found", path)
+	}
+	return cipher.Decrypt(filepath.Base(path), raw)
+}
+
+// Encrypt returns the content of the key file at the given path for the given plaintext key. The key
+// is encrypted if the keystore directory of the file is encrypted; otherwise it's returned as-is.
+func (f *EncryptedKeyFiles) Encrypt(path string, plaintext []byte) ([]byte, error) {
+	cipher, err := f.cipher(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if cipher == nil {
+		return plaintext, nil
+	}
+	return cipher.Encrypt(filepath.Base(path), plaintext)
+}
+
+// cipher returns the cipher of the keystore at the given path, or nil if the keystore isn't encrypted
+func (f *EncryptedKeyFiles) cipher(path string) (*keyFileCipher, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if cipher, ok := f.ciphers[path]; ok {
+		return cipher, nil
+	}
+
+	metadata, err := readKeyStoreMetadata(filepath.Join(path, keyStoreMetadataFile))
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+	kek, err := verifiedKEK(f.provider, metadata)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to open key store [%s]", path)
+	}
+	cipher, err := newKeyFileCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	f.ciphers[path] = cipher
+	return cipher, nil
+}
+
+// openKEK returns the verified key-encryption key of the keystore at the given path,
+// initializing the keystore's metadata if it doesn't exist
+func openKEK(path string, provider KEKProvider) ([]byte, error) {
+	if provider == nil {
+		return nil, errors.New("KEK provider is required")
+	}
+	if path == "" {
+		return nil, errors.New("key store path is required")
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create key store directory")
+	}
+
+	metadataPath := filepath.Join(path, keyStoreMetadataFile)
+	metadata, err := readKeyStoreMetadata(metadataPath)
+	if err != nil {
+		return nil, err
+	}
+	if metadata != nil {
+		return verifiedKEK(provider, metadata)
+	}
+
+	metadata, kek, err := newKeyStoreMetadata(provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeKeyStoreMetadata(metadataPath, metadata); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Initialized encrypted key store at [%s]", path)
+	return kek, nil
+}
+
+func newKeyStoreMetadata(provider KEKProvider) (*keyStoreMetadata, []byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate salt")
+	}
+	kek, err := provider.KEK(salt)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get key-encryption key")
+	}
+	if len(kek) == 0 {
+		return nil, nil, errors.New("key-encryption key is empty")
+	}
+	return &keyStoreMetadata{Salt: salt, Check: kekCheck(kek)}, kek, nil
+}
+
+func verifiedKEK(provider KEKProvider, metadata *keyStoreMetadata) ([]byte, error) {
+	kek, err := provider.KEK(metadata.Salt)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get key-encryption key")
+	}
+	if !hmac.Equal(kekCheck(kek), metadata.Check) {
+		return nil, errors.New("incorrect key-encryption key for key store")
+	}
+	return kek, nil
+}
+
+// kekCheck returns a value that verifies the key-encryption key without revealing it
+func kekCheck(kek []byte) []byte {
+	mac := hmac.New(sha256.New, kek)
+	mac.Write(kekCheckLabel) // nolint: errcheck
+	return mac.Sum(nil)
+}
+
+func readKeyStoreMetadata(path string) (*keyStoreMetadata, error) {
+	raw, err := ioutil.ReadFile(path) // nolint: gas
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to read key store metadata")
+	}
+	metadata := &keyStoreMetadata{}
+	if err := json.Unmarshal(raw, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to parse key store metadata")
+	}
+	return metadata, nil
+}
+
+func writeKeyStoreMetadata(path string, metadata *keyStoreMetadata) error {
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal key store metadata")
+	}
+	return fileutil.WriteFileAtomic(path, raw, keyFileMode)
+}
+
+// forEachKeyFile calls f with the name and content of each key file in the given directory
+func forEachKeyFile(path string, f func(name string, raw []byte) error) error {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read key store [%s]", path)
+	}
+	for _, file := range files {
+		if file.IsDir() || keyFileType(file.Name()) == "" {
+			continue
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(path, file.Name())) // nolint: gas
+		if err != nil {
+			return errors.Wrapf(err, "failed to read key [%s]", file.Name())
+		}
+		if err := f(file.Name(), raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyFileType returns the type of key stored in the file (named <ski>_<type>) or "" if it's not a key file
+func keyFileType(name string) string {
+	if strings.HasSuffix(name, ed25519KeyFileSuffix) {
+		return "ed25519_sk"
+	}
+	i := strings.LastIndex(name, "_")
+	if i <= 0 {
+		return ""
+	}
+	switch suffix := name[i+1:]; suffix {
+	case "sk", "pk", "key":
+		return suffix
+	default:
+		return ""
+	}
+}
+
+// keyFileCipher encrypts the key files of an encrypted keystore with AES-GCM. The content of each file
+// is bound to its name so that key files can't be swapped.
+type keyFileCipher struct {
+	cipher *aesgcm.Cipher
+}
+
+// newKeyFileCipher returns the cipher for the key files of a keystore with the given key-encryption key
+func newKeyFileCipher(kek []byte) (*keyFileCipher, error) {
+	// The file key is derived from the KEK so that KEKs of any length can be used
+	mac := hmac.New(sha256.New, kek)
+	mac.Write(fileKeyLabel) // nolint: errcheck
+	c, err := aesgcm.New(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &keyFileCipher{cipher: c}, nil
+}
+
+// Encrypt seals the (PEM-encoded) key of the named key file
+func (c *keyFileCipher) Encrypt(name string, plaintext []byte) ([]byte, error) {
+	sealed, err := c.cipher.Seal(plaintext, []byte(name))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to encrypt key [%s]", name)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: sealedKeyPEMType, Bytes: sealed}), nil
+}
+
+// Decrypt opens the key of the named key file
+func (c *keyFileCipher) Decrypt(name string, ciphertext []byte) ([]byte, error) {
+	block, _ := pem.Decode(ciphertext)
+	if block == nil || block.Type != sealedKeyPEMType {
+		return nil, errors.WithMessagef(errNotSealedKey, "failed to decrypt key [%s]", name)
+	}
+	plaintext, err := c.cipher.Open(block.Bytes, []byte(name))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to decrypt key [%s]", name)
+	}
+	return plaintext, nil
+}
+
+// transcodeKey decrypts the key file with the from cipher (nil if the key is in plaintext)
+// and re-encrypts it with the to cipher
+func transcodeKey(name string, raw []byte, from *keyFileCipher, to *keyFileCipher) ([]byte, error) {
+	if from != nil {
+		plaintext, err := from.Decrypt(name, raw)
+		if err != nil {
+			return nil, err
+		}
+		return to.Encrypt(name, plaintext)
+	}
+
+	if err := checkPlaintextKey(name, raw); err != nil {
+		return nil, err
+	}
+	return to.Encrypt(name, raw)
+}
+
+// checkPlaintextKey verifies that the key file holds a valid, unencrypted PEM-encoded key
+func checkPlaintextKey(name string, raw []byte) error {
+	var err error
+	switch keyFileType(name) {
+	case "sk":
+		_, err = utils.PEMtoPrivateKey(raw, nil)
+	case "pk":
+		_, err = utils.PEMtoPublicKey(raw, nil)
+	case "ed25519_sk":
+		_, err = pemToEd25519PrivateKey(raw)
+	case "key":
+		_, err = utils.PEMtoAES(raw, nil)
+	default:
+		err = errors.Errorf("[%s] is not a key file", name)
+	}
+	return err
+}
@@ -0,0 +1,296 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encryptedKeyStoreConfig struct {
+	core.CryptoSuiteConfig
+	encryption    string
+	passphrase    string
+	passphraseErr error
+}
+
+func (c *encryptedKeyStoreConfig) KeyStoreEncryption() string {
+	return c.encryption
+}
+
+func (c *encryptedKeyStoreConfig) KeyStorePassphrase() (string, error) {
+	if c.passphraseErr != nil {
+		return "", c.passphraseErr
+	}
+	return c.passphrase, nil
+}
+
+func TestEncryptedKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+
+	ski := generateKey(t, path, provider)
+
+	raw, err := ioutil.ReadFile(filepath.Join(path, hex.EncodeToString(ski)+"_sk"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), sealedKeyPEMType, "private key should be encrypted at rest")
+	assert.NotContains(t, string(raw), "PRIVATE KEY")
+
+	// Reopen with the same passphrase
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+	key, err := ks.GetKey(ski)
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+
+	// Reopen with a wrong passphrase
+	wrong, err := NewPassphraseKEKProvider([]byte("wrong"))
+	require.NoError(t, err)
+	_, err = NewEncryptedFileKeyStore(path, wrong)
+	assert.EqualError(t, err, "incorrect key-encryption key for key store")
+
+	_, err = NewPassphraseKEKProvider(nil)
+	assert.Error(t, err)
+}
+
+func TestRekeyKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	current, err := NewPassphraseKEKProvider([]byte("current"))
+	require.NoError(t, err)
+	ski := generateKey(t, path, current)
+
+	// A key-encryption key supplied by a provider such as a key management service
+	kek := []byte(strings.Repeat("k", 32))
+	next := KEKProviderFunc(func(salt []byte) ([]byte, error) { return kek, nil })
+
+	n, err := RekeyKeyStore(path, current, next)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = NewEncryptedFileKeyStore(path, current)
+	assert.Error(t, err)
+
+	ks, err := NewEncryptedFileKeyStore(path, next)
+	require.NoError(t, err)
+	_, err = ks.GetKey(ski)
+	require.NoError(t, err)
+
+	_, err = RekeyKeyStore(path, current, next)
+	assert.Error(t, err, "current KEK is no longer valid")
+
+	_, err = os.Stat(filepath.Join(path, pendingKeyStoreMetadataFile))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestImportPlaintextKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	// Generate a key in a plaintext keystore
+	plainKS, err := sw.NewFileBasedKeyStore(nil, path, false)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", plainKS)
+	require.NoError(t, err)
+	key, err := suite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	skFile := filepath.Join(path, hex.EncodeToString(key.SKI())+"_sk")
+	raw, err := ioutil.ReadFile(skFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "ENCRYPTED")
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+
+	// Encrypt the keystore in place
+	n, err := ImportPlaintextKeyStore(path, path, provider)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	raw, err = ioutil.ReadFile(skFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), sealedKeyPEMType)
+
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+	_, err = ks.GetKey(key.SKI())
+	require.NoError(t, err)
+
+	// Importing again skips the keys that are already encrypted
+	n, err = ImportPlaintextKeyStore(path, path, provider)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestEncryptedKeyStoreRejectsPlaintextKeys(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+
+	// A plaintext key copied into the encrypted keystore
+	plainKS, err := sw.NewFileBasedKeyStore(nil, path, false)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", plainKS)
+	require.NoError(t, err)
+	key, err := suite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	_, err = ks.GetKey(key.SKI())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ImportPlaintextKeyStore")
+
+	// A sealed key that was renamed (swapped with another key file) fails authentication
+	ski := generateKey(t, path, provider)
+	raw, err := ioutil.ReadFile(filepath.Join(path, hex.EncodeToString(ski)+"_sk"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, hex.EncodeToString(key.SKI())+"_sk"), raw, 0600))
+	_, err = ks.GetKey(key.SKI())
+	assert.Error(t, err)
+}
+
+func TestRekeyKeyStoreEd25519(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+	key, err := suite.KeyGen(&Ed25519KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	next := KEKProviderFunc(func(salt []byte) ([]byte, error) { return []byte("next"), nil })
+	n, err := RekeyKeyStore(path, provider, next)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	ks, err = NewEncryptedFileKeyStore(path, next)
+	require.NoError(t, err)
+	loaded, err := ks.GetKey(key.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, key.SKI(), loaded.SKI())
+}
+
+func TestCryptoSuiteByConfigWithEncryptedKeyStore(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SHA2").AnyTimes()
+	mockConfig.EXPECT().SecurityLevel().Return(256).AnyTimes()
+	mockConfig.EXPECT().KeyStorePath().Return(path).AnyTimes()
+
+	c, err := GetSuiteByConfig(&encryptedKeyStoreConfig{CryptoSuiteConfig: mockConfig, encryption: "passphrase", passphrase: "secret"})
+	require.NoError(t, err)
+	verifyHashFn(t, c)
+
+	_, err = os.Stat(filepath.Join(path, keyStoreMetadataFile))
+	assert.NoError(t, err, "encrypted key store should be initialized")
+
+	_, err = GetSuiteByConfig(&encryptedKeyStoreConfig{CryptoSuiteConfig: mockConfig, encryption: "passphrase"})
+	assert.Error(t, err, "passphrase is required")
+
+	_, err = GetSuiteByConfig(&encryptedKeyStoreConfig{CryptoSuiteConfig: mockConfig, encryption: "passphrase", passphraseErr: errors.New("secret not found")})
+	assert.EqualError(t, err, "secret not found", "passphrase resolution error should be returned")
+
+	_, err = GetSuiteByConfig(&encryptedKeyStoreConfig{CryptoSuiteConfig: mockConfig, encryption: "rot13"})
+	assert.Error(t, err)
+}
+
+func TestEncryptedKeyFiles(t *testing.T) {
+	path, err := ioutil.TempDir("", "encryptedks")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(path, hex.EncodeToString(generateKey(t, path, provider))+"_sk")
+	raw, err := ioutil.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	files, err := NewEncryptedKeyFiles(provider)
+	require.NoError(t, err)
+
+	plaintext, err := files.Decrypt(keyFile, raw)
+	require.NoError(t, err)
+	_, err = utils.PEMtoPrivateKey(plaintext, nil)
+	require.NoError(t, err, "decrypted key should be a PEM-encoded private key")
+
+	encrypted, err := files.Encrypt(keyFile, plaintext)
+	require.NoError(t, err)
+	assert.Contains(t, string(encrypted), sealedKeyPEMType, "keys of an encrypted key store should be encrypted")
+	decrypted, err := files.Decrypt(keyFile, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// Keys that aren't encrypted are returned as-is
+	decrypted, err = files.Decrypt(keyFile, plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	plaintextDir, err := ioutil.TempDir("", "plaintextks")
+	require.NoError(t, err)
+	defer os.RemoveAll(plaintextDir)
+	stored, err := files.Encrypt(filepath.Join(plaintextDir, "abcd_sk"), plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, stored, "keys of a plaintext key store should not be encrypted")
+	_, err = files.Decrypt(filepath.Join(plaintextDir, "abcd_sk"), raw)
+	assert.Error(t, err, "encrypted key without key store metadata should fail")
+
+	wrongProvider, err := NewPassphraseKEKProvider([]byte("wrong"))
+	require.NoError(t, err)
+	wrongFiles, err := NewEncryptedKeyFiles(wrongProvider)
+	require.NoError(t, err)
+	_, err = wrongFiles.Decrypt(keyFile, raw)
+	assert.Error(t, err)
+}
+
+func generateKey(t *testing.T, path string, provider KEKProvider) []byte {
+	ks, err := NewEncryptedFileKeyStore(path, provider)
+	require.NoError(t, err)
+	suite, err := GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+	key, err := suite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	return key.SKI()
+}
@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// NewKVKeyStore returns a keystore that stores the PEM-encoded keys in the given key-value store,
+// keyed by the name of the key (<ski>_<type>, like the files of the file-based keystore).
+// For example, the SQL key store of the msp package keeps the keys in a database.
+func NewKVKeyStore(store core.KVStore) (bccsp.KeyStore, error) {
+	if store == nil {
+		return nil, errors.New("key-value store is required")
+	}
+	storage := &kvKeyStorage{store: store}
+	ks, err := sw.NewStorageKeyStore(storage, false)
+	if err != nil {
+		return nil, err
+	}
+	return newEd25519KeyStore(ks, storage), nil
+}
+
+// kvKeyStorage stores the keys in a key-value store
+type kvKeyStorage struct {
+	store core.KVStore
+}
+
+// Load returns the named key
+func (s *kvKeyStorage) Load(name string) ([]byte, error) {
+	value, err := s.store.Load(name)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, sw.ErrKeyNotFound
+		}
+		return nil, errors.WithMessagef(err, "failed to load key [%s]", name)
+	}
+	raw, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("key [%s] is not of proper type", name)
+	}
+	return raw, nil
+}
+
+// Store stores the named key
+func (s *kvKeyStorage) Store(name string, raw []byte) error {
+	return s.store.Store(name, raw)
+}
@@ -8,8 +8,10 @@ package pkcs11
 
 import (
 	"fmt"
-
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkpatch/cachebridge"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -23,6 +25,13 @@ var logger = logging.NewLogger("fabsdk/core")
 var ctxCache *lazycache.Cache
 var once sync.Once
 var errSlotIDChanged = fmt.Errorf("slot id changed")
+var errDeviceFailure = fmt.Errorf("PKCS11 device failure")
+
+//session states (CKS_*) in which the user is logged in
+const (
+	cksROUserFunctions = 1
+	cksRWUserFunctions = 3
+)
 
 //LoadPKCS11ContextHandle loads PKCS11 context handler instance from underlying cache
 func LoadPKCS11ContextHandle(lib, label, pin string, opts ...Options) (*ContextHandle, error) {
@@ -35,9 +44,9 @@ func ReloadPKCS11ContextHandle(lib, label, pin string, opts ...Options) (*Contex
 }
 
 //LoadContextAndLogin loads Context handle and performs login
-func LoadContextAndLogin(lib, pin, label string) (*ContextHandle, error) {
+func LoadContextAndLogin(lib, pin, label string, opts ...Options) (*ContextHandle, error) {
 	logger.Debugf("Loading context and performing login for [%s-%s]", lib, label)
-	pkcs11Context, err := LoadPKCS11ContextHandle(lib, label, pin)
+	pkcs11Context, err := LoadPKCS11ContextHandle(lib, label, pin, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +69,10 @@ func LoadContextAndLogin(lib, pin, label string) (*ContextHandle, error) {
 
 //ContextHandle encapsulate basic mPkcs11.Ctx operations and manages sessions
 type ContextHandle struct {
+	//signatures and reloads are first to be 64-bit aligned for atomic operations
+	signatures uint64
+	reloads    uint64
+
 	ctx                *mPkcs11.Ctx
 	slot               uint
 	pin                string
@@ -69,6 +82,33 @@ type ContextHandle struct {
 	opts               ctxOpts
 	reloadNotification chan struct{}
 	lock               sync.RWMutex
+	done               chan struct{}
+
+	//openSessions number of sessions opened by the handle which are still open, either in use or in the pool
+	openSessions int32
+	unhealthy    int32
+	statsLock    sync.Mutex
+	errorCounts  map[string]uint64
+}
+
+//Stats is a snapshot of the state and the activity of a PKCS11 token
+type Stats struct {
+	//Label of the token
+	Label string
+	//Slot of the token
+	Slot uint
+	//Healthy is false if the last health check of the token failed
+	Healthy bool
+	//SessionsInUse number of open sessions which aren't in the session pool
+	SessionsInUse int
+	//IdleSessions number of sessions in the session pool
+	IdleSessions int
+	//Signatures number of successful signing operations
+	Signatures uint64
+	//Reloads number of times the PKCS11 context was reloaded and the slot of the token re-discovered
+	Reloads uint64
+	//Errors number of errors returned by the token, by CKR code
+	Errors map[string]uint64
 }
 
 // NotifyCtxReload registers a channel to get notification when underlying mPkcs11.Ctx is recreated
@@ -76,13 +116,157 @@ func (handle *ContextHandle) NotifyCtxReload(ch chan struct{}) {
 	handle.reloadNotification = ch
 }
 
+//Stats returns a snapshot of the state and the activity of the token
+func (handle *ContextHandle) Stats() Stats {
+	handle.lock.RLock()
+	slot := handle.slot
+	idle := len(handle.sessions)
+	handle.lock.RUnlock()
+
+	inUse := int(atomic.LoadInt32(&handle.openSessions)) - idle
+	if inUse < 0 {
+		inUse = 0
+	}
+
+	handle.statsLock.Lock()
+	errs := make(map[string]uint64, len(handle.errorCounts))
+	for code, count := range handle.errorCounts {
+		errs[code] = count
+	}
+	handle.statsLock.Unlock()
+
+	return Stats{
+		Label:         handle.label,
+		Slot:          slot,
+		Healthy:       handle.Healthy(),
+		SessionsInUse: inUse,
+		IdleSessions:  idle,
+		Signatures:    atomic.LoadUint64(&handle.signatures),
+		Reloads:       atomic.LoadUint64(&handle.reloads),
+		Errors:        errs,
+	}
+}
+
+//Healthy returns false if the last health check of the token failed, or if the token failed
+//since its last successful health check or operation
+func (handle *ContextHandle) Healthy() bool {
+	return atomic.LoadInt32(&handle.unhealthy) == 0
+}
+
+//HealthCheck checks that the token is still present in its slot and that the user is logged in.
+//If the check fails then the slot of the token is re-discovered and the PKCS11 context is reloaded,
+//which recovers from a failover of the HSM. An error is returned if the token is still unavailable.
+func (handle *ContextHandle) HealthCheck() error {
+	err := handle.probe()
+	if err == nil {
+		handle.setHealthy(true)
+		return nil
+	}
+
+	logger.Warnf("Health check of token [%s] failed [%s], re-discovering slot and reloading pkcs11 ctx", handle.label, err)
+	handle.recordError(err)
+
+	handle.lock.Lock()
+	session, err := handle.reLogin()
+	handle.lock.Unlock()
+	if err != nil {
+		handle.setHealthy(false)
+		return errors.WithMessagef(err, "token [%s] is unavailable", handle.label)
+	}
+
+	handle.ReturnSession(session)
+	handle.setHealthy(true)
+	return nil
+}
+
+//probe checks the token in its slot and the login state of a session
+func (handle *ContextHandle) probe() error {
+	handle.lock.RLock()
+	info, err := handle.ctx.GetTokenInfo(handle.slot)
+	handle.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+	if info.Label != handle.label {
+		return errSlotIDChanged
+	}
+
+	session, err := handle.OpenSession()
+	if err != nil {
+		return err
+	}
+	defer handle.ReturnSession(session)
+
+	handle.lock.RLock()
+	sessionInfo, err := handle.ctx.GetSessionInfo(session)
+	handle.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+	if handle.pin != "" && sessionInfo.State != cksROUserFunctions && sessionInfo.State != cksRWUserFunctions {
+		return mPkcs11.Error(mPkcs11.CKR_USER_NOT_LOGGED_IN)
+	}
+	return nil
+}
+
+//monitorHealth checks the health of the token at the given interval until the handle is finalized
+func (handle *ContextHandle) monitorHealth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := handle.HealthCheck(); err != nil {
+				logger.Warnf("Health check failed: %s", err)
+			}
+		case <-handle.done:
+			return
+		}
+	}
+}
+
+func (handle *ContextHandle) setHealthy(healthy bool) {
+	if healthy {
+		atomic.StoreInt32(&handle.unhealthy, 0)
+	} else {
+		atomic.StoreInt32(&handle.unhealthy, 1)
+	}
+}
+
+//recordError counts the errors returned by the token by CKR code, other errors are ignored.
+//The token is marked as unhealthy if the error is a failure of the device, so that the other
+//tokens of its group are tried first even if health checks are disabled
+func (handle *ContextHandle) recordError(err error) {
+	code, ok := errorCode(err)
+	if !ok {
+		return
+	}
+	if isDeviceFailure(err) {
+		handle.setHealthy(false)
+	}
+
+	handle.statsLock.Lock()
+	if handle.errorCounts == nil {
+		handle.errorCounts = make(map[string]uint64)
+	}
+	handle.errorCounts[code]++
+	handle.statsLock.Unlock()
+}
+
 //OpenSession opens a session between an application and a token.
 func (handle *ContextHandle) OpenSession() (mPkcs11.SessionHandle, error) {
 
 	handle.lock.RLock()
 	defer handle.lock.RUnlock()
 
-	return handle.ctx.OpenSession(handle.slot, mPkcs11.CKF_SERIAL_SESSION|mPkcs11.CKF_RW_SESSION)
+	session, err := handle.ctx.OpenSession(handle.slot, mPkcs11.CKF_SERIAL_SESSION|mPkcs11.CKF_RW_SESSION)
+	if err != nil {
+		handle.recordError(err)
+		return session, err
+	}
+	atomic.AddInt32(&handle.openSessions, 1)
+	return session, nil
 }
 
 // Login logs a user into a token
@@ -96,6 +280,7 @@ func (handle *ContextHandle) Login(session mPkcs11.SessionHandle) error {
 	}
 	err := handle.ctx.Login(session, mPkcs11.CKU_USER, handle.pin)
 	if err != nil && err != mPkcs11.Error(mPkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		handle.recordError(err)
 		return errors.Errorf("Login failed [%s]", err)
 	}
 	return nil
@@ -117,11 +302,13 @@ func (handle *ContextHandle) ReturnSession(session mPkcs11.SessionHandle) {
 	_, e = handle.ctx.GetSessionInfo(session)
 	if e != nil {
 		logger.Warnf("not returning session [%d], due to error [%s]. Discarding it", session, e)
+		handle.recordError(e)
 		e = handle.ctx.CloseSession(session)
 		if e != nil {
 			logger.Warn("unable to close session:", e)
 		}
 		cachebridge.ClearSession(fmt.Sprintf("%d", session))
+		handle.sessionClosed()
 		return
 	}
 
@@ -137,13 +324,35 @@ func (handle *ContextHandle) ReturnSession(session mPkcs11.SessionHandle) {
 		if e != nil {
 			logger.Warn("unable to close session: ", e)
 		}
+		handle.sessionClosed()
+	}
+}
+
+//sessionClosed updates the number of open sessions after a session was closed
+//care should be taken since handle.lock should be locked before calling this function
+func (handle *ContextHandle) sessionClosed() {
+	if atomic.AddInt32(&handle.openSessions, -1) < 0 {
+		// session was opened by a previous pkcs11 ctx
+		atomic.StoreInt32(&handle.openSessions, 0)
 	}
 }
 
 //GetSession returns session from session pool
 //if pool is empty or completely in use, creates new session
 //if new session is invalid recreates one after reloading ctx and re-login
-func (handle *ContextHandle) GetSession() (session mPkcs11.SessionHandle) {
+//an invalid (zero) session is returned if no session could be recovered
+func (handle *ContextHandle) GetSession() mPkcs11.SessionHandle {
+	session, err := handle.getSession(handle.opts.openSessionRetry, false)
+	if err != nil {
+		logger.Warnf("Failed to get session of token [%s]: %s", handle.label, err)
+	}
+	return session
+}
+
+//getSession is like GetSession, but returns an error if no valid session could be recovered after the given
+//number of re-login attempts. The device failures on which GetSession panics (CKR_DEVICE_REMOVED and
+//CKR_DEVICE_MEMORY) are returned as errors if failover is true (see TokenGroup)
+func (handle *ContextHandle) getSession(retries int, failover bool) (session mPkcs11.SessionHandle, err error) {
 	handle.lock.RLock()
 	select {
 	case session = <-handle.sessions:
@@ -155,10 +364,10 @@ func (handle *ContextHandle) GetSession() (session mPkcs11.SessionHandle) {
 		// cache is empty (or completely in use), create a new session
 		s, err := handle.OpenSession()
 		if err != nil {
-			logger.Debugf("opening a new session failed [%v], will retry %d times", err, handle.opts.openSessionRetry)
+			logger.Debugf("opening a new session failed [%v], will retry %d times", err, retries)
 			handle.lock.Lock()
 			defer handle.lock.Unlock()
-			for i := 0; i < handle.opts.openSessionRetry; i++ {
+			for i := 0; i < retries; i++ {
 				logger.Debugf("Trying re-login and open session attempt[%v]", i+1)
 				s, err = handle.reLogin()
 				if err != nil {
@@ -167,17 +376,17 @@ func (handle *ContextHandle) GetSession() (session mPkcs11.SessionHandle) {
 				} else {
 					logger.Debugf("Successfully able to re-login and open session[%d], attempt[%d], clearing cache now for new session", s, i+1)
 					cachebridge.ClearSession(fmt.Sprintf("%d", s))
-					return s
+					return s, nil
 				}
 			}
 			logger.Debugf("Exhausted all attempts to recover session, failed with error [%s], returning 0 session", err)
-			return s
+			return 0, errors.WithMessagef(err, "failed to open session with token [%s]", handle.label)
 		}
 		logger.Debugf("Created new pkcs11 session %+v on slot %d", s, handle.slot)
 		session = s
 		cachebridge.ClearSession(fmt.Sprintf("%d", session))
 	}
-	return handle.validateSession(session)
+	return handle.validateSession(session, failover)
 }
 
 // GetAttributeValue obtains the value of one or more object attributes.
@@ -209,7 +418,9 @@ func (handle *ContextHandle) GenerateKeyPair(session mPkcs11.SessionHandle, m []
 		return 0, 0, errors.Wrap(err, "failed to generate key pair")
 	}
 
-	return handle.ctx.GenerateKeyPair(session, m, public, private)
+	pub, priv, err := handle.ctx.GenerateKeyPair(session, m, public, private)
+	handle.recordError(err)
+	return pub, priv, err
 }
 
 //GenerateKey generates a secret key, creating a new key object.
@@ -292,7 +503,9 @@ func (handle *ContextHandle) SignInit(session mPkcs11.SessionHandle, m []*mPkcs1
 	handle.lock.RLock()
 	defer handle.lock.RUnlock()
 
-	return handle.ctx.SignInit(session, m, o)
+	err := handle.ctx.SignInit(session, m, o)
+	handle.recordError(err)
+	return err
 }
 
 // Sign signs (encrypts with private key) data in a single part, where the signature
@@ -301,7 +514,13 @@ func (handle *ContextHandle) Sign(session mPkcs11.SessionHandle, message []byte)
 	handle.lock.RLock()
 	defer handle.lock.RUnlock()
 
-	return handle.ctx.Sign(session, message)
+	signature, err := handle.ctx.Sign(session, message)
+	if err != nil {
+		handle.recordError(err)
+		return nil, err
+	}
+	atomic.AddUint64(&handle.signatures, 1)
+	return signature, nil
 }
 
 // VerifyInit initializes a verification operation, where the
@@ -311,7 +530,9 @@ func (handle *ContextHandle) VerifyInit(session mPkcs11.SessionHandle, m []*mPkc
 	handle.lock.RLock()
 	defer handle.lock.RUnlock()
 
-	return handle.ctx.VerifyInit(session, m, key)
+	err := handle.ctx.VerifyInit(session, m, key)
+	handle.recordError(err)
+	return err
 }
 
 // Verify verifies a signature in a single-part operation,
@@ -321,7 +542,11 @@ func (handle *ContextHandle) Verify(session mPkcs11.SessionHandle, data []byte,
 	handle.lock.RLock()
 	defer handle.lock.RUnlock()
 
-	return handle.ctx.Verify(session, data, signature)
+	err := handle.ctx.Verify(session, data, signature)
+	if err != mPkcs11.Error(mPkcs11.CKR_SIGNATURE_INVALID) {
+		handle.recordError(err)
+	}
+	return err
 }
 
 // CreateObject creates a new object.
@@ -363,8 +588,8 @@ func (handle *ContextHandle) FindKeyPairFromSKI(session mPkcs11.SessionHandle, s
 
 //validateSession validates given session
 //if session is invalid recreates one after reloading ctx and re-login
-//care should be taken since handle.lock should be read locked before calling this function
-func (handle *ContextHandle) validateSession(currentSession mPkcs11.SessionHandle) mPkcs11.SessionHandle {
+//panics on device failures, unless failover is true
+func (handle *ContextHandle) validateSession(currentSession mPkcs11.SessionHandle, failover bool) (mPkcs11.SessionHandle, error) {
 
 	handle.lock.RLock()
 
@@ -381,6 +606,7 @@ func (handle *ContextHandle) validateSession(currentSession mPkcs11.SessionHandl
 		mPkcs11.Error(mPkcs11.CKR_USER_NOT_LOGGED_IN):
 
 		logger.Warnf("Found error condition [%s], attempting to recreate pkcs11 context and re-login....", e)
+		handle.recordError(e)
 
 		handle.lock.RUnlock()
 		handle.lock.Lock()
@@ -389,27 +615,40 @@ func (handle *ContextHandle) validateSession(currentSession mPkcs11.SessionHandl
 		newSession, err := handle.reLogin()
 		if err != nil {
 			logger.Warnf("Re-login Failed : %s,", err)
+			return 0, errors.WithMessagef(err, "failed to recover session of token [%s] from [%s]", handle.label, e)
 		}
-		return newSession
+		return newSession, nil
 
 	case mPkcs11.Error(mPkcs11.CKR_DEVICE_MEMORY),
 		mPkcs11.Error(mPkcs11.CKR_DEVICE_REMOVED):
+		handle.recordError(e)
 		handle.lock.RUnlock()
-		panic(fmt.Sprintf("PKCS11 Session failure: [%s]", e))
+		if !failover {
+			panic(fmt.Sprintf("PKCS11 Session failure: [%s]", e))
+		}
+		return 0, errors.Wrapf(errDeviceFailure, "PKCS11 Session failure of token [%s]: [%s]", handle.label, e)
 
 	default:
 		handle.lock.RUnlock()
 		// default should be a valid session or valid error, return session as it is
-		return currentSession
+		return currentSession, nil
 	}
 }
 
 // reLogin destroys pkcs11 context and tries to re-login and returns new session
+// the token is marked as unhealthy if it fails
 // Note: this function isn't thread safe, recommended to use write lock for calling this function
 func (handle *ContextHandle) reLogin() (mPkcs11.SessionHandle, error) {
+	session, err := handle.recreateSession()
+	handle.setHealthy(err == nil)
+	return session, err
+}
+
+func (handle *ContextHandle) recreateSession() (mPkcs11.SessionHandle, error) {
 
 	// dispose existing pkcs11 ctx
 	handle.disposePKCS11Ctx()
+	atomic.StoreInt32(&handle.openSessions, 0)
 
 	// create new context
 	newCtx := handle.createNewPKCS11Ctx()
@@ -439,12 +678,15 @@ func (handle *ContextHandle) reLogin() (mPkcs11.SessionHandle, error) {
 	err = handle.ctx.Login(newSession, mPkcs11.CKU_USER, handle.pin)
 	if err != nil && err != mPkcs11.Error(mPkcs11.CKR_USER_ALREADY_LOGGED_IN) {
 		logger.Warnf("Unable to login with new session :%d", newSession)
+		handle.recordError(err)
 		return 0, errors.Errorf("unable to login with new session :%d", newSession)
 	}
 
 	handle.sendNotification()
 	handle.slot = slot
 	handle.sessions = make(chan mPkcs11.SessionHandle, handle.opts.sessionCacheSize)
+	atomic.StoreInt32(&handle.openSessions, 1)
+	atomic.AddUint64(&handle.reloads, 1)
 
 	logger.Infof("Able to login with recreated session successfully")
 	return newSession, nil
@@ -578,7 +820,7 @@ type pkcs11CtxCacheKey struct {
 
 //String return string value for pkcs11CtxCacheKey
 func (key *pkcs11CtxCacheKey) String() string {
-	return fmt.Sprintf("%x_%s_%s_%d_%d", key.lib, key.label, key.opts.connectionName, key.opts.sessionCacheSize, key.opts.openSessionRetry)
+	return fmt.Sprintf("%x_%s_%s_%d_%d_%s", key.lib, key.label, key.opts.connectionName, key.opts.sessionCacheSize, key.opts.openSessionRetry, key.opts.healthCheckInterval)
 }
 
 //getInstance loads ContextHandle instance from cache
@@ -618,6 +860,9 @@ func finalizer() lazyref.Finalizer {
 	return func(v interface{}) {
 		if handle, ok := v.(*ContextHandle); ok {
 			logger.Debugf("Finalizing pkcs11 ctx for [%s, %s]", handle.lib, handle.label)
+			if handle.done != nil {
+				close(handle.done)
+			}
 			err := handle.ctx.CloseAllSessions(handle.slot)
 			if err != nil {
 				logger.Warnf("unable to close all sessions in finalizer for [%s, %s] : %s", handle.lib, handle.label, err)
@@ -675,7 +920,11 @@ func loadLibInitializer() lazycache.EntryInitializer {
 			return &ContextHandle{}, errors.Errorf("Could not find token with label %s", ctxKey.label)
 		}
 		sessions := make(chan mPkcs11.SessionHandle, ctxKey.opts.sessionCacheSize)
-		return &ContextHandle{ctx: ctx, slot: slot, pin: ctxKey.pin, lib: ctxKey.lib, label: ctxKey.label, sessions: sessions, opts: ctxKey.opts}, nil
+		handle := &ContextHandle{ctx: ctx, slot: slot, pin: ctxKey.pin, lib: ctxKey.lib, label: ctxKey.label, sessions: sessions, opts: ctxKey.opts, done: make(chan struct{})}
+		if ctxKey.opts.healthCheckInterval > 0 {
+			go handle.monitorHealth(ctxKey.opts.healthCheckInterval)
+		}
+		return handle, nil
 	}
 }
 
@@ -687,3 +936,31 @@ func isEmpty(session mPkcs11.SessionHandle) error {
 	}
 	return errors.New("invalid session detected")
 }
+
+//isDeviceFailure returns true if the error is a failure of the device of the token
+func isDeviceFailure(err error) bool {
+	switch errors.Cause(err) {
+	case errDeviceFailure,
+		mPkcs11.Error(mPkcs11.CKR_DEVICE_ERROR),
+		mPkcs11.Error(mPkcs11.CKR_DEVICE_MEMORY),
+		mPkcs11.Error(mPkcs11.CKR_DEVICE_REMOVED),
+		mPkcs11.Error(mPkcs11.CKR_TOKEN_NOT_PRESENT):
+		return true
+	default:
+		return false
+	}
+}
+
+//errorCode returns the CKR code of a PKCS11 error, for example CKR_DEVICE_ERROR
+func errorCode(err error) (string, bool) {
+	e, ok := errors.Cause(err).(mPkcs11.Error)
+	if !ok {
+		return "", false
+	}
+
+	code := strings.TrimPrefix(e.Error(), fmt.Sprintf("pkcs11: 0x%X: ", uint(e)))
+	if code == "" {
+		code = fmt.Sprintf("0x%X", uint(e))
+	}
+	return code, true
+}
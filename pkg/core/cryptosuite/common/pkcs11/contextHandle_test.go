@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	mPkcs11 "github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -603,6 +604,260 @@ func TestGetSessionResilience(t *testing.T) {
 
 }
 
+func TestContextHandleStats(t *testing.T) {
+	handle, err := LoadContextAndLogin(lib, pin, label, WithConnectionName("stats"), WithSessionCacheSize(2))
+	assert.NoError(t, err)
+	assert.NotNil(t, handle)
+
+	stats := handle.Stats()
+	assert.Equal(t, label, stats.Label)
+	assert.True(t, stats.Healthy)
+	assert.Equal(t, 0, stats.SessionsInUse)
+	assert.Equal(t, 1, stats.IdleSessions)
+
+	session1 := handle.GetSession()
+	session2 := handle.GetSession()
+	session3 := handle.GetSession()
+	stats = handle.Stats()
+	assert.Equal(t, 3, stats.SessionsInUse)
+	assert.Equal(t, 0, stats.IdleSessions)
+
+	//pool size is 2, third session is closed
+	handle.ReturnSession(session1)
+	handle.ReturnSession(session2)
+	handle.ReturnSession(session3)
+	stats = handle.Stats()
+	assert.Equal(t, 0, stats.SessionsInUse)
+	assert.Equal(t, 2, stats.IdleSessions)
+
+	//errors are counted by CKR code
+	err = handle.SignInit(mPkcs11.SessionHandle(9999), []*mPkcs11.Mechanism{mPkcs11.NewMechanism(mPkcs11.CKM_ECDSA, nil)}, 0)
+	assert.Error(t, err)
+	_, err = handle.Sign(mPkcs11.SessionHandle(9999), []byte("digest"))
+	assert.Error(t, err)
+	stats = handle.Stats()
+	assert.Equal(t, uint64(2), stats.Errors["CKR_SESSION_HANDLE_INVALID"])
+	assert.Equal(t, uint64(0), stats.Signatures)
+
+	//snapshot is a copy
+	stats.Errors["CKR_SESSION_HANDLE_INVALID"] = 0
+	assert.Equal(t, uint64(2), handle.Stats().Errors["CKR_SESSION_HANDLE_INVALID"])
+}
+
+func TestContextHandleHealthCheck(t *testing.T) {
+	handle, err := LoadContextAndLogin(lib, pin, label, WithConnectionName("healthcheck"))
+	assert.NoError(t, err)
+	assert.NotNil(t, handle)
+
+	assert.NoError(t, handle.HealthCheck())
+	assert.True(t, handle.Healthy())
+	assert.Equal(t, uint64(0), handle.Stats().Reloads)
+
+	//token moved to another slot (e.g. failover of HSM), slot is re-discovered
+	slot := handle.slot
+	handle.lock.Lock()
+	handle.slot = 8888
+	handle.lock.Unlock()
+
+	assert.NoError(t, handle.HealthCheck())
+	assert.True(t, handle.Healthy())
+	assert.Equal(t, slot, handle.Stats().Slot)
+	assert.Equal(t, uint64(1), handle.Stats().Reloads)
+	assert.Equal(t, uint64(1), handle.Stats().Errors["CKR_SLOT_ID_INVALID"])
+
+	//token not available, health check fails
+	handle.lock.Lock()
+	labelBackup := handle.label
+	handle.label = "unknown"
+	handle.lock.Unlock()
+
+	assert.Error(t, handle.HealthCheck())
+	assert.False(t, handle.Healthy())
+
+	handle.lock.Lock()
+	handle.label = labelBackup
+	handle.lock.Unlock()
+
+	assert.NoError(t, handle.HealthCheck())
+	assert.True(t, handle.Healthy())
+
+	session := handle.GetSession()
+	assert.NoError(t, isEmpty(session))
+	handle.ReturnSession(session)
+}
+
+func TestContextHandleHealthMonitor(t *testing.T) {
+	handle, err := LoadContextAndLogin(lib, pin, label, WithConnectionName("healthmonitor"), WithHealthCheckInterval(50*time.Millisecond))
+	assert.NoError(t, err)
+	assert.NotNil(t, handle)
+
+	slot := handle.slot
+	handle.lock.Lock()
+	handle.slot = 8888
+	handle.lock.Unlock()
+
+	//slot is re-discovered by the health checks
+	deadline := time.Now().Add(ctxReloadTimeout)
+	for handle.Stats().Slot != slot {
+		if time.Now().After(deadline) {
+			t.Fatal("slot wasn't re-discovered by health checks")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, handle.Healthy())
+
+	session := handle.GetSession()
+	assert.NoError(t, isEmpty(session))
+	handle.ReturnSession(session)
+}
+
+func TestTokenGroupWithTokens(t *testing.T) {
+	handle1, err := LoadContextAndLogin(lib, pin, label1, WithConnectionName("group"))
+	assert.NoError(t, err)
+	handle2, err := LoadContextAndLogin(lib, pin, label2, WithConnectionName("group"))
+	assert.NoError(t, err)
+
+	group := NewTokenGroup(handle1, handle2)
+	assert.Equal(t, []*ContextHandle{handle1, handle2}, group.Handles())
+
+	var labels []string
+	openSession := func(handle *ContextHandle, session mPkcs11.SessionHandle) error {
+		labels = append(labels, handle.label)
+		return isEmpty(session)
+	}
+
+	assert.NoError(t, group.Do(openSession))
+	assert.Equal(t, []string{label1}, labels)
+
+	//unhealthy tokens are used last
+	handle1.setHealthy(false)
+	defer handle1.setHealthy(true)
+
+	labels = nil
+	assert.NoError(t, group.Do(openSession))
+	assert.Equal(t, []string{label2}, labels)
+}
+
+func TestTokenGroup(t *testing.T) {
+	handle1 := &ContextHandle{label: "token1"}
+	handle2 := &ContextHandle{label: "token2"}
+	handle3 := &ContextHandle{label: "token3"}
+	group := NewTokenGroup(handle1, handle2, handle3)
+
+	var labels []string
+	failWith := func(failing ...string) func(handle *ContextHandle) error {
+		return func(handle *ContextHandle) error {
+			labels = append(labels, handle.label)
+			for _, l := range failing {
+				if handle.label == l {
+					return errors.Errorf("%s failed", l)
+				}
+			}
+			return nil
+		}
+	}
+
+	//first token that succeeds
+	assert.NoError(t, group.try(failWith("token1")))
+	assert.Equal(t, []string{"token1", "token2"}, labels)
+
+	//healthy tokens first
+	handle1.setHealthy(false)
+	labels = nil
+	assert.NoError(t, group.try(failWith("token2")))
+	assert.Equal(t, []string{"token2", "token3"}, labels)
+
+	//error of last token
+	labels = nil
+	err := group.try(failWith("token1", "token2", "token3"))
+	assert.EqualError(t, err, "token1 failed")
+	assert.Equal(t, []string{"token2", "token3", "token1"}, labels)
+
+	//unhealthy token is healthy again once it succeeds
+	labels = nil
+	assert.NoError(t, group.try(failWith("token2", "token3")))
+	assert.Equal(t, []string{"token2", "token3", "token1"}, labels)
+	assert.True(t, handle1.Healthy())
+
+	assert.Error(t, NewTokenGroup().try(failWith()))
+}
+
+func TestTokenGroupMetrics(t *testing.T) {
+	handle := &ContextHandle{label: "token1"}
+	group := NewTokenGroup(handle)
+	otherGroup := NewTokenGroup(handle)
+
+	m := &mockMetrics{errors: make(map[string]int)}
+	group.SetMetrics(m)
+
+	fail := func(*ContextHandle) error {
+		err := errors.Wrap(mPkcs11.Error(mPkcs11.CKR_DEVICE_REMOVED), "sign failed")
+		handle.recordError(err)
+		return err
+	}
+
+	assert.Error(t, group.try(fail))
+	assert.False(t, m.healthy)
+	assert.Equal(t, map[string]int{"CKR_DEVICE_REMOVED": 1}, m.errors)
+
+	//errors of the token with other groups are recorded too, once
+	assert.Error(t, otherGroup.try(fail))
+	assert.NoError(t, group.try(func(*ContextHandle) error { return nil }))
+	assert.True(t, m.healthy)
+	assert.Equal(t, map[string]int{"CKR_DEVICE_REMOVED": 2}, m.errors)
+}
+
+func TestDeviceFailureMarksTokenUnhealthy(t *testing.T) {
+	handle := &ContextHandle{label: "token1"}
+
+	handle.recordError(mPkcs11.Error(mPkcs11.CKR_SIGNATURE_INVALID))
+	assert.True(t, handle.Healthy())
+
+	handle.recordError(errors.Wrap(mPkcs11.Error(mPkcs11.CKR_DEVICE_REMOVED), "sign failed"))
+	assert.False(t, handle.Healthy())
+	assert.Equal(t, map[string]uint64{"CKR_SIGNATURE_INVALID": 1, "CKR_DEVICE_REMOVED": 1}, handle.Stats().Errors)
+
+	assert.True(t, isDeviceFailure(errors.Wrap(errDeviceFailure, "session failure")))
+	assert.False(t, isDeviceFailure(errors.New("some error")))
+}
+
+type mockMetrics struct {
+	healthy bool
+	errors  map[string]int
+}
+
+func (m *mockMetrics) SetSessions(string, int, int)       {}
+func (m *mockMetrics) ObserveSign(string, float64, error) {}
+func (m *mockMetrics) AddReload(string)                   {}
+
+func (m *mockMetrics) AddError(token, code string) {
+	m.errors[code]++
+}
+
+func (m *mockMetrics) SetHealthy(token string, healthy bool) {
+	m.healthy = healthy
+}
+
+func TestErrorCode(t *testing.T) {
+	code, ok := errorCode(mPkcs11.Error(mPkcs11.CKR_DEVICE_ERROR))
+	assert.True(t, ok)
+	assert.Equal(t, "CKR_DEVICE_ERROR", code)
+
+	code, ok = errorCode(errors.Wrap(mPkcs11.Error(mPkcs11.CKR_SESSION_CLOSED), "sign failed"))
+	assert.True(t, ok)
+	assert.Equal(t, "CKR_SESSION_CLOSED", code)
+
+	code, ok = errorCode(mPkcs11.Error(0x8000ABCD))
+	assert.True(t, ok)
+	assert.Equal(t, "0x8000ABCD", code)
+
+	_, ok = errorCode(errors.New("some error"))
+	assert.False(t, ok)
+
+	_, ok = errorCode(nil)
+	assert.False(t, ok)
+}
+
 func TestMain(m *testing.M) {
 
 	possibilities := strings.Split(allLibs, ",")
@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+// Metrics records the session pools, the signing latency and the errors of PKCS11 tokens, by token label.
+// It is an interface literal with builtin types only, so that it can be implemented by packages which
+// don't depend on PKCS11 (for example the SDK metrics)
+type Metrics = interface {
+	//SetSessions records the number of open sessions of the token which are in use and idle
+	SetSessions(token string, inUse, idle int)
+	//ObserveSign records the duration in seconds and the outcome of a signing operation
+	ObserveSign(token string, seconds float64, err error)
+	//AddError counts an error returned by the token by CKR code
+	AddError(token, code string)
+	//SetHealthy records the health of the token
+	SetHealthy(token string, healthy bool)
+	//AddReload counts a reload of the PKCS11 context of the token
+	AddReload(token string)
+}
+
+// noopMetrics is used until metrics are set
+type noopMetrics struct{}
+
+func (noopMetrics) SetSessions(string, int, int)       {}
+func (noopMetrics) ObserveSign(string, float64, error) {}
+func (noopMetrics) AddError(string, string)            {}
+func (noopMetrics) SetHealthy(string, bool)            {}
+func (noopMetrics) AddReload(string)                   {}
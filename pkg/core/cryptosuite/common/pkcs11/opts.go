@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package pkcs11
 
+import "time"
+
 const (
 	defaultSessionCacheSize = 10
 	defaultOpenSessionRetry = 10
@@ -19,6 +21,8 @@ type ctxOpts struct {
 	openSessionRetry int
 	//connectionName do maintain unique instances in cache for connections under same label and lib
 	connectionName string
+	//healthCheckInterval interval of the health checks of the token, health checks are disabled if zero
+	healthCheckInterval time.Duration
 }
 
 //Options for PKCS11 ContextHandle
@@ -62,3 +66,12 @@ func WithConnectionName(name string) Options {
 		o.connectionName = name
 	}
 }
+
+//WithHealthCheckInterval interval of the health checks of the token. If a health check fails, the slot of the token
+//is re-discovered and the PKCS11 context is reloaded, for example after a failover of the HSM.
+//Health checks are disabled by default
+func WithHealthCheckInterval(interval time.Duration) Options {
+	return func(o *ctxOpts) {
+		o.healthCheckInterval = interval
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"sync"
+	"time"
+
+	mPkcs11 "github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+//TokenGroup is a group of PKCS11 tokens which hold the same keys, for example the tokens of HSMs
+//in a failover configuration. Operations are performed with the first token of the group that succeeds,
+//healthy tokens are tried before the tokens which failed their last health check or operation.
+//Device failures of the tokens of a group of several tokens are returned as errors instead of panics.
+//The context handles of the tokens are shared (see LoadContextAndLogin), so the group keeps its own
+//failover behaviour and metrics rather than setting them on the handles.
+type TokenGroup struct {
+	handles []*ContextHandle
+
+	metricsLock sync.Mutex
+	metrics     Metrics
+	//published are the stats of the tokens that were last recorded in the metrics
+	published map[*ContextHandle]Stats
+}
+
+//NewTokenGroup returns a group of the given tokens, in order of preference
+func NewTokenGroup(handles ...*ContextHandle) *TokenGroup {
+	return &TokenGroup{handles: handles}
+}
+
+//Handles returns the context handles of the tokens of the group
+func (g *TokenGroup) Handles() []*ContextHandle {
+	return g.handles
+}
+
+//SetMetrics sets the metrics that record the sessions, the signing latency and the errors of the tokens.
+//The metrics are updated from the stats of the tokens (see ContextHandle.Stats) after each operation of the group.
+func (g *TokenGroup) SetMetrics(m Metrics) {
+	g.metricsLock.Lock()
+	defer g.metricsLock.Unlock()
+
+	g.metrics = m
+	g.published = make(map[*ContextHandle]Stats)
+}
+
+//Do performs the operation with a session of the tokens of the group until it succeeds.
+//The error of the last token is returned if the operation failed with all tokens.
+//An unhealthy token is marked as healthy again once an operation succeeds with it.
+func (g *TokenGroup) Do(operation func(handle *ContextHandle, session mPkcs11.SessionHandle) error) error {
+	return g.try(func(handle *ContextHandle) error {
+		return g.withSession(handle, operation)
+	})
+}
+
+//Sign is like Do, and records the latency of the signing operation in the metrics
+func (g *TokenGroup) Sign(operation func(handle *ContextHandle, session mPkcs11.SessionHandle) error) error {
+	return g.try(func(handle *ContextHandle) error {
+		return g.withSession(handle, func(handle *ContextHandle, session mPkcs11.SessionHandle) error {
+			start := time.Now()
+			err := operation(handle, session)
+			g.getMetrics().ObserveSign(handle.label, time.Since(start).Seconds(), err)
+			return err
+		})
+	})
+}
+
+//try performs the operation with the tokens of the group until it succeeds
+func (g *TokenGroup) try(operation func(handle *ContextHandle) error) error {
+	if len(g.handles) == 0 {
+		return errors.New("no PKCS11 tokens in group")
+	}
+
+	var err error
+	for _, handle := range g.ordered() {
+		err = operation(handle)
+		if err == nil && !handle.Healthy() {
+			handle.setHealthy(true)
+		}
+		g.publishMetrics(handle)
+		if err == nil {
+			return nil
+		}
+		if len(g.handles) > 1 {
+			logger.Debugf("PKCS11 operation failed with token [%s]: %s", handle.label, err)
+		}
+	}
+	return err
+}
+
+//withSession performs the operation with a session of the token, which is returned to the session pool afterwards
+func (g *TokenGroup) withSession(handle *ContextHandle, operation func(handle *ContextHandle, session mPkcs11.SessionHandle) error) error {
+	failover := len(g.handles) > 1
+	retries := handle.opts.openSessionRetry
+	if failover && retries > 1 {
+		// the other tokens of the group are tried, don't hold the write lock of the token for all the retries
+		retries = 1
+	}
+
+	session, err := handle.getSession(retries, failover)
+	if err != nil {
+		return err
+	}
+	defer handle.ReturnSession(session)
+
+	return operation(handle, session)
+}
+
+//ordered returns the healthy tokens followed by the unhealthy tokens
+func (g *TokenGroup) ordered() []*ContextHandle {
+	if len(g.handles) == 1 {
+		return g.handles
+	}
+
+	ordered := make([]*ContextHandle, 0, len(g.handles))
+	var unhealthy []*ContextHandle
+	for _, handle := range g.handles {
+		if handle.Healthy() {
+			ordered = append(ordered, handle)
+		} else {
+			unhealthy = append(unhealthy, handle)
+		}
+	}
+	return append(ordered, unhealthy...)
+}
+
+func (g *TokenGroup) getMetrics() Metrics {
+	g.metricsLock.Lock()
+	defer g.metricsLock.Unlock()
+
+	if g.metrics == nil {
+		return noopMetrics{}
+	}
+	return g.metrics
+}
+
+//publishMetrics records the state of the token and the errors and reloads of the token since they were last recorded
+func (g *TokenGroup) publishMetrics(handle *ContextHandle) {
+	g.metricsLock.Lock()
+	defer g.metricsLock.Unlock()
+
+	if g.metrics == nil {
+		return
+	}
+
+	stats := handle.Stats()
+	last := g.published[handle]
+	g.metrics.SetSessions(stats.Label, stats.SessionsInUse, stats.IdleSessions)
+	g.metrics.SetHealthy(stats.Label, stats.Healthy)
+	for code, count := range stats.Errors {
+		for i := last.Errors[code]; i < count; i++ {
+			g.metrics.AddError(stats.Label, code)
+		}
+	}
+	for i := last.Reloads; i < stats.Reloads; i++ {
+		g.metrics.AddReload(stats.Label)
+	}
+	g.published[handle] = stats
+}
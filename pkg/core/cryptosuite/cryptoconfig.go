@@ -10,10 +10,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 )
 
@@ -101,8 +104,14 @@ func (c *Config) SecurityProviderLibPath() string {
 }
 
 //SecurityProviderPin will be set only if provider is PKCS11
+//The PIN may be a secret reference (see package secret), which is resolved when the PIN is requested
 func (c *Config) SecurityProviderPin() string {
-	return c.backend.GetString("client.BCCSP.security.pin")
+	pin, err := secret.ResolveString(c.backend.GetString("client.BCCSP.security.pin"))
+	if err != nil {
+		logger.Errorf("Failed to resolve security provider PIN: %s", err)
+		return ""
+	}
+	return pin
 }
 
 //SecurityProviderLabel will be set only if provider is PKCS11
@@ -110,6 +119,100 @@ func (c *Config) SecurityProviderLabel() string {
 	return c.backend.GetString("client.BCCSP.security.label")
 }
 
+// PKCS11TokenConfig is the configuration of an additional PKCS11 token which holds the same keys
+// as the token of the security provider, usually on another HSM
+type PKCS11TokenConfig struct {
+	// Library defaults to the library of the security provider
+	Library string
+	Label   string
+	// Pin defaults to the PIN of the security provider. It may be a secret reference
+	Pin string
+	// SessionCacheSize defaults to the session cache size of the security provider
+	SessionCacheSize int
+}
+
+// SecurityProviderSessionCacheSize returns the number of idle sessions kept in the session pool
+// of each PKCS11 token (zero for the default)
+func (c *Config) SecurityProviderSessionCacheSize() int {
+	return c.backend.GetInt("client.BCCSP.security.pkcs11.sessionCacheSize")
+}
+
+// SecurityProviderHealthCheckInterval returns the interval of the health checks of the PKCS11 tokens
+// (zero if health checks are disabled)
+func (c *Config) SecurityProviderHealthCheckInterval() time.Duration {
+	return c.backend.GetDuration("client.BCCSP.security.pkcs11.healthCheckInterval")
+}
+
+// SecurityProviderFailoverTokens returns the PKCS11 tokens which hold the same keys as the token of the
+// security provider. Signing is performed with any healthy token. An error is returned if the tokens
+// can't be parsed or if the PIN of a token can't be resolved
+func (c *Config) SecurityProviderFailoverTokens() ([]PKCS11TokenConfig, error) {
+	var tokens []PKCS11TokenConfig
+	if err := c.backend.UnmarshalKey("client.BCCSP.security.pkcs11.failoverTokens", &tokens); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse PKCS11 failover tokens")
+	}
+
+	for i, token := range tokens {
+		pin, err := secret.ResolveString(token.Pin)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to resolve PIN of PKCS11 failover token [%s]", token.Label)
+		}
+		tokens[i].Pin = pin
+		tokens[i].Library = pathvar.Subst(token.Library)
+	}
+	return tokens, nil
+}
+
+// KeyStoreEncryption returns the encryption of the private keys in the SW keystore: "none" (the default) or "passphrase"
+func (c *Config) KeyStoreEncryption() string {
+	return strings.ToLower(c.backend.GetString("client.BCCSP.security.keyStore.encryption"))
+}
+
+// KeyStorePassphrase returns the passphrase from which the key-encryption key of the SW keystore is derived.
+// The passphrase may be a secret reference (see package secret), which is resolved when the passphrase is requested
+func (c *Config) KeyStorePassphrase() (string, error) {
+	passphrase, err := secret.ResolveString(c.backend.GetString("client.BCCSP.security.keyStore.passphrase"))
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to resolve key store passphrase")
+	}
+	return passphrase, nil
+}
+
+// RemoteSignerURL returns the URL of the signing service. It will be set only if provider is remote
+func (c *Config) RemoteSignerURL() string {
+	return c.backend.GetString("client.BCCSP.security.remote.url")
+}
+
+// RemoteSignerTimeout returns the timeout of each request to the signing service (zero for the default)
+func (c *Config) RemoteSignerTimeout() time.Duration {
+	return c.backend.GetDuration("client.BCCSP.security.remote.timeout")
+}
+
+// RemoteSignerRetryAttempts returns the number of times a failed request to the signing service
+// is retried (negative for the default)
+func (c *Config) RemoteSignerRetryAttempts() int {
+	val, ok := c.backend.Lookup("client.BCCSP.security.remote.retry.attempts")
+	if !ok {
+		return -1
+	}
+	return cast.ToInt(val)
+}
+
+// RemoteSignerTLSCACertPath returns the path of the CA certificates of the signing service
+func (c *Config) RemoteSignerTLSCACertPath() string {
+	return pathvar.Subst(c.backend.GetString("client.BCCSP.security.remote.tlsCerts.ca.path"))
+}
+
+// RemoteSignerTLSClientCertPath returns the path of the client certificate for mutual TLS with the signing service
+func (c *Config) RemoteSignerTLSClientCertPath() string {
+	return pathvar.Subst(c.backend.GetString("client.BCCSP.security.remote.tlsCerts.client.cert.path"))
+}
+
+// RemoteSignerTLSClientKeyPath returns the path of the client key for mutual TLS with the signing service
+func (c *Config) RemoteSignerTLSClientKeyPath() string {
+	return pathvar.Subst(c.backend.GetString("client.BCCSP.security.remote.tlsCerts.client.key.path"))
+}
+
 // KeyStorePath returns the keystore path used by BCCSP
 func (c *Config) KeyStorePath() string {
 	keystorePath := pathvar.Subst(c.backend.GetString("client.credentialStore.cryptoStore.path"))
@@ -13,6 +13,7 @@ import (
 	"os"
 
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
@@ -297,6 +298,90 @@ func TestCryptoConfigWithMultipleBackends(t *testing.T) {
 	assert.Equal(t, cryptoConfig.SecurityProviderLabel(), "TESTLABEL")
 }
 
+func TestCryptoConfigKeyStoreEncryption(t *testing.T) {
+	os.Setenv("TEST_KEYSTORE_PASSPHRASE", "secret")
+	defer os.Unsetenv("TEST_KEYSTORE_PASSPHRASE")
+
+	backendMap := make(map[string]interface{})
+	backendMap["client.BCCSP.security.keyStore.encryption"] = "Passphrase"
+	backendMap["client.BCCSP.security.keyStore.passphrase"] = "env://TEST_KEYSTORE_PASSPHRASE"
+	cryptoConfig := ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap}).(*Config)
+
+	assert.Equal(t, "passphrase", cryptoConfig.KeyStoreEncryption())
+	passphrase, err := cryptoConfig.KeyStorePassphrase()
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", passphrase)
+
+	backendMap["client.BCCSP.security.keyStore.passphrase"] = "env://TEST_KEYSTORE_UNDEFINED"
+	cryptoConfig = ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap}).(*Config)
+	_, err = cryptoConfig.KeyStorePassphrase()
+	assert.Error(t, err, "resolution errors should be returned")
+
+	cryptoConfig = ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: map[string]interface{}{}}).(*Config)
+	assert.Equal(t, "", cryptoConfig.KeyStoreEncryption())
+}
+
+func TestCryptoConfigRemoteSigner(t *testing.T) {
+	backendMap := make(map[string]interface{})
+	backendMap["client.BCCSP.security.remote.url"] = "https://signer.example.com:8443"
+	backendMap["client.BCCSP.security.remote.timeout"] = "3s"
+	backendMap["client.BCCSP.security.remote.retry.attempts"] = 0
+	backendMap["client.BCCSP.security.remote.tlsCerts.ca.path"] = "/tmp/ca.pem"
+	backendMap["client.BCCSP.security.remote.tlsCerts.client.cert.path"] = "/tmp/cert.pem"
+	backendMap["client.BCCSP.security.remote.tlsCerts.client.key.path"] = "/tmp/key.pem"
+	cryptoConfig := ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap}).(*Config)
+
+	assert.Equal(t, "https://signer.example.com:8443", cryptoConfig.RemoteSignerURL())
+	assert.Equal(t, 3*time.Second, cryptoConfig.RemoteSignerTimeout())
+	assert.Equal(t, 0, cryptoConfig.RemoteSignerRetryAttempts())
+	assert.Equal(t, "/tmp/ca.pem", cryptoConfig.RemoteSignerTLSCACertPath())
+	assert.Equal(t, "/tmp/cert.pem", cryptoConfig.RemoteSignerTLSClientCertPath())
+	assert.Equal(t, "/tmp/key.pem", cryptoConfig.RemoteSignerTLSClientKeyPath())
+
+	cryptoConfig = ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: map[string]interface{}{}}).(*Config)
+	assert.Equal(t, time.Duration(0), cryptoConfig.RemoteSignerTimeout())
+	assert.Equal(t, -1, cryptoConfig.RemoteSignerRetryAttempts(), "default retries should be used")
+}
+
+func TestCryptoConfigPKCS11Tokens(t *testing.T) {
+	os.Setenv("TEST_PKCS11_PIN", "5678")
+	defer os.Unsetenv("TEST_PKCS11_PIN")
+
+	backendMap := make(map[string]interface{})
+	backendMap["client.BCCSP.security.pkcs11.sessionCacheSize"] = 20
+	backendMap["client.BCCSP.security.pkcs11.healthCheckInterval"] = "30s"
+	backendMap["client.BCCSP.security.pkcs11.failoverTokens"] = []interface{}{
+		map[string]interface{}{"label": "ForFabric2"},
+		map[string]interface{}{"label": "ForFabric3", "library": "/tmp/lib.so", "pin": "env://TEST_PKCS11_PIN", "sessionCacheSize": 5},
+	}
+	cryptoConfig := ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap}).(*Config)
+
+	assert.Equal(t, 20, cryptoConfig.SecurityProviderSessionCacheSize())
+	assert.Equal(t, 30*time.Second, cryptoConfig.SecurityProviderHealthCheckInterval())
+	tokens, err := cryptoConfig.SecurityProviderFailoverTokens()
+	assert.NoError(t, err)
+	assert.Equal(t, []PKCS11TokenConfig{
+		{Label: "ForFabric2"},
+		{Label: "ForFabric3", Library: "/tmp/lib.so", Pin: "5678", SessionCacheSize: 5},
+	}, tokens)
+
+	cryptoConfig = ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: map[string]interface{}{}}).(*Config)
+	assert.Equal(t, 0, cryptoConfig.SecurityProviderSessionCacheSize())
+	assert.Equal(t, time.Duration(0), cryptoConfig.SecurityProviderHealthCheckInterval())
+	tokens, err = cryptoConfig.SecurityProviderFailoverTokens()
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	// a PIN which can't be resolved is an error, rather than an empty PIN
+	backendMap["client.BCCSP.security.pkcs11.failoverTokens"] = []interface{}{
+		map[string]interface{}{"label": "ForFabric2", "pin": "env://TEST_PKCS11_MISSING_PIN"},
+	}
+	cryptoConfig = ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap}).(*Config)
+	_, err = cryptoConfig.SecurityProviderFailoverTokens()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ForFabric2")
+}
+
 //getCustomBackend returns custom backend to override config values and to avoid using new config file for test scenarios
 func getCustomBackend(configBackend ...core.ConfigBackend) *mocks.MockConfigBackend {
 	backendMap := make(map[string]interface{})
@@ -78,6 +78,11 @@ func GetSHA256Opts() core.HashOpts {
 	return &bccsp.SHA256Opts{}
 }
 
+//GetSHA384Opts returns options relating to SHA-384.
+func GetSHA384Opts() core.HashOpts {
+	return &bccsp.SHA384Opts{}
+}
+
 //GetSHAOpts returns options for computing SHA.
 func GetSHAOpts() core.HashOpts {
 	return &bccsp.SHAOpts{}
@@ -87,3 +92,18 @@ func GetSHAOpts() core.HashOpts {
 func GetECDSAP256KeyGenOpts(ephemeral bool) core.KeyGenOpts {
 	return &bccsp.ECDSAP256KeyGenOpts{Temporary: ephemeral}
 }
+
+//GetECDSAP384KeyGenOpts returns options for ECDSA key generation with curve P-384.
+func GetECDSAP384KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return &bccsp.ECDSAP384KeyGenOpts{Temporary: ephemeral}
+}
+
+//GetEd25519KeyGenOpts returns options for Ed25519 key generation.
+func GetEd25519KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return &sw.Ed25519KeyGenOpts{Temporary: ephemeral}
+}
+
+//GetEd25519PrivateKeyImportOpts returns options for importing Ed25519 private keys in PKCS#8 DER format.
+func GetEd25519PrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
+	return &sw.Ed25519PrivateKeyImportOpts{Temporary: ephemeral}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+// Key algorithms returned by KeyAlgorithm
+const (
+	KeyAlgorithmUnknown   = ""
+	KeyAlgorithmECDSAP256 = "ECDSA_P256"
+	KeyAlgorithmECDSAP384 = "ECDSA_P384"
+	KeyAlgorithmEd25519   = "ED25519"
+	KeyAlgorithmRSA       = "RSA"
+)
+
+// keyAlgorithms caches the algorithms of the keys by SKI (signing keys are long-lived)
+var keyAlgorithms sync.Map
+
+// KeyAlgorithm returns the algorithm of the given (public or private) asymmetric key,
+// or KeyAlgorithmUnknown if it can't be determined
+func KeyAlgorithm(key core.Key) string {
+	if key == nil {
+		return KeyAlgorithmUnknown
+	}
+
+	ski := string(key.SKI())
+	if algorithm, ok := keyAlgorithms.Load(ski); ok {
+		return algorithm.(string)
+	}
+
+	algorithm := keyAlgorithm(key)
+	if algorithm != KeyAlgorithmUnknown && ski != "" {
+		keyAlgorithms.Store(ski, algorithm)
+	}
+	return algorithm
+}
+
+func keyAlgorithm(key core.Key) string {
+	pubKey, err := key.PublicKey()
+	if err != nil || pubKey == nil {
+		return KeyAlgorithmUnknown
+	}
+	raw, err := pubKey.Bytes()
+	if err != nil {
+		return KeyAlgorithmUnknown
+	}
+	pub, err := x509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		return KeyAlgorithmUnknown
+	}
+
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384
+		}
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA
+	}
+	return KeyAlgorithmUnknown
+}
+
+// signatureHashOpts holds the signature hashes that identities opted into, by SKI (see SetSignatureHashOpts)
+var signatureHashOpts sync.Map
+
+// SetSignatureHashOpts makes the signing manager sign the hash of the given options with the given
+// (identity's) key, rather than the hash of the crypto suite. For example, an identity with an ECDSA
+// P-384 key may opt into SHA-384 (GetSHA384Opts) if the MSPs that verify its signatures use SHA-384.
+// Nil options restore the hash of the crypto suite.
+func SetSignatureHashOpts(key core.Key, opts core.HashOpts) {
+	if opts == nil {
+		signatureHashOpts.Delete(string(key.SKI()))
+		return
+	}
+	signatureHashOpts.Store(string(key.SKI()), opts)
+}
+
+// GetSignatureHashOpts returns the options of the hash that's signed with the given key.
+// Returns nil for Ed25519 keys, which sign the message itself. Other keys (including ECDSA P-384)
+// sign the hash of the crypto suite (GetSHAOpts), which is the hash that MSPs verify against,
+// unless the identity of the key opted into another hash (see SetSignatureHashOpts).
+func GetSignatureHashOpts(key core.Key) core.HashOpts {
+	if KeyAlgorithm(key) == KeyAlgorithmEd25519 {
+		return nil
+	}
+	if opts, ok := signatureHashOpts.Load(string(key.SKI())); ok {
+		return opts.(core.HashOpts)
+	}
+	return GetSHAOpts()
+}
@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyAlgorithm(t *testing.T) {
+	suite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	tests := []struct {
+		opts      core.KeyGenOpts
+		algorithm string
+		hashOpts  core.HashOpts
+	}{
+		{GetECDSAP256KeyGenOpts(true), KeyAlgorithmECDSAP256, GetSHAOpts()},
+		{GetECDSAP384KeyGenOpts(true), KeyAlgorithmECDSAP384, GetSHAOpts()},
+		{GetEd25519KeyGenOpts(true), KeyAlgorithmEd25519, nil},
+	}
+
+	for _, tc := range tests {
+		key, err := suite.KeyGen(tc.opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.algorithm, KeyAlgorithm(key))
+		assert.Equal(t, tc.algorithm, KeyAlgorithm(key), "cached algorithm should be returned")
+
+		pubKey, err := key.PublicKey()
+		require.NoError(t, err)
+		assert.Equal(t, tc.algorithm, KeyAlgorithm(pubKey))
+
+		assert.Equal(t, tc.hashOpts, GetSignatureHashOpts(key))
+	}
+
+	assert.Equal(t, KeyAlgorithmUnknown, KeyAlgorithm(nil))
+}
+
+func TestSetSignatureHashOpts(t *testing.T) {
+	suite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	key, err := suite.KeyGen(GetECDSAP384KeyGenOpts(true))
+	require.NoError(t, err)
+	other, err := suite.KeyGen(GetECDSAP384KeyGenOpts(true))
+	require.NoError(t, err)
+
+	SetSignatureHashOpts(key, GetSHA384Opts())
+	assert.Equal(t, GetSHA384Opts(), GetSignatureHashOpts(key))
+	assert.Equal(t, GetSHAOpts(), GetSignatureHashOpts(other), "other identities should sign the hash of the crypto suite")
+
+	SetSignatureHashOpts(key, nil)
+	assert.Equal(t, GetSHAOpts(), GetSignatureHashOpts(key))
+}
@@ -63,6 +63,22 @@ type Logger interface {
 	Errorln(args ...interface{})
 }
 
+// Field is a key-value pair that is attached to a structured log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// StructuredLogger is implemented by loggers that emit log entries as a message along with
+// key-value fields (for example, as JSON) rather than as an interpolated string. Loggers that
+// do not implement StructuredLogger receive the fields appended to the message.
+type StructuredLogger interface {
+	Logger
+
+	// LogFields logs the message along with the given fields at the given level
+	LogFields(level Level, msg string, fields ...Field)
+}
+
 // LoggerProvider is a factory for module loggers
 // TODO: should this be renamed to LoggerFactory?
 type LoggerProvider interface {
@@ -432,7 +432,7 @@ func (l *Log) getCallerInfo(opts *loggerOpts) string {
 		return ""
 	}
 
-	const MAXCALLERS = 6  // search MAXCALLERS frames for the real caller
+	const MAXCALLERS = 10 // search MAXCALLERS frames for the real caller (structured logging adds frames)
 	const SKIPCALLERS = 3 // skip SKIPCALLERS frames when determining the real caller
 	const NOTFOUND = "n/a"
 
@@ -35,6 +35,28 @@ func TestDefaultLoggingWithCallerInfo(t *testing.T) {
 	testDefaultLogging(t)
 }
 
+func TestCallerInfoThroughWrappers(t *testing.T) {
+	ShowCallerInfo(moduleName, api.INFO)
+	defer HideCallerInfo(moduleName, api.INFO)
+
+	logger := LoggerProvider().GetLogger(moduleName).(*Log)
+	var out bytes.Buffer
+	logger.ChangeOutput(&out)
+
+	//wrappers such as the structured logging helpers add frames between the caller and the logger
+	logger.infoThroughWrappers(5, "brown fox jumps over the lazy dog")
+	assert.Contains(t, out.String(), "- modlog.TestCallerInfoThroughWrappers ")
+}
+
+//go:noinline
+func (l *Log) infoThroughWrappers(depth int, msg string) {
+	if depth == 0 {
+		l.Info(msg)
+		return
+	}
+	l.infoThroughWrappers(depth-1, msg)
+}
+
 func testDefaultLogging(t *testing.T) {
 
 	logger := LoggerProvider().GetLogger(moduleName)
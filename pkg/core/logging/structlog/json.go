@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package structlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/metadata"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+)
+
+// Keys of the standard fields of a JSON log entry
+const (
+	TimeKey    = "ts"
+	LevelKey   = "level"
+	ModuleKey  = "module"
+	MessageKey = "msg"
+)
+
+// NewJSONProvider returns a logger provider which writes each log entry to the given writer
+// as a JSON object on a single line. The object contains the time, level, module and message of
+// the entry followed by its fields. Log levels are those set with logging.SetLevel.
+// If out is nil then entries are written to stdout.
+func NewJSONProvider(out io.Writer) api.LoggerProvider {
+	if out == nil {
+		out = os.Stdout
+	}
+
+	encoder := &jsonEncoder{out: out}
+	return ProviderFunc(func(module string) Sink {
+		return SinkFunc(func(level api.Level, msg string, fields ...api.Field) {
+			if !modlog.IsEnabledFor(module, level) {
+				return
+			}
+			encoder.encode(module, level, msg, fields)
+		})
+	})
+}
+
+type jsonEncoder struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func (e *jsonEncoder) encode(module string, level api.Level, msg string, fields []api.Field) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	writeJSONField(&buf, TimeKey, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(',')
+	writeJSONField(&buf, LevelKey, strings.ToLower(metadata.ParseString(level)))
+	buf.WriteByte(',')
+	writeJSONField(&buf, ModuleKey, module)
+	buf.WriteByte(',')
+	writeJSONField(&buf, MessageKey, msg)
+	for _, f := range fields {
+		buf.WriteByte(',')
+		writeJSONField(&buf, f.Key, f.Value)
+	}
+	buf.WriteString("}\n")
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if _, err := e.out.Write(buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing log entry: %s\n", err)
+	}
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	buf.Write(marshal(key))
+	buf.WriteByte(':')
+	buf.Write(marshal(value))
+}
+
+func marshal(value interface{}) []byte {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value)) //nolint: errcheck
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package structlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+)
+
+// LevelCritical is the slog level of critical entries (logged before the SDK exits or panics)
+const LevelCritical = slog.LevelError + 4
+
+// NewSlogProvider returns a logger provider which logs to the given slog logger. If logger is
+// nil then the default slog logger is used. The module is added to each entry as the "module"
+// attribute. Entries are logged if their level is enabled both with logging.SetLevel and by
+// the slog handler.
+func NewSlogProvider(logger *slog.Logger) api.LoggerProvider {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return ProviderFunc(func(module string) Sink {
+		moduleLogger := logger.With(ModuleKey, module)
+		return SinkFunc(func(level api.Level, msg string, fields ...api.Field) {
+			if !modlog.IsEnabledFor(module, level) {
+				return
+			}
+
+			ctx := context.Background()
+			slogLevel := toSlogLevel(level)
+			if !moduleLogger.Enabled(ctx, slogLevel) {
+				return
+			}
+
+			attrs := make([]slog.Attr, len(fields))
+			for i, f := range fields {
+				attrs[i] = slog.Any(f.Key, f.Value)
+			}
+			moduleLogger.LogAttrs(ctx, slogLevel, msg, attrs...)
+		})
+	})
+}
+
+func toSlogLevel(level api.Level) slog.Level {
+	switch level {
+	case api.DEBUG:
+		return slog.LevelDebug
+	case api.INFO:
+		return slog.LevelInfo
+	case api.WARNING:
+		return slog.LevelWarn
+	case api.ERROR:
+		return slog.LevelError
+	default:
+		return LevelCritical
+	}
+}
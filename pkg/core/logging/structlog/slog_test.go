@@ -0,0 +1,48 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package structlog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogProvider(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := NewSlogProvider(slog.New(handler)).GetLogger(testModule)
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String(), "debug entries should not be logged at info level")
+
+	logger.(api.StructuredLogger).LogFields(api.WARNING, "endorsement failed", api.Field{Key: "peer", Value: "peer0"})
+
+	entry := decodeEntry(t, buf.String())
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, testModule, entry[ModuleKey])
+	assert.Equal(t, "endorsement failed", entry["msg"])
+	assert.Equal(t, "peer0", entry["peer"])
+	buf.Reset()
+
+	logger.Errorf("failed %d times", 3)
+	assert.Equal(t, "ERROR", decodeEntry(t, buf.String())["level"])
+	buf.Reset()
+
+	modlog.SetLevel(testModule, api.WARNING)
+	defer modlog.SetLevel(testModule, api.INFO)
+
+	logger.Info("hidden")
+	assert.Empty(t, buf.String(), "info entries should not be logged if the module level is warning")
+}
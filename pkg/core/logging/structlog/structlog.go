@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package structlog provides structured logger providers that emit log entries as a message
+// along with key-value fields (such as txID, channel and peer). The providers may be passed
+// to the SDK using fabsdk.WithLoggerPkg.
+//
+//  Basic Flow:
+//  1) Create a provider (NewJSONProvider, NewZapProvider or NewSlogProvider)
+//  2) Pass the provider to fabsdk.New using fabsdk.WithLoggerPkg
+//  3) Attach fields to a context using logging.WithFields
+package structlog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+)
+
+// Sink writes structured log entries
+type Sink interface {
+	// LogFields writes the message along with the given fields at the given level
+	LogFields(level api.Level, msg string, fields ...api.Field)
+}
+
+// SinkFunc is a function that implements Sink
+type SinkFunc func(level api.Level, msg string, fields ...api.Field)
+
+// LogFields invokes the function
+func (f SinkFunc) LogFields(level api.Level, msg string, fields ...api.Field) {
+	f(level, msg, fields...)
+}
+
+// Logger implements api.StructuredLogger on top of a Sink. The printf-style functions of
+// api.Logger are logged as a message without fields.
+type Logger struct {
+	sink Sink
+}
+
+// NewLogger returns a new Logger which writes to the given sink
+func NewLogger(sink Sink) *Logger {
+	return &Logger{sink: sink}
+}
+
+// LogFields logs the message along with the given fields at the given level
+func (l *Logger) LogFields(level api.Level, msg string, fields ...api.Field) {
+	l.sink.LogFields(level, msg, fields...)
+}
+
+// Fatal is CRITICAL log followed by a call to os.Exit(1).
+func (l *Logger) Fatal(v ...interface{}) {
+	l.sink.LogFields(api.CRITICAL, fmt.Sprint(v...))
+	os.Exit(1)
+}
+
+// Fatalf is CRITICAL log formatted followed by a call to os.Exit(1).
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.sink.LogFields(api.CRITICAL, fmt.Sprintf(format, v...))
+	os.Exit(1)
+}
+
+// Fatalln is CRITICAL log ln followed by a call to os.Exit(1).
+func (l *Logger) Fatalln(v ...interface{}) {
+	l.sink.LogFields(api.CRITICAL, sprintln(v...))
+	os.Exit(1)
+}
+
+// Panic is CRITICAL log followed by a call to panic()
+func (l *Logger) Panic(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	l.sink.LogFields(api.CRITICAL, msg)
+	panic(msg)
+}
+
+// Panicf is CRITICAL log formatted followed by a call to panic()
+func (l *Logger) Panicf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	l.sink.LogFields(api.CRITICAL, msg)
+	panic(msg)
+}
+
+// Panicln is CRITICAL log ln followed by a call to panic()
+func (l *Logger) Panicln(v ...interface{}) {
+	msg := sprintln(v...)
+	l.sink.LogFields(api.CRITICAL, msg)
+	panic(msg)
+}
+
+// Print logs at INFO level. Arguments are handled in the manner of fmt.Print.
+func (l *Logger) Print(v ...interface{}) {
+	l.sink.LogFields(api.INFO, fmt.Sprint(v...))
+}
+
+// Printf logs at INFO level. Arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.sink.LogFields(api.INFO, fmt.Sprintf(format, v...))
+}
+
+// Println logs at INFO level. Arguments are handled in the manner of fmt.Println.
+func (l *Logger) Println(v ...interface{}) {
+	l.sink.LogFields(api.INFO, sprintln(v...))
+}
+
+// Debug logs at DEBUG level. Arguments are handled in the manner of fmt.Print.
+func (l *Logger) Debug(args ...interface{}) {
+	l.sink.LogFields(api.DEBUG, fmt.Sprint(args...))
+}
+
+// Debugf logs at DEBUG level. Arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.sink.LogFields(api.DEBUG, fmt.Sprintf(format, args...))
+}
+
+// Debugln logs at DEBUG level. Arguments are handled in the manner of fmt.Println.
+func (l *Logger) Debugln(args ...interface{}) {
+	l.sink.LogFields(api.DEBUG, sprintln(args...))
+}
+
+// Info logs at INFO level. Arguments are handled in the manner of fmt.Print.
+func (l *Logger) Info(args ...interface{}) {
+	l.sink.LogFields(api.INFO, fmt.Sprint(args...))
+}
+
+// Infof logs at INFO level. Arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.sink.LogFields(api.INFO, fmt.Sprintf(format, args...))
+}
+
+// Infoln logs at INFO level. Arguments are handled in the manner of fmt.Println.
+func (l *Logger) Infoln(args ...interface{}) {
+	l.sink.LogFields(api.INFO, sprintln(args...))
+}
+
+// Warn logs at WARNING level. Arguments are handled in the manner of fmt.Print.
+func (l *Logger) Warn(args ...interface{}) {
+	l.sink.LogFields(api.WARNING, fmt.Sprint(args...))
+}
+
+// Warnf logs at WARNING level. Arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.sink.LogFields(api.WARNING, fmt.Sprintf(format, args...))
+}
+
+// Warnln logs at WARNING level. Arguments are handled in the manner of fmt.Println.
+func (l *Logger) Warnln(args ...interface{}) {
+	l.sink.LogFields(api.WARNING, sprintln(args...))
+}
+
+// Error logs at ERROR level. Arguments are handled in the manner of fmt.Print.
+func (l *Logger) Error(args ...interface{}) {
+	l.sink.LogFields(api.ERROR, fmt.Sprint(args...))
+}
+
+// Errorf logs at ERROR level. Arguments are handled in the manner of fmt.Printf.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.sink.LogFields(api.ERROR, fmt.Sprintf(format, args...))
+}
+
+// Errorln logs at ERROR level. Arguments are handled in the manner of fmt.Println.
+func (l *Logger) Errorln(args ...interface{}) {
+	l.sink.LogFields(api.ERROR, sprintln(args...))
+}
+
+// ProviderFunc returns a sink for the given module
+type ProviderFunc func(module string) Sink
+
+// GetLogger returns a structured logger for the given module
+func (f ProviderFunc) GetLogger(module string) api.Logger {
+	return NewLogger(f(module))
+}
+
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
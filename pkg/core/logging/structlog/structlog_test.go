@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package structlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModule = "structlog-test"
+
+func TestJSONProvider(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONProvider(&buf).GetLogger(testModule)
+
+	sl, ok := logger.(api.StructuredLogger)
+	require.True(t, ok, "expecting a structured logger")
+
+	sl.LogFields(api.WARNING, "endorsement failed",
+		api.Field{Key: "txID", Value: "tx1"},
+		api.Field{Key: "block", Value: 10},
+		api.Field{Key: "err", Value: fmt.Errorf("timeout")},
+	)
+
+	entry := decodeEntry(t, buf.String())
+	assert.Equal(t, "warning", entry[LevelKey])
+	assert.Equal(t, testModule, entry[ModuleKey])
+	assert.Equal(t, "endorsement failed", entry[MessageKey])
+	assert.NotEmpty(t, entry[TimeKey])
+	assert.Equal(t, "tx1", entry["txID"])
+	assert.Equal(t, float64(10), entry["block"])
+	assert.Equal(t, "timeout", entry["err"])
+
+	// Keys are written in order
+	line := buf.String()
+	assert.True(t, strings.Index(line, `"msg"`) < strings.Index(line, `"txID"`))
+	assert.True(t, strings.Index(line, `"txID"`) < strings.Index(line, `"block"`))
+}
+
+func TestJSONProviderLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONProvider(&buf).GetLogger(testModule)
+
+	modlog.SetLevel(testModule, api.INFO)
+	logger.Debugf("hidden %d", 1)
+	assert.Empty(t, buf.String(), "debug entries should not be logged at info level")
+
+	logger.Infof("shown %d", 1)
+	assert.Equal(t, "shown 1", decodeEntry(t, buf.String())[MessageKey])
+	buf.Reset()
+
+	modlog.SetLevel(testModule, api.DEBUG)
+	defer modlog.SetLevel(testModule, api.INFO)
+
+	logger.Debugln("now", "shown")
+	entry := decodeEntry(t, buf.String())
+	assert.Equal(t, "debug", entry[LevelKey])
+	assert.Equal(t, "now shown", entry[MessageKey])
+}
+
+func TestLoggerPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONProvider(&buf).GetLogger(testModule)
+
+	assert.PanicsWithValue(t, "bad thing", func() { logger.Panicf("bad %s", "thing") })
+	assert.Equal(t, "critical", decodeEntry(t, buf.String())[LevelKey])
+}
+
+type zapEntry struct {
+	level         string
+	msg           string
+	keysAndValues []interface{}
+}
+
+type mockSugaredLogger struct {
+	entries []zapEntry
+}
+
+func (m *mockSugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	m.entries = append(m.entries, zapEntry{"debug", msg, keysAndValues})
+}
+
+func (m *mockSugaredLogger) Infow(msg string, keysAndValues ...interface{}) {
+	m.entries = append(m.entries, zapEntry{"info", msg, keysAndValues})
+}
+
+func (m *mockSugaredLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	m.entries = append(m.entries, zapEntry{"warn", msg, keysAndValues})
+}
+
+func (m *mockSugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	m.entries = append(m.entries, zapEntry{"error", msg, keysAndValues})
+}
+
+func TestZapProvider(t *testing.T) {
+	zl := &mockSugaredLogger{}
+	logger := NewZapProvider(zl).GetLogger(testModule)
+
+	modlog.SetLevel(testModule, api.INFO)
+	logger.Debug("hidden")
+	assert.Empty(t, zl.entries, "debug entries should not be logged at info level")
+
+	modlog.SetLevel(testModule, api.DEBUG)
+	defer modlog.SetLevel(testModule, api.INFO)
+
+	logger.(api.StructuredLogger).LogFields(api.INFO, "block received", api.Field{Key: "channel", Value: "mychannel"})
+	logger.Debug("debug")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	require.Len(t, zl.entries, 4)
+	assert.Equal(t, zapEntry{"info", "block received", []interface{}{ModuleKey, testModule, "channel", "mychannel"}}, zl.entries[0])
+	assert.Equal(t, "debug", zl.entries[1].level)
+	assert.Equal(t, "warn", zl.entries[2].level)
+	assert.Equal(t, "error", zl.entries[3].level)
+}
+
+func decodeEntry(t *testing.T, line string) map[string]interface{} {
+	entry := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(line), &entry), "invalid JSON entry: %s", line)
+	return entry
+}
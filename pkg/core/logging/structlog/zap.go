@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package structlog
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+)
+
+// SugaredLogger contains the functions of zap's *zap.SugaredLogger that are used by the
+// zap provider. A *zap.SugaredLogger may be passed directly to NewZapProvider, which keeps
+// zap out of the dependencies of the SDK.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// NewZapProvider returns a logger provider which logs to the given zap logger
+// (for example, zap.NewProduction().Sugar()). The module is added to each entry as the
+// "module" field. Entries are logged if their level is enabled both with logging.SetLevel
+// and by the zap logger. Critical entries are logged at error level before the SDK exits or panics.
+func NewZapProvider(logger SugaredLogger) api.LoggerProvider {
+	return ProviderFunc(func(module string) Sink {
+		return SinkFunc(func(level api.Level, msg string, fields ...api.Field) {
+			if !modlog.IsEnabledFor(module, level) {
+				return
+			}
+
+			keysAndValues := make([]interface{}, 0, 2*(len(fields)+1))
+			keysAndValues = append(keysAndValues, ModuleKey, module)
+			for _, f := range fields {
+				keysAndValues = append(keysAndValues, f.Key, f.Value)
+			}
+
+			switch level {
+			case api.DEBUG:
+				logger.Debugw(msg, keysAndValues...)
+			case api.INFO:
+				logger.Infow(msg, keysAndValues...)
+			case api.WARNING:
+				logger.Warnw(msg, keysAndValues...)
+			default:
+				logger.Errorw(msg, keysAndValues...)
+			}
+		})
+	})
+}
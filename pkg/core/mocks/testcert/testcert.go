@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testcert generates X.509 certificates for tests. By default a certificate is self-signed
+// with a new ECDSA P-256 key and is valid from an hour ago for an hour.
+package testcert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Certificate is a test certificate along with its private key
+type Certificate struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// PEM returns the PEM encoding of the certificate
+func (c *Certificate) PEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Cert.Raw})
+}
+
+// KeyPEM returns the PEM encoding of the private key: SEC 1 for ECDSA keys, PKCS #8 otherwise
+func (c *Certificate) KeyPEM() ([]byte, error) {
+	if key, ok := c.Key.(*ecdsa.PrivateKey); ok {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Option configures a test certificate
+type Option func(opts *options)
+
+type options struct {
+	template *x509.Certificate
+	key      crypto.Signer
+	parent   *Certificate
+}
+
+// WithCommonName sets the common name of the subject
+func WithCommonName(commonName string) Option {
+	return func(opts *options) {
+		opts.template.Subject = pkix.Name{CommonName: commonName}
+	}
+}
+
+// WithValidity sets the validity period of the certificate
+func WithValidity(notBefore, notAfter time.Time) Option {
+	return func(opts *options) {
+		opts.template.NotBefore = notBefore
+		opts.template.NotAfter = notAfter
+	}
+}
+
+// WithCA makes the certificate a CA certificate, which may sign certificates
+func WithCA() Option {
+	return func(opts *options) {
+		opts.template.IsCA = true
+		opts.template.BasicConstraintsValid = true
+		opts.template.KeyUsage |= x509.KeyUsageCertSign
+	}
+}
+
+// WithKeyUsage adds the given key usages
+func WithKeyUsage(usage x509.KeyUsage) Option {
+	return func(opts *options) {
+		opts.template.KeyUsage |= usage
+	}
+}
+
+// WithExtKeyUsage adds the given extended key usages
+func WithExtKeyUsage(usages ...x509.ExtKeyUsage) Option {
+	return func(opts *options) {
+		opts.template.ExtKeyUsage = append(opts.template.ExtKeyUsage, usages...)
+	}
+}
+
+// WithKey certifies the given key (for example an Ed25519 key) instead of a new ECDSA P-256 key
+func WithKey(key crypto.Signer) Option {
+	return func(opts *options) {
+		opts.key = key
+	}
+}
+
+// WithParent signs the certificate with the given CA certificate instead of self-signing it
+func WithParent(parent *Certificate) Option {
+	return func(opts *options) {
+		opts.parent = parent
+	}
+}
+
+// New generates a test certificate with the given options
+func New(opts ...Option) (*Certificate, error) {
+	now := time.Now()
+	o := &options{
+		template: &x509.Certificate{
+			SerialNumber: big.NewInt(now.UnixNano()),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(time.Hour),
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.key == nil {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate key")
+		}
+		o.key = key
+	}
+
+	parent, parentKey := o.template, o.key
+	if o.parent != nil {
+		parent, parentKey = o.parent.Cert, o.parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, o.template, parent, o.key.Public(), parentKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate")
+	}
+
+	return &Certificate{Cert: cert, Key: o.key}, nil
+}
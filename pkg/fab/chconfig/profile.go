@@ -0,0 +1,421 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const profileVersion = "1.0.0"
+
+type profileOptions struct {
+	clientOrg string
+	discovery fab.DiscoveryService
+	peers     []fab.Peer
+}
+
+// ProfileOption configures the connection profile generator
+type ProfileOption func(opts *profileOptions)
+
+// WithClientOrganization sets client.organization in the generated profile to the organization with the given MSP ID
+func WithClientOrganization(mspID string) ProfileOption {
+	return func(opts *profileOptions) {
+		opts.clientOrg = mspID
+	}
+}
+
+// WithDiscoveryService adds the peers returned by the given discovery service to the generated profile,
+// in addition to the anchor peers defined in the config block
+func WithDiscoveryService(discovery fab.DiscoveryService) ProfileOption {
+	return func(opts *profileOptions) {
+		opts.discovery = discovery
+	}
+}
+
+// WithDiscoveredPeers adds the given peers (for example, the result of a previous discovery query) to the
+// generated profile, in addition to the anchor peers defined in the config block
+func WithDiscoveredPeers(peers ...fab.Peer) ProfileOption {
+	return func(opts *profileOptions) {
+		opts.peers = append(opts.peers, peers...)
+	}
+}
+
+// GenerateProfile generates a connection profile (in YAML, suitable for config.FromRaw) from a channel config block.
+// The profile contains the organizations of the channel along with their MSP IDs, the anchor peers and orderers of the
+// channel, and the channel itself. The TLS CA certs of each peer and orderer are taken from the tls_root_certs (and
+// tls_intermediate_certs) of the MSP of its organization.
+//
+// The generated profile doesn't contain client settings (other than client.organization) such as the credential
+// store or crypto config path; those may be provided by an additional config backend.
+func GenerateProfile(block *common.Block, opts ...ProfileOption) ([]byte, error) {
+	options := &profileOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	channelID, err := channelIDFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := resource.ExtractConfigFromBlock(block)
+	if err != nil {
+		return nil, errors.WithMessage(err, "extract config from block failed")
+	}
+	if config.ChannelGroup == nil {
+		return nil, errors.New("channel group not found in config block")
+	}
+
+	peers := options.peers
+	if options.discovery != nil {
+		discovered, err := options.discovery.GetPeers()
+		if err != nil {
+			return nil, errors.WithMessage(err, "discovery of channel peers failed")
+		}
+		peers = append(peers, discovered...)
+	}
+
+	g := newProfileGenerator(channelID)
+	if err := g.loadOrdererOrgs(config.ChannelGroup); err != nil {
+		return nil, err
+	}
+	if err := g.loadApplicationOrgs(config.ChannelGroup); err != nil {
+		return nil, err
+	}
+	if err := g.loadOrdererAddresses(config.ChannelGroup); err != nil {
+		return nil, err
+	}
+	if err := g.addDiscoveredPeers(peers); err != nil {
+		return nil, err
+	}
+
+	if options.clientOrg != "" {
+		org, ok := g.orgForMSP(options.clientOrg)
+		if !ok {
+			return nil, errors.Errorf("client organization [%s] is not a member of channel [%s]", options.clientOrg, channelID)
+		}
+		g.profile.Client = &profileClient{Organization: org}
+	}
+
+	return yaml.Marshal(g.profile)
+}
+
+type connectionProfile struct {
+	Version       string                    `yaml:"version"`
+	Client        *profileClient            `yaml:"client,omitempty"`
+	Channels      map[string]profileChannel `yaml:"channels"`
+	Organizations map[string]*profileOrg    `yaml:"organizations"`
+	Orderers      map[string]profileNode    `yaml:"orderers,omitempty"`
+	Peers         map[string]profileNode    `yaml:"peers,omitempty"`
+}
+
+type profileClient struct {
+	Organization string `yaml:"organization"`
+}
+
+type profileChannel struct {
+	Orderers []string               `yaml:"orderers,omitempty"`
+	Peers    map[string]profileRole `yaml:"peers,omitempty"`
+}
+
+type profileRole struct {
+	EndorsingPeer  bool `yaml:"endorsingPeer"`
+	ChaincodeQuery bool `yaml:"chaincodeQuery"`
+	LedgerQuery    bool `yaml:"ledgerQuery"`
+	EventSource    bool `yaml:"eventSource"`
+}
+
+type profileOrg struct {
+	MSPID string   `yaml:"mspid"`
+	Peers []string `yaml:"peers,omitempty"`
+}
+
+type profileNode struct {
+	URL         string                 `yaml:"url"`
+	GRPCOptions map[string]interface{} `yaml:"grpcOptions,omitempty"`
+	TLSCACerts  *profileTLSCerts       `yaml:"tlsCACerts,omitempty"`
+}
+
+type profileTLSCerts struct {
+	Pem string `yaml:"pem"`
+}
+
+type profileGenerator struct {
+	channelID string
+	profile   *connectionProfile
+	// tlsCerts contains the PEM-encoded TLS root and intermediate certs of each org (keyed by org name)
+	tlsCerts map[string]string
+	// peerAddresses and ordererAddresses contain the address of each node (keyed by node name)
+	peerAddresses    map[string]string
+	ordererAddresses map[string]string
+	ordererOrgs      []string
+}
+
+func newProfileGenerator(channelID string) *profileGenerator {
+	return &profileGenerator{
+		channelID: channelID,
+		profile: &connectionProfile{
+			Version:       profileVersion,
+			Channels:      map[string]profileChannel{channelID: {Peers: make(map[string]profileRole)}},
+			Organizations: make(map[string]*profileOrg),
+			Orderers:      make(map[string]profileNode),
+			Peers:         make(map[string]profileNode),
+		},
+		tlsCerts:         make(map[string]string),
+		peerAddresses:    make(map[string]string),
+		ordererAddresses: make(map[string]string),
+	}
+}
+
+// loadOrdererOrgs loads the orderer orgs along with their orderer endpoints (if the orgs define them)
+func (g *profileGenerator) loadOrdererOrgs(channelGroup *common.ConfigGroup) error {
+	ordererGroup, ok := channelGroup.Groups[channelConfig.OrdererGroupKey]
+	if !ok {
+		return nil
+	}
+
+	for _, orgName := range sortedGroupKeys(ordererGroup.Groups) {
+		orgGroup := ordererGroup.Groups[orgName]
+		if err := g.loadOrg(orgName, orgGroup); err != nil {
+			return err
+		}
+		g.ordererOrgs = append(g.ordererOrgs, orgName)
+
+		value, ok := orgGroup.Values[channelConfig.EndpointsKey]
+		if !ok {
+			continue
+		}
+		addresses := &common.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, addresses); err != nil {
+			return errors.Wrapf(err, "unmarshal orderer endpoints of org [%s] failed", orgName)
+		}
+		for _, address := range addresses.Addresses {
+			g.addOrderer(address, g.tlsCerts[orgName])
+		}
+	}
+
+	return nil
+}
+
+// loadApplicationOrgs loads the application orgs along with their anchor peers
+func (g *profileGenerator) loadApplicationOrgs(channelGroup *common.ConfigGroup) error {
+	applicationGroup, ok := channelGroup.Groups[channelConfig.ApplicationGroupKey]
+	if !ok {
+		return nil
+	}
+
+	for _, orgName := range sortedGroupKeys(applicationGroup.Groups) {
+		orgGroup := applicationGroup.Groups[orgName]
+		if err := g.loadOrg(orgName, orgGroup); err != nil {
+			return err
+		}
+
+		value, ok := orgGroup.Values[channelConfig.AnchorPeersKey]
+		if !ok {
+			continue
+		}
+		anchorPeers := &pb.AnchorPeers{}
+		if err := proto.Unmarshal(value.Value, anchorPeers); err != nil {
+			return errors.Wrapf(err, "unmarshal anchor peers of org [%s] failed", orgName)
+		}
+		for _, anchorPeer := range anchorPeers.AnchorPeers {
+			g.addPeer(orgName, net.JoinHostPort(anchorPeer.Host, strconv.Itoa(int(anchorPeer.Port))))
+		}
+	}
+
+	return nil
+}
+
+// loadOrdererAddresses loads the channel-wide orderer addresses. These addresses don't identify the org of the
+// orderer so they're trusted with the TLS certs of all of the orderer orgs.
+func (g *profileGenerator) loadOrdererAddresses(channelGroup *common.ConfigGroup) error {
+	value, ok := channelGroup.Values[channelConfig.OrdererAddressesKey]
+	if !ok {
+		return nil
+	}
+
+	addresses := &common.OrdererAddresses{}
+	if err := proto.Unmarshal(value.Value, addresses); err != nil {
+		return errors.Wrap(err, "unmarshal orderer addresses from config failed")
+	}
+
+	var tlsCerts strings.Builder
+	for _, orgName := range g.ordererOrgs {
+		tlsCerts.WriteString(g.tlsCerts[orgName])
+	}
+
+	for _, address := range addresses.Addresses {
+		g.addOrderer(address, tlsCerts.String())
+	}
+
+	return nil
+}
+
+func (g *profileGenerator) addDiscoveredPeers(peers []fab.Peer) error {
+	for _, peer := range peers {
+		orgName, ok := g.orgForMSP(peer.MSPID())
+		if !ok {
+			return errors.Errorf("MSP [%s] of discovered peer [%s] is not a member of channel [%s]", peer.MSPID(), peer.URL(), g.channelID)
+		}
+		g.addPeer(orgName, endpoint.ToAddress(peer.URL()))
+	}
+	return nil
+}
+
+func (g *profileGenerator) loadOrg(orgName string, orgGroup *common.ConfigGroup) error {
+	value, ok := orgGroup.Values[channelConfig.MSPKey]
+	if !ok {
+		return errors.Errorf("MSP config not found for org [%s]", orgName)
+	}
+
+	mspConfig := &mb.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return errors.Wrapf(err, "unmarshal MSPConfig of org [%s] failed", orgName)
+	}
+	fabricConfig := &mb.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return errors.Wrapf(err, "unmarshal FabricMSPConfig of org [%s] failed", orgName)
+	}
+
+	var tlsCerts bytes.Buffer
+	for _, cert := range append(fabricConfig.TlsRootCerts, fabricConfig.TlsIntermediateCerts...) {
+		tlsCerts.Write(bytes.TrimSpace(cert))
+		tlsCerts.WriteString("\n")
+	}
+
+	g.tlsCerts[orgName] = tlsCerts.String()
+	g.profile.Organizations[orgName] = &profileOrg{MSPID: fabricConfig.Name}
+
+	return nil
+}
+
+func (g *profileGenerator) addPeer(orgName, address string) {
+	name, exists := g.nodeName(g.peerAddresses, address)
+	if exists {
+		return
+	}
+
+	g.profile.Peers[name] = g.newNode(address, g.tlsCerts[orgName])
+	g.profile.Channels[g.channelID].Peers[name] = profileRole{
+		EndorsingPeer:  true,
+		ChaincodeQuery: true,
+		LedgerQuery:    true,
+		EventSource:    true,
+	}
+
+	org := g.profile.Organizations[orgName]
+	org.Peers = append(org.Peers, name)
+}
+
+func (g *profileGenerator) addOrderer(address, tlsCerts string) {
+	name, exists := g.nodeName(g.ordererAddresses, address)
+	if exists {
+		return
+	}
+
+	g.profile.Orderers[name] = g.newNode(address, tlsCerts)
+
+	channel := g.profile.Channels[g.channelID]
+	channel.Orderers = append(channel.Orderers, name)
+	g.profile.Channels[g.channelID] = channel
+}
+
+// nodeName returns the name of the node with the given address and whether the node was already added. The host
+// of the address is used as the name unless a node with the same host but a different port was already added,
+// in which case the address itself is used.
+func (g *profileGenerator) nodeName(addresses map[string]string, address string) (string, bool) {
+	name := address
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		if existing, ok := addresses[host]; !ok || existing == address {
+			name = host
+		}
+	}
+
+	if _, ok := addresses[name]; ok {
+		return name, true
+	}
+	addresses[name] = address
+	return name, false
+}
+
+func (g *profileGenerator) newNode(address, tlsCerts string) profileNode {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	node := profileNode{
+		GRPCOptions: map[string]interface{}{
+			"ssl-target-name-override": host,
+		},
+	}
+
+	if tlsCerts == "" {
+		node.URL = "grpc://" + address
+		return node
+	}
+
+	node.URL = "grpcs://" + address
+	node.TLSCACerts = &profileTLSCerts{Pem: tlsCerts}
+	return node
+}
+
+func (g *profileGenerator) orgForMSP(mspID string) (string, bool) {
+	for name, org := range g.profile.Organizations {
+		if org.MSPID == mspID {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func channelIDFromBlock(block *common.Block) (string, error) {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return "", errors.New("invalid block")
+	}
+
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return "", errors.WithMessage(err, "extract envelope from block failed")
+	}
+
+	channelHeader, err := protoutil.ChannelHeader(envelope)
+	if err != nil {
+		return "", errors.WithMessage(err, "extract channel header from block failed")
+	}
+
+	if channelHeader.ChannelId == "" {
+		return "", errors.New("channel ID not found in block")
+	}
+
+	return channelHeader.ChannelId, nil
+}
+
+func sortedGroupKeys(groups map[string]*common.ConfigGroup) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
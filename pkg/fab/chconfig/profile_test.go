@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileChannelID = "mychannel"
+
+func TestGenerateProfile(t *testing.T) {
+	ordererCert := newProfileTestCert(t, "tlsca.example.com")
+	org1Cert := newProfileTestCert(t, "tlsca.org1.example.com")
+
+	block := newProfileTestBlock(t, ordererCert, org1Cert)
+	discoveredPeer := &mocks.MockPeer{MockURL: "grpcs://peer1.org1.example.com:7051", MockMSP: "Org1MSP"}
+
+	raw, err := GenerateProfile(block, WithClientOrganization("Org1MSP"), WithDiscoveredPeers(discoveredPeer))
+	require.NoError(t, err)
+
+	backends, err := config.FromRaw(raw, "yaml")()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err, string(raw))
+
+	client, ok := backends[0].Lookup("client.organization")
+	require.True(t, ok)
+	assert.Equal(t, "Org1", client)
+
+	channelPeers := endpointConfig.ChannelPeers(profileChannelID)
+	require.Len(t, channelPeers, 2)
+	for _, peer := range channelPeers {
+		assert.Equal(t, "Org1MSP", peer.MSPID)
+		assert.True(t, peer.EndorsingPeer)
+		require.NotNil(t, peer.TLSCACert)
+		assert.Equal(t, "tlsca.org1.example.com", peer.TLSCACert.Subject.CommonName)
+	}
+
+	peerConfig, ok := endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpcs://peer0.org1.example.com:7051", peerConfig.URL)
+	_, ok = endpointConfig.PeerConfig("peer1.org1.example.com")
+	assert.True(t, ok, "discovered peer should be included in the profile")
+
+	orderers := endpointConfig.ChannelOrderers(profileChannelID)
+	require.Len(t, orderers, 1)
+	assert.Equal(t, "grpcs://orderer.example.com:7050", orderers[0].URL)
+	require.NotNil(t, orderers[0].TLSCACert)
+	assert.Equal(t, "tlsca.example.com", orderers[0].TLSCACert.Subject.CommonName)
+
+	orgs := endpointConfig.NetworkConfig().Organizations
+	require.Contains(t, orgs, "orderer")
+	assert.Equal(t, "OrdererMSP", orgs["orderer"].MSPID)
+}
+
+func TestGenerateProfileErrors(t *testing.T) {
+	_, err := GenerateProfile(&common.Block{})
+	assert.Error(t, err)
+
+	block := newProfileTestBlock(t, newProfileTestCert(t, "tlsca.example.com"), newProfileTestCert(t, "tlsca.org1.example.com"))
+
+	_, err = GenerateProfile(block, WithClientOrganization("Org2MSP"))
+	assert.Error(t, err, "client org that isn't a member of the channel should be rejected")
+
+	_, err = GenerateProfile(block, WithDiscoveredPeers(&mocks.MockPeer{MockURL: "peer0.org2.example.com:7051", MockMSP: "Org2MSP"}))
+	assert.Error(t, err, "discovered peer of an org that isn't a member of the channel should be rejected")
+}
+
+func newProfileTestBlock(t *testing.T, ordererCert, org1Cert []byte) *common.Block {
+	channelGroup := &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{
+			channelConfig.OrdererGroupKey: {
+				Groups: map[string]*common.ConfigGroup{
+					"Orderer": newProfileTestOrgGroup(t, "OrdererMSP", ordererCert),
+				},
+			},
+			channelConfig.ApplicationGroupKey: {
+				Groups: map[string]*common.ConfigGroup{
+					"Org1": newProfileTestOrgGroup(t, "Org1MSP", org1Cert),
+				},
+			},
+		},
+		Values: map[string]*common.ConfigValue{
+			channelConfig.OrdererAddressesKey: {
+				Value: marshalProfileTestProto(t, &common.OrdererAddresses{Addresses: []string{"orderer.example.com:7050"}}),
+			},
+		},
+	}
+
+	anchorPeers := &pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}}
+	channelGroup.Groups[channelConfig.ApplicationGroupKey].Groups["Org1"].Values[channelConfig.AnchorPeersKey] = &common.ConfigValue{
+		Value: marshalProfileTestProto(t, anchorPeers),
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: marshalProfileTestProto(t, &common.ChannelHeader{
+				Type:      int32(common.HeaderType_CONFIG),
+				ChannelId: profileChannelID,
+			}),
+		},
+		Data: marshalProfileTestProto(t, &common.ConfigEnvelope{Config: &common.Config{ChannelGroup: channelGroup}}),
+	}
+	envelope := &common.Envelope{Payload: marshalProfileTestProto(t, payload)}
+
+	return &common.Block{
+		Header: &common.BlockHeader{},
+		Data:   &common.BlockData{Data: [][]byte{marshalProfileTestProto(t, envelope)}},
+	}
+}
+
+func newProfileTestOrgGroup(t *testing.T, mspID string, tlsRootCert []byte) *common.ConfigGroup {
+	fabricMSPConfig := &mb.FabricMSPConfig{
+		Name:         mspID,
+		TlsRootCerts: [][]byte{tlsRootCert},
+	}
+	mspConfig := &mb.MSPConfig{Config: marshalProfileTestProto(t, fabricMSPConfig)}
+
+	return &common.ConfigGroup{
+		Values: map[string]*common.ConfigValue{
+			channelConfig.MSPKey: {Value: marshalProfileTestProto(t, mspConfig)},
+		},
+	}
+}
+
+func newProfileTestCert(t *testing.T, commonName string) []byte {
+	cert, err := testcert.New(testcert.WithCommonName(commonName), testcert.WithCA())
+	require.NoError(t, err)
+	return cert.PEM()
+}
+
+func marshalProfileTestProto(t *testing.T, msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return bytes
+}
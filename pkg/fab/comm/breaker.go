@@ -0,0 +1,451 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	reqContext "context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
+	"github.com/pkg/errors"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed indicates that requests are sent to the endpoint
+	BreakerClosed BreakerState = iota
+	// BreakerOpen indicates that requests are rejected without being sent to the endpoint
+	BreakerOpen
+	// BreakerHalfOpen indicates that a single probe request is allowed through to the endpoint
+	// in order to determine whether the endpoint has recovered
+	BreakerHalfOpen
+)
+
+// String returns the name of the state
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOpts contains the thresholds of the circuit breakers
+type BreakerOpts struct {
+	// FailureRatio is the ratio of failed requests (0 to 1) at or above which the breaker opens
+	FailureRatio float64
+	// MinRequests is the minimum number of requests within the window before the failure ratio is evaluated
+	MinRequests int
+	// Window is the interval over which the failure ratio is computed
+	Window time.Duration
+	// SlowCallThreshold (optional) is the latency above which a successful request is counted as a failure
+	SlowCallThreshold time.Duration
+	// CoolDown is the time that the breaker stays open before a probe request is allowed through
+	CoolDown time.Duration
+}
+
+// DefaultBreakerOpts are the default circuit breaker thresholds
+var DefaultBreakerOpts = BreakerOpts{
+	FailureRatio: 0.5,
+	MinRequests:  5,
+	Window:       time.Minute,
+	CoolDown:     30 * time.Second,
+}
+
+// BreakerEvent is emitted when the circuit breaker of an endpoint changes state
+type BreakerEvent struct {
+	Target string
+	From   BreakerState
+	To     BreakerState
+	Stats  BreakerStats
+}
+
+// BreakerEventHandler is invoked when the circuit breaker of an endpoint changes state
+type BreakerEventHandler func(event BreakerEvent)
+
+// BreakerStats contains the request statistics of an endpoint within the current window
+type BreakerStats struct {
+	Requests     int
+	Failures     int
+	FailureRatio float64
+	// Latency is the exponentially weighted moving average of the latency of the requests
+	Latency time.Duration
+}
+
+// Breakers tracks the failure ratio and latency of requests to each endpoint (peer or orderer) and
+// opens the circuit breaker of an endpoint when the failure ratio reaches the threshold. Requests
+// to an endpoint with an open breaker are rejected until the cool-down elapses, after which a single
+// probe request is allowed through. The breaker closes if the probe succeeds and opens again otherwise.
+//
+// Breakers implements fab.TargetFilter so that endpoints with an open breaker are excluded from
+// selection. All methods may be invoked on a nil Breakers, in which case every endpoint is accepted.
+type Breakers struct {
+	opts     BreakerOpts
+	lock     sync.RWMutex
+	breakers map[string]*breaker
+	handlers []BreakerEventHandler
+	metrics  *metrics.ConnectionMetrics
+}
+
+// BreakerOpt is a circuit breaker option
+type BreakerOpt func(b *Breakers)
+
+// WithBreakerEventHandler registers a handler that's invoked when a circuit breaker changes state
+func WithBreakerEventHandler(handler BreakerEventHandler) BreakerOpt {
+	return func(b *Breakers) {
+		b.handlers = append(b.handlers, handler)
+	}
+}
+
+// NewBreakers returns a new set of circuit breakers with the given thresholds. Thresholds that
+// are not set are taken from DefaultBreakerOpts.
+func NewBreakers(opts BreakerOpts, options ...BreakerOpt) *Breakers {
+	if opts.FailureRatio <= 0 {
+		opts.FailureRatio = DefaultBreakerOpts.FailureRatio
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = DefaultBreakerOpts.MinRequests
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultBreakerOpts.Window
+	}
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = DefaultBreakerOpts.CoolDown
+	}
+
+	b := &Breakers{
+		opts:     opts,
+		breakers: make(map[string]*breaker),
+		metrics:  (&metrics.ClientMetrics{}).Connections(),
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	return b
+}
+
+// SetMetrics sets the metrics that record the state and the state changes of the breakers
+func (b *Breakers) SetMetrics(m *metrics.ConnectionMetrics) {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.metrics = m
+}
+
+// OnStateChange registers a handler that's invoked when a circuit breaker changes state
+func (b *Breakers) OnStateChange(handler BreakerEventHandler) {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Accept returns false if the breaker of the given peer is open (and the cool-down hasn't elapsed)
+// or a probe request is already in progress
+func (b *Breakers) Accept(peer fab.Peer) bool {
+	return b.Accepts(peer.URL())
+}
+
+// Accepts returns false if the breaker of the given endpoint is open (and the cool-down hasn't elapsed)
+// or a probe request is already in progress
+func (b *Breakers) Accepts(url string) bool {
+	if b == nil || url == "" {
+		return true
+	}
+
+	br, ok := b.get(url)
+	if !ok {
+		return true
+	}
+
+	br.lock.Lock()
+	defer br.lock.Unlock()
+
+	return br.accepts(b.opts)
+}
+
+// Allow returns false if a request may not be sent to the given endpoint. If the breaker is open
+// and the cool-down has elapsed then the request is allowed through as a probe. The outcome of an
+// allowed request must be recorded with Record.
+func (b *Breakers) Allow(url string) bool {
+	if b == nil || url == "" {
+		return true
+	}
+
+	target := endpoint.ToAddress(url)
+	br := b.getOrCreate(target)
+
+	br.lock.Lock()
+	allowed := br.accepts(b.opts)
+	var event *BreakerEvent
+	if allowed && br.state != BreakerClosed {
+		event = br.transition(target, BreakerHalfOpen)
+		br.probing = true
+	}
+	br.lock.Unlock()
+
+	b.notify(event)
+
+	return allowed
+}
+
+// Record records the outcome and latency of a request to the given endpoint
+func (b *Breakers) Record(url string, latency time.Duration, err error) {
+	if b == nil || url == "" {
+		return
+	}
+
+	target := endpoint.ToAddress(url)
+	br := b.getOrCreate(target)
+
+	failed := IsEndpointFailure(err) || (b.opts.SlowCallThreshold > 0 && latency > b.opts.SlowCallThreshold)
+
+	br.lock.Lock()
+	event := br.record(target, b.opts, latency, failed)
+	br.lock.Unlock()
+
+	b.notify(event)
+}
+
+// State returns the state of the breaker of the given endpoint
+func (b *Breakers) State(url string) BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+
+	br, ok := b.get(url)
+	if !ok {
+		return BreakerClosed
+	}
+
+	br.lock.Lock()
+	defer br.lock.Unlock()
+
+	return br.state
+}
+
+// Stats returns the request statistics of the given endpoint
+func (b *Breakers) Stats(url string) BreakerStats {
+	if b == nil {
+		return BreakerStats{}
+	}
+
+	br, ok := b.get(url)
+	if !ok {
+		return BreakerStats{}
+	}
+
+	br.lock.Lock()
+	defer br.lock.Unlock()
+
+	return br.stats()
+}
+
+func (b *Breakers) get(url string) (*breaker, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	br, ok := b.breakers[endpoint.ToAddress(url)]
+	return br, ok
+}
+
+func (b *Breakers) getOrCreate(target string) *breaker {
+	b.lock.RLock()
+	br, ok := b.breakers[target]
+	b.lock.RUnlock()
+	if ok {
+		return br
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	br, ok = b.breakers[target]
+	if !ok {
+		br = &breaker{windowStart: time.Now()}
+		b.breakers[target] = br
+	}
+	return br
+}
+
+func (b *Breakers) notify(event *BreakerEvent) {
+	if event == nil {
+		return
+	}
+
+	b.lock.RLock()
+	handlers := append([]BreakerEventHandler{}, b.handlers...)
+	m := b.metrics
+	b.lock.RUnlock()
+
+	logger.Infof("Circuit breaker for %s changed from %s to %s (requests: %d, failures: %d)",
+		event.Target, event.From, event.To, event.Stats.Requests, event.Stats.Failures)
+
+	m.BreakerState.With(metrics.TargetLabel, event.Target).Set(float64(event.To))
+	m.BreakerTransitions.With(metrics.TargetLabel, event.Target, metrics.StateLabel, event.To.String()).Add(1)
+
+	for _, handler := range handlers {
+		handler(*event)
+	}
+}
+
+// latencyWeight is the weight of the latest request in the moving average of the latency
+const latencyWeight = 0.2
+
+type breaker struct {
+	lock        sync.Mutex
+	state       BreakerState
+	openedAt    time.Time
+	probing     bool
+	windowStart time.Time
+	requests    int
+	failures    int
+	latency     time.Duration
+}
+
+func (br *breaker) accepts(opts BreakerOpts) bool {
+	switch br.state {
+	case BreakerOpen:
+		return !time.Now().Before(br.openedAt.Add(opts.CoolDown))
+	case BreakerHalfOpen:
+		return !br.probing
+	default:
+		return true
+	}
+}
+
+func (br *breaker) record(target string, opts BreakerOpts, latency time.Duration, failed bool) *BreakerEvent {
+	if br.latency == 0 {
+		br.latency = latency
+	} else {
+		br.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(br.latency))
+	}
+
+	if br.state == BreakerHalfOpen {
+		br.probing = false
+		if failed {
+			return br.open(target)
+		}
+		br.resetWindow()
+		return br.transition(target, BreakerClosed)
+	}
+
+	if time.Since(br.windowStart) > opts.Window {
+		br.resetWindow()
+	}
+
+	br.requests++
+	if failed {
+		br.failures++
+	}
+
+	if br.state == BreakerClosed && br.requests >= opts.MinRequests &&
+		float64(br.failures)/float64(br.requests) >= opts.FailureRatio {
+		return br.open(target)
+	}
+	return nil
+}
+
+func (br *breaker) open(target string) *BreakerEvent {
+	br.openedAt = time.Now()
+	event := br.transition(target, BreakerOpen)
+	br.resetWindow()
+	return event
+}
+
+func (br *breaker) transition(target string, state BreakerState) *BreakerEvent {
+	if br.state == state {
+		return nil
+	}
+	event := &BreakerEvent{Target: target, From: br.state, To: state, Stats: br.stats()}
+	br.state = state
+	return event
+}
+
+func (br *breaker) resetWindow() {
+	br.windowStart = time.Now()
+	br.requests = 0
+	br.failures = 0
+}
+
+func (br *breaker) stats() BreakerStats {
+	s := BreakerStats{Requests: br.requests, Failures: br.failures, Latency: br.latency}
+	if br.requests > 0 {
+		s.FailureRatio = float64(br.failures) / float64(br.requests)
+	}
+	return s
+}
+
+// NewCircuitOpenError returns the error for a request that was rejected because the circuit breaker
+// of the given endpoint is open
+func NewCircuitOpenError(group status.Group, url string) error {
+	return status.New(group, status.CircuitOpen.ToInt32(), "circuit breaker is open for "+url, []interface{}{url})
+}
+
+// IsEndpointFailure returns true if the given error indicates that the endpoint is unavailable or
+// unresponsive (as opposed to, for example, a chaincode error returned by a healthy peer)
+func IsEndpointFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Cause(err) == reqContext.DeadlineExceeded {
+		return true
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Group {
+	case status.EndorserClientStatus, status.OrdererClientStatus:
+		return s.Code == status.ConnectionFailed.ToInt32()
+	case status.ClientStatus:
+		return s.Code == status.Timeout.ToInt32()
+	case status.GRPCTransportStatus:
+		code := grpcCodes.Code(s.Code)
+		return code == grpcCodes.Unavailable || code == grpcCodes.DeadlineExceeded
+	default:
+		return false
+	}
+}
+
+// breakersProvider is implemented by infra providers that track the health of the endpoints
+type breakersProvider interface {
+	CircuitBreakers() *Breakers
+}
+
+// CircuitBreakers returns the circuit breakers of the given client context or nil if
+// circuit breakers are not enabled
+func CircuitBreakers(ctx context.Client) *Breakers {
+	if p, ok := ctx.InfraProvider().(breakersProvider); ok {
+		return p.CircuitBreakers()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+const breakerTarget = "grpcs://peer0.org1.example.com:7051"
+
+var errConnection = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+
+func TestBreakerOpenAndProbe(t *testing.T) {
+	var events []BreakerEvent
+	breakers := NewBreakers(BreakerOpts{FailureRatio: 0.5, MinRequests: 4, CoolDown: 50 * time.Millisecond},
+		WithBreakerEventHandler(func(event BreakerEvent) {
+			events = append(events, event)
+		}))
+
+	peer := &mocks.MockPeer{MockURL: breakerTarget}
+	assert.True(t, breakers.Accept(peer))
+
+	breakers.Record(breakerTarget, 10*time.Millisecond, nil)
+	breakers.Record(breakerTarget, 10*time.Millisecond, errConnection)
+	breakers.Record(breakerTarget, 10*time.Millisecond, errors.New("chaincode error"))
+	assert.Equal(t, BreakerClosed, breakers.State(breakerTarget), "breaker should not open before the minimum number of requests")
+
+	stats := breakers.Stats(breakerTarget)
+	assert.Equal(t, 3, stats.Requests)
+	assert.Equal(t, 1, stats.Failures, "errors that aren't endpoint failures should not count")
+	assert.Equal(t, 10*time.Millisecond, stats.Latency)
+
+	breakers.Record(breakerTarget, 10*time.Millisecond, errConnection)
+	assert.Equal(t, BreakerOpen, breakers.State(breakerTarget))
+	assert.False(t, breakers.Accept(peer))
+	assert.False(t, breakers.Allow(breakerTarget))
+	require.Len(t, events, 1)
+	assert.Equal(t, BreakerEvent{Target: "peer0.org1.example.com:7051", From: BreakerClosed, To: BreakerOpen,
+		Stats: BreakerStats{Requests: 4, Failures: 2, FailureRatio: 0.5, Latency: 10 * time.Millisecond}}, events[0])
+
+	time.Sleep(60 * time.Millisecond)
+
+	// a single probe is allowed through once the cool-down elapses
+	assert.True(t, breakers.Accept(peer))
+	assert.True(t, breakers.Allow(breakerTarget))
+	assert.Equal(t, BreakerHalfOpen, breakers.State(breakerTarget))
+	assert.False(t, breakers.Allow(breakerTarget), "only one probe should be allowed")
+	assert.False(t, breakers.Accept(peer))
+
+	// failed probe opens the breaker again
+	breakers.Record(breakerTarget, 10*time.Millisecond, errConnection)
+	assert.Equal(t, BreakerOpen, breakers.State(breakerTarget))
+
+	time.Sleep(60 * time.Millisecond)
+
+	// successful probe closes the breaker
+	assert.True(t, breakers.Allow(breakerTarget))
+	breakers.Record(breakerTarget, 10*time.Millisecond, nil)
+	assert.Equal(t, BreakerClosed, breakers.State(breakerTarget))
+	assert.True(t, breakers.Accept(peer))
+
+	var states []BreakerState
+	for _, event := range events {
+		states = append(states, event.To)
+	}
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, states)
+}
+
+func TestBreakerSlowCalls(t *testing.T) {
+	breakers := NewBreakers(BreakerOpts{MinRequests: 2, SlowCallThreshold: 100 * time.Millisecond})
+
+	breakers.Record(breakerTarget, 200*time.Millisecond, nil)
+	breakers.Record(breakerTarget, 10*time.Millisecond, nil)
+	assert.Equal(t, BreakerOpen, breakers.State(breakerTarget), "slow calls should count as failures")
+}
+
+func TestBreakerWindow(t *testing.T) {
+	breakers := NewBreakers(BreakerOpts{MinRequests: 2, Window: 20 * time.Millisecond})
+
+	breakers.Record(breakerTarget, time.Millisecond, errConnection)
+	time.Sleep(30 * time.Millisecond)
+	breakers.Record(breakerTarget, time.Millisecond, errConnection)
+	assert.Equal(t, BreakerClosed, breakers.State(breakerTarget), "failures of the previous window should be discarded")
+	assert.Equal(t, 1, breakers.Stats(breakerTarget).Requests)
+}
+
+func TestNilBreakers(t *testing.T) {
+	var breakers *Breakers
+
+	assert.True(t, breakers.Allow(breakerTarget))
+	assert.True(t, breakers.Accept(&mocks.MockPeer{MockURL: breakerTarget}))
+	breakers.Record(breakerTarget, time.Second, errConnection)
+	assert.Equal(t, BreakerClosed, breakers.State(breakerTarget))
+}
+
+func TestIsEndpointFailure(t *testing.T) {
+	assert.False(t, IsEndpointFailure(nil))
+	assert.False(t, IsEndpointFailure(errors.New("some error")))
+	assert.False(t, IsEndpointFailure(status.New(status.EndorserServerStatus, 500, "chaincode error", nil)))
+	assert.True(t, IsEndpointFailure(errConnection))
+	assert.True(t, IsEndpointFailure(errors.Wrap(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "", nil), "broadcast failed")))
+	assert.True(t, IsEndpointFailure(status.New(status.ClientStatus, status.Timeout.ToInt32(), "timeout", nil)))
+	assert.True(t, IsEndpointFailure(status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "unavailable", nil)))
+	assert.True(t, IsEndpointFailure(errors.WithMessage(reqContext.DeadlineExceeded, "send failed")))
+}
@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -42,6 +43,7 @@ type CachingConnector struct {
 	waitgroup     sync.WaitGroup
 	janitorDone   chan bool
 	janitorClosed chan bool
+	metrics       *metrics.ConnectionMetrics
 }
 
 type cachedConn struct {
@@ -49,6 +51,7 @@ type cachedConn struct {
 	conn      *grpc.ClientConn
 	open      int
 	lastClose time.Time
+	draining  bool
 }
 
 // NewCachingConnector creates a GRPC connection cache. The cache is governed by
@@ -61,6 +64,7 @@ func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *Cachi
 		janitorClosed: make(chan bool, 1),
 		sweepTime:     sweepTime,
 		idleTime:      idleTime,
+		metrics:       (&metrics.ClientMetrics{}).Connections(),
 	}
 
 	// cc.janitorClosed determines if a goroutine needs to be spun up.
@@ -72,6 +76,14 @@ func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *Cachi
 	return &cc
 }
 
+// SetMetrics sets the metrics that record the number of open and idle connections and the dial latency.
+func (cc *CachingConnector) SetMetrics(m *metrics.ConnectionMetrics) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.metrics = m
+}
+
 // Close cleans up cached connections.
 func (cc *CachingConnector) Close() {
 	cc.lock.RLock()
@@ -104,6 +116,8 @@ func (cc *CachingConnector) Close() {
 	}
 
 	cc.flush()
+	cc.metrics.OpenConnections.Set(0)
+	cc.metrics.IdleConnections.Set(0)
 	close(cc.janitorClosed)
 	close(cc.janitorDone)
 	cc.janitorDone = nil
@@ -113,6 +127,14 @@ func (cc *CachingConnector) Close() {
 func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	logger.Debugf("DialContext: %s", target)
 
+	startTime := time.Now()
+	conn, err := cc.dialContext(ctx, target, opts...)
+	cc.observeDial(target, time.Since(startTime), err)
+
+	return conn, err
+}
+
+func (cc *CachingConnector) dialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	cc.lock.Lock()
 	c, ok := cc.loadConn(target)
 	if !ok {
@@ -123,18 +145,53 @@ func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts
 		}
 		c = createdConn
 	}
+	cc.updateMetrics()
 
 	cc.lock.Unlock()
 
 	if err := cc.openConn(ctx, c); err != nil {
 		cc.lock.Lock()
 		setClosed(c)
+		cc.updateMetrics()
 		cc.lock.Unlock()
 		return nil, errors.Errorf("dialing connection timed out [%s]", target)
 	}
 	return c.conn, nil
 }
 
+func (cc *CachingConnector) observeDial(target string, duration time.Duration, err error) {
+	cc.lock.RLock()
+	m := cc.metrics
+	cc.lock.RUnlock()
+
+	m.DialDuration.With(metrics.TargetLabel, target, metrics.StatusLabel, metrics.Status(err)).Observe(duration.Seconds())
+}
+
+// updateMetrics updates the number of open and idle connections. The lock must be held by the caller.
+func (cc *CachingConnector) updateMetrics() {
+	idle := 0
+	for _, c := range cc.index {
+		if c.open == 0 {
+			idle++
+		}
+	}
+	cc.metrics.OpenConnections.Set(float64(len(cc.index)))
+	cc.metrics.IdleConnections.Set(float64(idle))
+}
+
+// ConnectionStates returns the connectivity state of each cached connection, keyed by target.
+// Targets that are not in the cache have either never been dialed or have been closed due to inactivity.
+func (cc *CachingConnector) ConnectionStates() map[string]connectivity.State {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	states := make(map[string]connectivity.State, len(cc.conns))
+	for target, c := range cc.conns {
+		states[target] = c.conn.GetState()
+	}
+	return states
+}
+
 // ReleaseConn notifies the cache that the connection is no longer in use.
 func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	cc.lock.Lock()
@@ -162,10 +219,46 @@ func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	logger.Debugf("ReleaseConn [%s]", cconn.target)
 
 	setClosed(cconn)
+	if cconn.draining && cconn.open == 0 {
+		logger.Debugf("closing drained connection [%s]", cconn.target)
+		cc.removeConn(cconn)
+	}
+	cc.updateMetrics()
 
 	cc.ensureJanitorStarted()
 }
 
+// Drain stops handing out the cached connections to the given targets (or to all targets if none
+// are specified). New connections are created on the next dial. Connections that are still in use are
+// closed when they are released; idle connections are closed immediately.
+func (cc *CachingConnector) Drain(targets ...string) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	drain := func(c *cachedConn) {
+		logger.Debugf("draining connection [%s]", c.target)
+		delete(cc.conns, c.target)
+		c.draining = true
+		if c.open == 0 {
+			cc.removeConn(c)
+		}
+	}
+
+	if len(targets) == 0 {
+		for _, c := range cc.conns {
+			drain(c)
+		}
+	} else {
+		for _, target := range targets {
+			if c, ok := cc.conns[target]; ok {
+				drain(c)
+			}
+		}
+	}
+
+	cc.updateMetrics()
+}
+
 func (cc *CachingConnector) loadConn(target string) (*cachedConn, bool) {
 	c, ok := cc.conns[target]
 	if ok {
@@ -243,8 +336,8 @@ func (cc *CachingConnector) shutdownConn(cconn *cachedConn) {
 	}
 
 	logger.Debugf("connection was shutdown [%s]", cconn.target)
-	delete(cc.conns, cconn.target)
-	delete(cc.index, cconn.conn)
+	cc.deleteConn(cconn)
+	cc.updateMetrics()
 
 	cc.ensureJanitorStarted()
 }
@@ -260,17 +353,26 @@ func (cc *CachingConnector) sweepAndRemove() {
 			cc.removeConn(cachedConn)
 		}
 	}
+	cc.updateMetrics()
 }
 
 func (cc *CachingConnector) removeConn(c *cachedConn) {
 	logger.Debugf("removing connection [%s]", c.target)
-	delete(cc.index, c.conn)
-	delete(cc.conns, c.target)
+	cc.deleteConn(c)
 	if err := c.conn.Close(); err != nil {
 		logger.Debugf("unable to close connection [%s]", err)
 	}
 }
 
+// deleteConn removes the connection from the cache. A drained connection is no longer
+// cached by target, so the cached connection for its target (if any) is a newer one.
+func (cc *CachingConnector) deleteConn(c *cachedConn) {
+	delete(cc.index, c.conn)
+	if cc.conns[c.target] == c {
+		delete(cc.conns, c.target)
+	}
+}
+
 func (cc *CachingConnector) ensureJanitorStarted() {
 	select {
 	case <-cc.janitorClosed:
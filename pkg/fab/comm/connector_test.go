@@ -61,6 +61,58 @@ func TestConnectorHappyPath(t *testing.T) {
 	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "connections should not match")
 }
 
+func TestConnectorConnectionStates(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	assert.Empty(t, connector.ConnectionStates())
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+
+	states := connector.ConnectionStates()
+	require.Len(t, states, 1)
+	assert.Equal(t, conn.GetState(), states[endorserAddr[0]])
+}
+
+func TestConnectorDrain(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	dial := func(target string) *grpc.ClientConn {
+		ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+		defer cancel()
+		conn, err := connector.DialContext(ctx, target, grpc.WithInsecure())
+		require.NoError(t, err)
+		return conn
+	}
+
+	conn1 := dial(endorserAddr[0])
+	conn2 := dial(endorserAddr[1])
+	connector.ReleaseConn(conn2)
+
+	connector.Drain(endorserAddr[0], endorserAddr[1])
+
+	// The idle connection is closed immediately
+	assert.Equal(t, connectivity.Shutdown, conn2.GetState())
+	// The connection that is in use remains open until it is released
+	assert.NotEqual(t, connectivity.Shutdown, conn1.GetState())
+
+	// A new connection is created for the drained target
+	conn3 := dial(endorserAddr[0])
+	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "connections should not match")
+
+	connector.ReleaseConn(conn1)
+	assert.Equal(t, connectivity.Shutdown, conn1.GetState())
+	assert.NotEqual(t, connectivity.Shutdown, conn3.GetState())
+
+	states := connector.ConnectionStates()
+	require.Len(t, states, 1)
+	assert.Contains(t, states, endorserAddr[0])
+}
+
 func TestConnectorDoubleClose(t *testing.T) {
 	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
 	defer connector.Close()
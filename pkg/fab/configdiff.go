@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fab
+
+import (
+	"bytes"
+	"crypto/x509"
+	"reflect"
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// EndpointConfigChanges describes the differences between two endpoint configurations.
+// Peers, orderers, channels and organizations are identified by their names in the network configuration.
+type EndpointConfigChanges struct {
+	AddedPeers      []string
+	RemovedPeers    []string
+	UpdatedPeers    []string
+	AddedOrderers   []string
+	RemovedOrderers []string
+	UpdatedOrderers []string
+	AddedChannels   []string
+	RemovedChannels []string
+	UpdatedChannels []string
+	AddedOrgs       []string
+	RemovedOrgs     []string
+	UpdatedOrgs     []string
+
+	// TimeoutsChanged is true if any of the timeouts or refresh intervals changed
+	TimeoutsChanged bool
+	// TLSClientCertsChanged is true if the client certificates used for mutual TLS changed
+	TLSClientCertsChanged bool
+
+	// AffectedChannels contains the channels whose services (discovery, selection, event service, etc.)
+	// need to be refreshed as a result of the changes
+	AffectedChannels []string
+	// AllChannels is true if the services of all channels need to be refreshed as a result of the changes.
+	// This is the case if a global setting changed or if a changed peer may have been discovered on any channel.
+	AllChannels bool
+}
+
+// IsEmpty returns true if the configurations are equivalent
+func (c *EndpointConfigChanges) IsEmpty() bool {
+	return len(c.AddedPeers) == 0 && len(c.RemovedPeers) == 0 && len(c.UpdatedPeers) == 0 &&
+		len(c.AddedOrderers) == 0 && len(c.RemovedOrderers) == 0 && len(c.UpdatedOrderers) == 0 &&
+		len(c.AddedChannels) == 0 && len(c.RemovedChannels) == 0 && len(c.UpdatedChannels) == 0 &&
+		len(c.AddedOrgs) == 0 && len(c.RemovedOrgs) == 0 && len(c.UpdatedOrgs) == 0 &&
+		!c.TimeoutsChanged && !c.TLSClientCertsChanged
+}
+
+// ChangedEndpoints returns the URLs (from both configurations) of the peers and orderers that were removed
+// or updated. Connections to these endpoints should be re-established.
+func (c *EndpointConfigChanges) ChangedEndpoints(previous, current fab.EndpointConfig) []string {
+	var urls []string
+	add := func(url string) {
+		for _, u := range urls {
+			if u == url {
+				return
+			}
+		}
+		urls = append(urls, url)
+	}
+
+	prevNetwork := networkConfigOf(previous)
+	currNetwork := networkConfigOf(current)
+
+	for _, name := range append(append([]string{}, c.RemovedPeers...), c.UpdatedPeers...) {
+		if p, ok := prevNetwork.Peers[name]; ok {
+			add(p.URL)
+		}
+		if p, ok := currNetwork.Peers[name]; ok {
+			add(p.URL)
+		}
+	}
+	for _, name := range append(append([]string{}, c.RemovedOrderers...), c.UpdatedOrderers...) {
+		if o, ok := prevNetwork.Orderers[name]; ok {
+			add(o.URL)
+		}
+		if o, ok := currNetwork.Orderers[name]; ok {
+			add(o.URL)
+		}
+	}
+
+	sort.Strings(urls)
+	return urls
+}
+
+// DiffEndpointConfig compares the previous and the current endpoint configurations and returns the changes
+func DiffEndpointConfig(previous, current fab.EndpointConfig) *EndpointConfigChanges {
+	prevNetwork := networkConfigOf(previous)
+	currNetwork := networkConfigOf(current)
+
+	changes := &EndpointConfigChanges{}
+
+	changes.AddedPeers, changes.RemovedPeers, changes.UpdatedPeers = diffKeys(
+		keysOf(prevNetwork.Peers), keysOf(currNetwork.Peers),
+		func(name string) bool { return peerConfigEqual(prevNetwork.Peers[name], currNetwork.Peers[name]) },
+	)
+	changes.AddedOrderers, changes.RemovedOrderers, changes.UpdatedOrderers = diffKeys(
+		keysOf(prevNetwork.Orderers), keysOf(currNetwork.Orderers),
+		func(name string) bool {
+			return ordererConfigEqual(prevNetwork.Orderers[name], currNetwork.Orderers[name])
+		},
+	)
+	changes.AddedChannels, changes.RemovedChannels, changes.UpdatedChannels = diffKeys(
+		keysOf(prevNetwork.Channels), keysOf(currNetwork.Channels),
+		func(name string) bool {
+			return reflect.DeepEqual(prevNetwork.Channels[name], currNetwork.Channels[name])
+		},
+	)
+	changes.AddedOrgs, changes.RemovedOrgs, changes.UpdatedOrgs = diffKeys(
+		keysOf(prevNetwork.Organizations), keysOf(currNetwork.Organizations),
+		func(name string) bool {
+			return reflect.DeepEqual(prevNetwork.Organizations[name], currNetwork.Organizations[name])
+		},
+	)
+
+	changes.TimeoutsChanged = timeoutsChanged(previous, current)
+	changes.TLSClientCertsChanged = !reflect.DeepEqual(previous.TLSClientCerts(), current.TLSClientCerts())
+
+	changes.AffectedChannels, changes.AllChannels = affectedChannels(changes, prevNetwork, currNetwork)
+
+	return changes
+}
+
+func affectedChannels(changes *EndpointConfigChanges, prevNetwork, currNetwork *fab.NetworkConfig) ([]string, bool) {
+	if changes.TimeoutsChanged || changes.TLSClientCertsChanged || len(changes.UpdatedOrgs) > 0 {
+		return nil, true
+	}
+
+	changedPeers := toSet(changes.RemovedPeers, changes.UpdatedPeers)
+	changedOrderers := toSet(changes.RemovedOrderers, changes.UpdatedOrderers, changes.AddedOrderers)
+
+	// Peers may be found by dynamic discovery on any channel, so a changed peer
+	// that isn't explicitly configured for a channel affects all channels
+	for name := range changedPeers {
+		if !isChannelPeer(prevNetwork, name) && !isChannelPeer(currNetwork, name) {
+			return nil, true
+		}
+	}
+
+	affected := toSet(changes.AddedChannels, changes.RemovedChannels, changes.UpdatedChannels)
+	for _, network := range []*fab.NetworkConfig{prevNetwork, currNetwork} {
+		for channelID, chConfig := range network.Channels {
+			if referencesChanged(chConfig, changedPeers, changedOrderers) {
+				affected[channelID] = struct{}{}
+			}
+		}
+	}
+
+	var channels []string
+	for channelID := range affected {
+		channels = append(channels, channelID)
+	}
+	sort.Strings(channels)
+	return channels, false
+}
+
+func referencesChanged(chConfig fab.ChannelEndpointConfig, changedPeers, changedOrderers map[string]struct{}) bool {
+	for name := range chConfig.Peers {
+		if _, ok := changedPeers[name]; ok {
+			return true
+		}
+	}
+
+	if len(chConfig.Orderers) == 0 {
+		// The channel uses all of the configured orderers
+		return len(changedOrderers) > 0
+	}
+
+	for _, name := range chConfig.Orderers {
+		if _, ok := changedOrderers[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isChannelPeer(network *fab.NetworkConfig, name string) bool {
+	for _, chConfig := range network.Channels {
+		if _, ok := chConfig.Peers[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func timeoutsChanged(previous, current fab.EndpointConfig) bool {
+	for t := fab.PeerConnection; t <= fab.SelectionServiceRefresh; t++ {
+		if previous.Timeout(t) != current.Timeout(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerConfigEqual(p1, p2 fab.PeerConfig) bool {
+	return p1.URL == p2.URL && certEqual(p1.TLSCACert, p2.TLSCACert) && reflect.DeepEqual(p1.GRPCOptions, p2.GRPCOptions)
+}
+
+func ordererConfigEqual(o1, o2 fab.OrdererConfig) bool {
+	return o1.URL == o2.URL && certEqual(o1.TLSCACert, o2.TLSCACert) && reflect.DeepEqual(o1.GRPCOptions, o2.GRPCOptions)
+}
+
+func certEqual(c1, c2 *x509.Certificate) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	}
+	return bytes.Equal(c1.Raw, c2.Raw)
+}
+
+func networkConfigOf(config fab.EndpointConfig) *fab.NetworkConfig {
+	if networkConfig := config.NetworkConfig(); networkConfig != nil {
+		return networkConfig
+	}
+	return &fab.NetworkConfig{}
+}
+
+// diffKeys returns the keys that were added, removed, and (of the keys in both sets) the keys for which equal returns false
+func diffKeys(previous, current []string, equal func(key string) bool) (added, removed, updated []string) {
+	prevSet := toSet(previous)
+	currSet := toSet(current)
+
+	for _, key := range current {
+		if _, ok := prevSet[key]; !ok {
+			added = append(added, key)
+		} else if !equal(key) {
+			updated = append(updated, key)
+		}
+	}
+	for _, key := range previous {
+		if _, ok := currSet[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed, updated
+}
+
+func keysOf(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toSet(lists ...[]string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, list := range lists {
+		for _, item := range list {
+			set[item] = struct{}{}
+		}
+	}
+	return set
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+)
+
+const diffTestConfig = `
+client:
+  organization: org1
+channels:
+  mychannel:
+    peers:
+      peer0.org1.example.com:
+  yourchannel:
+    orderers:
+      - orderer.example.com
+    peers:
+      peer1.org1.example.com:
+organizations:
+  org1:
+    mspid: Org1MSP
+    peers:
+      - peer0.org1.example.com
+      - peer1.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com:7050
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+  peer1.org1.example.com:
+    url: grpc://peer1.org1.example.com:7051
+`
+
+func newDiffTestConfig(t *testing.T, replacements ...string) fab.EndpointConfig {
+	raw := strings.NewReplacer(replacements...).Replace(diffTestConfig)
+	backends, err := config.FromRaw([]byte(raw), "yaml")()
+	require.NoError(t, err)
+	endpointConfig, err := ConfigFromBackend(backends...)
+	require.NoError(t, err)
+	return endpointConfig
+}
+
+func TestDiffEndpointConfigNoChanges(t *testing.T) {
+	changes := DiffEndpointConfig(newDiffTestConfig(t), newDiffTestConfig(t))
+	assert.True(t, changes.IsEmpty())
+	assert.Empty(t, changes.AffectedChannels)
+	assert.False(t, changes.AllChannels)
+}
+
+func TestDiffEndpointConfigChannelPeer(t *testing.T) {
+	previous := newDiffTestConfig(t)
+	current := newDiffTestConfig(t, "peer0.org1.example.com:7051", "peer0.org1.example.com:8051")
+
+	changes := DiffEndpointConfig(previous, current)
+	require.False(t, changes.IsEmpty())
+	assert.Equal(t, []string{"peer0.org1.example.com"}, changes.UpdatedPeers)
+	assert.Empty(t, changes.AddedPeers)
+	assert.Empty(t, changes.RemovedPeers)
+	assert.False(t, changes.AllChannels)
+	assert.Equal(t, []string{"mychannel"}, changes.AffectedChannels)
+	assert.Equal(t, []string{"grpc://peer0.org1.example.com:7051", "grpc://peer0.org1.example.com:8051"}, changes.ChangedEndpoints(previous, current))
+}
+
+func TestDiffEndpointConfigOrderer(t *testing.T) {
+	previous := newDiffTestConfig(t)
+	current := newDiffTestConfig(t, "orderer.example.com:7050", "orderer.example.com:8050")
+
+	changes := DiffEndpointConfig(previous, current)
+	assert.Equal(t, []string{"orderer.example.com"}, changes.UpdatedOrderers)
+	assert.False(t, changes.AllChannels)
+	// mychannel doesn't specify orderers so it uses all of them
+	assert.Equal(t, []string{"mychannel", "yourchannel"}, changes.AffectedChannels)
+}
+
+func TestDiffEndpointConfigGlobalChanges(t *testing.T) {
+	previous := newDiffTestConfig(t)
+	current := newDiffTestConfig(t, "client:\n  organization: org1", "client:\n  organization: org1\n  global:\n    timeout:\n      query: 99s")
+
+	changes := DiffEndpointConfig(previous, current)
+	assert.True(t, changes.TimeoutsChanged)
+	assert.True(t, changes.AllChannels)
+	assert.False(t, changes.IsEmpty())
+}
+
+func TestDiffEndpointConfigRemovedPeer(t *testing.T) {
+	previous := newDiffTestConfig(t, "      - peer1.org1.example.com\n", "      - peer1.org1.example.com\n      - peer2.org1.example.com\n",
+		"peers:\n  peer0", "peers:\n  peer2.org1.example.com:\n    url: grpc://peer2.org1.example.com:7051\n  peer0")
+	current := newDiffTestConfig(t)
+
+	changes := DiffEndpointConfig(previous, current)
+	assert.Equal(t, []string{"peer2.org1.example.com"}, changes.RemovedPeers)
+	assert.Equal(t, []string{"org1"}, changes.UpdatedOrgs)
+	// The peer isn't a channel peer so it may have been discovered on any channel
+	assert.True(t, changes.AllChannels)
+	assert.Equal(t, []string{"grpc://peer2.org1.example.com:7051"}, changes.ChangedEndpoints(previous, current))
+}
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
@@ -133,6 +135,7 @@ type EndpointConfig struct {
 	channelPeersByChannel    map[string][]fab.ChannelPeer
 	channelOrderersByChannel map[string][]fab.OrdererConfig
 	tlsClientCerts           []tls.Certificate
+	tlsClientKeyPair         *tlsClientKeyPair
 	peerMatchers             []matcherEntry
 	ordererMatchers          []matcherEntry
 	channelMatchers          []matcherEntry
@@ -273,6 +276,9 @@ func (c *EndpointConfig) TLSCACertPool() fab.CertPool {
 
 // TLSClientCerts loads the client's certs for mutual TLS
 func (c *EndpointConfig) TLSClientCerts() []tls.Certificate {
+	if c.tlsClientKeyPair != nil {
+		return c.tlsClientKeyPair.certificates()
+	}
 	return c.tlsClientCerts
 }
 
@@ -291,6 +297,60 @@ func (c *EndpointConfig) loadPrivateKeyFromConfig(clientConfig *ClientConfig, cl
 	return []tls.Certificate{clientCerts}, nil
 }
 
+// loadTLSClientKeyPair loads the TLS client certs of a private key that's stored as a secret
+func (c *EndpointConfig) loadTLSClientKeyPair(clientConfig *ClientConfig) error {
+	keyPair := &tlsClientKeyPair{cert: clientConfig.TLSCerts.Client.Cert, key: clientConfig.TLSCerts.Client.Key}
+	if _, err := keyPair.load(); err != nil {
+		return errors.WithMessage(err, "failed to load TLS client certs")
+	}
+
+	logger.Debug("pk resolved from secret successfully")
+
+	c.tlsClientKeyPair = keyPair
+	return nil
+}
+
+// tlsClientKeyPair holds the TLS client certs of a private key that's stored as a secret. The key
+// is resolved on use so that the certs are rebuilt when the key is rotated.
+type tlsClientKeyPair struct {
+	cert   endpoint.TLSConfig
+	key    endpoint.TLSConfig
+	lock   sync.Mutex
+	certPB []byte
+	keyPB  []byte
+	certs  []tls.Certificate
+}
+
+// certificates returns the certs for the current key, or the certs of the previous key if
+// the current key can't be loaded
+func (p *tlsClientKeyPair) certificates() []tls.Certificate {
+	certs, err := p.load()
+	if err != nil {
+		logger.Warnf("Failed to load TLS client certs with the rotated key, using the previous key: %s", err)
+	}
+	return certs
+}
+
+func (p *tlsClientKeyPair) load() ([]tls.Certificate, error) {
+	cb := p.cert.Bytes()
+	kb := p.key.Bytes()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.certs != nil && bytes.Equal(cb, p.certPB) && bytes.Equal(kb, p.keyPB) {
+		return p.certs, nil
+	}
+
+	clientCerts, err := tls.X509KeyPair(cb, kb)
+	if err != nil {
+		return p.certs, errors.Errorf("Error loading cert/key pair as TLS client credentials: %s", err)
+	}
+
+	p.certPB, p.keyPB, p.certs = cb, kb, []tls.Certificate{clientCerts}
+	return p.certs, nil
+}
+
 // CryptoConfigPath ...
 func (c *EndpointConfig) CryptoConfigPath() string {
 	return pathvar.Subst(c.backend.GetString("client.cryptoconfig.path"))
@@ -1379,6 +1439,9 @@ func (c *EndpointConfig) loadTLSClientCerts(configEntity *endpointConfigEntity)
 	// If CryptoSuite fails to load private key from cert then load private key from config
 	if err != nil || pk == nil {
 		logger.Debugf("Reading pk from config, unable to retrieve from cert: %s", err)
+		if configEntity.Client.TLSCerts.Client.Key.IsSecret() {
+			return c.loadTLSClientKeyPair(&configEntity.Client)
+		}
 		tlsClientCerts, error := c.loadPrivateKeyFromConfig(&configEntity.Client, clientCerts, cb)
 		if error != nil {
 			return errors.WithMessage(error, "failed to load TLS client certs")
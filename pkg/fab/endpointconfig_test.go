@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -28,7 +29,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
 )
@@ -991,6 +994,54 @@ YZjcDi7YEOZ3Fs1hxKmIxR+TTR2vf9I=
 	}
 }
 
+func TestTLSClientCertsFromSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlssecret")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writeKeyPair := func() *testcert.Certificate {
+		cert, e := testcert.New(testcert.WithCommonName("sdk_go"))
+		require.NoError(t, e)
+		keyPEM, e := cert.KeyPEM()
+		require.NoError(t, e)
+		require.NoError(t, ioutil.WriteFile(certFile, cert.PEM(), 0600))
+		require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0600))
+		return cert
+	}
+	cert := writeKeyPair()
+
+	clientConfig := &ClientConfig{}
+	clientConfig.TLSCerts.Client.Cert.Path = "file://" + certFile
+	clientConfig.TLSCerts.Client.Key.Path = "file://" + keyFile
+	require.NoError(t, clientConfig.TLSCerts.Client.Cert.LoadBytes())
+	require.NoError(t, clientConfig.TLSCerts.Client.Key.LoadBytes())
+
+	config := &EndpointConfig{}
+	require.NoError(t, config.loadTLSClientKeyPair(clientConfig))
+
+	certs := config.TLSClientCerts()
+	require.Len(t, certs, 1)
+	assert.Equal(t, cert.Cert.Raw, certs[0].Certificate[0])
+
+	// the key is resolved on use so the rotated key pair is picked up once the secrets are refreshed
+	rotated := writeKeyPair()
+	require.NoError(t, secret.Refresh())
+
+	certs = config.TLSClientCerts()
+	require.Len(t, certs, 1)
+	assert.Equal(t, rotated.Cert.Raw, certs[0].Certificate[0])
+
+	// the previous key pair is used if the rotated key can't be loaded
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("invalid"), 0600))
+	require.NoError(t, secret.Refresh())
+
+	certs = config.TLSClientCerts()
+	require.Len(t, certs, 1)
+	assert.Equal(t, rotated.Cert.Raw, certs[0].Certificate[0])
+}
+
 func TestTLSClientCertsPemBeforeFiles(t *testing.T) {
 
 	clientTLSOverride := endpoint.MutualTLSConfig{}
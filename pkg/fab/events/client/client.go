@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package client
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -47,19 +48,30 @@ type Client struct {
 	registerOnce    sync.Once
 	afterConnect    handler
 	beforeReconnect handler
+	logCtx          context.Context
 }
 
 type handler func() error
 
+type channelConfigProvider interface {
+	ChannelConfig() fab.ChannelCfg
+}
+
 // New returns a new event client
 func New(dispatcher eventservice.Dispatcher, opts ...options.Opt) *Client {
 	params := defaultParams()
 	options.Apply(params, opts)
 
+	logCtx := context.Background()
+	if p, ok := dispatcher.(channelConfigProvider); ok {
+		logCtx = logging.WithFields(logCtx, logging.KV(logging.ChannelKey, p.ChannelConfig().ID()))
+	}
+
 	return &Client{
 		Service:         eventservice.New(dispatcher, opts...),
 		params:          *params,
 		connectionState: int32(Disconnected),
+		logCtx:          logCtx,
 	}
 }
 
@@ -219,7 +231,7 @@ func (c *Client) connect() error {
 
 	if err != nil {
 		c.mustSetConnectionState(Disconnected)
-		logger.Debugf("... got error in connection response: %s", err)
+		logger.Debugw(c.logCtx, "... got error in connection response", logging.KV(logging.ErrorKey, err))
 		return err
 	}
 
@@ -227,7 +239,7 @@ func (c *Client) connect() error {
 		logger.Debug("Submitting connection event registration...")
 		_, eventch, err1 := c.registerConnectionEvent()
 		if err != nil {
-			logger.Errorf("Error registering for connection events: %s", err1)
+			logger.Errorw(c.logCtx, "Error registering for connection events", logging.KV(logging.ErrorKey, err1))
 			c.Close()
 		}
 		c.connEvent = eventch
@@ -247,23 +259,23 @@ func (c *Client) connect() error {
 	logger.Debug("Submitting connected event")
 	err2 := c.Submit(dispatcher.NewConnectedEvent())
 	if err2 != nil {
-		logger.Warnf("Submit failed %s", err2)
+		logger.Warnw(c.logCtx, "Submit failed", logging.KV(logging.ErrorKey, err2))
 	}
 	return err
 }
 
 func (c *Client) t(handlerImp handler, errch chan error) error {
 	if err1 := handlerImp(); err1 != nil {
-		logger.Warnf("Error invoking afterConnect handler: %s. Disconnecting...", err1)
+		logger.Warnw(c.logCtx, "Error invoking afterConnect handler. Disconnecting...", logging.KV(logging.ErrorKey, err1))
 
 		err2 := c.Submit(dispatcher.NewDisconnectEvent(errch))
 		if err2 != nil {
-			logger.Warnf("Submit failed %s", err2)
+			logger.Warnw(c.logCtx, "Submit failed", logging.KV(logging.ErrorKey, err2))
 		}
 		select {
 		case disconnErr := <-errch:
 			if disconnErr != nil {
-				logger.Warnf("Received error from disconnect request: %s", disconnErr)
+				logger.Warnw(c.logCtx, "Received error from disconnect request", logging.KV(logging.ErrorKey, disconnErr))
 			} else {
 				logger.Debug("Received success from disconnect request")
 			}
@@ -289,9 +301,9 @@ func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts time.Dur
 	var attempts uint
 	for {
 		attempts++
-		logger.Debugf("Attempt #%d to connect...", attempts)
+		logger.Debugw(c.logCtx, "Attempting to connect...", logging.KV("attempt", attempts))
 		if err := c.connect(); err != nil {
-			logger.Warnf("... connection attempt failed: %s", err)
+			logger.Warnw(c.logCtx, "... connection attempt failed", logging.KV("attempt", attempts), logging.KV(logging.ErrorKey, err))
 			if maxAttempts > 0 && attempts >= maxAttempts {
 				logger.Warn("maximum connect attempts exceeded")
 				return errors.New("maximum connect attempts exceeded")
@@ -374,10 +386,10 @@ func (c *Client) monitorConnection() {
 		if event.Connected {
 			logger.Debug("Event client has connected")
 		} else if c.reconn {
-			logger.Warnf("Event client has disconnected. Details: %s", event.Err)
+			logger.Warnw(c.logCtx, "Event client has disconnected", logging.KV(logging.ErrorKey, event.Err))
 			if c.setConnectionState(Connected, Disconnected) {
 				if event.Err.IsFatal() {
-					logger.Warnf("Reconnect is not possible due to fatal error. Terminating: %s", event.Err)
+					logger.Warnw(c.logCtx, "Reconnect is not possible due to fatal error. Terminating", logging.KV(logging.ErrorKey, event.Err))
 					go c.Close()
 					break
 				}
@@ -388,7 +400,7 @@ func (c *Client) monitorConnection() {
 				logger.Warn("Reconnect already in progress. Setting state to disconnected")
 			}
 		} else {
-			logger.Debugf("Event client has disconnected. Terminating: %s", event.Err)
+			logger.Debugw(c.logCtx, "Event client has disconnected. Terminating", logging.KV(logging.ErrorKey, event.Err))
 			go c.Close()
 			break
 		}
@@ -397,7 +409,7 @@ func (c *Client) monitorConnection() {
 }
 
 func (c *Client) reconnect() {
-	logger.Debugf("Waiting %s before attempting to reconnect event client...", c.reconnInitialDelay)
+	logger.Debugw(c.logCtx, "Waiting before attempting to reconnect event client...", logging.KV("delay", c.reconnInitialDelay))
 	time.Sleep(c.reconnInitialDelay)
 
 	logger.Debug("Attempting to reconnect event client...")
@@ -405,16 +417,16 @@ func (c *Client) reconnect() {
 	handlerImp := c.beforeReconnectHandler()
 	if handlerImp != nil {
 		if err := handlerImp(); err != nil {
-			logger.Errorf("Error invoking beforeReconnect handler: %s", err)
+			logger.Errorw(c.logCtx, "Error invoking beforeReconnect handler", logging.KV(logging.ErrorKey, err))
 			return
 		}
 	}
 
 	if err := c.connectWithRetry(c.maxReconnAttempts, c.timeBetweenConnAttempts); err != nil {
-		logger.Warnf("Could not reconnect event client: %s. Closing.", err)
+		logger.Warnw(c.logCtx, "Could not reconnect event client. Closing.", logging.KV(logging.ErrorKey, err))
 		c.Close()
 	} else {
-		logger.Infof("Event client has reconnected")
+		logger.Infow(c.logCtx, "Event client has reconnected")
 	}
 }
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	reqContext "context"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/peerresolver"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -37,7 +39,11 @@ type Dispatcher struct {
 	discoveryService       fab.DiscoveryService
 	peerResolver           peerresolver.Resolver
 	peerMonitorDone        chan struct{}
+	metrics                *metrics.EventMetrics
+	connectedURL           string
+	hasConnected           bool
 	peer                   fab.Peer
+	logCtx                 reqContext.Context
 	lock                   sync.RWMutex
 }
 
@@ -46,13 +52,20 @@ func New(context context.Client, chConfig fab.ChannelCfg, discoveryService fab.D
 	params := defaultParams(context, chConfig.ID())
 	options.Apply(params, opts)
 
+	eventMetrics := context.GetMetrics().Events()
+
+	// Registration metrics are recorded by the event service dispatcher
+	esOpts := append([]options.Opt{esdispatcher.WithMetrics(chConfig.ID(), eventMetrics)}, opts...)
+
 	dispatcher := &Dispatcher{
-		Dispatcher:         esdispatcher.New(opts...),
+		Dispatcher:         esdispatcher.New(esOpts...),
 		params:             *params,
 		context:            context,
 		chConfig:           chConfig,
 		discoveryService:   discoveryService,
 		connectionProvider: connectionProvider,
+		metrics:            eventMetrics,
+		logCtx:             logging.WithFields(reqContext.Background(), logging.KV(logging.ChannelKey, chConfig.ID())),
 	}
 	dispatcher.peerResolver = params.peerResolverProvider(dispatcher, context, chConfig.ID(), opts...)
 
@@ -128,13 +141,14 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 
 	conn, err := ed.connectionProvider(ed.context, ed.chConfig, peer)
 	if err != nil {
-		logger.Warnf("error creating connection: %s", err)
+		logger.Warnw(ed.logCtx, "Error creating connection", logging.KV(logging.PeerKey, peer.URL()), logging.KV(logging.ErrorKey, err))
 		evt.ErrCh <- errors.WithMessagef(err, "could not create client conn")
 		return
 	}
 
 	ed.connection = conn
 	ed.setConnectedPeer(peer)
+	ed.connectionOpened(peer.URL())
 
 	go ed.connection.Receive(eventch)
 
@@ -150,11 +164,12 @@ func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
 		return
 	}
 
-	logger.Debug("Closing connection due to disconnect event...")
+	logger.Debugw(ed.logCtx, "Closing connection due to disconnect event")
 
 	ed.connection.Close()
 	ed.connection = nil
 	ed.setConnectedPeer(nil)
+	ed.connectionClosed()
 
 	evt.Errch <- nil
 }
@@ -176,7 +191,7 @@ func (ed *Dispatcher) HandleRegisterConnectionEvent(e esdispatcher.Event) {
 func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 	evt := e.(*ConnectedEvent)
 
-	logger.Debugf("Handling connected event: %+v", evt)
+	logger.Debugw(ed.logCtx, "Handling connected event", logging.KV("event", evt))
 
 	if ed.connectionRegistration != nil && ed.connectionRegistration.Eventch != nil {
 		select {
@@ -196,22 +211,23 @@ func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 	evt := e.(*DisconnectedEvent)
 
-	logger.Debugf("Disconnecting from event server: %s", evt.Err)
+	logger.Debugw(ed.logCtx, "Disconnecting from event server", logging.KV(logging.ErrorKey, evt.Err))
 
 	if ed.connection != nil {
 		ed.connection.Close()
 		ed.connection = nil
+		ed.connectionClosed()
 	}
 
 	if ed.connectionRegistration != nil {
-		logger.Debugf("Disconnected from event server: %s", evt.Err)
+		logger.Debugw(ed.logCtx, "Disconnected from event server", logging.KV(logging.ErrorKey, evt.Err))
 		select {
 		case ed.connectionRegistration.Eventch <- NewConnectionEvent(false, evt.Err):
 		default:
 			logger.Warn("Unable to send to connection event channel.")
 		}
 	} else {
-		logger.Warnf("Disconnected from event server: %s", evt.Err)
+		logger.Warnw(ed.logCtx, "Disconnected from event server", logging.KV(logging.ErrorKey, evt.Err))
 	}
 
 	if ed.peerMonitorDone != nil {
@@ -241,7 +257,7 @@ func (ed *Dispatcher) clearConnectionRegistration() {
 }
 
 func (ed *Dispatcher) monitorPeer(done chan struct{}) {
-	logger.Debugf("Starting peer monitor on channel [%s]", ed.chConfig.ID())
+	logger.Debugw(ed.logCtx, "Starting peer monitor")
 
 	ticker := time.NewTicker(ed.peerMonitorPeriod)
 	defer ticker.Stop()
@@ -251,11 +267,11 @@ func (ed *Dispatcher) monitorPeer(done chan struct{}) {
 		case <-ticker.C:
 			if ed.disconnected() {
 				// Disconnected
-				logger.Debugf("Client on channel [%s] has disconnected - stopping disconnect monitor", ed.chConfig.ID())
+				logger.Debugw(ed.logCtx, "Client has disconnected - stopping disconnect monitor")
 				return
 			}
 		case <-done:
-			logger.Debugf("Stopping block height monitor on channel [%s]", ed.chConfig.ID())
+			logger.Debugw(ed.logCtx, "Stopping block height monitor")
 			return
 		}
 	}
@@ -270,27 +286,29 @@ func (ed *Dispatcher) disconnected() bool {
 		return false
 	}
 
-	logger.Debugf("Checking if event client should disconnect from peer [%s] on channel [%s]...", connectedPeer.URL(), ed.chConfig.ID())
+	peerField := logging.KV(logging.PeerKey, connectedPeer.URL())
+
+	logger.Debugw(ed.logCtx, "Checking if event client should disconnect from peer", peerField)
 
 	peers, err := ed.discoveryService.GetPeers()
 	if err != nil {
-		logger.Warnf("Error calling peer resolver: %s", err)
+		logger.Warnw(ed.logCtx, "Error calling peer resolver", logging.KV(logging.ErrorKey, err))
 		return false
 	}
 
 	if !ed.peerResolver.ShouldDisconnect(peers, connectedPeer) {
-		logger.Debugf("Event client will not disconnect from peer [%s] on channel [%s]...", connectedPeer.URL(), ed.chConfig.ID())
+		logger.Debugw(ed.logCtx, "Event client will not disconnect from peer", peerField)
 		return false
 	}
 
-	logger.Warnf("The peer resolver determined that the event client should be disconnected from connected peer [%s] on channel [%s]. Disconnecting ...", connectedPeer.URL(), ed.chConfig.ID())
+	logger.Warnw(ed.logCtx, "The peer resolver determined that the event client should be disconnected from connected peer. Disconnecting ...", peerField)
 
 	if err := ed.disconnect(); err != nil {
-		logger.Warnf("Error disconnecting event client from peer [%s] on channel [%s]: %s", connectedPeer.URL(), ed.chConfig.ID(), err)
+		logger.Warnw(ed.logCtx, "Error disconnecting event client from peer", peerField, logging.KV(logging.ErrorKey, err))
 		return false
 	}
 
-	logger.Warnf("Successfully disconnected event client from peer [%s] on channel [%s]", connectedPeer.URL(), ed.chConfig.ID())
+	logger.Warnw(ed.logCtx, "Successfully disconnected event client from peer", peerField)
 	return true
 }
 
@@ -312,6 +330,22 @@ func (ed *Dispatcher) disconnect() error {
 	return nil
 }
 
+// connectionOpened records a new connection to the given peer. Every connection after
+// the first one is counted as a reconnect.
+func (ed *Dispatcher) connectionOpened(peerURL string) {
+	ed.connectedURL = peerURL
+	ed.metrics.Connections.With(metrics.ChannelLabel, ed.chConfig.ID(), metrics.PeerLabel, peerURL).Add(1)
+
+	if ed.hasConnected {
+		ed.metrics.Reconnects.With(metrics.ChannelLabel, ed.chConfig.ID(), metrics.PeerLabel, peerURL).Add(1)
+	}
+	ed.hasConnected = true
+}
+
+func (ed *Dispatcher) connectionClosed() {
+	ed.metrics.Connections.With(metrics.ChannelLabel, ed.chConfig.ID(), metrics.PeerLabel, ed.connectedURL).Add(-1)
+}
+
 func (ed *Dispatcher) setConnectedPeer(peer fab.Peer) {
 	ed.lock.Lock()
 	defer ed.lock.Unlock()
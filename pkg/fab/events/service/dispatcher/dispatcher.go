@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -189,6 +190,7 @@ func (ed *Dispatcher) initRegistrations() error {
 		}
 	}
 
+	ed.updateRegistrationMetrics()
 	return nil
 }
 
@@ -197,6 +199,7 @@ func (ed *Dispatcher) clearRegistrations(closeChannel bool) {
 	ed.clearFilteredBlockRegistrations(closeChannel)
 	ed.clearTxRegistrations(closeChannel)
 	ed.clearChaincodeRegistrations(closeChannel)
+	ed.updateRegistrationMetrics()
 }
 
 // clearBlockRegistrations removes all block registrations and closes the corresponding event channels.
@@ -297,6 +300,7 @@ func (ed *Dispatcher) handleRegisterBlockEvent(e Event) {
 	event := e.(*RegisterBlockEvent)
 
 	ed.registerBlockEvent(event.Reg)
+	ed.updateRegistrationMetrics()
 	event.RegCh <- event.Reg
 }
 
@@ -307,6 +311,7 @@ func (ed *Dispatcher) registerBlockEvent(reg *BlockReg) {
 func (ed *Dispatcher) handleRegisterFilteredBlockEvent(e Event) {
 	event := e.(*RegisterFilteredBlockEvent)
 	ed.registerFilteredBlockEvent(event.Reg)
+	ed.updateRegistrationMetrics()
 	event.RegCh <- event.Reg
 }
 
@@ -325,6 +330,7 @@ func (ed *Dispatcher) handleRegisterCCEvent(e Event) {
 		if err := ed.registerCCEvent(event.Reg); err != nil {
 			event.ErrCh <- err
 		} else {
+			ed.updateRegistrationMetrics()
 			event.RegCh <- event.Reg
 		}
 	}
@@ -345,6 +351,7 @@ func (ed *Dispatcher) handleRegisterTxStatusEvent(e Event) {
 	if err := ed.registerTxStatusEvent(event.Reg); err != nil {
 		event.ErrCh <- err
 	} else {
+		ed.updateRegistrationMetrics()
 		event.RegCh <- event.Reg
 	}
 }
@@ -376,6 +383,7 @@ func (ed *Dispatcher) handleUnregisterEvent(e Event) {
 	if err != nil {
 		logger.Warnf("Error in unregister: %s", err)
 	}
+	ed.updateRegistrationMetrics()
 }
 
 func (ed *Dispatcher) handleBlockEvent(e Event) {
@@ -435,6 +443,11 @@ func (ed *Dispatcher) HandleBlock(block *cb.Block, sourceURL string) {
 		return
 	}
 
+	ed.metrics.BlocksReceived.With(metrics.ChannelLabel, ed.channelID, metrics.PeerLabel, sourceURL, metrics.TypeLabel, "block").Add(1)
+	if lag, ok := blockLag(block); ok {
+		ed.metrics.BlockLag.With(metrics.ChannelLabel, ed.channelID, metrics.PeerLabel, sourceURL).Observe(lag.Seconds())
+	}
+
 	if ed.updateLastBlockInfoOnly {
 		ed.updateLastBlockInfoOnly = false
 		return
@@ -454,6 +467,8 @@ func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL st
 		return
 	}
 
+	ed.metrics.BlocksReceived.With(metrics.ChannelLabel, ed.channelID, metrics.PeerLabel, sourceURL, metrics.TypeLabel, "filtered_block").Add(1)
+
 	if ed.updateLastBlockInfoOnly {
 		ed.updateLastBlockInfoOnly = false
 		return
@@ -463,6 +478,33 @@ func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL st
 	ed.publishFilteredBlockEvents(fblock, sourceURL)
 }
 
+func (ed *Dispatcher) updateRegistrationMetrics() {
+	registrations := ed.metrics.Registrations.With(metrics.ChannelLabel, ed.channelID)
+	registrations.With(metrics.TypeLabel, "block").Set(float64(len(ed.blockRegistrations)))
+	registrations.With(metrics.TypeLabel, "filtered_block").Set(float64(len(ed.filteredBlockRegistrations)))
+	registrations.With(metrics.TypeLabel, "chaincode").Set(float64(len(ed.ccRegistrations)))
+	registrations.With(metrics.TypeLabel, "tx_status").Set(float64(len(ed.txRegistrations)))
+}
+
+// blockLag returns the time elapsed since the first transaction in the block was created
+func blockLag(block *cb.Block) (time.Duration, bool) {
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return 0, false
+	}
+
+	env, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[0])
+	if err != nil {
+		return 0, false
+	}
+
+	chdr, err := protoutil.ChannelHeader(env)
+	if err != nil || chdr.Timestamp == nil {
+		return 0, false
+	}
+
+	return time.Since(time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))), true
+}
+
 func (ed *Dispatcher) unregisterBlockEvents(registration *BlockReg) error {
 	for i, reg := range ed.blockRegistrations {
 		if reg == registration {
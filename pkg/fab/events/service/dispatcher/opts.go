@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -22,12 +23,15 @@ type params struct {
 	initialFilteredBlockRegistrations []*FilteredBlockReg
 	initialCCRegistrations            []*ChaincodeReg
 	initialTxStatusRegistrations      []*TxStatusReg
+	channelID                         string
+	metrics                           *metrics.EventMetrics
 }
 
 func defaultParams() *params {
 	return &params{
 		eventConsumerBufferSize: 100,
 		eventConsumerTimeout:    500 * time.Millisecond,
+		metrics:                 (&metrics.ClientMetrics{}).Events(),
 	}
 }
 
@@ -64,6 +68,15 @@ func WithSnapshot(value fab.EventSnapshot) options.Opt {
 	}
 }
 
+// WithMetrics sets the metrics that are recorded for blocks and registrations on the given channel.
+func WithMetrics(channelID string, value *metrics.EventMetrics) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(metricsSetter); ok {
+			setter.SetMetrics(channelID, value)
+		}
+	}
+}
+
 type eventConsumerBufferSizeSetter interface {
 	SetEventConsumerBufferSize(value uint)
 }
@@ -82,6 +95,15 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	p.eventConsumerTimeout = value
 }
 
+type metricsSetter interface {
+	SetMetrics(channelID string, value *metrics.EventMetrics)
+}
+
+func (p *params) SetMetrics(channelID string, value *metrics.EventMetrics) {
+	p.channelID = channelID
+	p.metrics = value
+}
+
 type snapshotSetter interface {
 	SetSnapshot(value fab.EventSnapshot) error
 }
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/aesgcm"
+	"github.com/pkg/errors"
+)
+
+const dataKeySize = 32
+
+// envelopeMagic prefixes envelope-encrypted values
+var envelopeMagic = []byte("ENV1")
+
+// KeyWrapper wraps (encrypts) and unwraps the data keys of envelope-encrypted values.
+// Implementations may keep the key-encryption key locally or delegate to a key management service.
+type KeyWrapper interface {
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(wrappedKey []byte) ([]byte, error)
+}
+
+// aesKeyWrapper wraps data keys with AES-GCM using a local key-encryption key
+type aesKeyWrapper struct {
+	cipher *aesgcm.Cipher
+}
+
+// NewAESKeyWrapper returns a KeyWrapper that wraps data keys with AES-GCM using the given
+// key-encryption key (which must be 16, 24 or 32 bytes long)
+func NewAESKeyWrapper(kek []byte) (KeyWrapper, error) {
+	c, err := aesgcm.New(kek)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid key-encryption key")
+	}
+	return &aesKeyWrapper{cipher: c}, nil
+}
+
+// WrapKey encrypts the given data key
+func (w *aesKeyWrapper) WrapKey(dataKey []byte) ([]byte, error) {
+	return w.cipher.Seal(dataKey, nil)
+}
+
+// UnwrapKey decrypts the given wrapped data key
+func (w *aesKeyWrapper) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	return w.cipher.Open(wrappedKey, nil)
+}
+
+// SealEnvelope encrypts the value with a new random data key and returns the envelope,
+// which contains the data key (wrapped by the key wrapper) along with the encrypted value
+func SealEnvelope(wrapper KeyWrapper, value []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "failed to generate data key")
+	}
+
+	wrappedKey, err := wrapper.WrapKey(dataKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to wrap data key")
+	}
+	if len(wrappedKey) > 0xffff {
+		return nil, errors.New("wrapped data key is too long")
+	}
+
+	c, err := aesgcm.New(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := c.Seal(value, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, len(envelopeMagic)+2+len(wrappedKey)+len(ciphertext))
+	envelope = append(envelope, envelopeMagic...)
+	envelope = append(envelope, byte(len(wrappedKey)>>8), byte(len(wrappedKey)))
+	envelope = append(envelope, wrappedKey...)
+	return append(envelope, ciphertext...), nil
+}
+
+// OpenEnvelope decrypts a value that was encrypted with SealEnvelope
+func OpenEnvelope(wrapper KeyWrapper, envelope []byte) ([]byte, error) {
+	if !IsEnvelope(envelope) || len(envelope) < len(envelopeMagic)+2 {
+		return nil, errors.New("value is not envelope encrypted")
+	}
+
+	rest := envelope[len(envelopeMagic):]
+	keyLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < keyLen {
+		return nil, errors.New("envelope is truncated")
+	}
+
+	dataKey, err := wrapper.UnwrapKey(rest[:keyLen])
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to unwrap data key")
+	}
+
+	c, err := aesgcm.New(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open(rest[keyLen:], nil)
+}
+
+// IsEnvelope returns true if the value appears to be envelope encrypted
+func IsEnvelope(value []byte) bool {
+	return bytes.HasPrefix(value, envelopeMagic)
+}
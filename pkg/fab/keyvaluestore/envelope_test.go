@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	_, err := NewAESKeyWrapper([]byte("short"))
+	assert.Error(t, err)
+
+	wrapper, err := NewAESKeyWrapper(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+
+	envelope, err := SealEnvelope(wrapper, []byte("private key"))
+	require.NoError(t, err)
+	assert.True(t, IsEnvelope(envelope))
+
+	value, err := OpenEnvelope(wrapper, envelope)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("private key"), value)
+
+	// Each envelope has its own data key
+	other, err := SealEnvelope(wrapper, []byte("private key"))
+	require.NoError(t, err)
+	assert.NotEqual(t, envelope, other)
+
+	_, err = OpenEnvelope(wrapper, []byte("private key"))
+	assert.Error(t, err)
+
+	_, err = OpenEnvelope(wrapper, envelope[:len(envelope)-1])
+	assert.Error(t, err)
+
+	_, err = OpenEnvelope(wrapper, envelope[:8])
+	assert.Error(t, err)
+}
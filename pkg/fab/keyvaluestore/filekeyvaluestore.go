@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/fileutil"
 	"github.com/pkg/errors"
 )
 
@@ -146,7 +147,7 @@ func (fkvs *FileKeyValueStore) Store(key interface{}, value interface{}) error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(file, valueBytes, newFileMode)
+	return fileutil.WriteFileAtomic(file, valueBytes, newFileMode)
 }
 
 // Delete deletes the value for a key.
@@ -0,0 +1,335 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const defaultSQLTable = "kvstore"
+
+// likeEscape escapes the wildcards of LIKE patterns. It isn't a backslash since backslashes
+// are escape characters in the string literals of some databases (e.g. MySQL).
+const likeEscape = '!'
+
+var (
+	sqlTableName      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	likePatternEscape = strings.NewReplacer(string(likeEscape), string(likeEscape)+string(likeEscape), "%", string(likeEscape)+"%", "_", string(likeEscape)+"_")
+)
+
+// SQLDialect describes the differences in SQL syntax between databases
+type SQLDialect struct {
+	// Placeholder returns the placeholder of the n-th (1-based) argument of a statement
+	Placeholder func(n int) string
+	// KeyType is the column type of the keys
+	KeyType string
+	// ValueType is the column type of the values
+	ValueType string
+	// QuoteIdentifier quotes the name of the table so that reserved words can be used as names.
+	// Optional. If not provided, the name isn't quoted.
+	QuoteIdentifier func(name string) string
+}
+
+var (
+	// SQLiteDialect is the dialect of SQLite (the default)
+	SQLiteDialect = SQLDialect{Placeholder: questionMark, KeyType: "VARCHAR(512)", ValueType: "BLOB", QuoteIdentifier: doubleQuote}
+	// MySQLDialect is the dialect of MySQL and MariaDB
+	MySQLDialect = SQLDialect{Placeholder: questionMark, KeyType: "VARCHAR(512)", ValueType: "LONGBLOB", QuoteIdentifier: backQuote}
+	// PostgresDialect is the dialect of PostgreSQL
+	PostgresDialect = SQLDialect{Placeholder: dollarNumber, KeyType: "VARCHAR(512)", ValueType: "BYTEA", QuoteIdentifier: doubleQuote}
+)
+
+func questionMark(int) string {
+	return "?"
+}
+
+func dollarNumber(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// doubleQuote quotes an identifier as defined by standard SQL. Table names are validated
+// against sqlTableName so they can't contain quotes.
+func doubleQuote(name string) string {
+	return `"` + name + `"`
+}
+
+// backQuote quotes an identifier as MySQL does by default (without ANSI_QUOTES)
+func backQuote(name string) string {
+	return "`" + name + "`"
+}
+
+// SQLKeyValueStore stores the key-value pairs in a table of a SQL database.
+// KeySerializer maps a key to a unique string; Marshaller and Unmarshaller
+// serialize/de-serialize a value to and from a byte array. If a KeyWrapper
+// is provided then the values are envelope encrypted.
+//
+// The store doesn't register a SQL driver: the application imports the driver of
+// its database (e.g. SQLite) and opens the database with database/sql.
+type SQLKeyValueStore struct {
+	db            *sql.DB
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+	keyWrapper    KeyWrapper
+
+	selectQuery string
+	insertQuery string
+	updateQuery string
+	deleteQuery string
+	keysQuery   string
+}
+
+// SQLKeyValueStoreOptions allow overriding store defaults
+type SQLKeyValueStoreOptions struct {
+	// Database, mandatory
+	DB *sql.DB
+	// Optional. Name of the table (created if it doesn't exist). If not provided, "kvstore" is used.
+	Table string
+	// Optional. If not provided, SQLiteDialect is used.
+	Dialect *SQLDialect
+	// Optional. If not provided, keys must be strings.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+	// Optional. If provided, each value is encrypted with a new data key which is wrapped with the KeyWrapper.
+	KeyWrapper KeyWrapper
+}
+
+// NewSQLKeyValueStore creates a new instance of SQLKeyValueStore using provided options
+func NewSQLKeyValueStore(opts *SQLKeyValueStoreOptions) (*SQLKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("SQLKeyValueStoreOptions is nil")
+	}
+	if opts.DB == nil {
+		return nil, errors.New("SQLKeyValueStore database is nil")
+	}
+
+	table := opts.Table
+	if table == "" {
+		table = defaultSQLTable
+	}
+	if !sqlTableName.MatchString(table) {
+		return nil, errors.Errorf("invalid table name [%s]", table)
+	}
+
+	dialect := SQLiteDialect
+	if opts.Dialect != nil {
+		dialect = *opts.Dialect
+	}
+
+	name := table
+	if dialect.QuoteIdentifier != nil {
+		name = dialect.QuoteIdentifier(table)
+	}
+
+	s := &SQLKeyValueStore{
+		db:            opts.DB,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+		keyWrapper:    opts.KeyWrapper,
+		selectQuery:   fmt.Sprintf("SELECT v FROM %s WHERE k = %s", name, dialect.Placeholder(1)),
+		insertQuery:   fmt.Sprintf("INSERT INTO %s (k, v) VALUES (%s, %s)", name, dialect.Placeholder(1), dialect.Placeholder(2)),
+		updateQuery:   fmt.Sprintf("UPDATE %s SET v = %s WHERE k = %s", name, dialect.Placeholder(1), dialect.Placeholder(2)),
+		deleteQuery:   fmt.Sprintf("DELETE FROM %s WHERE k = %s", name, dialect.Placeholder(1)),
+		keysQuery:     fmt.Sprintf("SELECT k FROM %s WHERE k LIKE %s ESCAPE '%c'", name, dialect.Placeholder(1), likeEscape),
+	}
+	if s.keySerializer == nil {
+		s.keySerializer = stringKeySerializer
+	}
+	if s.marshaller == nil {
+		s.marshaller = defaultMarshaller
+	}
+	if s.unmarshaller == nil {
+		s.unmarshaller = defaultUnmarshaller
+	}
+
+	createQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (k %s NOT NULL PRIMARY KEY, v %s)", name, dialect.KeyType, dialect.ValueType)
+	if _, err := s.db.Exec(createQuery); err != nil {
+		return nil, errors.Wrapf(err, "failed to create table [%s]", table)
+	}
+
+	return s, nil
+}
+
+func stringKeySerializer(key interface{}) (string, error) {
+	keyString, ok := key.(string)
+	if !ok {
+		return "", errors.New("converting key to string failed")
+	}
+	return keyString, nil
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *SQLKeyValueStore) Load(key interface{}) (interface{}, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	err = s.db.QueryRow(s.selectQuery, k).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, core.ErrKeyValueNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "query failed")
+	}
+	if value == nil {
+		return nil, core.ErrKeyValueNotFound
+	}
+
+	if s.keyWrapper != nil {
+		value, err = OpenEnvelope(s.keyWrapper, value)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to decrypt value of key [%s]", k)
+		}
+	}
+
+	return s.unmarshaller(value)
+}
+
+// Store sets the value for the key. Concurrent writers (in this or other processes)
+// may store the same key; the value of the last writer is retained.
+func (s *SQLKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+
+	if s.keyWrapper != nil {
+		valueBytes, err = SealEnvelope(s.keyWrapper, valueBytes)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to encrypt value of key [%s]", k)
+		}
+	}
+
+	return s.upsert(k, valueBytes)
+}
+
+// upsert updates the row of the key or inserts it if it doesn't exist. The statements are portable
+// across databases (as opposed to INSERT ... ON CONFLICT, which isn't supported by all of them).
+func (s *SQLKeyValueStore) upsert(k string, value []byte) error {
+	result, err := s.db.Exec(s.updateQuery, value, k)
+	if err != nil {
+		return errors.Wrap(err, "update failed")
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	_, insertErr := s.db.Exec(s.insertQuery, k, value)
+	if insertErr == nil {
+		return nil
+	}
+
+	// The row may exist if it was inserted by a concurrent writer or if the database
+	// reports the number of changed (rather than matched) rows
+	if _, err := s.db.Exec(s.updateQuery, value, k); err != nil {
+		return errors.Wrap(insertErr, "insert failed")
+	}
+	return nil
+}
+
+// Delete deletes the value for a key.
+func (s *SQLKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(s.deleteQuery, k); err != nil {
+		return errors.Wrap(err, "delete failed")
+	}
+	return nil
+}
+
+// Keys returns the (sorted) serialized keys that start with the given prefix
+func (s *SQLKeyValueStore) Keys(prefix string) ([]string, error) {
+	rows, err := s.db.Query(s.keysQuery, likePatternEscape.Replace(prefix)+"%")
+	if err != nil {
+		return nil, errors.Wrap(err, "query failed")
+	}
+	defer rows.Close() // nolint: errcheck
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, errors.Wrap(err, "scan failed")
+		}
+		// LIKE is case insensitive in some databases
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "query failed")
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ImportFiles copies the files of a FileKeyValueStore (that uses the default key serializer)
+// into the given store. The key of each value is the path of its file relative to the store
+// path, which is the key that was used to store the value in the FileKeyValueStore.
+// Returns the number of imported values.
+func ImportFiles(path string, store core.KVStore) (int, error) {
+	count := 0
+	err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.Contains(info.Name(), ".tmp") {
+			return nil
+		}
+
+		key, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		value, err := ioutil.ReadFile(file) // nolint: gas
+		if err != nil {
+			return err
+		}
+		if err := store.Store(filepath.ToSlash(key), value); err != nil {
+			return errors.WithMessagef(err, "failed to import [%s]", file)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, errors.Wrapf(err, "failed to import files from [%s]", path)
+	}
+	return count, nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSQLDB opens a SQLite database in a temporary file. The returned function removes it.
+func newTestSQLDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "sqlkvs")
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "store.db")+"?_busy_timeout=5000")
+	require.NoError(t, err)
+	return db, func() {
+		db.Close() // nolint: errcheck
+		os.RemoveAll(dir)
+	}
+}
+
+func newTestSQLStore(t *testing.T, wrapper KeyWrapper) (*SQLKeyValueStore, func()) {
+	db, cleanup := newTestSQLDB(t)
+
+	store, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, KeyWrapper: wrapper})
+	require.NoError(t, err)
+	return store, cleanup
+}
+
+func TestSQLKeyValueStore(t *testing.T) {
+	store, cleanup := newTestSQLStore(t, nil)
+	defer cleanup()
+
+	_, err := store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	assert.EqualError(t, store.Store(nil, []byte("v")), "key is nil")
+	assert.EqualError(t, store.Store("key1", nil), "value is nil")
+
+	require.NoError(t, store.Store("key1", []byte("value1")))
+	require.NoError(t, store.Store("key2", []byte("value2")))
+
+	value, err := store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	// Overwrite
+	require.NoError(t, store.Store("key1", []byte("value1b")))
+	value, err = store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1b"), value)
+
+	keys, err := store.Keys("key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key2"}, keys)
+
+	require.NoError(t, store.Delete("key1"))
+	_, err = store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	keys, err = store.Keys("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key2"}, keys)
+}
+
+func TestSQLKeyValueStoreOptions(t *testing.T) {
+	_, err := NewSQLKeyValueStore(nil)
+	assert.Error(t, err)
+
+	_, err = NewSQLKeyValueStore(&SQLKeyValueStoreOptions{})
+	assert.Error(t, err)
+
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+
+	_, err = NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, Table: "users; DROP TABLE users"})
+	assert.Error(t, err)
+
+	store, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, Table: "pgstore", Dialect: &PostgresDialect})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT v FROM "pgstore" WHERE k = $1`, store.selectQuery)
+	assert.Equal(t, `UPDATE "pgstore" SET v = $1 WHERE k = $2`, store.updateQuery)
+	assert.Equal(t, `SELECT k FROM "pgstore" WHERE k LIKE $1 ESCAPE '!'`, store.keysQuery)
+
+	// SQLite also accepts numbered placeholders
+	require.NoError(t, store.Store("key", []byte("value")))
+	keys, err := store.Keys("k")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+}
+
+func TestSQLKeyValueStoreDialects(t *testing.T) {
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+
+	custom := SQLDialect{Placeholder: questionMark, KeyType: "TEXT", ValueType: "BLOB"}
+
+	tests := []struct {
+		name        string
+		dialect     *SQLDialect
+		table       string
+		selectQuery string
+	}{
+		{name: "SQLite", dialect: &SQLiteDialect, table: "keys", selectQuery: `SELECT v FROM "keys" WHERE k = ?`},
+		{name: "MySQL", dialect: &MySQLDialect, table: "keys", selectQuery: "SELECT v FROM `keys` WHERE k = ?"},
+		{name: "Postgres", dialect: &PostgresDialect, table: "keys", selectQuery: `SELECT v FROM "keys" WHERE k = $1`},
+		{name: "Unquoted", dialect: &custom, table: "customstore", selectQuery: "SELECT v FROM customstore WHERE k = ?"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, Table: tc.table, Dialect: tc.dialect})
+			require.NoError(t, err)
+			assert.Equal(t, tc.selectQuery, store.selectQuery)
+
+			// SQLite accepts the quotes and placeholders of each dialect, so reserved words can be used as table names
+			require.NoError(t, store.Store("key", []byte(tc.name)))
+			value, err := store.Load("key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tc.name), value)
+			require.NoError(t, store.Delete("key"))
+		})
+	}
+}
+
+func TestSQLKeyValueStoreKeysPrefix(t *testing.T) {
+	store, cleanup := newTestSQLStore(t, nil)
+	defer cleanup()
+
+	for _, k := range []string{"a_b", "axb", "a%b", "a!b", "A_B", "b_a"} {
+		require.NoError(t, store.Store(k, []byte("v")))
+	}
+
+	// The wildcards of LIKE are matched literally
+	keys, err := store.Keys("a_")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a_b"}, keys)
+
+	keys, err = store.Keys("a%")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a%b"}, keys)
+
+	keys, err = store.Keys("a!")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a!b"}, keys)
+
+	keys, err = store.Keys("a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a!b", "a%b", "a_b", "axb"}, keys)
+}
+
+func TestSQLKeyValueStoreEncryption(t *testing.T) {
+	wrapper, err := NewAESKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+
+	store, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, KeyWrapper: wrapper})
+	require.NoError(t, err)
+	require.NoError(t, store.Store("key", []byte("secret")))
+
+	value, err := store.Load("key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), value)
+
+	// The value is encrypted at rest
+	plainStore, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db})
+	require.NoError(t, err)
+	raw, err := plainStore.Load("key")
+	require.NoError(t, err)
+	assert.True(t, IsEnvelope(raw.([]byte)))
+	assert.False(t, bytes.Contains(raw.([]byte), []byte("secret")))
+
+	// A different key-encryption key can't decrypt the value
+	otherWrapper, err := NewAESKeyWrapper(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	otherStore, err := NewSQLKeyValueStore(&SQLKeyValueStoreOptions{DB: db, KeyWrapper: otherWrapper})
+	require.NoError(t, err)
+	_, err = otherStore.Load("key")
+	assert.Error(t, err)
+}
+
+func TestSQLKeyValueStoreConcurrentWriters(t *testing.T) {
+	store, cleanup := newTestSQLStore(t, nil)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- store.Store(fmt.Sprintf("key%d", i%2), []byte(fmt.Sprintf("value%d", i)))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	keys, err := store.Keys("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key0", "key1"}, keys)
+}
+
+func TestImportFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlkvsimport")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileStore, err := New(&FileKeyValueStoreOptions{Path: dir})
+	require.NoError(t, err)
+	require.NoError(t, fileStore.Store("a", []byte("1")))
+	require.NoError(t, fileStore.Store("sub/b", []byte("2")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "c.tmp123"), []byte("3"), 0600))
+
+	store, cleanup := newTestSQLStore(t, nil)
+	defer cleanup()
+	n, err := ImportFiles(dir, store)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	value, err := store.Load("sub/b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+}
@@ -96,3 +96,13 @@ func (mgr *MockCAClient) RemoveAffiliation(request *api.AffiliationRequest) (*ap
 func (mgr *MockCAClient) GetCAInfo() (*api.GetCAInfoResponse, error) {
 	return nil, errors.New("not implemented")
 }
+
+// GetCertificates returns the certificates issued by the CA
+func (mgr *MockCAClient) GetCertificates(request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GenCRL generates a CRL
+func (mgr *MockCAClient) GenCRL(request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	return nil, errors.New("not implemented")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -40,6 +41,7 @@ type MockProviderContext struct {
 	localDiscoveryProvider fab.LocalDiscoveryProvider
 	infraProvider          fab.InfraProvider
 	channelProvider        fab.ChannelProvider
+	tracer                 tracing.Tracer
 }
 
 // ProviderUsersOptions ...
@@ -211,6 +213,16 @@ func (pc *MockProviderContext) GetMetrics() *metrics.ClientMetrics {
 	return &metrics.ClientMetrics{}
 }
 
+// GetTracer returns the tracer (nil unless set with SetTracer)
+func (pc *MockProviderContext) GetTracer() tracing.Tracer {
+	return pc.tracer
+}
+
+//SetTracer sets the tracer for unit-test purposes
+func (pc *MockProviderContext) SetTracer(tracer tracing.Tracer) {
+	pc.tracer = tracer
+}
+
 // MockContext holds core providers and identity to enable mocking.
 type MockContext struct {
 	*MockProviderContext
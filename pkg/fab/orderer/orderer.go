@@ -9,6 +9,7 @@ package orderer
 import (
 	reqContext "context"
 	"crypto/x509"
+	"fmt"
 	"io"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -236,6 +238,11 @@ func (o *Orderer) releaseConn(ctx reqContext.Context, conn *grpc.ClientConn) {
 	commManager.ReleaseConn(conn)
 }
 
+// logContext returns the given context with the orderer URL attached as a log field
+func (o *Orderer) logContext(ctx reqContext.Context) reqContext.Context {
+	return logging.WithFields(ctx, logging.KV(logging.OrdererKey, o.url))
+}
+
 // URL Get the Orderer url. Required property for the instance objects.
 // Returns the address of the Orderer.
 func (o *Orderer) URL() string {
@@ -255,7 +262,7 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 	}
 	defer o.releaseConn(ctx, conn)
 
-	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
+	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Broadcast(tracing.InjectGRPC(ctx))
 	if err != nil {
 		rpcStatus, ok := grpcstatus.FromError(err)
 		if ok {
@@ -277,7 +284,7 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 		return nil, errors.Wrap(err, "failed to send envelope to orderer")
 	}
 	if err = broadcastClient.CloseSend(); err != nil {
-		logger.Debugf("unable to close broadcast client [%s]", err)
+		logger.Debugw(o.logContext(ctx), "Unable to close broadcast client", logging.KV(logging.ErrorKey, err))
 	}
 
 	return wrapStreamStatusRPC(responses, errs)
@@ -359,9 +366,9 @@ func (o *Orderer) SendDeliver(ctx reqContext.Context, envelope *fab.SignedEnvelo
 	}
 
 	// Create atomic broadcast client
-	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Deliver(tracing.InjectGRPC(ctx))
 	if err != nil {
-		logger.Errorf("deliver failed [%s]", err)
+		logger.Errorw(o.logContext(ctx), "Deliver failed", logging.KV(logging.ErrorKey, err))
 		o.releaseConn(ctx, conn)
 
 		errs <- errors.Wrap(err, "deliver failed")
@@ -372,28 +379,28 @@ func (o *Orderer) SendDeliver(ctx reqContext.Context, envelope *fab.SignedEnvelo
 
 	// Receive blocks from the GRPC stream and put them on the channel
 	go func() {
-		blockStream(broadcastClient, responses, errs)
+		blockStream(o.logContext(ctx), broadcastClient, responses, errs)
 		o.releaseConn(ctx, conn)
 	}()
 
 	// Send block request envelope
-	logger.Debug("Requesting blocks from ordering service")
+	logger.Debugw(o.logContext(ctx), "Requesting blocks from ordering service")
 	err = broadcastClient.Send(&common.Envelope{
 		Payload:   envelope.Payload,
 		Signature: envelope.Signature,
 	})
 	if err != nil {
-		logger.Warnf("failed to send block request to orderer [%s]", err)
+		logger.Warnw(o.logContext(ctx), "Failed to send block request to orderer", logging.KV(logging.ErrorKey, err))
 	}
 
 	if err = broadcastClient.CloseSend(); err != nil {
-		logger.Debugf("unable to close deliver client [%s]", err)
+		logger.Debugw(o.logContext(ctx), "Unable to close deliver client", logging.KV(logging.ErrorKey, err))
 	}
 
 	return responses, errs
 }
 
-func blockStream(deliverClient ab.AtomicBroadcast_DeliverClient, responses chan *common.Block, errs chan error) {
+func blockStream(logCtx reqContext.Context, deliverClient ab.AtomicBroadcast_DeliverClient, responses chan *common.Block, errs chan error) {
 
 	for {
 		response, err := deliverClient.Recv()
@@ -413,19 +420,20 @@ func blockStream(deliverClient ab.AtomicBroadcast_DeliverClient, responses chan
 		switch t := response.Type.(type) {
 		// Seek operation success, no more responses
 		case *ab.DeliverResponse_Status:
-			logger.Debugf("Received deliver response status from ordering service: %s", t.Status)
+			logger.Debugw(logCtx, "Received deliver response status from ordering service", logging.KV("status", t.Status))
 			if t.Status != common.Status_SUCCESS {
 				errs <- status.New(status.OrdererServerStatus, int32(t.Status), "error status from ordering service", []interface{}{})
 			}
 
 		// Response is a requested block
 		case *ab.DeliverResponse_Block:
-			logger.Debug("Received block from ordering service")
-			responses <- response.GetBlock()
+			block := response.GetBlock()
+			logger.Debugw(logCtx, "Received block from ordering service", logging.KV("block", block.GetHeader().GetNumber()))
+			responses <- block
 		// Unknown response
 		default:
 			// ignore unknown types.
-			logger.Infof("unknown response type from ordering service %T", t)
+			logger.Infow(logCtx, "Unknown response type from ordering service", logging.KV("type", fmt.Sprintf("%T", t)))
 		}
 	}
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -155,7 +156,7 @@ func (p *peerEndorser) sendProposal(ctx reqContext.Context, proposal fab.Process
 	defer p.releaseConn(ctx, conn)
 
 	endorserClient := pb.NewEndorserClient(conn)
-	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal)
+	resp, err := endorserClient.ProcessProposal(tracing.InjectGRPC(ctx), proposal.SignedProposal)
 
 	//TODO separate check for stable & devstable error messages should be refactored
 	if err != nil {
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fab
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// ReloadableEndpointConfig is an EndpointConfig whose underlying configuration may be
+// replaced at runtime. Each function delegates to the current configuration, so components
+// that hold a reference to the ReloadableEndpointConfig see the new configuration as
+// soon as it has been swapped in.
+//
+// Certificates that are added to the TLS CA cert pool at runtime (for example, the TLS root
+// certificates of the MSPs in a channel configuration) are added to the cert pool of each
+// new configuration so that existing channels remain usable after a swap.
+//
+// The client certificates for mutual TLS may also be overridden at runtime (for example, with a
+// certificate that was enrolled with the TLS profile of a CA). The override survives a swap.
+type ReloadableEndpointConfig struct {
+	current        atomic.Value
+	tlsClientCerts atomic.Value
+	lock           sync.Mutex
+	addedCerts     []*x509.Certificate
+	certPool       *reloadableCertPool
+}
+
+// endpointConfigHolder allows any implementation of EndpointConfig to be stored in an atomic.Value
+type endpointConfigHolder struct {
+	fab.EndpointConfig
+}
+
+// tlsClientCertsHolder allows a nil slice of certificates to be stored in an atomic.Value
+type tlsClientCertsHolder struct {
+	certs []tls.Certificate
+}
+
+// NewReloadableEndpointConfig returns a new ReloadableEndpointConfig which initially delegates to the given config
+func NewReloadableEndpointConfig(config fab.EndpointConfig) *ReloadableEndpointConfig {
+	c := &ReloadableEndpointConfig{}
+	c.certPool = &reloadableCertPool{config: c}
+	c.current.Store(endpointConfigHolder{config})
+	c.tlsClientCerts.Store(tlsClientCertsHolder{})
+	return c
+}
+
+// Current returns the current endpoint configuration
+func (c *ReloadableEndpointConfig) Current() fab.EndpointConfig {
+	return c.current.Load().(endpointConfigHolder).EndpointConfig
+}
+
+// Swap atomically replaces the current endpoint configuration and returns the previous one
+func (c *ReloadableEndpointConfig) Swap(config fab.EndpointConfig) fab.EndpointConfig {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.addedCerts) > 0 {
+		config.TLSCACertPool().Add(c.addedCerts...)
+	}
+
+	previous := c.Current()
+	c.current.Store(endpointConfigHolder{config})
+	return previous
+}
+
+func (c *ReloadableEndpointConfig) addCerts(certs ...*x509.Certificate) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, cert := range certs {
+		if cert != nil && !containsCert(c.addedCerts, cert) {
+			c.addedCerts = append(c.addedCerts, cert)
+		}
+	}
+	c.Current().TLSCACertPool().Add(certs...)
+}
+
+func containsCert(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if certEqual(c, cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// Timeout reads timeouts for the given timeout type
+func (c *ReloadableEndpointConfig) Timeout(tType fab.TimeoutType) time.Duration {
+	return c.Current().Timeout(tType)
+}
+
+// OrderersConfig returns a list of defined orderers
+func (c *ReloadableEndpointConfig) OrderersConfig() []fab.OrdererConfig {
+	return c.Current().OrderersConfig()
+}
+
+// OrdererConfig returns the requested orderer
+func (c *ReloadableEndpointConfig) OrdererConfig(nameOrURL string) (*fab.OrdererConfig, bool) {
+	return c.Current().OrdererConfig(nameOrURL)
+}
+
+// PeersConfig retrieves the fabric peers for the specified org from the config
+func (c *ReloadableEndpointConfig) PeersConfig(org string) ([]fab.PeerConfig, bool) {
+	return c.Current().PeersConfig(org)
+}
+
+// PeerConfig retrieves a specific peer from the configuration by name or url
+func (c *ReloadableEndpointConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, bool) {
+	return c.Current().PeerConfig(nameOrURL)
+}
+
+// NetworkConfig returns the network configuration defined in the config file
+func (c *ReloadableEndpointConfig) NetworkConfig() *fab.NetworkConfig {
+	return c.Current().NetworkConfig()
+}
+
+// NetworkPeers returns the network peers configuration, all the peers from all the orgs in config
+func (c *ReloadableEndpointConfig) NetworkPeers() []fab.NetworkPeer {
+	return c.Current().NetworkPeers()
+}
+
+// ChannelConfig returns the channel configuration
+func (c *ReloadableEndpointConfig) ChannelConfig(name string) *fab.ChannelEndpointConfig {
+	return c.Current().ChannelConfig(name)
+}
+
+// ChannelPeers returns the channel peers configuration
+func (c *ReloadableEndpointConfig) ChannelPeers(name string) []fab.ChannelPeer {
+	return c.Current().ChannelPeers(name)
+}
+
+// ChannelOrderers returns a list of channel orderers
+func (c *ReloadableEndpointConfig) ChannelOrderers(name string) []fab.OrdererConfig {
+	return c.Current().ChannelOrderers(name)
+}
+
+// TLSCACertPool returns a cert pool that delegates to the cert pool of the current configuration
+func (c *ReloadableEndpointConfig) TLSCACertPool() fab.CertPool {
+	return c.certPool
+}
+
+// TLSClientCerts returns the client's certs for mutual TLS. The certs set with SetTLSClientCerts
+// take precedence over the certs of the current configuration.
+func (c *ReloadableEndpointConfig) TLSClientCerts() []tls.Certificate {
+	if certs, ok := c.TLSClientCertsOverride(); ok {
+		return certs
+	}
+	return c.Current().TLSClientCerts()
+}
+
+// SetTLSClientCerts overrides the client's certs for mutual TLS. Setting an empty
+// slice removes the override so that the certs of the current configuration are used again.
+func (c *ReloadableEndpointConfig) SetTLSClientCerts(certs []tls.Certificate) {
+	if len(certs) == 0 {
+		c.tlsClientCerts.Store(tlsClientCertsHolder{})
+		return
+	}
+	c.tlsClientCerts.Store(tlsClientCertsHolder{certs: certs})
+}
+
+// TLSClientCertsOverride returns the certs set with SetTLSClientCerts and
+// false if the client's certs for mutual TLS were not overridden
+func (c *ReloadableEndpointConfig) TLSClientCertsOverride() ([]tls.Certificate, bool) {
+	certs := c.tlsClientCerts.Load().(tlsClientCertsHolder).certs
+	return certs, len(certs) > 0
+}
+
+// CryptoConfigPath returns the crypto config path
+func (c *ReloadableEndpointConfig) CryptoConfigPath() string {
+	return c.Current().CryptoConfigPath()
+}
+
+// reloadableCertPool delegates to the cert pool of the current configuration
+// and remembers the certificates that are added at runtime
+type reloadableCertPool struct {
+	config *ReloadableEndpointConfig
+}
+
+// Get returns the cert pool of the current configuration
+func (p *reloadableCertPool) Get() (*x509.CertPool, error) {
+	return p.config.Current().TLSCACertPool().Get()
+}
+
+// Add adds the given certificates to the cert pool of the current configuration
+// and to the cert pools of subsequent configurations
+func (p *reloadableCertPool) Add(certs ...*x509.Certificate) {
+	p.config.addCerts(certs...)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fab
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+)
+
+func TestReloadableEndpointConfig(t *testing.T) {
+	previous := newDiffTestConfig(t)
+	current := newDiffTestConfig(t, "peer0.org1.example.com:7051", "peer0.org1.example.com:8051")
+
+	config := NewReloadableEndpointConfig(previous)
+	assert.Equal(t, previous, config.Current())
+
+	peerConfig, ok := config.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:7051", peerConfig.URL)
+
+	assert.Equal(t, previous, config.Swap(current))
+	assert.Equal(t, current, config.Current())
+
+	peerConfig, ok = config.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:8051", peerConfig.URL)
+	assert.Len(t, config.ChannelPeers("mychannel"), 1)
+}
+
+func TestReloadableEndpointConfigCertPool(t *testing.T) {
+	cert := loadTestCert(t)
+
+	config := NewReloadableEndpointConfig(newDiffTestConfig(t))
+	config.TLSCACertPool().Add(cert)
+
+	pool, err := config.TLSCACertPool().Get()
+	require.NoError(t, err)
+	assert.Len(t, pool.Subjects(), 1)
+
+	// Certs added at runtime are added to the pool of the new config
+	current := newDiffTestConfig(t)
+	config.Swap(current)
+
+	pool, err = current.TLSCACertPool().Get()
+	require.NoError(t, err)
+	assert.Len(t, pool.Subjects(), 1)
+}
+
+func TestReloadableEndpointConfigTLSClientCerts(t *testing.T) {
+	previous := newDiffTestConfig(t)
+	config := NewReloadableEndpointConfig(previous)
+
+	_, ok := config.TLSClientCertsOverride()
+	assert.False(t, ok)
+	assert.Equal(t, previous.TLSClientCerts(), config.TLSClientCerts())
+
+	certs := []tls.Certificate{{Certificate: [][]byte{[]byte("enrolled")}}}
+	config.SetTLSClientCerts(certs)
+
+	override, ok := config.TLSClientCertsOverride()
+	require.True(t, ok)
+	assert.Equal(t, certs, override)
+	assert.Equal(t, certs, config.TLSClientCerts())
+
+	// The override is retained when the configuration is swapped
+	config.Swap(newDiffTestConfig(t))
+	assert.Equal(t, certs, config.TLSClientCerts())
+
+	config.SetTLSClientCerts(nil)
+	_, ok = config.TLSClientCertsOverride()
+	assert.False(t, ok)
+	assert.Equal(t, config.Current().TLSClientCerts(), config.TLSClientCerts())
+}
+
+func loadTestCert(t *testing.T) *x509.Certificate {
+	certBytes, err := ioutil.ReadFile(pathvar.Subst(certPath))
+	require.NoError(t, err)
+	block, _ := pem.Decode(certBytes)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
@@ -13,10 +13,11 @@ import (
 	"github.com/pkg/errors"
 )
 
-// SigningManager is used for signing objects with private key
+// SigningManager is used for signing objects with private key.
+// The hash that's signed depends on the type of the key (see cryptosuite.GetSignatureHashOpts),
+// so that identities with different key types may be used with the same signing manager.
 type SigningManager struct {
 	cryptoProvider core.CryptoSuite
-	hashOpts       core.HashOpts
 	signerOpts     core.SignerOpts
 }
 
@@ -25,7 +26,7 @@ type SigningManager struct {
 // @param {Config} config - configuration provider
 // @returns {SigningManager} new signing manager
 func New(cryptoProvider core.CryptoSuite) (*SigningManager, error) {
-	return &SigningManager{cryptoProvider: cryptoProvider, hashOpts: cryptosuite.GetSHAOpts()}, nil
+	return &SigningManager{cryptoProvider: cryptoProvider}, nil
 }
 
 // Sign will sign the given object using provided key
@@ -39,10 +40,16 @@ func (mgr *SigningManager) Sign(object []byte, key core.Key) ([]byte, error) {
 		return nil, errors.New("key (for signing) required")
 	}
 
-	digest, err := mgr.cryptoProvider.Hash(object, mgr.hashOpts)
-	if err != nil {
-		return nil, err
+	// Ed25519 keys sign the object itself
+	digest := object
+	if hashOpts := cryptosuite.GetSignatureHashOpts(key); hashOpts != nil {
+		var err error
+		digest, err = mgr.cryptoProvider.Hash(object, hashOpts)
+		if err != nil {
+			return nil, err
+		}
 	}
+
 	signature, err := mgr.cryptoProvider.Sign(key, digest, mgr.signerOpts)
 	if err != nil {
 		return nil, err
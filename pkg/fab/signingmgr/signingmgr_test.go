@@ -10,6 +10,9 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	bccspwrapper "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -48,3 +51,60 @@ func TestSigningManager(t *testing.T) {
 	}
 
 }
+
+func TestSigningManagerKeyTypes(t *testing.T) {
+	suite, err := sw.GetSuiteWithDefaultEphemeral()
+	if err != nil {
+		t.Fatalf("Failed to create crypto suite: %s", err)
+	}
+
+	signingMgr, err := New(suite)
+	if err != nil {
+		t.Fatalf("Failed to create signing manager: %s", err)
+	}
+
+	object := []byte("Hello")
+	sha256Digest, err := suite.Hash(object, cryptosuite.GetSHA256Opts())
+	if err != nil {
+		t.Fatalf("Failed to hash object: %s", err)
+	}
+
+	sha384Digest, err := suite.Hash(object, cryptosuite.GetSHA384Opts())
+	if err != nil {
+		t.Fatalf("Failed to hash object: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     core.KeyGenOpts
+		hashOpts core.HashOpts
+		message  []byte
+	}{
+		{"ECDSA P-256", cryptosuite.GetECDSAP256KeyGenOpts(true), nil, sha256Digest},
+		{"ECDSA P-384", cryptosuite.GetECDSAP384KeyGenOpts(true), nil, sha256Digest},
+		{"ECDSA P-384 with SHA-384", cryptosuite.GetECDSAP384KeyGenOpts(true), cryptosuite.GetSHA384Opts(), sha384Digest},
+		{"Ed25519", cryptosuite.GetEd25519KeyGenOpts(true), nil, object},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := suite.KeyGen(tc.opts)
+			if err != nil {
+				t.Fatalf("Failed to generate key: %s", err)
+			}
+			if tc.hashOpts != nil {
+				cryptosuite.SetSignatureHashOpts(key, tc.hashOpts)
+			}
+
+			signature, err := signingMgr.Sign(object, key)
+			if err != nil {
+				t.Fatalf("Failed to sign object: %s", err)
+			}
+
+			valid, err := suite.Verify(key, signature, tc.message, nil)
+			if err != nil || !valid {
+				t.Fatalf("Expecting valid signature of %s key (%v)", tc.name, err)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package txn
 import (
 	reqContext "context"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -17,9 +18,12 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 )
 
 // CreateChaincodeInvokeProposal creates a proposal for transaction.
@@ -112,14 +116,27 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 	var wg sync.WaitGroup
 	errs := multi.Errors{}
 
+	breakers := comm.CircuitBreakers(ctx)
+
 	for _, p := range targets {
 		wg.Add(1)
 		go func(processor fab.ProposalProcessor) {
 			defer wg.Done()
 
-			// TODO: The RPC should be timed-out.
-			//resp, err := processor.ProcessTransactionProposal(context.NewRequestOLD(ctx), request)
-			resp, err := processor.ProcessTransactionProposal(reqCtx, request)
+			url := processorURL(processor)
+			var resp *fab.TransactionProposalResponse
+			var err error
+			if breakers.Allow(url) {
+				// TODO: The RPC should be timed-out.
+				//resp, err := processor.ProcessTransactionProposal(context.NewRequestOLD(ctx), request)
+				spanCtx, span := startEndorserSpan(reqCtx, proposal, processor)
+				startTime := time.Now()
+				resp, err = processor.ProcessTransactionProposal(spanCtx, request)
+				breakers.Record(url, time.Since(startTime), err)
+				tracing.EndSpan(span, err)
+			} else {
+				err = comm.NewCircuitOpenError(status.EndorserClientStatus, url)
+			}
 			if err != nil {
 				logger.Debugf("Received error response from txn proposal processing: %s", err)
 				responseMtx.Lock()
@@ -161,3 +178,20 @@ func getTargetsWithoutDuplicates(targets []fab.ProposalProcessor) []fab.Proposal
 
 	return uniqueTargets
 }
+
+// startEndorserSpan starts a span for the endorsement of the proposal by the given processor
+func startEndorserSpan(reqCtx reqContext.Context, proposal *fab.TransactionProposal, processor fab.ProposalProcessor) (reqContext.Context, tracing.Span) {
+	attrs := []tracing.Attribute{tracing.Attr(tracing.TxIDKey, string(proposal.TxnID))}
+	if url := processorURL(processor); url != "" {
+		attrs = append(attrs, tracing.Attr(tracing.PeerURLKey, url))
+	}
+	return tracing.StartSpan(reqCtx, "txn.ProcessTransactionProposal", attrs...)
+}
+
+// processorURL returns the URL of the given proposal processor (if it has one)
+func processorURL(processor fab.ProposalProcessor) string {
+	if p, ok := processor.(interface{ URL() string }); ok {
+		return p.URL()
+	}
+	return ""
+}
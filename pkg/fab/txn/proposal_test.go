@@ -20,9 +20,11 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	mock_context "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
@@ -291,6 +293,45 @@ func TestProposalResponseError(t *testing.T) {
 	assert.Equal(t, testError, errs[0])
 }
 
+type breakersInfraProvider struct {
+	mocks.MockInfraProvider
+	breakers *comm.Breakers
+}
+
+func (p *breakersInfraProvider) CircuitBreakers() *comm.Breakers {
+	return p.breakers
+}
+
+func TestSendProposalCircuitBreaker(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+	breakers := comm.NewBreakers(comm.BreakerOpts{MinRequests: 1, CoolDown: time.Minute})
+	ctx.SetCustomInfraProvider(&breakersInfraProvider{breakers: breakers})
+
+	connErr := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	peer1 := &mocks.MockPeer{MockName: "peer1", MockURL: "grpcs://peer1:7051", Error: connErr}
+	peer2 := &mocks.MockPeer{MockName: "peer2", MockURL: "grpcs://peer2:7051", Status: 200}
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	targets := []fab.ProposalProcessor{peer1, peer2}
+	_, err := SendProposal(reqCtx, &fab.TransactionProposal{Proposal: &pb.Proposal{}}, targets)
+	require.Error(t, err)
+	assert.Equal(t, comm.BreakerOpen, breakers.State(peer1.MockURL))
+	assert.Equal(t, comm.BreakerClosed, breakers.State(peer2.MockURL))
+
+	responses, err := SendProposal(reqCtx, &fab.TransactionProposal{Proposal: &pb.Proposal{}}, targets)
+	require.Error(t, err)
+	assert.Len(t, responses, 1)
+	assert.Equal(t, 1, peer1.ProcessProposalCalls, "proposal should not be sent to a peer whose circuit breaker is open")
+	assert.Equal(t, 2, peer2.ProcessProposalCalls)
+
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.EqualValues(t, status.CircuitOpen, s.Code)
+}
+
 func setupMassiveTestPeers(numberOfPeers int) []fab.ProposalProcessor {
 	peers := []fab.ProposalProcessor{}
 
@@ -10,8 +10,10 @@ package txn
 import (
 	reqContext "context"
 	"math/rand"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -20,7 +22,10 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	ctxprovider "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -187,14 +192,28 @@ func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope,
 }
 
 func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer, client ctxprovider.Client) (*fab.TransactionResponse, error) {
+	// skip orderers whose circuit breaker is open
+	breakers := comm.CircuitBreakers(client)
+	if !breakers.Allow(orderer.URL()) {
+		logger.Debugf("Not broadcasting envelope to orderer %s since its circuit breaker is open", orderer.URL())
+		return nil, comm.NewCircuitOpenError(status.OrdererClientStatus, orderer.URL())
+	}
+
 	logger.Debugf("Broadcasting envelope to orderer: %s\n", orderer.URL())
 	// create a childContext for this SendBroadcast orderer using the config's timeout value
 	// the parent context (reqCtx) should not have a timeout value
 	childCtx, cancel := context.NewRequest(client, context.WithTimeoutType(fab.OrdererResponse), context.WithParent(reqCtx))
 	defer cancel()
 
+	childCtx, span := tracing.StartSpan(childCtx, "txn.SendBroadcast", tracing.Attr(tracing.OrdererURLKey, orderer.URL()))
+	startTime := time.Now()
+	_, err := orderer.SendBroadcast(childCtx, envelope)
+	breakers.Record(orderer.URL(), time.Since(startTime), err)
+	observeBroadcast(client, envelope, orderer.URL(), time.Since(startTime), err)
+	tracing.EndSpan(span, err)
+
 	// Send request
-	if _, err := orderer.SendBroadcast(childCtx, envelope); err != nil {
+	if err != nil {
 		logger.Debugf("Receive Error Response from orderer: %s\n", err)
 		return nil, errors.Wrapf(err, "calling orderer '%s' failed", orderer.URL())
 	}
@@ -203,6 +222,19 @@ func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orde
 	return &fab.TransactionResponse{Orderer: orderer.URL()}, nil
 }
 
+// observeBroadcast records the duration and the status of a broadcast to an orderer
+func observeBroadcast(client ctxprovider.Client, envelope *fab.SignedEnvelope, ordererURL string, duration time.Duration, err error) {
+	channelID, chErr := protoutil.ChannelID(&common.Envelope{Payload: envelope.Payload, Signature: envelope.Signature})
+	if chErr != nil {
+		logger.Debugf("Unable to extract channel ID from envelope: %s", chErr)
+	}
+
+	m := client.GetMetrics().Orderer()
+	labels := []string{metrics.ChannelLabel, channelID, metrics.OrdererLabel, ordererURL, metrics.StatusLabel, metrics.Status(err)}
+	m.Broadcasts.With(labels...).Add(1)
+	m.BroadcastDuration.With(labels...).Observe(duration.Seconds())
+}
+
 // SendPayload sends the given payload to each orderer and returns a block response
 func SendPayload(reqCtx reqContext.Context, payload *common.Payload, orderers []fab.Orderer) (*common.Block, error) {
 	if len(orderers) == 0 {
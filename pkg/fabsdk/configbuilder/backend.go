@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configbuilder
+
+import (
+	"strings"
+)
+
+// backend is an in-memory config backend. Values are stored in nested maps with lower-case keys,
+// mirroring the structure produced by the viper-based backend, so that the SDK's config
+// implementations unmarshal them the same way as a YAML connection profile.
+type backend struct {
+	values map[string]interface{}
+}
+
+// Lookup returns the value for the given dot-separated key. Keys are case-insensitive.
+func (b *backend) Lookup(key string) (interface{}, bool) {
+	var value interface{} = b.values
+	for _, k := range strings.Split(strings.ToLower(key), ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = m[k]
+		if !ok {
+			return nil, false
+		}
+	}
+	return copyValue(value), true
+}
+
+// set sets the value of the given dot-separated key, creating intermediate maps as required
+func (b *backend) set(key string, value interface{}) {
+	keys := strings.Split(strings.ToLower(key), ".")
+
+	m := b.values
+	for _, k := range keys[:len(keys)-1] {
+		child, ok := m[k].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[k] = child
+		}
+		m = child
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// copyValue returns a deep copy of nested maps so that callers (for example, decode hooks
+// which fill in defaults) can't modify the backend
+func copyValue(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = copyValue(v)
+	}
+	return c
+}
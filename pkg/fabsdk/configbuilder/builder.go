@@ -0,0 +1,512 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configbuilder builds the SDK configuration (EndpointConfig, IdentityConfig and
+// CryptoSuiteConfig) programmatically from typed Go values, as an alternative to a YAML
+// connection profile. This is useful when the configuration is assembled at runtime, for
+// example from a secrets manager or a database.
+//
+//  Basic Flow:
+//  1) Create a builder using New
+//  2) Add the client, organizations, peers, orderers, channels, etc. using the With... functions
+//  3) Pass the builder's ConfigProvider to fabsdk.New, or call Build to get the individual configs
+package configbuilder
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/configlint"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/pkg/errors"
+)
+
+var timeoutKeys = map[fab.TimeoutType]string{
+	fab.PeerConnection:           "client.peer.timeout.connection",
+	fab.PeerResponse:             "client.peer.timeout.response",
+	fab.DiscoveryGreylistExpiry:  "client.peer.timeout.discovery.greylistExpiry",
+	fab.EventReg:                 "client.eventService.timeout.registrationResponse",
+	fab.OrdererConnection:        "client.orderer.timeout.connection",
+	fab.OrdererResponse:          "client.orderer.timeout.response",
+	fab.DiscoveryConnection:      "client.discovery.timeout.connection",
+	fab.DiscoveryResponse:        "client.discovery.timeout.response",
+	fab.Query:                    "client.global.timeout.query",
+	fab.Execute:                  "client.global.timeout.execute",
+	fab.ResMgmt:                  "client.global.timeout.resmgmt",
+	fab.ConnectionIdle:           "client.global.cache.connectionIdle",
+	fab.EventServiceIdle:         "client.global.cache.eventServiceIdle",
+	fab.ChannelConfigRefresh:     "client.global.cache.channelConfig",
+	fab.ChannelMembershipRefresh: "client.global.cache.channelMembership",
+	fab.DiscoveryServiceRefresh:  "client.global.cache.discovery",
+	fab.SelectionServiceRefresh:  "client.global.cache.selection",
+	fab.CacheSweepInterval:       "client.cache.interval.sweep",
+}
+
+// Client contains the client settings
+type Client struct {
+	// Organization is the name of the organization of the client
+	Organization string
+	// CredentialStorePath is the path of the user store
+	CredentialStorePath string
+	// CryptoStorePath is the path of the key store
+	CryptoStorePath string
+	// CryptoConfigPath is the root path of the organizations' crypto material (see Organization.CryptoPath)
+	CryptoConfigPath string
+	// TLSCert and TLSKey are the PEM-encoded client certificate and private key used for mutual TLS
+	TLSCert []byte
+	TLSKey  []byte
+	// SystemCertPool indicates whether the system cert pool is used to verify TLS server certificates
+	SystemCertPool bool
+}
+
+// Organization contains the settings of an organization
+type Organization struct {
+	MSPID string
+	// CryptoPath is the path of the organization's users' MSP directories (relative to Client.CryptoConfigPath)
+	CryptoPath string
+	// Peers contains the names of the organization's peers
+	Peers []string
+	// CertificateAuthorities contains the names of the organization's CAs
+	CertificateAuthorities []string
+	// Users contains embedded user credentials, keyed by user name
+	Users map[string]User
+}
+
+// User contains the PEM-encoded certificate and private key of an embedded user
+type User struct {
+	Cert []byte
+	Key  []byte
+}
+
+// Endpoint contains the settings of a peer or orderer
+type Endpoint struct {
+	URL string
+	// TLSCACert is the PEM-encoded TLS CA certificate used to verify the endpoint's TLS certificate
+	TLSCACert             []byte
+	SSLTargetNameOverride string
+	KeepAliveTime         time.Duration
+	KeepAliveTimeout      time.Duration
+	KeepAlivePermit       bool
+	FailFast              bool
+	AllowInsecure         bool
+}
+
+// CertificateAuthority contains the settings of a Fabric CA
+type CertificateAuthority struct {
+	URL    string
+	CAName string
+	// TLSCACerts are the PEM-encoded TLS CA certificates used to verify the CA server's TLS certificate
+	TLSCACerts [][]byte
+	// TLSClientCert and TLSClientKey are the PEM-encoded client certificate and private key used for mutual TLS
+	TLSClientCert []byte
+	TLSClientKey  []byte
+	// Registrar contains the credentials of the CA registrar
+	Registrar msp.EnrollCredentials
+}
+
+// Channel contains the settings of a channel
+type Channel struct {
+	// Peers contains the roles of each of the channel's peers, keyed by peer name
+	Peers map[string]ChannelPeer
+	// Orderers contains the names of the channel's orderers
+	Orderers []string
+	// Policies contains the channel policies. Zero values are replaced with the SDK defaults.
+	Policies fab.ChannelPolicies
+}
+
+// ChannelPeer contains the roles of a peer in a channel. Note that, unlike in a YAML connection profile,
+// omitted roles are disabled; use AllRoles for a peer that has all roles.
+type ChannelPeer struct {
+	EndorsingPeer  bool
+	ChaincodeQuery bool
+	LedgerQuery    bool
+	EventSource    bool
+}
+
+// AllRoles returns the roles of a peer that endorses, processes queries and is an event source
+func AllRoles() ChannelPeer {
+	return ChannelPeer{
+		EndorsingPeer:  true,
+		ChaincodeQuery: true,
+		LedgerQuery:    true,
+		EventSource:    true,
+	}
+}
+
+// CryptoSuite contains the crypto suite settings
+type CryptoSuite struct {
+	SecurityEnabled bool
+	HashAlgorithm   string
+	Level           int
+	// Provider is the crypto suite provider, SW or PKCS11
+	Provider   string
+	SoftVerify bool
+	// Library, Pin and Label are used by the PKCS11 provider
+	Library string
+	Pin     string
+	Label   string
+	// KeyStoreEncryption is the encryption of the private keys in the SW keystore, "none" or "passphrase"
+	KeyStoreEncryption string
+	// KeyStorePassphrase is the passphrase (or a secret reference) of the encrypted SW keystore
+	KeyStorePassphrase string
+}
+
+// Configs contains the configs built by a Builder
+type Configs struct {
+	EndpointConfig    fab.EndpointConfig
+	IdentityConfig    msp.IdentityConfig
+	CryptoSuiteConfig core.CryptoSuiteConfig
+}
+
+// Builder builds the SDK configuration. The With... functions record the configuration and any
+// errors in the given values; all errors are returned when the configuration is built.
+type Builder struct {
+	backend *backend
+	errs    multi.Errors
+}
+
+// New returns a new configuration builder
+func New() *Builder {
+	b := &Builder{backend: &backend{values: make(map[string]interface{})}}
+	b.backend.set("version", "1.0.0")
+	return b
+}
+
+// WithClient sets the client settings
+func (b *Builder) WithClient(client Client) *Builder {
+	b.set("client.organization", client.Organization)
+	b.set("client.credentialStore.path", client.CredentialStorePath)
+	b.set("client.credentialStore.cryptoStore.path", client.CryptoStorePath)
+	b.set("client.cryptoconfig.path", client.CryptoConfigPath)
+	b.backend.set("client.tlsCerts.systemCertPool", client.SystemCertPool)
+
+	if len(client.TLSCert) > 0 || len(client.TLSKey) > 0 {
+		b.checkCert("client.tlsCerts.client.cert", client.TLSCert)
+		b.backend.set("client.tlsCerts.client", map[string]interface{}{
+			"cert": pemValue(client.TLSCert),
+			"key":  pemValue(client.TLSKey),
+		})
+	}
+	return b
+}
+
+// WithTimeout sets the given timeout
+func (b *Builder) WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) *Builder {
+	key, ok := timeoutKeys[timeoutType]
+	if !ok {
+		b.errorf("unsupported timeout type [%d]", timeoutType)
+		return b
+	}
+	if timeout < 0 {
+		b.errorf("%s: negative timeout [%s]", key, timeout)
+		return b
+	}
+	b.backend.set(key, timeout.String())
+	return b
+}
+
+// WithOrganization adds (or replaces) the organization with the given name
+func (b *Builder) WithOrganization(name string, org Organization) *Builder {
+	if !b.checkName("organization", name) {
+		return b
+	}
+	if org.MSPID == "" {
+		b.errorf("organizations[%s]: MSP ID is required", name)
+	}
+
+	value := map[string]interface{}{
+		"mspid":                  org.MSPID,
+		"cryptopath":             org.CryptoPath,
+		"peers":                  org.Peers,
+		"certificateauthorities": org.CertificateAuthorities,
+	}
+
+	if len(org.Users) > 0 {
+		users := make(map[string]interface{})
+		for userName, user := range org.Users {
+			b.checkCert("organizations["+name+"].users["+userName+"].cert", user.Cert)
+			users[strings.ToLower(userName)] = map[string]interface{}{
+				"cert": pemValue(user.Cert),
+				"key":  pemValue(user.Key),
+			}
+		}
+		value["users"] = users
+	}
+
+	b.setEntity("organizations", name, value)
+	return b
+}
+
+// WithPeer adds (or replaces) the peer with the given name
+func (b *Builder) WithPeer(name string, peer Endpoint) *Builder {
+	if b.checkName("peer", name) {
+		b.setEntity("peers", name, b.endpointValue("peers["+name+"]", peer))
+	}
+	return b
+}
+
+// WithOrderer adds (or replaces) the orderer with the given name
+func (b *Builder) WithOrderer(name string, orderer Endpoint) *Builder {
+	if b.checkName("orderer", name) {
+		b.setEntity("orderers", name, b.endpointValue("orderers["+name+"]", orderer))
+	}
+	return b
+}
+
+// WithCertificateAuthority adds (or replaces) the certificate authority with the given name
+func (b *Builder) WithCertificateAuthority(name string, ca CertificateAuthority) *Builder {
+	if !b.checkName("certificate authority", name) {
+		return b
+	}
+	if ca.URL == "" {
+		b.errorf("certificateAuthorities[%s]: URL is required", name)
+	}
+
+	var tlsCACerts []string
+	for _, cert := range ca.TLSCACerts {
+		b.checkCert("certificateAuthorities["+name+"].tlsCACerts", cert)
+		tlsCACerts = append(tlsCACerts, string(cert))
+	}
+
+	tlsConfig := map[string]interface{}{
+		"pem": tlsCACerts,
+	}
+	if len(ca.TLSClientCert) > 0 || len(ca.TLSClientKey) > 0 {
+		b.checkCert("certificateAuthorities["+name+"].tlsCACerts.client.cert", ca.TLSClientCert)
+		tlsConfig["client"] = map[string]interface{}{
+			"cert": pemValue(ca.TLSClientCert),
+			"key":  pemValue(ca.TLSClientKey),
+		}
+	}
+
+	b.setEntity("certificateAuthorities", name, map[string]interface{}{
+		"url":        ca.URL,
+		"caname":     ca.CAName,
+		"tlscacerts": tlsConfig,
+		"registrar": map[string]interface{}{
+			"enrollid":     ca.Registrar.EnrollID,
+			"enrollsecret": ca.Registrar.EnrollSecret,
+		},
+	})
+	return b
+}
+
+// WithChannel adds (or replaces) the channel with the given ID
+func (b *Builder) WithChannel(channelID string, channel Channel) *Builder {
+	if !b.checkName("channel", channelID) {
+		return b
+	}
+
+	peers := make(map[string]interface{})
+	for name, roles := range channel.Peers {
+		peers[strings.ToLower(name)] = map[string]interface{}{
+			"endorsingpeer":  roles.EndorsingPeer,
+			"chaincodequery": roles.ChaincodeQuery,
+			"ledgerquery":    roles.LedgerQuery,
+			"eventsource":    roles.EventSource,
+		}
+	}
+
+	b.setEntity("channels", channelID, map[string]interface{}{
+		"peers":    peers,
+		"orderers": channel.Orderers,
+		"policies": channelPolicies(channel.Policies),
+	})
+	return b
+}
+
+// WithCryptoSuite sets the crypto suite settings. If not set, the SDK defaults are used.
+func (b *Builder) WithCryptoSuite(cs CryptoSuite) *Builder {
+	b.backend.set("client.BCCSP.security", map[string]interface{}{
+		"enabled":       cs.SecurityEnabled,
+		"hashalgorithm": cs.HashAlgorithm,
+		"level":         cs.Level,
+		"default":       map[string]interface{}{"provider": cs.Provider},
+		"softverify":    cs.SoftVerify,
+		"library":       cs.Library,
+		"pin":           cs.Pin,
+		"label":         cs.Label,
+		"keystore": map[string]interface{}{
+			"encryption": cs.KeyStoreEncryption,
+			"passphrase": cs.KeyStorePassphrase,
+		},
+	})
+	return b
+}
+
+// ConfigProvider returns a config provider (which may be passed to fabsdk.New) that validates the
+// configuration and provides it as a config backend
+func (b *Builder) ConfigProvider() core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		if err := b.validate(); err != nil {
+			return nil, err
+		}
+		return []core.ConfigBackend{b.backend}, nil
+	}
+}
+
+// Build validates the configuration and returns the endpoint, identity and crypto suite configs. These may be
+// passed to fabsdk.New using fabsdk.WithEndpointConfig, fabsdk.WithIdentityConfig and fabsdk.WithCryptoSuiteConfig.
+func (b *Builder) Build() (*Configs, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	endpointConfig, err := fabImpl.ConfigFromBackend(b.backend)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create endpoint config")
+	}
+
+	identityConfig, err := mspImpl.ConfigFromBackend(b.backend)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create identity config")
+	}
+
+	return &Configs{
+		EndpointConfig:    endpointConfig,
+		IdentityConfig:    identityConfig,
+		CryptoSuiteConfig: cryptosuite.ConfigFromBackend(b.backend),
+	}, nil
+}
+
+// validate returns the errors recorded while building the configuration along with the errors
+// reported by the config linter (for example, channels that reference peers that weren't added)
+func (b *Builder) validate() error {
+	errs := append(multi.Errors{}, b.errs...)
+
+	report, err := configlint.Lint(func() ([]core.ConfigBackend, error) {
+		return []core.ConfigBackend{b.backend}, nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, d := range report.Errors() {
+			errs = append(errs, errors.Errorf("%s: %s", d.Path, d.Message))
+		}
+	}
+
+	if err := errs.ToError(); err != nil {
+		return errors.WithMessage(err, "invalid configuration")
+	}
+	return nil
+}
+
+func (b *Builder) endpointValue(path string, endpoint Endpoint) map[string]interface{} {
+	if endpoint.URL == "" {
+		b.errorf("%s: URL is required", path)
+	}
+
+	grpcOptions := make(map[string]interface{})
+	if endpoint.SSLTargetNameOverride != "" {
+		grpcOptions["ssl-target-name-override"] = endpoint.SSLTargetNameOverride
+	}
+	if endpoint.KeepAliveTime != 0 {
+		grpcOptions["keep-alive-time"] = endpoint.KeepAliveTime
+	}
+	if endpoint.KeepAliveTimeout != 0 {
+		grpcOptions["keep-alive-timeout"] = endpoint.KeepAliveTimeout
+	}
+	if endpoint.KeepAlivePermit {
+		grpcOptions["keep-alive-permit"] = true
+	}
+	if endpoint.FailFast {
+		grpcOptions["fail-fast"] = true
+	}
+	if endpoint.AllowInsecure {
+		grpcOptions["allow-insecure"] = true
+	}
+
+	value := map[string]interface{}{
+		"url":         endpoint.URL,
+		"grpcoptions": grpcOptions,
+	}
+	if len(endpoint.TLSCACert) > 0 {
+		b.checkCert(path+".tlsCACerts", endpoint.TLSCACert)
+		value["tlscacerts"] = pemValue(endpoint.TLSCACert)
+	}
+	return value
+}
+
+func (b *Builder) setEntity(kind, name string, value map[string]interface{}) {
+	entities, ok := b.backend.values[strings.ToLower(kind)].(map[string]interface{})
+	if !ok {
+		entities = make(map[string]interface{})
+		b.backend.values[strings.ToLower(kind)] = entities
+	}
+	entities[strings.ToLower(name)] = value
+}
+
+func (b *Builder) set(key, value string) {
+	if value != "" {
+		b.backend.set(key, value)
+	}
+}
+
+func (b *Builder) checkName(kind, name string) bool {
+	if name == "" {
+		b.errorf("%s name is required", kind)
+		return false
+	}
+	return true
+}
+
+// checkCert records an error if the given bytes don't contain a PEM-encoded certificate
+func (b *Builder) checkCert(path string, certPEM []byte) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		b.errorf("%s: no PEM-encoded certificate found", path)
+		return
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		b.errorf("%s: invalid certificate: %s", path, err)
+	}
+}
+
+func (b *Builder) errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, errors.Errorf(format, args...))
+}
+
+func pemValue(pemBytes []byte) map[string]interface{} {
+	return map[string]interface{}{"pem": string(pemBytes)}
+}
+
+// channelPolicies converts the given policies to the type expected by the endpoint config
+func channelPolicies(policies fab.ChannelPolicies) fabImpl.ChannelPolicies {
+	return fabImpl.ChannelPolicies{
+		QueryChannelConfig: fabImpl.QueryChannelConfigPolicy{
+			MinResponses: policies.QueryChannelConfig.MinResponses,
+			MaxTargets:   policies.QueryChannelConfig.MaxTargets,
+			RetryOpts:    policies.QueryChannelConfig.RetryOpts,
+		},
+		Discovery: fabImpl.DiscoveryPolicy{
+			MinResponses: policies.Discovery.MinResponses,
+			MaxTargets:   policies.Discovery.MaxTargets,
+			RetryOpts:    policies.Discovery.RetryOpts,
+		},
+		Selection: fabImpl.SelectionPolicy{
+			SortingStrategy:         fabImpl.SelectionSortingStrategy(policies.Selection.SortingStrategy),
+			Balancer:                fabImpl.BalancerType(policies.Selection.Balancer),
+			BlockHeightLagThreshold: policies.Selection.BlockHeightLagThreshold,
+		},
+		EventService: fabImpl.EventServicePolicy{
+			ResolverStrategy:                 string(policies.EventService.ResolverStrategy),
+			MinBlockHeightResolverMode:       string(policies.EventService.MinBlockHeightResolverMode),
+			Balancer:                         fabImpl.BalancerType(policies.EventService.Balancer),
+			BlockHeightLagThreshold:          policies.EventService.BlockHeightLagThreshold,
+			PeerMonitor:                      string(policies.EventService.PeerMonitor),
+			ReconnectBlockHeightLagThreshold: policies.EventService.ReconnectBlockHeightLagThreshold,
+			PeerMonitorPeriod:                policies.EventService.PeerMonitorPeriod,
+		},
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configbuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	tlsCACert := newTestCert(t, "tlsca.example.com")
+
+	configs, err := newTestBuilder(tlsCACert).Build()
+	require.NoError(t, err)
+
+	endpointConfig := configs.EndpointConfig
+	assert.Equal(t, 45*time.Second, endpointConfig.Timeout(fab.Query))
+	assert.Equal(t, 5*time.Second, endpointConfig.Timeout(fab.PeerConnection))
+
+	peerConfig, ok := endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpcs://peer0.org1.example.com:7051", peerConfig.URL)
+	assert.Equal(t, "peer0.org1.example.com", peerConfig.GRPCOptions["ssl-target-name-override"])
+	require.NotNil(t, peerConfig.TLSCACert)
+	assert.Equal(t, "tlsca.example.com", peerConfig.TLSCACert.Subject.CommonName)
+
+	channelPeers := endpointConfig.ChannelPeers("mychannel")
+	require.Len(t, channelPeers, 2)
+	for _, peer := range channelPeers {
+		if peer.URL == "grpcs://peer1.org1.example.com:7051" {
+			assert.False(t, peer.EndorsingPeer)
+			assert.True(t, peer.EventSource)
+		} else {
+			assert.True(t, peer.EndorsingPeer)
+		}
+		assert.Equal(t, "Org1MSP", peer.MSPID)
+	}
+
+	orderers := endpointConfig.ChannelOrderers("mychannel")
+	require.Len(t, orderers, 1)
+	assert.Equal(t, "grpcs://orderer.example.com:7050", orderers[0].URL)
+
+	channelConfig := endpointConfig.ChannelConfig("mychannel")
+	assert.Equal(t, 2, channelConfig.Policies.QueryChannelConfig.MinResponses)
+	assert.Equal(t, fab.Balanced, channelConfig.Policies.Selection.SortingStrategy)
+	assert.NotZero(t, channelConfig.Policies.Discovery.MaxTargets, "missing policy values should be defaulted")
+
+	caConfig, ok := configs.IdentityConfig.CAConfig("ca.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "https://ca.org1.example.com:7054", caConfig.URL)
+	assert.Equal(t, "admin", caConfig.Registrar.EnrollID)
+	require.Len(t, caConfig.TLSCAServerCerts, 1)
+	assert.Equal(t, "/tmp/state-store", configs.IdentityConfig.CredentialStorePath())
+
+	assert.True(t, configs.CryptoSuiteConfig.IsSecurityEnabled())
+	assert.Equal(t, "SHA2", configs.CryptoSuiteConfig.SecurityAlgorithm())
+	assert.Equal(t, 256, configs.CryptoSuiteConfig.SecurityLevel())
+}
+
+func TestConfigProvider(t *testing.T) {
+	sdk, err := fabsdk.New(newTestBuilder(newTestCert(t, "tlsca.example.com")).ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	configBackend, err := sdk.Config()
+	require.NoError(t, err)
+	value, ok := configBackend.Lookup("client.organization")
+	require.True(t, ok)
+	assert.Equal(t, "org1", value)
+}
+
+func TestBuildInvalid(t *testing.T) {
+	b := New().
+		WithClient(Client{Organization: "org1"}).
+		WithTimeout(fab.Query, -time.Second).
+		WithOrganization("org1", Organization{}).
+		WithPeer("peer0.org1.example.com", Endpoint{URL: "grpcs://peer0.org1.example.com:7051", TLSCACert: []byte("invalid")}).
+		WithOrderer("orderer.example.com", Endpoint{}).
+		WithChannel("mychannel", Channel{
+			Peers: map[string]ChannelPeer{"peer5.org1.example.com": AllRoles()},
+		})
+
+	_, err := b.Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client.global.timeout.query: negative timeout")
+	assert.Contains(t, err.Error(), "organizations[org1]: MSP ID is required")
+	assert.Contains(t, err.Error(), "peers[peer0.org1.example.com].tlsCACerts: no PEM-encoded certificate found")
+	assert.Contains(t, err.Error(), "orderers[orderer.example.com]: URL is required")
+	assert.Contains(t, err.Error(), "peer [peer5.org1.example.com] is not configured")
+
+	_, err = b.ConfigProvider()()
+	assert.Error(t, err)
+}
+
+func newTestBuilder(tlsCACert []byte) *Builder {
+	return New().
+		WithClient(Client{
+			Organization:        "org1",
+			CredentialStorePath: "/tmp/state-store",
+			CryptoStorePath:     "/tmp/msp",
+		}).
+		WithTimeout(fab.Query, 45*time.Second).
+		WithTimeout(fab.PeerConnection, 5*time.Second).
+		WithCryptoSuite(CryptoSuite{
+			SecurityEnabled: true,
+			HashAlgorithm:   "SHA2",
+			Level:           256,
+			Provider:        "SW",
+			SoftVerify:      true,
+		}).
+		WithOrganization("org1", Organization{
+			MSPID:                  "Org1MSP",
+			CryptoPath:             "/tmp/msp",
+			Peers:                  []string{"peer0.org1.example.com", "peer1.org1.example.com"},
+			CertificateAuthorities: []string{"ca.org1.example.com"},
+		}).
+		WithPeer("peer0.org1.example.com", Endpoint{
+			URL:                   "grpcs://peer0.org1.example.com:7051",
+			TLSCACert:             tlsCACert,
+			SSLTargetNameOverride: "peer0.org1.example.com",
+			KeepAliveTime:         10 * time.Second,
+		}).
+		WithPeer("peer1.org1.example.com", Endpoint{
+			URL:       "grpcs://peer1.org1.example.com:7051",
+			TLSCACert: tlsCACert,
+		}).
+		WithOrderer("orderer.example.com", Endpoint{
+			URL:       "grpcs://orderer.example.com:7050",
+			TLSCACert: tlsCACert,
+		}).
+		WithCertificateAuthority("ca.org1.example.com", CertificateAuthority{
+			URL:        "https://ca.org1.example.com:7054",
+			TLSCACerts: [][]byte{tlsCACert},
+			Registrar:  msp.EnrollCredentials{EnrollID: "admin", EnrollSecret: "adminpw"},
+		}).
+		WithChannel("mychannel", Channel{
+			Peers: map[string]ChannelPeer{
+				"peer0.org1.example.com": AllRoles(),
+				"peer1.org1.example.com": {EventSource: true, LedgerQuery: true},
+			},
+			Orderers: []string{"orderer.example.com"},
+			Policies: fab.ChannelPolicies{
+				QueryChannelConfig: fab.QueryChannelConfigPolicy{MinResponses: 2},
+				Selection:          fab.SelectionPolicy{SortingStrategy: fab.Balanced},
+			},
+		})
+}
+
+func newTestCert(t *testing.T, commonName string) []byte {
+	now := time.Now()
+	cert, err := testcert.New(testcert.WithCommonName(commonName), testcert.WithCA(), testcert.WithValidity(now.Add(-time.Hour), now.Add(365*24*time.Hour)))
+	require.NoError(t, err)
+	return cert.PEM()
+}
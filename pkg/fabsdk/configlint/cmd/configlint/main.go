@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// configlint validates a connection profile and reports the problems that were found.
+//
+//  Usage:
+//  configlint -config config.yaml [-json] [-strict]
+//
+// The exit status is 1 if any errors were found (or, with -strict, any warnings) and 2 if
+// the configuration couldn't be loaded.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/configlint"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to the connection profile (required)")
+	jsonOutput := flag.Bool("json", false, "output the diagnostics as JSON")
+	strict := flag.Bool("strict", false, "treat warnings as errors")
+	flag.Parse()
+
+	if *configPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	report, err := configlint.LintFile(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "configlint: %s\n", err)
+		os.Exit(2)
+	}
+
+	if err := printReport(report, *jsonOutput); err != nil {
+		fmt.Fprintf(os.Stderr, "configlint: %s\n", err)
+		os.Exit(2)
+	}
+
+	if report.HasErrors() || (*strict && len(report.Warnings()) > 0) {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *configlint.Report, jsonOutput bool) error {
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	if len(report.Diagnostics) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+
+	fmt.Println(report)
+	fmt.Printf("%d error(s), %d warning(s)\n", len(report.Errors()), len(report.Warnings()))
+	return nil
+}
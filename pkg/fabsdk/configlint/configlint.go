@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configlint validates connection profiles before they are used by the SDK.
+// Misconfigurations that would otherwise only be reported when the endpoint config is
+// loaded or when a peer is first dialed (unreadable or expired certificates, channels
+// referencing unknown peers, entity matchers that never match, invalid timeouts, etc.)
+// are reported as diagnostics, each of which identifies the offending config item.
+//
+//  Basic Flow:
+//  1) Lint a connection profile using LintFile (or Lint for any config provider)
+//  2) Inspect the diagnostics of the report, for example using HasErrors
+package configlint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	"github.com/pkg/errors"
+)
+
+// Severity is the severity of a diagnostic
+type Severity string
+
+const (
+	// SeverityError indicates a problem that prevents the SDK from loading or using the configuration
+	SeverityError Severity = "error"
+	// SeverityWarning indicates a probable misconfiguration that doesn't prevent the configuration from loading
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic codes
+const (
+	CodeInvalidProfile      = "invalid-profile"
+	CodeUnknownOrganization = "unknown-organization"
+	CodeMissingURL          = "missing-url"
+	CodeMissingTLSCert      = "missing-tls-cert"
+	CodeUnreadableCert      = "unreadable-cert"
+	CodeInvalidCert         = "invalid-cert"
+	CodeExpiredCert         = "expired-cert"
+	CodeNotYetValidCert     = "not-yet-valid-cert"
+	CodeExpiringCert        = "expiring-cert"
+	CodeKeyPairMismatch     = "key-pair-mismatch"
+	CodeInvalidMatcher      = "invalid-matcher"
+	CodeUnmatchedMatcher    = "unmatched-matcher"
+	CodeOverlappingMatchers = "overlapping-matchers"
+	CodeUnknownMappedEntity = "unknown-mapped-entity"
+	CodeUnknownPeer         = "unknown-peer"
+	CodeUnknownOrderer      = "unknown-orderer"
+	CodeUnknownCA           = "unknown-ca"
+	CodeOrgWithoutCA        = "org-without-ca"
+	CodeInvalidTimeout      = "invalid-timeout"
+	CodeTimeoutWithoutUnit  = "timeout-without-unit"
+	CodePeerNotInAnyChannel = "peer-not-in-any-channel"
+)
+
+const defaultExpiryWarning = 30 * 24 * time.Hour
+
+// Diagnostic describes a problem found in the configuration
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	// Code identifies the type of problem (see the Code constants)
+	Code string `json:"code"`
+	// Path identifies the config item, for example peers[peer0.org1.example.com].tlsCACerts.path
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String returns the diagnostic in the form: severity: path: message [code]
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", d.Severity, d.Path, d.Message, d.Code)
+}
+
+// Report contains the diagnostics of a configuration, sorted by path
+type Report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// HasErrors returns true if the report contains at least one error
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the diagnostics with error severity
+func (r *Report) Errors() []Diagnostic {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the diagnostics with warning severity
+func (r *Report) Warnings() []Diagnostic {
+	return r.filter(SeverityWarning)
+}
+
+// String returns the diagnostics, one per line
+func (r *Report) String() string {
+	lines := make([]string, len(r.Diagnostics))
+	for i, d := range r.Diagnostics {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Report) filter(severity Severity) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, d := range r.Diagnostics {
+		if d.Severity == severity {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+type options struct {
+	expiryWarning time.Duration
+	now           func() time.Time
+}
+
+// Option configures the linter
+type Option func(opts *options)
+
+// WithExpiryWarning sets how long before its expiry a certificate is reported with a warning.
+// The default is 30 days.
+func WithExpiryWarning(d time.Duration) Option {
+	return func(opts *options) {
+		opts.expiryWarning = d
+	}
+}
+
+// WithTime sets the time against which the validity of certificates is checked. The default is the current time.
+func WithTime(t time.Time) Option {
+	return func(opts *options) {
+		opts.now = func() time.Time { return t }
+	}
+}
+
+// LintFile loads the connection profile at the given path (using config.FromFile) and lints it
+func LintFile(path string, opts ...Option) (*Report, error) {
+	return Lint(config.FromFile(path), opts...)
+}
+
+// Lint loads the configuration from the given provider and returns a report of the problems that were
+// found. An error is returned only if the configuration can't be loaded at all (for example, if the
+// file doesn't exist or isn't valid YAML); all other problems are reported as diagnostics.
+func Lint(configProvider core.ConfigProvider, opts ...Option) (*Report, error) {
+	backends, err := configProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load config backend")
+	}
+
+	options := &options{
+		expiryWarning: defaultExpiryWarning,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	l := &linter{
+		backend: lookup.New(backends...),
+		opts:    options,
+	}
+	l.lint()
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		if l.diagnostics[i].Path != l.diagnostics[j].Path {
+			return l.diagnostics[i].Path < l.diagnostics[j].Path
+		}
+		return l.diagnostics[i].Code < l.diagnostics[j].Code
+	})
+
+	return &Report{Diagnostics: l.diagnostics}, nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configlint
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+)
+
+const validProfile = `
+client:
+  organization: org1
+  tlsCerts:
+    client:
+      cert:
+        path: ${DIR}/client.pem
+      key:
+        path: ${DIR}/client-key.pem
+  global:
+    timeout:
+      query: 45s
+channels:
+  mychannel:
+    orderers:
+      - orderer.example.com
+    peers:
+      peer0.org1.example.com:
+organizations:
+  org1:
+    mspid: Org1MSP
+    peers:
+      - peer0.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpcs://orderer.example.com:7050
+    tlsCACerts:
+      path: ${DIR}/ca.pem
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    tlsCACerts:
+      path: ${DIR}/ca.pem
+certificateAuthorities:
+  ca.org1.example.com:
+    url: https://ca.org1.example.com:7054
+    tlsCACerts:
+      path: ${DIR}/ca.pem
+`
+
+const invalidProfile = `
+client:
+  organization: org3
+  tlsCerts:
+    client:
+      cert:
+        path: ${DIR}/client.pem
+      key:
+        path: ${DIR}/other-key.pem
+  global:
+    timeout:
+      query: 45
+      execute: soon
+channels:
+  mychannel:
+    orderers:
+      - orderer2.example.com
+    peers:
+      peer0.org1.example.com:
+      peer5.org1.example.com:
+organizations:
+  org1:
+    mspid: Org1MSP
+    peers:
+      - peer0.org1.example.com
+      - peer1.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpcs://orderer.example.com:7050
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    tlsCACerts:
+      path: ${DIR}/expired.pem
+  peer1.org1.example.com:
+    url: grpcs://peer1.org1.example.com:7051
+    tlsCACerts:
+      path: ${DIR}/missing.pem
+entityMatchers:
+  peer:
+    - pattern: peer0\.org1\.example\.com
+      mappedHost: peer0.org1.example.com
+    - pattern: peer0\.org1\.(.*)
+      mappedHost: peer0.org1.example.com
+    - pattern: peer9\.org9\.example\.com
+      mappedHost: peer9.org9.example.com
+    - pattern: "["
+`
+
+func TestLintValidProfile(t *testing.T) {
+	dir := newCertDir(t)
+	defer os.RemoveAll(dir)
+
+	report, err := Lint(config.FromRaw([]byte(strings.Replace(validProfile, "${DIR}", dir, -1)), "yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics, report.String())
+	assert.False(t, report.HasErrors())
+}
+
+func TestLintInvalidProfile(t *testing.T) {
+	dir := newCertDir(t)
+	defer os.RemoveAll(dir)
+
+	report, err := Lint(config.FromRaw([]byte(strings.Replace(invalidProfile, "${DIR}", dir, -1)), "yaml"))
+	require.NoError(t, err)
+	require.True(t, report.HasErrors())
+
+	expected := []struct {
+		severity Severity
+		code     string
+		path     string
+	}{
+		{SeverityError, CodeUnknownOrganization, "client.organization"},
+		{SeverityError, CodeKeyPairMismatch, "client.tlsCerts.client"},
+		{SeverityWarning, CodeTimeoutWithoutUnit, "client.global.timeout.query"},
+		{SeverityError, CodeInvalidTimeout, "client.global.timeout.execute"},
+		{SeverityError, CodeUnknownOrderer, "channels[mychannel].orderers[0]"},
+		{SeverityError, CodeUnknownPeer, "channels[mychannel].peers[peer5.org1.example.com]"},
+		{SeverityWarning, CodeOrgWithoutCA, "organizations[org1].certificateAuthorities"},
+		{SeverityError, CodeMissingTLSCert, "orderers[orderer.example.com].tlsCACerts"},
+		{SeverityError, CodeExpiredCert, "peers[peer0.org1.example.com].tlsCACerts"},
+		{SeverityError, CodeUnreadableCert, "peers[peer1.org1.example.com].tlsCACerts.path"},
+		{SeverityWarning, CodePeerNotInAnyChannel, "peers[peer1.org1.example.com]"},
+		{SeverityWarning, CodeOverlappingMatchers, "entityMatchers.peer"},
+		{SeverityWarning, CodeUnmatchedMatcher, "entityMatchers.peer[2].pattern"},
+		{SeverityError, CodeUnknownMappedEntity, "entityMatchers.peer[2].mappedHost"},
+		{SeverityError, CodeInvalidMatcher, "entityMatchers.peer[3].pattern"},
+	}
+
+	for _, e := range expected {
+		assert.True(t, hasDiagnostic(report, e.severity, e.code, e.path), "expected %s [%s] at %s in:\n%s", e.severity, e.code, e.path, report)
+	}
+	assert.Len(t, report.Diagnostics, len(expected), report.String())
+}
+
+func TestLintExpiringCert(t *testing.T) {
+	dir := newCertDir(t)
+	defer os.RemoveAll(dir)
+
+	profile := strings.Replace(validProfile, "${DIR}", dir, -1)
+
+	report, err := Lint(config.FromRaw([]byte(profile), "yaml"), WithTime(time.Now().Add(360*24*time.Hour)))
+	require.NoError(t, err)
+	assert.False(t, report.HasErrors())
+	assert.True(t, hasDiagnostic(report, SeverityWarning, CodeExpiringCert, "peers[peer0.org1.example.com].tlsCACerts"), report.String())
+
+	report, err = Lint(config.FromRaw([]byte(profile), "yaml"), WithTime(time.Now().Add(-48*time.Hour)))
+	require.NoError(t, err)
+	assert.True(t, hasDiagnostic(report, SeverityError, CodeNotYetValidCert, "client.tlsCerts.client.cert"), report.String())
+}
+
+func TestLintFile(t *testing.T) {
+	_, err := LintFile("/nonexistent/config.yaml")
+	assert.Error(t, err)
+
+	dir := newCertDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Replace(validProfile, "${DIR}", dir, -1)), 0600))
+
+	report, err := LintFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics, report.String())
+}
+
+func hasDiagnostic(report *Report, severity Severity, code, path string) bool {
+	for _, d := range report.Diagnostics {
+		if d.Severity == severity && d.Code == code && d.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// newCertDir creates a directory containing a valid CA cert, a valid client key pair, an
+// expired cert and an unrelated private key
+func newCertDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "configlint")
+	require.NoError(t, err)
+
+	now := time.Now()
+	writeCert(t, dir, "ca", now.Add(-time.Hour), now.Add(365*24*time.Hour))
+	writeCert(t, dir, "client", now.Add(-time.Hour), now.Add(365*24*time.Hour))
+	writeCert(t, dir, "expired", now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	writeCert(t, dir, "other", now.Add(-time.Hour), now.Add(365*24*time.Hour))
+
+	return dir
+}
+
+func writeCert(t *testing.T, dir, name string, notBefore, notAfter time.Time) {
+	cert, err := testcert.New(testcert.WithCommonName(name), testcert.WithCA(), testcert.WithKeyUsage(x509.KeyUsageDigitalSignature), testcert.WithValidity(notBefore, notAfter))
+	require.NoError(t, err)
+	keyPEM, err := cert.KeyPEM()
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".pem"), cert.PEM(), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0600))
+}
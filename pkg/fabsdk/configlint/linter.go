@@ -0,0 +1,571 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configlint
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+)
+
+const (
+	defaultEntity = "_default"
+
+	peerMatcher    = "peer"
+	ordererMatcher = "orderer"
+	caMatcher      = "certificateauthority"
+	channelMatcher = "channel"
+)
+
+// timeoutKeys are the config keys of the timeouts and refresh intervals of the endpoint config
+var timeoutKeys = []string{
+	"client.peer.timeout.connection",
+	"client.peer.timeout.response",
+	"client.peer.timeout.discovery.greylistExpiry",
+	"client.eventService.timeout.registrationResponse",
+	"client.orderer.timeout.connection",
+	"client.orderer.timeout.response",
+	"client.discovery.timeout.connection",
+	"client.discovery.timeout.response",
+	"client.global.timeout.query",
+	"client.global.timeout.execute",
+	"client.global.timeout.resmgmt",
+	"client.global.cache.connectionIdle",
+	"client.global.cache.eventServiceIdle",
+	"client.global.cache.channelConfig",
+	"client.global.cache.channelMembership",
+	"client.global.cache.discovery",
+	"client.global.cache.selection",
+	"client.cache.interval.sweep",
+}
+
+// profile contains the sections of the connection profile that are linted
+type profile struct {
+	Client                 fabImpl.ClientConfig
+	Channels               map[string]channelConfig
+	Organizations          map[string]fabImpl.OrganizationConfig
+	Orderers               map[string]fabImpl.OrdererConfig
+	Peers                  map[string]fabImpl.PeerConfig
+	CertificateAuthorities map[string]caConfig
+	EntityMatchers         map[string][]fabImpl.MatchConfig
+}
+
+type channelConfig struct {
+	Orderers []string
+	Peers    map[string]interface{}
+}
+
+type caConfig struct {
+	URL        string
+	TLSCACerts endpoint.MutualTLSConfig
+}
+
+type matcher struct {
+	index  int
+	regex  *regexp.Regexp
+	config fabImpl.MatchConfig
+}
+
+type linter struct {
+	backend     *lookup.ConfigLookup
+	opts        *options
+	profile     profile
+	matchers    map[string][]matcher
+	diagnostics []Diagnostic
+}
+
+func (l *linter) lint() {
+	if !l.loadProfile() {
+		return
+	}
+
+	l.checkTimeouts()
+	l.checkMatchers()
+	l.checkClient()
+	l.checkPeers()
+	l.checkOrderers()
+	l.checkCAs()
+	l.checkOrganizations()
+	l.checkChannels()
+	l.checkPeersInChannels()
+}
+
+func (l *linter) loadProfile() bool {
+	ok := true
+	unmarshal := func(key string, rawVal interface{}) {
+		if err := l.backend.UnmarshalKey(key, rawVal); err != nil {
+			l.errorf(CodeInvalidProfile, key, "failed to parse: %s", err)
+			ok = false
+		}
+	}
+
+	unmarshal("client", &l.profile.Client)
+	unmarshal("channels", &l.profile.Channels)
+	unmarshal("organizations", &l.profile.Organizations)
+	unmarshal("orderers", &l.profile.Orderers)
+	unmarshal("peers", &l.profile.Peers)
+	unmarshal("certificateAuthorities", &l.profile.CertificateAuthorities)
+	unmarshal("entityMatchers", &l.profile.EntityMatchers)
+
+	return ok
+}
+
+func (l *linter) checkTimeouts() {
+	for _, key := range timeoutKeys {
+		value, ok := l.backend.Lookup(key)
+		if !ok || value == nil {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				l.errorf(CodeInvalidTimeout, key, "invalid duration %q", v)
+			} else if d < 0 {
+				l.errorf(CodeInvalidTimeout, key, "negative duration %q", v)
+			}
+		case int, int32, int64, float32, float64:
+			if fmt.Sprint(v) != "0" {
+				l.warnf(CodeTimeoutWithoutUnit, key, "duration %v has no unit and is interpreted as nanoseconds", v)
+			}
+		default:
+			l.errorf(CodeInvalidTimeout, key, "invalid duration %v", v)
+		}
+	}
+}
+
+func (l *linter) checkMatchers() {
+	l.matchers = make(map[string][]matcher)
+	for kind, configs := range l.profile.EntityMatchers {
+		for i, config := range configs {
+			path := fmt.Sprintf("entityMatchers.%s[%d].pattern", kind, i)
+			regex, err := regexp.Compile(config.Pattern)
+			if err != nil {
+				l.errorf(CodeInvalidMatcher, path, "invalid pattern %q: %s", config.Pattern, err)
+				continue
+			}
+			l.matchers[kind] = append(l.matchers[kind], matcher{index: i, regex: regex, config: config})
+		}
+	}
+
+	l.checkEntityMatchers(peerMatcher, l.knownPeerNames(), keySet(l.profile.Peers))
+	l.checkEntityMatchers(ordererMatcher, l.knownOrdererNames(), keySet(l.profile.Orderers))
+	l.checkEntityMatchers(caMatcher, l.knownCANames(), keySet(l.profile.CertificateAuthorities))
+
+	for _, m := range l.matchers[channelMatcher] {
+		if m.config.MappedName == "" || m.config.IgnoreEndpoint {
+			continue
+		}
+		if _, ok := l.profile.Channels[strings.ToLower(m.config.MappedName)]; !ok {
+			l.errorf(CodeUnknownMappedEntity, fmt.Sprintf("entityMatchers.%s[%d].mappedName", channelMatcher, m.index),
+				"mapped channel [%s] is not configured", m.config.MappedName)
+		}
+	}
+}
+
+// checkEntityMatchers reports matchers that don't match any of the known names, names that are matched by
+// more than one matcher (only the first matching matcher is applied) and mapped hosts that aren't configured
+func (l *linter) checkEntityMatchers(kind string, knownNames []string, configured map[string]struct{}) {
+	matchers := l.matchers[kind]
+	matchedBy := make(map[string][]int)
+
+	for _, m := range matchers {
+		matched := false
+		for _, name := range knownNames {
+			if m.regex.MatchString(name) {
+				matched = true
+				matchedBy[name] = append(matchedBy[name], m.index)
+			}
+		}
+		if !matched {
+			l.warnf(CodeUnmatchedMatcher, fmt.Sprintf("entityMatchers.%s[%d].pattern", kind, m.index),
+				"pattern %q doesn't match any configured or referenced %s", m.config.Pattern, kind)
+		}
+	}
+
+	// Report each set of overlapping matchers once (for example, not for both host and host:port)
+	reported := make(map[string]bool)
+	for _, name := range knownNames {
+		indexes := matchedBy[name]
+		key := fmt.Sprint(indexes)
+		if len(indexes) < 2 || reported[key] {
+			continue
+		}
+		reported[key] = true
+		l.warnf(CodeOverlappingMatchers, fmt.Sprintf("entityMatchers.%s", kind),
+			"[%s] is matched by matchers %v; only matcher %d is applied", name, indexes, indexes[0])
+	}
+
+	_, hasDefault := configured[defaultEntity]
+	for _, m := range matchers {
+		if m.config.IgnoreEndpoint || m.config.MappedHost == "" || hasDefault || strings.Contains(m.config.MappedHost, "$") {
+			continue
+		}
+		if _, ok := configured[strings.ToLower(m.config.MappedHost)]; !ok {
+			l.errorf(CodeUnknownMappedEntity, fmt.Sprintf("entityMatchers.%s[%d].mappedHost", kind, m.index),
+				"mapped host [%s] is not configured", m.config.MappedHost)
+		}
+	}
+}
+
+func (l *linter) checkClient() {
+	if l.profile.Client.Organization == "" {
+		l.errorf(CodeUnknownOrganization, "client.organization", "client organization is not set")
+	} else if _, ok := l.profile.Organizations[strings.ToLower(l.profile.Client.Organization)]; !ok {
+		l.errorf(CodeUnknownOrganization, "client.organization", "organization [%s] is not configured", l.profile.Client.Organization)
+	}
+
+	l.checkKeyPair("client.tlsCerts.client", l.profile.Client.TLSCerts.Client)
+}
+
+func (l *linter) checkPeers() {
+	for _, name := range sortedKeys(l.profile.Peers) {
+		peer := l.profile.Peers[name]
+		path := fmt.Sprintf("peers[%s]", name)
+		l.checkEndpoint(path, name, peer.URL, peer.GRPCOptions, peer.TLSCACerts)
+	}
+}
+
+func (l *linter) checkOrderers() {
+	for _, name := range sortedKeys(l.profile.Orderers) {
+		orderer := l.profile.Orderers[name]
+		path := fmt.Sprintf("orderers[%s]", name)
+		l.checkEndpoint(path, name, orderer.URL, orderer.GRPCOptions, orderer.TLSCACerts)
+	}
+}
+
+func (l *linter) checkEndpoint(path, name, url string, grpcOptions map[string]interface{}, tlsCACerts endpoint.TLSConfig) {
+	if name == defaultEntity {
+		// The default entity provides default settings and may omit the URL and certs
+		if tlsCACerts.Pem != "" || tlsCACerts.Path != "" {
+			l.checkCert(path+".tlsCACerts", tlsCACerts)
+		}
+		return
+	}
+
+	if url == "" {
+		l.errorf(CodeMissingURL, path+".url", "URL is not set")
+	}
+
+	if tlsCACerts.Pem != "" || tlsCACerts.Path != "" {
+		l.checkCert(path+".tlsCACerts", tlsCACerts)
+		return
+	}
+
+	allowInsecure := grpcOptions["allow-insecure"] == true
+	if url != "" && endpoint.AttemptSecured(url, allowInsecure) && !l.backend.GetBool("client.tlsCerts.systemCertPool") {
+		l.errorf(CodeMissingTLSCert, path+".tlsCACerts", "TLS is enabled for %s but neither tlsCACerts.pem nor tlsCACerts.path is set", url)
+	}
+}
+
+func (l *linter) checkCAs() {
+	for _, name := range sortedKeys(l.profile.CertificateAuthorities) {
+		ca := l.profile.CertificateAuthorities[name]
+		path := fmt.Sprintf("certificateAuthorities[%s]", name)
+
+		if ca.URL == "" && name != defaultEntity {
+			l.errorf(CodeMissingURL, path+".url", "URL is not set")
+		}
+
+		for i, p := range ca.TLSCACerts.Pem {
+			l.checkCert(fmt.Sprintf("%s.tlsCACerts.pem[%d]", path, i), endpoint.TLSConfig{Pem: p})
+		}
+		for _, p := range strings.Split(ca.TLSCACerts.Path, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				l.checkCert(path+".tlsCACerts.path", endpoint.TLSConfig{Path: p})
+			}
+		}
+
+		l.checkKeyPair(path+".tlsCACerts.client", ca.TLSCACerts.Client)
+	}
+}
+
+func (l *linter) checkOrganizations() {
+	clientOrg := strings.ToLower(l.profile.Client.Organization)
+
+	for _, name := range sortedKeys(l.profile.Organizations) {
+		org := l.profile.Organizations[name]
+		path := fmt.Sprintf("organizations[%s]", name)
+
+		for i, peer := range org.Peers {
+			if _, ok := l.resolve(peerMatcher, peer, l.profile.Peers); !ok {
+				l.errorf(CodeUnknownPeer, fmt.Sprintf("%s.peers[%d]", path, i), "peer [%s] is not configured", peer)
+			}
+		}
+
+		for i, ca := range org.CertificateAuthorities {
+			if _, ok := l.resolve(caMatcher, ca, l.profile.CertificateAuthorities); !ok {
+				l.errorf(CodeUnknownCA, fmt.Sprintf("%s.certificateAuthorities[%d]", path, i), "certificate authority [%s] is not configured", ca)
+			}
+		}
+
+		// Orderer organizations typically don't have CAs so only
+		// peer organizations and the client organization are reported
+		if len(org.CertificateAuthorities) == 0 && (len(org.Peers) > 0 || name == clientOrg) {
+			l.warnf(CodeOrgWithoutCA, path+".certificateAuthorities", "organization has no certificate authorities")
+		}
+
+		for _, user := range sortedKeys(org.Users) {
+			l.checkKeyPair(fmt.Sprintf("%s.users[%s]", path, user), org.Users[user])
+		}
+	}
+}
+
+func (l *linter) checkChannels() {
+	for _, channelID := range sortedKeys(l.profile.Channels) {
+		channel := l.profile.Channels[channelID]
+		path := fmt.Sprintf("channels[%s]", channelID)
+
+		for _, peer := range sortedKeys(channel.Peers) {
+			if _, ok := l.resolve(peerMatcher, peer, l.profile.Peers); !ok {
+				l.errorf(CodeUnknownPeer, fmt.Sprintf("%s.peers[%s]", path, peer), "peer [%s] is not configured", peer)
+			}
+		}
+
+		for i, orderer := range channel.Orderers {
+			if _, ok := l.resolve(ordererMatcher, orderer, l.profile.Orderers); !ok {
+				l.errorf(CodeUnknownOrderer, fmt.Sprintf("%s.orderers[%d]", path, i), "orderer [%s] is not configured", orderer)
+			}
+		}
+	}
+}
+
+func (l *linter) checkPeersInChannels() {
+	inChannel := make(map[string]struct{})
+	for _, channel := range l.profile.Channels {
+		for peer := range channel.Peers {
+			if resolved, ok := l.resolve(peerMatcher, peer, l.profile.Peers); ok {
+				inChannel[resolved] = struct{}{}
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(l.profile.Peers) {
+		if name == defaultEntity {
+			continue
+		}
+		if _, ok := inChannel[name]; !ok {
+			l.warnf(CodePeerNotInAnyChannel, fmt.Sprintf("peers[%s]", name), "peer is not a member of any channel")
+		}
+	}
+}
+
+// resolve returns the configured entity that the given name refers to, applying the entity
+// matchers of the given kind in the same way as the SDK. False is returned if the name
+// doesn't refer to a configured entity.
+func (l *linter) resolve(kind, name string, configured interface{}) (string, bool) {
+	entities := keySet(configured)
+	_, hasDefault := entities[defaultEntity]
+
+	for _, m := range l.matchers[kind] {
+		if !m.regex.MatchString(name) {
+			continue
+		}
+		if m.config.IgnoreEndpoint {
+			// The entity is deliberately excluded
+			return "", true
+		}
+		mappedHost := strings.ToLower(m.regex.ReplaceAllString(name, m.config.MappedHost))
+		if _, ok := entities[mappedHost]; ok {
+			return mappedHost, true
+		}
+		return "", hasDefault || m.config.URLSubstitutionExp != ""
+	}
+
+	lowerName := strings.ToLower(name)
+	if _, ok := entities[lowerName]; ok {
+		return lowerName, true
+	}
+	return "", false
+}
+
+func (l *linter) knownPeerNames() []string {
+	names := keySet(l.profile.Peers)
+	for _, channel := range l.profile.Channels {
+		for peer := range channel.Peers {
+			names[peer] = struct{}{}
+		}
+	}
+	for _, org := range l.profile.Organizations {
+		for _, peer := range org.Peers {
+			names[peer] = struct{}{}
+		}
+	}
+	for _, peer := range l.profile.Peers {
+		if peer.URL != "" {
+			names[endpoint.ToAddress(peer.URL)] = struct{}{}
+		}
+	}
+	delete(names, defaultEntity)
+	return sortedKeys(names)
+}
+
+func (l *linter) knownOrdererNames() []string {
+	names := keySet(l.profile.Orderers)
+	for _, channel := range l.profile.Channels {
+		for _, orderer := range channel.Orderers {
+			names[orderer] = struct{}{}
+		}
+	}
+	for _, orderer := range l.profile.Orderers {
+		if orderer.URL != "" {
+			names[endpoint.ToAddress(orderer.URL)] = struct{}{}
+		}
+	}
+	delete(names, defaultEntity)
+	return sortedKeys(names)
+}
+
+func (l *linter) knownCANames() []string {
+	names := keySet(l.profile.CertificateAuthorities)
+	for _, org := range l.profile.Organizations {
+		for _, ca := range org.CertificateAuthorities {
+			names[ca] = struct{}{}
+		}
+	}
+	delete(names, defaultEntity)
+	return sortedKeys(names)
+}
+
+// checkCert reports certificates that can't be loaded or parsed and certificates that aren't valid at the current time
+func (l *linter) checkCert(path string, cfg endpoint.TLSConfig) {
+	certPEM, ok := l.loadPEM(path, cfg)
+	if ok {
+		l.checkCertPEM(path, certPEM)
+	}
+}
+
+func (l *linter) checkCertPEM(path string, certPEM []byte) {
+	if certPEM == nil {
+		return
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		l.errorf(CodeInvalidCert, path, "no PEM encoded certificate found")
+		return
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		l.errorf(CodeInvalidCert, path, "failed to parse certificate: %s", err)
+		return
+	}
+
+	l.checkValidity(path, cert)
+}
+
+func (l *linter) checkValidity(path string, cert *x509.Certificate) {
+	now := l.opts.now()
+	subject := cert.Subject.CommonName
+
+	switch {
+	case now.After(cert.NotAfter):
+		l.errorf(CodeExpiredCert, path, "certificate [%s] expired at %s", subject, cert.NotAfter.Format(time.RFC3339))
+	case now.Before(cert.NotBefore):
+		l.errorf(CodeNotYetValidCert, path, "certificate [%s] is not valid before %s", subject, cert.NotBefore.Format(time.RFC3339))
+	case now.Add(l.opts.expiryWarning).After(cert.NotAfter):
+		l.warnf(CodeExpiringCert, path, "certificate [%s] expires at %s", subject, cert.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// checkKeyPair checks the certificate of the key pair and, if the private key is also
+// configured, ensures that the private key matches the certificate
+func (l *linter) checkKeyPair(path string, keyPair endpoint.TLSKeyPair) {
+	hasCert := keyPair.Cert.Pem != "" || keyPair.Cert.Path != ""
+	hasKey := keyPair.Key.Pem != "" || keyPair.Key.Path != ""
+	if !hasCert {
+		return
+	}
+
+	certPEM, ok := l.loadPEM(path+".cert", keyPair.Cert)
+	if !ok {
+		return
+	}
+	l.checkCertPEM(path+".cert", certPEM)
+
+	if !hasKey {
+		// The private key may be in the key store
+		return
+	}
+
+	keyPEM, ok := l.loadPEM(path+".key", keyPair.Key)
+	if !ok {
+		return
+	}
+
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		l.errorf(CodeKeyPairMismatch, path, "private key doesn't match the certificate: %s", err)
+	}
+}
+
+// loadPEM returns the embedded PEM or the content of the file at the path (the PEM takes precedence as in the SDK)
+func (l *linter) loadPEM(path string, cfg endpoint.TLSConfig) ([]byte, bool) {
+	if cfg.Pem != "" {
+		return []byte(cfg.Pem), true
+	}
+	if cfg.Path == "" {
+		return nil, true
+	}
+
+	filePath := pathvar.Subst(cfg.Path)
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		l.errorf(CodeUnreadableCert, path+".path", "unable to read [%s]: %s", filePath, err)
+		return nil, false
+	}
+	return content, true
+}
+
+func (l *linter) errorf(code, path, format string, args ...interface{}) {
+	l.add(SeverityError, code, path, format, args...)
+}
+
+func (l *linter) warnf(code, path, format string, args ...interface{}) {
+	l.add(SeverityWarning, code, path, format, args...)
+}
+
+func (l *linter) add(severity Severity, code, path, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// keySet returns the keys of the given map (which must have string keys) as a set
+func keySet(m interface{}) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		set[k.String()] = struct{}{}
+	}
+	return set
+}
+
+// sortedKeys returns the sorted keys of the given map (which must have string keys)
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -9,6 +9,8 @@ package fabsdk
 
 import (
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/operations"
@@ -18,12 +20,16 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/health"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	metricsCfg "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics/cfg"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
@@ -39,6 +45,11 @@ type FabricSDK struct {
 	cryptoSuite   core.CryptoSuite
 	system        *operations.System
 	clientMetrics *metrics.ClientMetrics
+	health        *health.Registry
+	closed        int32
+
+	endpointConfig *fabImpl.ReloadableEndpointConfig
+	reloadLock     sync.Mutex
 }
 
 type configs struct {
@@ -53,12 +64,15 @@ type options struct {
 	MSP               sdkApi.MSPProviderFactory
 	Service           sdkApi.ServiceProviderFactory
 	Logger            api.LoggerProvider
+	Tracer            tracing.Tracer
 	CryptoSuiteConfig core.CryptoSuiteConfig
 	endpointConfig    fab.EndpointConfig
 	IdentityConfig    msp.IdentityConfig
 	ConfigBackend     []core.ConfigBackend
 	ProviderOpts      []coptions.Opt // Provider options are passed along to the various providers
 	metricsConfig     metricsCfg.MetricsConfig
+	breakers          *comm.Breakers
+	keyStore          core.KVStore
 }
 
 // Option configures the SDK.
@@ -186,6 +200,7 @@ func WithServicePkg(service sdkApi.ServiceProviderFactory) Option {
 }
 
 // WithLoggerPkg injects the logger implementation into the SDK.
+// Structured logger providers are available in package pkg/core/logging/structlog.
 func WithLoggerPkg(logger api.LoggerProvider) Option {
 	return func(opts *options) error {
 		opts.Logger = logger
@@ -193,6 +208,15 @@ func WithLoggerPkg(logger api.LoggerProvider) Option {
 	}
 }
 
+// WithTracer injects the tracer implementation into the SDK instance. The tracer records spans
+// along the request path of the clients of this instance and is a no-op by default.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(opts *options) error {
+		opts.Tracer = tracer
+		return nil
+	}
+}
+
 // WithMetricsConfig injects a MetricsConfig interface to the SDK
 // it accepts either a full interface of MetricsConfig or a list
 // of sub interfaces each implementing one (or more) function(s) of MetricsConfig
@@ -207,6 +231,18 @@ func WithMetricsConfig(metricsConfigs ...interface{}) Option {
 	}
 }
 
+// WithCircuitBreakers enables circuit breakers for the peers and orderers. The failure ratio and latency of
+// the requests to each endpoint are tracked and, when the failure ratio reaches the threshold, the breaker of
+// the endpoint opens: the endpoint is excluded from endorser and orderer selection until the cool-down elapses,
+// after which a probe request is allowed through. State changes are recorded in the comm_breaker_state and
+// comm_breaker_transitions metrics and are delivered to the handlers registered with comm.WithBreakerEventHandler.
+func WithCircuitBreakers(breakerOpts comm.BreakerOpts, opts ...comm.BreakerOpt) Option {
+	return func(o *options) error {
+		o.breakers = comm.NewBreakers(breakerOpts, opts...)
+		return nil
+	}
+}
+
 // WithProviderOpts adds options which are propagated to the various providers.
 func WithProviderOpts(sopts ...coptions.Opt) Option {
 	return func(opts *options) error {
@@ -215,6 +251,20 @@ func WithProviderOpts(sopts ...coptions.Opt) Option {
 	}
 }
 
+// WithKeyStore stores the private keys in the given key-value store, such as a SQL key store
+// (see msp.NewSQLKeyStore), rather than in files: the crypto suite stores the keys that it generates
+// (including the enrollment keys) in the store and the identity managers load the keys of the users
+// from it. The core and MSP packages must support key stores (the default packages do).
+func WithKeyStore(store core.KVStore) Option {
+	return func(opts *options) error {
+		if store == nil {
+			return errors.New("key store is nil")
+		}
+		opts.keyStore = store
+		return nil
+	}
+}
+
 // WithErrorHandler sets an error handler that will be invoked when a service error is experienced.
 // This allows the client to take a decision of whether to ignore the error, shut down the client context,
 // or shut down the entire SDK.
@@ -248,6 +298,10 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to initialize configuration")
 	}
 
+	// The endpoint config may be replaced at runtime (see ReloadConfig)
+	sdk.endpointConfig = fabImpl.NewReloadableEndpointConfig(cfg.endpointConfig)
+	cfg.endpointConfig = sdk.endpointConfig
+
 	// Initialize rand (TODO: should probably be optional)
 	rand.Seed(time.Now().UnixNano())
 
@@ -264,7 +318,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 
 	// Initialize IdentityManagerProvider
-	identityManagerProvider, err := sdk.opts.MSP.CreateIdentityManagerProvider(cfg.endpointConfig, sdk.cryptoSuite, userStore)
+	identityManagerProvider, err := sdk.createIdentityManagerProvider(cfg.endpointConfig, cfg.cryptoSuiteConfig, userStore)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create identity manager provider")
 	}
@@ -275,6 +329,14 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
 
+	if sdk.opts.breakers != nil {
+		setter, ok := infraProvider.(breakersSetter)
+		if !ok {
+			return errors.New("infra provider does not support circuit breakers")
+		}
+		setter.SetCircuitBreakers(sdk.opts.breakers)
+	}
+
 	// Initialize local discovery provider
 	localDiscoveryProvider, err := sdk.opts.Service.CreateLocalDiscoveryProvider(cfg.endpointConfig)
 	if err != nil {
@@ -288,6 +350,11 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 
 	sdk.initMetrics(cfg)
 
+	// the PKCS11 crypto suite records the metrics of its tokens
+	if setter, ok := sdk.cryptoSuite.(pkcs11MetricsSetter); ok {
+		setter.SetPKCS11Metrics(sdk.clientMetrics.PKCS11())
+	}
+
 	//update sdk providers list since all required providers are initialized
 	sdk.provider = context.NewProvider(context.WithCryptoSuiteConfig(cfg.cryptoSuiteConfig),
 		context.WithEndpointConfig(cfg.endpointConfig),
@@ -300,6 +367,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		context.WithInfraProvider(infraProvider),
 		context.WithChannelProvider(channelProvider),
 		context.WithClientMetrics(sdk.clientMetrics),
+		context.WithTracer(sdk.opts.Tracer),
 	)
 
 	//initialize
@@ -324,6 +392,11 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		}
 	}
 
+	err = sdk.initHealth(cfg)
+	if err != nil {
+		return errors.WithMessage(err, "failed to initialize health checks")
+	}
+
 	logger.Debug("SDK initialized successfully")
 	return nil
 }
@@ -331,6 +404,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 // Close frees up caches and connections being maintained by the SDK
 func (sdk *FabricSDK) Close() {
 	logger.Debug("SDK closing")
+	atomic.StoreInt32(&sdk.closed, 1)
 	if pvdr, ok := sdk.provider.LocalDiscoveryProvider().(closeable); ok {
 		pvdr.Close()
 	}
@@ -355,6 +429,9 @@ func (sdk *FabricSDK) CloseContext(ctxt fab.ClientContext) {
 
 //Config returns config backend used by all SDK config types
 func (sdk *FabricSDK) Config() (core.ConfigBackend, error) {
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
 	if sdk.opts.ConfigBackend == nil {
 		return nil, errors.New("unable to find config backend")
 	}
@@ -389,10 +466,40 @@ func (sdk *FabricSDK) ChannelContext(channelID string, options ...ContextOption)
 	return channelProvider
 }
 
+// createIdentityManagerProvider creates the identity manager provider, which loads the keys
+// of the users from the key store (if any) or from the (possibly encrypted) keystore directories
+func (sdk *FabricSDK) createIdentityManagerProvider(endpointConfig fab.EndpointConfig, cryptoSuiteConfig core.CryptoSuiteConfig, userStore msp.UserStore) (msp.IdentityManagerProvider, error) {
+	if sdk.opts.keyStore == nil {
+		kekProvider, err := sw.KeyStoreKEKProvider(cryptoSuiteConfig)
+		if err != nil {
+			return nil, err
+		}
+		if kekProvider == nil {
+			return sdk.opts.MSP.CreateIdentityManagerProvider(endpointConfig, sdk.cryptoSuite, userStore)
+		}
+		factory, ok := sdk.opts.MSP.(kekMSPProviderFactory)
+		if !ok {
+			return nil, errors.New("MSP pkg doesn't support encrypted key stores")
+		}
+		return factory.CreateIdentityManagerProviderWithKEK(endpointConfig, sdk.cryptoSuite, userStore, kekProvider)
+	}
+	factory, ok := sdk.opts.MSP.(keyStoreMSPProviderFactory)
+	if !ok {
+		return nil, errors.New("MSP pkg doesn't support key stores")
+	}
+	return factory.CreateIdentityManagerProviderWithKeyStore(endpointConfig, sdk.cryptoSuite, userStore, sdk.opts.keyStore)
+}
+
 // initializeCryptoSuite Initializes crypto provider
 func (sdk *FabricSDK) initializeCryptoSuite(cryptoSuiteConfig core.CryptoSuiteConfig) error {
 	var err error
-	sdk.cryptoSuite, err = sdk.opts.Core.CreateCryptoSuiteProvider(cryptoSuiteConfig)
+	if sdk.opts.keyStore == nil {
+		sdk.cryptoSuite, err = sdk.opts.Core.CreateCryptoSuiteProvider(cryptoSuiteConfig)
+	} else if factory, ok := sdk.opts.Core.(keyStoreCoreProviderFactory); ok {
+		sdk.cryptoSuite, err = factory.CreateCryptoSuiteProviderWithKeyStore(cryptoSuiteConfig, sdk.opts.keyStore)
+	} else {
+		err = errors.New("core pkg doesn't support key stores")
+	}
 	if err != nil {
 		return errors.WithMessage(err, "failed to initialize crypto suite")
 	}
@@ -567,3 +674,33 @@ func withErrorHandlerProviderOpt(value fab.ErrorHandler) coptions.Opt {
 type errHandlerSetter interface {
 	SetErrorHandler(value fab.ErrorHandler)
 }
+
+type keyStoreCoreProviderFactory interface {
+	CreateCryptoSuiteProviderWithKeyStore(config core.CryptoSuiteConfig, keyStore core.KVStore) (core.CryptoSuite, error)
+}
+
+type keyStoreMSPProviderFactory interface {
+	CreateIdentityManagerProviderWithKeyStore(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore, keyStore core.KVStore) (msp.IdentityManagerProvider, error)
+}
+
+type kekMSPProviderFactory interface {
+	CreateIdentityManagerProviderWithKEK(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore, kekProvider sw.KEKProvider) (msp.IdentityManagerProvider, error)
+}
+
+type pkcs11MetricsSetter interface {
+	SetPKCS11Metrics(m pkcs11Metrics)
+}
+
+// pkcs11Metrics is the metrics interface of the PKCS11 context handles (pkcs11.Metrics), which is
+// implemented by metrics.PKCS11Metrics. It's declared here to not depend on PKCS11.
+type pkcs11Metrics = interface {
+	SetSessions(token string, inUse, idle int)
+	ObserveSign(token string, seconds float64, err error)
+	AddError(token, code string)
+	SetHealthy(token string, healthy bool)
+	AddReload(token string)
+}
+
+type breakersSetter interface {
+	SetCircuitBreakers(breakers *comm.Breakers)
+}
@@ -27,7 +27,7 @@ func (sdk *FabricSDK) initMetrics(configs *configs) {
 			panic("metrics failed to start: " + err.Error())
 		}
 
-		// for now NewClientMetrics supports channel client. TODO: if other client types require metrics tracking, update this function
+		// NewClientMetrics builds the metrics of the channel client as well as those of the other SDK components
 		sdk.clientMetrics = metrics.NewClientMetrics(sdk.system.Provider)
 	}
 }
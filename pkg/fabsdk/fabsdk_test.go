@@ -9,6 +9,12 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	reqContext "context"
+	"database/sql"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -23,16 +29,22 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
 	context2 "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defmsp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/health"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	mockapisdk "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocksdkapi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -86,6 +98,42 @@ func TestDoubleClose(t *testing.T) {
 	sdk.Close()
 }
 
+func TestHealth(t *testing.T) {
+	configPath := filepath.Join(metadata.GetProjectPath(), metadata.SDKConfigPath, sdkConfigFile)
+	sdk, err := New(configImpl.FromFile(configPath))
+	require.NoError(t, err)
+
+	require.NotNil(t, sdk.Health())
+	assert.Empty(t, sdk.Health().Live(reqContext.Background()))
+
+	err = sdk.Health().Register("app", health.Liveness, health.CheckerFunc(func(reqContext.Context) error {
+		return errors.New("app is unhealthy")
+	}))
+	require.NoError(t, err)
+	assert.Len(t, sdk.Health().Live(reqContext.Background()), 1)
+
+	sdk.Health().Deregister("app")
+
+	server := httptest.NewServer(sdk.Health().Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + health.LivenessPath)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	sdk.Close()
+
+	failedChecks := sdk.Health().Live(reqContext.Background())
+	require.Len(t, failedChecks, 1)
+	assert.Equal(t, SDKComponent, failedChecks[0].Component)
+
+	resp, err = http.Get(server.URL + health.LivenessPath)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
 func TestWithCorePkg(t *testing.T) {
 	// Test New SDK with valid config file
 	configPath := filepath.Join(metadata.GetProjectPath(), metadata.SDKConfigPath, sdkConfigFile)
@@ -134,6 +182,46 @@ func TestWithMSPPkg(t *testing.T) {
 	}
 }
 
+func TestWithKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sdkkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "keys.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	keyStore, err := msp.NewSQLKeyStore(&msp.SQLStoreOptions{DB: db})
+	require.NoError(t, err)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cryptoConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	cryptoConfig.EXPECT().SecurityProvider().Return("sw").AnyTimes()
+	cryptoConfig.EXPECT().SecurityAlgorithm().Return("SHA2").AnyTimes()
+	cryptoConfig.EXPECT().SecurityLevel().Return(256).AnyTimes()
+
+	// Initialize the default crypto suite so that it isn't replaced by the suite of the test
+	cryptosuite.GetDefault()
+
+	sdk := &FabricSDK{opts: options{Core: defcore.NewProviderFactory(), MSP: defmsp.NewProviderFactory()}}
+	require.Error(t, WithKeyStore(nil)(&sdk.opts))
+	require.NoError(t, WithKeyStore(keyStore)(&sdk.opts))
+	require.NoError(t, sdk.initializeCryptoSuite(cryptoConfig))
+
+	// The keys generated by the crypto suite are stored in the database
+	key, err := sdk.cryptoSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	_, err = keyStore.Load(hex.EncodeToString(key.SKI()) + "_sk")
+	require.NoError(t, err)
+
+	// Packages that don't support key stores are rejected
+	sdk.opts.Core = mockapisdk.NewMockCoreProviderFactory(mockCtrl)
+	assert.Error(t, sdk.initializeCryptoSuite(cryptoConfig))
+	sdk.opts.MSP = mockapisdk.NewMockMSPProviderFactory(mockCtrl)
+	_, err = sdk.createIdentityManagerProvider(nil, nil)
+	assert.Error(t, err)
+}
+
 func TestWithServicePkg(t *testing.T) {
 	// Test New SDK with valid config file
 	configPath := filepath.Join(metadata.GetProjectPath(), metadata.SDKConfigPath, sdkConfigFile)
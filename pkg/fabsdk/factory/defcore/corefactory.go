@@ -40,6 +40,12 @@ func (f *ProviderFactory) CreateCryptoSuiteProvider(config core.CryptoSuiteConfi
 	return cryptoSuiteProvider, err
 }
 
+// CreateCryptoSuiteProviderWithKeyStore returns a new default implementation of BCCSP whose keys are stored
+// in the given key-value store
+func (f *ProviderFactory) CreateCryptoSuiteProviderWithKeyStore(config core.CryptoSuiteConfig, keyStore core.KVStore) (core.CryptoSuite, error) {
+	return cryptosuiteimpl.GetSuiteByConfigWithKVStore(config, keyStore)
+}
+
 // CreateSigningManager returns a new default implementation of signing manager
 func (f *ProviderFactory) CreateSigningManager(cryptoProvider core.CryptoSuite) (core.SigningManager, error) {
 	return signingMgr.New(cryptoProvider)
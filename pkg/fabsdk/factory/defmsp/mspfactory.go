@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	kvs "github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/msppvdr"
 	mspimpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
@@ -53,3 +54,16 @@ func (f *ProviderFactory) CreateUserStore(config msp.IdentityConfig) (msp.UserSt
 func (f *ProviderFactory) CreateIdentityManagerProvider(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error) {
 	return msppvdr.New(endpointConfig, cryptoProvider, userStore)
 }
+
+// CreateIdentityManagerProviderWithKeyStore returns a new default implementation of MSP provider whose
+// identity managers load the private keys of the users from the given key store
+func (f *ProviderFactory) CreateIdentityManagerProviderWithKeyStore(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore, keyStore core.KVStore) (msp.IdentityManagerProvider, error) {
+	return msppvdr.New(endpointConfig, cryptoProvider, userStore, mspimpl.WithPrivKeyStore(keyStore))
+}
+
+// CreateIdentityManagerProviderWithKEK returns a new default implementation of MSP provider whose
+// identity managers decrypt the private keys of the encrypted keystore directories with the
+// key-encryption key of the given provider
+func (f *ProviderFactory) CreateIdentityManagerProviderWithKEK(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore, kekProvider sw.KEKProvider) (msp.IdentityManagerProvider, error) {
+	return msppvdr.New(endpointConfig, cryptoProvider, userStore, mspimpl.WithKEKProvider(kekProvider))
+}
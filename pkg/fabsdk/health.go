@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/health"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	// SDKComponent is the health check component which reports whether the SDK is open
+	SDKComponent = "sdk"
+	// EventsComponent is the health check component which reports the connection state of the event clients
+	EventsComponent = "events"
+	// DiscoveryComponent is the health check component which reports the freshness of the discovered peers
+	DiscoveryComponent = "discovery"
+
+	peerComponentPrefix    = "peer:"
+	ordererComponentPrefix = "orderer:"
+	caComponentPrefix      = "ca:"
+
+	// discoveryMaxAgeFactor is multiplied by the discovery refresh interval to determine
+	// the maximum age of the discovered peers
+	discoveryMaxAgeFactor = 3
+)
+
+type connectionStateProvider interface {
+	ConnectionStates() map[string]connectivity.State
+}
+
+type eventClientChecker interface {
+	CheckEventClients() error
+}
+
+type discoveryChecker interface {
+	CheckDiscovery(maxAge time.Duration) error
+}
+
+// Health returns the health check registry of the SDK. The registry contains the checks of the SDK
+// (connectivity to peers and orderers, event client connections, discovery freshness and
+// CA reachability) and applications may register their own checkers. Liveness and readiness are
+// served at /livez and /readyz respectively by the operations server, which is only started in
+// the pprof build. Otherwise the endpoints may be served by mounting Health().Handler() on an
+// application HTTP server.
+func (sdk *FabricSDK) Health() *health.Registry {
+	return sdk.health
+}
+
+// initHealth registers the health checks of the SDK and registers their endpoints on the operations server
+func (sdk *FabricSDK) initHealth(cfg *configs) error {
+	sdk.health = health.NewRegistry()
+
+	checkers := map[string]health.Checker{}
+	netConfig := cfg.endpointConfig.NetworkConfig()
+	if netConfig == nil {
+		netConfig = &fab.NetworkConfig{}
+	}
+
+	for component, checker := range sdk.endpointCheckers(netConfig) {
+		checkers[component] = checker
+	}
+
+	if checker, ok := sdk.provider.ChannelProvider().(eventClientChecker); ok {
+		checkers[EventsComponent] = health.CheckerFunc(func(context.Context) error {
+			return checker.CheckEventClients()
+		})
+	}
+
+	if checker, ok := sdk.provider.ChannelProvider().(discoveryChecker); ok {
+		maxAge := discoveryMaxAgeFactor * cfg.endpointConfig.Timeout(fab.DiscoveryServiceRefresh)
+		checkers[DiscoveryComponent] = health.CheckerFunc(func(context.Context) error {
+			return checker.CheckDiscovery(maxAge)
+		})
+	}
+
+	for caID, caURL := range caURLs(netConfig, cfg.identityConfig) {
+		checkers[caComponentPrefix+caID] = health.NewReachabilityChecker(caURL)
+	}
+
+	for component, checker := range checkers {
+		if err := sdk.health.Register(component, health.Readiness, checker); err != nil {
+			return errors.WithMessagef(err, "failed to register health checker for [%s]", component)
+		}
+	}
+
+	err := sdk.health.Register(SDKComponent, health.Liveness, health.CheckerFunc(func(context.Context) error {
+		if atomic.LoadInt32(&sdk.closed) == 1 {
+			return errors.New("SDK is closed")
+		}
+		return nil
+	}))
+	if err != nil {
+		return errors.WithMessage(err, "failed to register SDK health checker")
+	}
+
+	if sdk.system != nil {
+		sdk.system.RegisterHandler(health.LivenessPath, sdk.health.LivenessHandler(), false)
+		sdk.system.RegisterHandler(health.ReadinessPath, sdk.health.ReadinessHandler(), false)
+	}
+
+	return nil
+}
+
+// endpointCheckers returns the connectivity checkers of the peers and orderers in the given network config
+func (sdk *FabricSDK) endpointCheckers(netConfig *fab.NetworkConfig) map[string]health.Checker {
+	checkers := map[string]health.Checker{}
+
+	stateProvider, ok := sdk.provider.InfraProvider().CommManager().(connectionStateProvider)
+	if !ok {
+		return checkers
+	}
+
+	for name, peerCfg := range netConfig.Peers {
+		checkers[peerComponentPrefix+name] = health.NewConnectionChecker(endpoint.ToAddress(peerCfg.URL), stateProvider.ConnectionStates)
+	}
+	for name, ordererCfg := range netConfig.Orderers {
+		checkers[ordererComponentPrefix+name] = health.NewConnectionChecker(endpoint.ToAddress(ordererCfg.URL), stateProvider.ConnectionStates)
+	}
+	return checkers
+}
+
+// caURLs returns the URLs of the CAs of all organizations keyed by CA ID
+func caURLs(netConfig *fab.NetworkConfig, identityConfig msp.IdentityConfig) map[string]string {
+	urls := make(map[string]string)
+	for _, orgConfig := range netConfig.Organizations {
+		for _, caID := range orgConfig.CertificateAuthorities {
+			caConfig, ok := identityConfig.CAConfig(caID)
+			if !ok || caConfig.URL == "" {
+				logger.Debugf("No URL configured for CA [%s]", caID)
+				continue
+			}
+			urls[caID] = caConfig.URL
+		}
+	}
+	return urls
+}
@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package health provides the health checks of the SDK. Liveness and readiness are computed
+// from the registered checkers and may be served by the operations server (or by an
+// application HTTP server) for use by probes such as those of Kubernetes.
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	// LivenessPath is the path of the liveness endpoint on the operations server
+	LivenessPath = "/livez"
+	// ReadinessPath is the path of the readiness endpoint on the operations server
+	ReadinessPath = "/readyz"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// Checker is implemented by components that report their health. The checker returns
+// an error describing the problem if the component is unhealthy.
+// Checker is compatible with the HealthChecker of the operations server.
+type Checker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerFunc is a function that implements Checker
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck invokes the function
+func (f CheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Probe indicates whether a checker contributes to liveness or to readiness
+type Probe int
+
+const (
+	// Readiness checkers indicate whether the application is able to serve requests (for example,
+	// whether the peers and orderers are reachable). A failed readiness check does not affect liveness.
+	Readiness Probe = iota
+	// Liveness checkers indicate whether the application is running properly. A failed liveness
+	// check also fails readiness.
+	Liveness
+)
+
+// FailedCheck contains the component and the reason of a failed check
+type FailedCheck = healthz.FailedCheck
+
+// Registry holds the health checkers of the SDK and of the application
+type Registry struct {
+	liveness  *healthz.HealthHandler
+	readiness *healthz.HealthHandler
+	timeout   time.Duration
+}
+
+// NewRegistry returns a new health check registry
+func NewRegistry() *Registry {
+	r := &Registry{
+		liveness:  healthz.NewHealthHandler(),
+		readiness: healthz.NewHealthHandler(),
+	}
+	r.SetTimeout(defaultTimeout)
+	return r
+}
+
+// SetTimeout sets the maximum duration of the checks
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.timeout = timeout
+	r.liveness.SetTimeout(timeout)
+	r.readiness.SetTimeout(timeout)
+}
+
+// Register registers a checker for the given component. Liveness checkers contribute
+// to both liveness and readiness. An error is returned if the component is already registered.
+func (r *Registry) Register(component string, probe Probe, checker Checker) error {
+	if err := r.readiness.RegisterChecker(component, checker); err != nil {
+		return err
+	}
+
+	if probe == Liveness {
+		if err := r.liveness.RegisterChecker(component, checker); err != nil {
+			r.readiness.DeregisterChecker(component)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deregister removes the checker of the given component
+func (r *Registry) Deregister(component string) {
+	r.liveness.DeregisterChecker(component)
+	r.readiness.DeregisterChecker(component)
+}
+
+// Live runs the liveness checks and returns the failed checks
+func (r *Registry) Live(ctx context.Context) []FailedCheck {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.liveness.RunChecks(ctx)
+}
+
+// Ready runs the readiness checks and returns the failed checks
+func (r *Registry) Ready(ctx context.Context) []FailedCheck {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.readiness.RunChecks(ctx)
+}
+
+// LivenessHandler returns an HTTP handler which responds with status 200 if all
+// liveness checks pass and 503 otherwise
+func (r *Registry) LivenessHandler() http.Handler {
+	return r.liveness
+}
+
+// ReadinessHandler returns an HTTP handler which responds with status 200 if all
+// readiness checks pass and 503 otherwise
+func (r *Registry) ReadinessHandler() http.Handler {
+	return r.readiness
+}
+
+// Handler returns an HTTP handler which serves liveness at LivenessPath and readiness at
+// ReadinessPath. It may be mounted on an application HTTP server, for example when the
+// operations server isn't started.
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(LivenessPath, r.LivenessHandler())
+	mux.Handle(ReadinessPath, r.ReadinessHandler())
+	return mux
+}
+
+// ConnectionStates returns the connectivity state of the cached GRPC connections keyed by target
+type ConnectionStates func() map[string]connectivity.State
+
+// NewConnectionChecker returns a checker that reports the connectivity state of the given
+// endpoint (peer or orderer) target. The endpoint is considered unhealthy if its connection
+// is in a transient failure. Connections are established on demand and closed when idle,
+// so an endpoint without a connection is not considered unhealthy.
+func NewConnectionChecker(target string, states ConnectionStates) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		state, ok := states()[target]
+		if !ok {
+			return nil
+		}
+
+		switch state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return errors.Errorf("connection to [%s] is in state [%s]", target, state)
+		default:
+			return nil
+		}
+	})
+}
+
+// NewReachabilityChecker returns a checker that reports whether a network connection
+// may be established to the host and port of the given URL
+func NewReachabilityChecker(rawURL string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		address, err := hostPort(rawURL)
+		if err != nil {
+			return err
+		}
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return errors.Wrapf(err, "[%s] is not reachable", rawURL)
+		}
+		return conn.Close()
+	})
+}
+
+func hostPort(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid URL [%s]", rawURL)
+	}
+
+	if u.Port() != "" {
+		return u.Host, nil
+	}
+
+	switch u.Scheme {
+	case "https":
+		return net.JoinHostPort(u.Hostname(), "443"), nil
+	case "http":
+		return net.JoinHostPort(u.Hostname(), "80"), nil
+	default:
+		return "", errors.Errorf("port not specified in URL [%s]", rawURL)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	livenessErr := errors.New("not alive")
+	readinessErr := errors.New("not ready")
+
+	var live, ready error
+	require.NoError(t, r.Register("liveness", Liveness, CheckerFunc(func(context.Context) error { return live })))
+	require.NoError(t, r.Register("readiness", Readiness, CheckerFunc(func(context.Context) error { return ready })))
+	require.Error(t, r.Register("readiness", Liveness, CheckerFunc(func(context.Context) error { return nil })))
+
+	assert.Empty(t, r.Live(context.Background()))
+	assert.Empty(t, r.Ready(context.Background()))
+	assert.Equal(t, http.StatusOK, serve(r.LivenessHandler()))
+	assert.Equal(t, http.StatusOK, serve(r.ReadinessHandler()))
+
+	ready = readinessErr
+	assert.Empty(t, r.Live(context.Background()))
+	assert.Equal(t, []FailedCheck{{Component: "readiness", Reason: readinessErr.Error()}}, r.Ready(context.Background()))
+	assert.Equal(t, http.StatusOK, serve(r.LivenessHandler()))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(r.ReadinessHandler()))
+
+	ready = nil
+	live = livenessErr
+	assert.Equal(t, []FailedCheck{{Component: "liveness", Reason: livenessErr.Error()}}, r.Live(context.Background()))
+	assert.Equal(t, []FailedCheck{{Component: "liveness", Reason: livenessErr.Error()}}, r.Ready(context.Background()))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(r.LivenessHandler()))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(r.ReadinessHandler()))
+
+	r.Deregister("liveness")
+	assert.Empty(t, r.Live(context.Background()))
+	assert.Empty(t, r.Ready(context.Background()))
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+
+	var ready error
+	require.NoError(t, r.Register("readiness", Readiness, CheckerFunc(func(context.Context) error { return ready })))
+
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	assert.Equal(t, http.StatusOK, get(t, server.URL+LivenessPath))
+	assert.Equal(t, http.StatusOK, get(t, server.URL+ReadinessPath))
+	assert.Equal(t, http.StatusNotFound, get(t, server.URL+"/healthz"))
+
+	ready = errors.New("not ready")
+	assert.Equal(t, http.StatusOK, get(t, server.URL+LivenessPath))
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, server.URL+ReadinessPath))
+}
+
+func TestConnectionChecker(t *testing.T) {
+	const target = "peer0.org1.example.com:7051"
+
+	states := map[string]connectivity.State{}
+	checker := NewConnectionChecker(target, func() map[string]connectivity.State { return states })
+
+	assert.NoError(t, checker.HealthCheck(context.Background()), "endpoint without a connection should be healthy")
+
+	states[target] = connectivity.Ready
+	assert.NoError(t, checker.HealthCheck(context.Background()))
+
+	states[target] = connectivity.Idle
+	assert.NoError(t, checker.HealthCheck(context.Background()))
+
+	states[target] = connectivity.TransientFailure
+	assert.Error(t, checker.HealthCheck(context.Background()))
+}
+
+func TestReachabilityChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	url := "https://" + listener.Addr().String()
+
+	assert.NoError(t, NewReachabilityChecker(url).HealthCheck(context.Background()))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, NewReachabilityChecker(url).HealthCheck(context.Background()))
+
+	assert.Error(t, NewReachabilityChecker("grpc://localhost").HealthCheck(context.Background()), "expecting error for missing port")
+}
+
+func TestHostPort(t *testing.T) {
+	tests := map[string]string{
+		"https://ca.org1.example.com:7054": "ca.org1.example.com:7054",
+		"http://ca.org1.example.com":       "ca.org1.example.com:80",
+		"https://ca.org1.example.com":      "ca.org1.example.com:443",
+		"ca.org1.example.com:7054":         "ca.org1.example.com:7054",
+	}
+
+	for url, expected := range tests {
+		address, err := hostPort(url)
+		require.NoError(t, err)
+		assert.Equal(t, expected, address)
+	}
+}
+
+func serve(handler http.Handler) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Code
+}
+
+func get(t *testing.T, url string) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
@@ -9,7 +9,6 @@ package metrics
 import "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
 
 var (
-	// for now, only channel clients require metrics tracking. TODO: update to generalize metrics for other client types if needed.
 	queriesReceived = metrics.CounterOpts{
 		Namespace:    "channel",
 		Name:         "queries_received",
@@ -68,7 +67,10 @@ var (
 	}
 )
 
-// ClientMetrics contains the metrics used in the (channel) client
+// ClientMetrics contains the metrics used in the channel client. The metrics of the
+// other SDK components are accessed with Events, Discovery, Connections, Orderer,
+// ResMgmt, Ledger and CA. If the metrics were not built with NewClientMetrics then
+// these accessors return disabled (no-op) metrics.
 type ClientMetrics struct {
 	QueriesReceived    metrics.Counter
 	QueriesFailed      metrics.Counter
@@ -78,6 +80,8 @@ type ClientMetrics struct {
 	ExecutionsFailed   metrics.Counter
 	ExecutionDuration  metrics.Histogram
 	ExecutionTimeouts  metrics.Counter
+
+	sdkMetrics *sdkMetrics
 }
 
 // NewClientMetrics builds a new instance of ClientMetrics
@@ -91,5 +95,6 @@ func NewClientMetrics(p metrics.Provider) *ClientMetrics {
 		ExecutionsFailed:   p.NewCounter(executionsFailed),
 		ExecutionDuration:  p.NewHistogram(executionDuration),
 		ExecutionTimeouts:  p.NewCounter(executionTimeouts),
+		sdkMetrics:         newSDKMetrics(p),
 	}
 }
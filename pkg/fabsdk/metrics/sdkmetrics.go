@@ -0,0 +1,459 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+)
+
+// Label names that are shared by the SDK metrics
+const (
+	ChannelLabel   = "channel"
+	PeerLabel      = "peer"
+	OrdererLabel   = "orderer"
+	CALabel        = "ca"
+	TargetLabel    = "target"
+	ServiceLabel   = "service"
+	OperationLabel = "operation"
+	StatusLabel    = "status"
+	TypeLabel      = "type"
+	StateLabel     = "state"
+	TokenLabel     = "token"
+	CodeLabel      = "code"
+)
+
+// Values of the status label
+const (
+	StatusSuccess = "success"
+	StatusTimeout = "timeout"
+	StatusFailure = "failure"
+)
+
+var (
+	eventConnections = metrics.GaugeOpts{
+		Namespace:    "event",
+		Name:         "connections",
+		Help:         "The number of open event service connections.",
+		LabelNames:   []string{ChannelLabel, PeerLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{peer}",
+	}
+	eventReconnects = metrics.CounterOpts{
+		Namespace:    "event",
+		Name:         "reconnects",
+		Help:         "The number of times that the event service reconnected to a peer.",
+		LabelNames:   []string{ChannelLabel, PeerLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{peer}",
+	}
+	eventBlocksReceived = metrics.CounterOpts{
+		Namespace:    "event",
+		Name:         "blocks_received",
+		Help:         "The number of blocks (or filtered blocks) received by the event service.",
+		LabelNames:   []string{ChannelLabel, PeerLabel, TypeLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{peer}.%{type}",
+	}
+	eventBlockLag = metrics.HistogramOpts{
+		Namespace:    "event",
+		Name:         "block_lag",
+		Help:         "The time in seconds between the creation of the first transaction in a block and the receipt of the block.",
+		LabelNames:   []string{ChannelLabel, PeerLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{peer}",
+	}
+	eventRegistrations = metrics.GaugeOpts{
+		Namespace:    "event",
+		Name:         "registrations",
+		Help:         "The number of outstanding event registrations.",
+		LabelNames:   []string{ChannelLabel, TypeLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{type}",
+	}
+
+	discoveryRefreshDuration = metrics.HistogramOpts{
+		Namespace:    "discovery",
+		Name:         "refresh_duration",
+		Help:         "The time to refresh the peers (discovery) or endorsers (selection) of a channel.",
+		LabelNames:   []string{ChannelLabel, ServiceLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{service}",
+	}
+	discoveryRefreshErrors = metrics.CounterOpts{
+		Namespace:    "discovery",
+		Name:         "refresh_errors",
+		Help:         "The number of failed refreshes of the peers (discovery) or endorsers (selection) of a channel.",
+		LabelNames:   []string{ChannelLabel, ServiceLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{service}",
+	}
+	discoveryGreylistedPeers = metrics.CounterOpts{
+		Namespace:    "discovery",
+		Name:         "greylisted_peers",
+		Help:         "The number of times that a peer was greylisted.",
+		LabelNames:   []string{ChannelLabel, PeerLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{peer}",
+	}
+
+	connOpen = metrics.GaugeOpts{
+		Namespace:    "comm",
+		Name:         "open_connections",
+		Help:         "The number of open (cached) GRPC connections.",
+		LabelNames:   []string{},
+		StatsdFormat: "%{#fqname}",
+	}
+	connIdle = metrics.GaugeOpts{
+		Namespace:    "comm",
+		Name:         "idle_connections",
+		Help:         "The number of cached GRPC connections that are not in use.",
+		LabelNames:   []string{},
+		StatsdFormat: "%{#fqname}",
+	}
+	connDialDuration = metrics.HistogramOpts{
+		Namespace:    "comm",
+		Name:         "dial_duration",
+		Help:         "The time to establish a GRPC connection.",
+		LabelNames:   []string{TargetLabel, StatusLabel},
+		StatsdFormat: "%{#fqname}.%{target}.%{status}",
+	}
+	connBreakerState = metrics.GaugeOpts{
+		Namespace:    "comm",
+		Name:         "breaker_state",
+		Help:         "The state of the circuit breaker of an endpoint (0 = closed, 1 = open, 2 = half-open).",
+		LabelNames:   []string{TargetLabel},
+		StatsdFormat: "%{#fqname}.%{target}",
+	}
+	connBreakerTransitions = metrics.CounterOpts{
+		Namespace:    "comm",
+		Name:         "breaker_transitions",
+		Help:         "The number of times that the circuit breaker of an endpoint changed state.",
+		LabelNames:   []string{TargetLabel, StateLabel},
+		StatsdFormat: "%{#fqname}.%{target}.%{state}",
+	}
+
+	ordererBroadcasts = metrics.CounterOpts{
+		Namespace:    "orderer",
+		Name:         "broadcasts",
+		Help:         "The number of envelopes broadcast to an orderer.",
+		LabelNames:   []string{ChannelLabel, OrdererLabel, StatusLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{orderer}.%{status}",
+	}
+	ordererBroadcastDuration = metrics.HistogramOpts{
+		Namespace:    "orderer",
+		Name:         "broadcast_duration",
+		Help:         "The time to broadcast an envelope to an orderer.",
+		LabelNames:   []string{ChannelLabel, OrdererLabel, StatusLabel},
+		StatsdFormat: "%{#fqname}.%{channel}.%{orderer}.%{status}",
+	}
+
+	pkcs11SessionsInUse = metrics.GaugeOpts{
+		Namespace:    "pkcs11",
+		Name:         "sessions_in_use",
+		Help:         "The number of open sessions of a PKCS11 token that are in use.",
+		LabelNames:   []string{TokenLabel},
+		StatsdFormat: "%{#fqname}.%{token}",
+	}
+	pkcs11IdleSessions = metrics.GaugeOpts{
+		Namespace:    "pkcs11",
+		Name:         "idle_sessions",
+		Help:         "The number of sessions in the session pool of a PKCS11 token.",
+		LabelNames:   []string{TokenLabel},
+		StatsdFormat: "%{#fqname}.%{token}",
+	}
+	pkcs11SignDuration = metrics.HistogramOpts{
+		Namespace:    "pkcs11",
+		Name:         "sign_duration",
+		Help:         "The time to sign with a PKCS11 token.",
+		LabelNames:   []string{TokenLabel, StatusLabel},
+		StatsdFormat: "%{#fqname}.%{token}.%{status}",
+	}
+	pkcs11Errors = metrics.CounterOpts{
+		Namespace:    "pkcs11",
+		Name:         "errors",
+		Help:         "The number of errors returned by a PKCS11 token, by CKR code.",
+		LabelNames:   []string{TokenLabel, CodeLabel},
+		StatsdFormat: "%{#fqname}.%{token}.%{code}",
+	}
+	pkcs11TokenHealthy = metrics.GaugeOpts{
+		Namespace:    "pkcs11",
+		Name:         "token_healthy",
+		Help:         "The outcome of the last health check of a PKCS11 token (1 = healthy, 0 = unhealthy).",
+		LabelNames:   []string{TokenLabel},
+		StatsdFormat: "%{#fqname}.%{token}",
+	}
+	pkcs11Reloads = metrics.CounterOpts{
+		Namespace:    "pkcs11",
+		Name:         "reloads",
+		Help:         "The number of times that the context of a PKCS11 token was reloaded and its slot re-discovered.",
+		LabelNames:   []string{TokenLabel},
+		StatsdFormat: "%{#fqname}.%{token}",
+	}
+)
+
+// EventMetrics contains the metrics of the event service
+type EventMetrics struct {
+	Connections    metrics.Gauge
+	Reconnects     metrics.Counter
+	BlocksReceived metrics.Counter
+	BlockLag       metrics.Histogram
+	Registrations  metrics.Gauge
+}
+
+// NewEventMetrics builds a new instance of EventMetrics
+func NewEventMetrics(p metrics.Provider) *EventMetrics {
+	return &EventMetrics{
+		Connections:    p.NewGauge(eventConnections),
+		Reconnects:     p.NewCounter(eventReconnects),
+		BlocksReceived: p.NewCounter(eventBlocksReceived),
+		BlockLag:       p.NewHistogram(eventBlockLag),
+		Registrations:  p.NewGauge(eventRegistrations),
+	}
+}
+
+// DiscoveryMetrics contains the metrics of the discovery and selection services
+type DiscoveryMetrics struct {
+	RefreshDuration metrics.Histogram
+	RefreshErrors   metrics.Counter
+	GreylistedPeers metrics.Counter
+}
+
+// NewDiscoveryMetrics builds a new instance of DiscoveryMetrics
+func NewDiscoveryMetrics(p metrics.Provider) *DiscoveryMetrics {
+	return &DiscoveryMetrics{
+		RefreshDuration: p.NewHistogram(discoveryRefreshDuration),
+		RefreshErrors:   p.NewCounter(discoveryRefreshErrors),
+		GreylistedPeers: p.NewCounter(discoveryGreylistedPeers),
+	}
+}
+
+// Values of the service label of the discovery metrics
+const (
+	DiscoveryService = "discovery"
+	SelectionService = "selection"
+)
+
+// ObserveRefresh records the duration (in seconds) and the outcome of a refresh by the given service
+// (DiscoveryService or SelectionService)
+func (m *DiscoveryMetrics) ObserveRefresh(channelID, service string, seconds float64, err error) {
+	labels := []string{ChannelLabel, channelID, ServiceLabel, service}
+	m.RefreshDuration.With(labels...).Observe(seconds)
+	if err != nil {
+		m.RefreshErrors.With(labels...).Add(1)
+	}
+}
+
+// ConnectionMetrics contains the metrics of the GRPC connection cache and the circuit breakers of the endpoints
+type ConnectionMetrics struct {
+	OpenConnections    metrics.Gauge
+	IdleConnections    metrics.Gauge
+	DialDuration       metrics.Histogram
+	BreakerState       metrics.Gauge
+	BreakerTransitions metrics.Counter
+}
+
+// NewConnectionMetrics builds a new instance of ConnectionMetrics
+func NewConnectionMetrics(p metrics.Provider) *ConnectionMetrics {
+	return &ConnectionMetrics{
+		OpenConnections:    p.NewGauge(connOpen),
+		IdleConnections:    p.NewGauge(connIdle),
+		DialDuration:       p.NewHistogram(connDialDuration),
+		BreakerState:       p.NewGauge(connBreakerState),
+		BreakerTransitions: p.NewCounter(connBreakerTransitions),
+	}
+}
+
+// OrdererMetrics contains the metrics of the orderer client
+type OrdererMetrics struct {
+	Broadcasts        metrics.Counter
+	BroadcastDuration metrics.Histogram
+}
+
+// NewOrdererMetrics builds a new instance of OrdererMetrics
+func NewOrdererMetrics(p metrics.Provider) *OrdererMetrics {
+	return &OrdererMetrics{
+		Broadcasts:        p.NewCounter(ordererBroadcasts),
+		BroadcastDuration: p.NewHistogram(ordererBroadcastDuration),
+	}
+}
+
+// PKCS11Metrics contains the metrics of the session pools and the signing activity of the PKCS11 tokens
+type PKCS11Metrics struct {
+	SessionsInUse metrics.Gauge
+	IdleSessions  metrics.Gauge
+	SignDuration  metrics.Histogram
+	Errors        metrics.Counter
+	TokenHealthy  metrics.Gauge
+	Reloads       metrics.Counter
+}
+
+// NewPKCS11Metrics builds a new instance of PKCS11Metrics
+func NewPKCS11Metrics(p metrics.Provider) *PKCS11Metrics {
+	return &PKCS11Metrics{
+		SessionsInUse: p.NewGauge(pkcs11SessionsInUse),
+		IdleSessions:  p.NewGauge(pkcs11IdleSessions),
+		SignDuration:  p.NewHistogram(pkcs11SignDuration),
+		Errors:        p.NewCounter(pkcs11Errors),
+		TokenHealthy:  p.NewGauge(pkcs11TokenHealthy),
+		Reloads:       p.NewCounter(pkcs11Reloads),
+	}
+}
+
+// SetSessions records the number of open sessions of a token which are in use and idle
+func (m *PKCS11Metrics) SetSessions(token string, inUse, idle int) {
+	m.SessionsInUse.With(TokenLabel, token).Set(float64(inUse))
+	m.IdleSessions.With(TokenLabel, token).Set(float64(idle))
+}
+
+// ObserveSign records the duration in seconds and the outcome of a signing operation of a token
+func (m *PKCS11Metrics) ObserveSign(token string, seconds float64, err error) {
+	m.SignDuration.With(TokenLabel, token, StatusLabel, Status(err)).Observe(seconds)
+}
+
+// AddError counts an error returned by a token by CKR code
+func (m *PKCS11Metrics) AddError(token, code string) {
+	m.Errors.With(TokenLabel, token, CodeLabel, code).Add(1)
+}
+
+// SetHealthy records the health of a token
+func (m *PKCS11Metrics) SetHealthy(token string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	m.TokenHealthy.With(TokenLabel, token).Set(value)
+}
+
+// AddReload counts a reload of the PKCS11 context of a token
+func (m *PKCS11Metrics) AddReload(token string) {
+	m.Reloads.With(TokenLabel, token).Add(1)
+}
+
+// OperationMetrics contains the metrics of the operations of a client (resource management, ledger or CA).
+// The operations are labelled with the scope of the operation (channel or CA), the operation name and the status.
+type OperationMetrics struct {
+	Operations        metrics.Counter
+	OperationDuration metrics.Histogram
+
+	scopeLabel string
+}
+
+// NewOperationMetrics builds a new instance of OperationMetrics for the given client namespace
+// (for example, "resmgmt") and scope label (for example, "channel")
+func NewOperationMetrics(p metrics.Provider, namespace, scopeLabel string) *OperationMetrics {
+	labels := []string{scopeLabel, OperationLabel, StatusLabel}
+	statsdFormat := fmt.Sprintf("%%{#fqname}.%%{%s}.%%{%s}.%%{%s}", scopeLabel, OperationLabel, StatusLabel)
+
+	return &OperationMetrics{
+		Operations: p.NewCounter(metrics.CounterOpts{
+			Namespace:    namespace,
+			Name:         "operations",
+			Help:         fmt.Sprintf("The number of %s client operations.", namespace),
+			LabelNames:   labels,
+			StatsdFormat: statsdFormat,
+		}),
+		OperationDuration: p.NewHistogram(metrics.HistogramOpts{
+			Namespace:    namespace,
+			Name:         "operation_duration",
+			Help:         fmt.Sprintf("The time to complete a %s client operation.", namespace),
+			LabelNames:   labels,
+			StatsdFormat: statsdFormat,
+		}),
+		scopeLabel: scopeLabel,
+	}
+}
+
+// Observe records the outcome and duration (in seconds) of an operation
+func (m *OperationMetrics) Observe(scope, operation string, seconds float64, err error) {
+	labels := []string{m.scopeLabel, scope, OperationLabel, operation, StatusLabel, Status(err)}
+	m.Operations.With(labels...).Add(1)
+	m.OperationDuration.With(labels...).Observe(seconds)
+}
+
+// Status returns the value of the status label for the given error
+func Status(err error) string {
+	if err == nil {
+		return StatusSuccess
+	}
+	if s, ok := status.FromError(err); ok {
+		if s.Code == status.Timeout.ToInt32() {
+			return StatusTimeout
+		}
+		return fmt.Sprintf("%s:%d", s.Group, s.Code)
+	}
+	return StatusFailure
+}
+
+var disabledMetrics = newSDKMetrics(&disabled.Provider{})
+
+// sdkMetrics contains the metrics of the SDK components other than the channel client
+type sdkMetrics struct {
+	events      *EventMetrics
+	discovery   *DiscoveryMetrics
+	connections *ConnectionMetrics
+	orderer     *OrdererMetrics
+	resMgmt     *OperationMetrics
+	ledger      *OperationMetrics
+	ca          *OperationMetrics
+	pkcs11      *PKCS11Metrics
+}
+
+func newSDKMetrics(p metrics.Provider) *sdkMetrics {
+	return &sdkMetrics{
+		events:      NewEventMetrics(p),
+		discovery:   NewDiscoveryMetrics(p),
+		connections: NewConnectionMetrics(p),
+		orderer:     NewOrdererMetrics(p),
+		resMgmt:     NewOperationMetrics(p, "resmgmt", ChannelLabel),
+		ledger:      NewOperationMetrics(p, "ledger", ChannelLabel),
+		ca:          NewOperationMetrics(p, "ca", CALabel),
+		pkcs11:      NewPKCS11Metrics(p),
+	}
+}
+
+func (m *ClientMetrics) sdk() *sdkMetrics {
+	if m == nil || m.sdkMetrics == nil {
+		return disabledMetrics
+	}
+	return m.sdkMetrics
+}
+
+// Events returns the event service metrics
+func (m *ClientMetrics) Events() *EventMetrics {
+	return m.sdk().events
+}
+
+// Discovery returns the discovery and selection service metrics
+func (m *ClientMetrics) Discovery() *DiscoveryMetrics {
+	return m.sdk().discovery
+}
+
+// Connections returns the GRPC connection cache metrics
+func (m *ClientMetrics) Connections() *ConnectionMetrics {
+	return m.sdk().connections
+}
+
+// Orderer returns the orderer client metrics
+func (m *ClientMetrics) Orderer() *OrdererMetrics {
+	return m.sdk().orderer
+}
+
+// ResMgmt returns the resource management client metrics
+func (m *ClientMetrics) ResMgmt() *OperationMetrics {
+	return m.sdk().resMgmt
+}
+
+// Ledger returns the ledger client metrics
+func (m *ClientMetrics) Ledger() *OperationMetrics {
+	return m.sdk().ledger
+}
+
+// CA returns the CA client metrics
+func (m *ClientMetrics) CA() *OperationMetrics {
+	return m.sdk().ca
+}
+
+// PKCS11 returns the PKCS11 token metrics
+func (m *ClientMetrics) PKCS11() *PKCS11Metrics {
+	return m.sdk().pkcs11
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, StatusSuccess, Status(nil))
+	assert.Equal(t, StatusFailure, Status(errors.New("some error")))
+	assert.Equal(t, StatusTimeout, Status(status.New(status.ClientStatus, status.Timeout.ToInt32(), "timed out", nil)))
+	assert.Equal(t, "Endorser Server Status:500", Status(status.New(status.EndorserServerStatus, 500, "failed", nil)))
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	var nilMetrics *ClientMetrics
+
+	for _, m := range []*ClientMetrics{nilMetrics, {}} {
+		require.NotNil(t, m.Events())
+		require.NotNil(t, m.Discovery())
+		require.NotNil(t, m.Connections())
+		require.NotNil(t, m.Orderer())
+		require.NotNil(t, m.ResMgmt())
+		require.NotNil(t, m.Ledger())
+		require.NotNil(t, m.CA())
+		require.NotNil(t, m.PKCS11())
+
+		// Should not panic
+		m.ResMgmt().Observe("mychannel", "JoinChannel", 1, nil)
+		m.Discovery().ObserveRefresh("mychannel", DiscoveryService, 1, errors.New("some error"))
+		m.PKCS11().SetSessions("token", 1, 2)
+		m.PKCS11().ObserveSign("token", 0.1, nil)
+		m.PKCS11().AddError("token", "CKR_DEVICE_ERROR")
+		m.PKCS11().SetHealthy("token", false)
+		m.PKCS11().AddReload("token")
+	}
+}
+
+func TestOperationMetrics(t *testing.T) {
+	p := newRecordingProvider()
+	m := NewOperationMetrics(p, "ca", CALabel)
+
+	m.Observe("ca.org1.example.com", "Enroll", 0.5, nil)
+	m.Observe("ca.org1.example.com", "Enroll", 1.5, errors.New("enroll failed"))
+
+	assert.Equal(t, 1.0, p.value("ca_operations", "ca", "ca.org1.example.com", "operation", "Enroll", "status", StatusSuccess))
+	assert.Equal(t, 1.0, p.value("ca_operations", "ca", "ca.org1.example.com", "operation", "Enroll", "status", StatusFailure))
+	assert.Equal(t, 0.5, p.value("ca_operation_duration", "ca", "ca.org1.example.com", "operation", "Enroll", "status", StatusSuccess))
+	assert.Equal(t, 1.5, p.value("ca_operation_duration", "ca", "ca.org1.example.com", "operation", "Enroll", "status", StatusFailure))
+}
+
+func TestDiscoveryMetrics(t *testing.T) {
+	p := newRecordingProvider()
+	m := NewDiscoveryMetrics(p)
+
+	m.ObserveRefresh("mychannel", SelectionService, 2, nil)
+	assert.Equal(t, 2.0, p.value("discovery_refresh_duration", "channel", "mychannel", "service", SelectionService))
+	assert.Equal(t, 0.0, p.value("discovery_refresh_errors", "channel", "mychannel", "service", SelectionService))
+
+	m.ObserveRefresh("mychannel", SelectionService, 1, errors.New("refresh failed"))
+	assert.Equal(t, 3.0, p.value("discovery_refresh_duration", "channel", "mychannel", "service", SelectionService))
+	assert.Equal(t, 1.0, p.value("discovery_refresh_errors", "channel", "mychannel", "service", SelectionService))
+}
+
+// recordingProvider is a metrics provider that accumulates the values
+// of all metrics by name and label values
+type recordingProvider struct {
+	mutex  sync.Mutex
+	values map[string]float64
+}
+
+func newRecordingProvider() *recordingProvider {
+	return &recordingProvider{values: make(map[string]float64)}
+}
+
+func (p *recordingProvider) value(name string, labelValues ...string) float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.values[key(name, labelValues)]
+}
+
+func (p *recordingProvider) add(name string, labelValues []string, delta float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.values[key(name, labelValues)] += delta
+}
+
+func (p *recordingProvider) NewCounter(o metrics.CounterOpts) metrics.Counter {
+	return &counter{&recorder{p: p, name: o.Namespace + "_" + o.Name}}
+}
+
+func (p *recordingProvider) NewGauge(o metrics.GaugeOpts) metrics.Gauge {
+	return &gauge{&recorder{p: p, name: o.Namespace + "_" + o.Name}}
+}
+
+func (p *recordingProvider) NewHistogram(o metrics.HistogramOpts) metrics.Histogram {
+	return &histogram{&recorder{p: p, name: o.Namespace + "_" + o.Name}}
+}
+
+type recorder struct {
+	p           *recordingProvider
+	name        string
+	labelValues []string
+}
+
+func (r *recorder) with(labelValues []string) *recorder {
+	return &recorder{p: r.p, name: r.name, labelValues: append(append([]string{}, r.labelValues...), labelValues...)}
+}
+
+type counter struct{ *recorder }
+
+func (c *counter) With(labelValues ...string) metrics.Counter { return &counter{c.with(labelValues)} }
+func (c *counter) Add(delta float64)                          { c.p.add(c.name, c.labelValues, delta) }
+
+type gauge struct{ *recorder }
+
+func (g *gauge) With(labelValues ...string) metrics.Gauge { return &gauge{g.with(labelValues)} }
+func (g *gauge) Add(delta float64)                        { g.p.add(g.name, g.labelValues, delta) }
+func (g *gauge) Set(value float64)                        { g.p.add(g.name, g.labelValues, value) }
+
+type histogram struct{ *recorder }
+
+func (h *histogram) With(labelValues ...string) metrics.Histogram {
+	return &histogram{h.with(labelValues)}
+}
+func (h *histogram) Observe(value float64) { h.p.add(h.name, h.labelValues, value) }
+
+func key(name string, labelValues []string) string {
+	return name + "|" + strings.Join(labelValues, "|")
+}
@@ -8,6 +8,7 @@ package chpvdr
 
 import (
 	reqContext "context"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
@@ -27,18 +28,21 @@ var logger = logging.NewLogger("fabsdk")
 type ChannelProvider struct {
 	providerContext context.Providers
 	ctxtCaches      *lazycache.Cache
+	tracker         *serviceTracker
 }
 
 // New creates a ChannelProvider based on a context
 func New(config fab.EndpointConfig, opts ...options.Opt) (*ChannelProvider, error) {
+	tracker := newServiceTracker()
 	return &ChannelProvider{
 		ctxtCaches: lazycache.New(
 			"Client_Context_Cache",
 			func(key lazycache.Key) (interface{}, error) {
 				ck := key.(*ctxtCacheKey)
-				return newContextCache(ck.context, opts), nil
+				return newContextCache(ck.context, tracker, opts), nil
 			},
 		),
+		tracker: tracker,
 	}, nil
 }
 
@@ -64,6 +68,30 @@ func (cp *ChannelProvider) CloseContext(ctx fab.ClientContext) {
 	cp.ctxtCaches.Delete(key)
 }
 
+// InvalidateChannels refreshes the services (discovery, selection, membership, channel configuration
+// and event service) of the given channels for all contexts so that they are re-created using the
+// current endpoint configuration. Event clients are re-connected on next use; the previous event
+// clients are closed once they are idle or, at the latest, after drainTimeout.
+func (cp *ChannelProvider) InvalidateChannels(drainTimeout time.Duration, channelIDs ...string) {
+	if len(channelIDs) == 0 {
+		return
+	}
+	cp.invalidate(drainTimeout, channelIDs...)
+}
+
+// InvalidateAll refreshes the services of all channels for all contexts. (See InvalidateChannels.)
+func (cp *ChannelProvider) InvalidateAll(drainTimeout time.Duration) {
+	cp.invalidate(drainTimeout)
+}
+
+func (cp *ChannelProvider) invalidate(drainTimeout time.Duration, channelIDs ...string) {
+	cp.ctxtCaches.Range(func(key string, value interface{}) bool {
+		value.(*contextCache).invalidate(channelIDs...)
+		return true
+	})
+	cp.tracker.resetEventClients(drainTimeout, channelIDs...)
+}
+
 // ChannelService creates a ChannelService for an identity
 func (cp *ChannelProvider) ChannelService(ctx fab.ClientContext, channelID string) (fab.ChannelService, error) {
 	key, err := newCtxtCacheKey(ctx)
@@ -22,6 +22,8 @@ import (
 
 type cache interface {
 	Get(lazycache.Key, ...interface{}) (interface{}, error)
+	Delete(lazycache.Key)
+	DeleteAll()
 	Close()
 }
 
@@ -32,19 +34,21 @@ type contextCache struct {
 	selectionServiceCache cache
 	chCfgCache            cache
 	membershipCache       cache
+	tracker               *serviceTracker
 }
 
 var cfgCacheProvider = func(opts ...options.Opt) cache {
 	return chconfig.NewRefCache(opts...)
 }
 
-func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
+func newContextCache(ctx fab.ClientContext, tracker *serviceTracker, opts []options.Opt) *contextCache {
 	eventIdleTime := ctx.EndpointConfig().Timeout(fab.EventServiceIdle)
 	chConfigRefresh := ctx.EndpointConfig().Timeout(fab.ChannelConfigRefresh)
 	membershipRefresh := ctx.EndpointConfig().Timeout(fab.ChannelMembershipRefresh)
 
 	c := &contextCache{
-		ctx: ctx,
+		ctx:     ctx,
+		tracker: tracker,
 	}
 
 	c.chCfgCache = cfgCacheProvider(append(opts, chconfig.WithRefreshInterval(chConfigRefresh))...)
@@ -54,7 +58,12 @@ func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 		"Discovery_Service_Cache",
 		func(key lazycache.Key) (interface{}, error) {
 			ck := key.(*cacheKey)
-			return c.createDiscoveryService(ck.channelConfig, opts...)
+			discoveryService, err := c.createDiscoveryService(ck.channelConfig, opts...)
+			if err != nil {
+				return nil, err
+			}
+			c.tracker.addDiscoveryService(ck.channelConfig.ID(), discoveryService)
+			return discoveryService, nil
 		},
 	)
 
@@ -70,12 +79,14 @@ func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 		"Event_Service_Cache",
 		func(key lazycache.Key) (interface{}, error) {
 			ck := key.(*eventCacheKey)
-			return NewEventClientRef(
+			ref := NewEventClientRef(
 				eventIdleTime,
 				func() (fab.EventClient, error) {
 					return c.createEventClient(ck.channelConfig, ck.opts...)
 				},
-			), nil
+			)
+			c.tracker.addEventClient(ck.channelConfig.ID(), ref)
+			return ref, nil
 		},
 	)
 
@@ -99,6 +110,37 @@ func (c *contextCache) Close() {
 	c.discoveryServiceCache.Close()
 }
 
+// invalidate removes the selection service, discovery service, membership and channel
+// configuration of the given channels (or of all channels if no channel is specified) so that
+// they are re-created with the current configuration the next time they are accessed.
+// Event clients are not removed since they are reset (and drained) separately.
+func (c *contextCache) invalidate(channelIDs ...string) {
+	if len(channelIDs) == 0 {
+		logger.Debug("Invalidating services of all channels")
+		c.selectionServiceCache.DeleteAll()
+		c.discoveryServiceCache.DeleteAll()
+		c.membershipCache.DeleteAll()
+		c.chCfgCache.DeleteAll()
+		return
+	}
+
+	for _, channelID := range channelIDs {
+		logger.Debugf("Invalidating services of channel [%s]", channelID)
+
+		// The selection and discovery services are keyed by channel ID
+		c.selectionServiceCache.Delete(lazycache.NewStringKey(channelID))
+		c.discoveryServiceCache.Delete(lazycache.NewStringKey(channelID))
+
+		// The membership and channel config keys are derived from the channel ID only
+		if key, err := membership.NewCacheKey(membership.Context{}, nil, channelID); err == nil {
+			c.membershipCache.Delete(key)
+		}
+		if key, err := chconfig.NewCacheKey(c.ctx, nil, channelID); err == nil {
+			c.chCfgCache.Delete(key)
+		}
+	}
+}
+
 func (c *contextCache) createEventClient(chConfig fab.ChannelCfg, opts ...options.Opt) (fab.EventClient, error) {
 	discovery, err := c.GetDiscoveryService(chConfig.ID())
 	if err != nil {
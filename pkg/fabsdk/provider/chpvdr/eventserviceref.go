@@ -7,16 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
 )
 
 const (
-	defaultTimeout = 60 * time.Second
+	defaultTimeout    = 60 * time.Second
+	drainPollInterval = 250 * time.Millisecond
 )
 
 type eventClientProvider func() (fab.EventClient, error)
@@ -29,25 +32,25 @@ type eventClientProvider func() (fab.EventClient, error)
 type EventClientRef struct {
 	ref         *lazyref.Reference
 	provider    eventClientProvider
+	idleTimeout time.Duration
 	eventClient fab.EventClient
+	draining    map[fab.EventClient]struct{}
 	closed      int32
+	lock        sync.RWMutex
 }
 
 // NewEventClientRef returns a new EventClientRef
 func NewEventClientRef(idleTimeout time.Duration, evtClientProvider eventClientProvider) *EventClientRef {
-	clientRef := &EventClientRef{
-		provider: evtClientProvider,
-	}
-
 	if idleTimeout == 0 {
 		idleTimeout = defaultTimeout
 	}
 
-	clientRef.ref = lazyref.New(
-		clientRef.initializer(),
-		lazyref.WithFinalizer(clientRef.finalizer()),
-		lazyref.WithIdleExpiration(idleTimeout),
-	)
+	clientRef := &EventClientRef{
+		provider:    evtClientProvider,
+		idleTimeout: idleTimeout,
+		draining:    make(map[fab.EventClient]struct{}),
+	}
+	clientRef.ref = clientRef.newReference()
 
 	return clientRef
 }
@@ -60,7 +63,38 @@ func (ref *EventClientRef) Close() {
 	}
 
 	logger.Debug("Closing the event client")
-	ref.ref.Close()
+	ref.reference().Close()
+
+	for _, eventClient := range ref.drainingClients() {
+		logger.Debug("Forcing close of draining event client")
+		eventClient.Close()
+	}
+}
+
+// Reset replaces the event client so that a new event client is created (with the current configuration)
+// the next time the event client ref is accessed. The previous event client is closed as soon as all of its
+// registrations have been unregistered or, at the latest, once the given drain timeout has elapsed.
+func (ref *EventClientRef) Reset(drainTimeout time.Duration) {
+	if ref.Closed() {
+		return
+	}
+
+	ref.lock.Lock()
+	prevRef := ref.ref
+	prevClient := ref.eventClient
+	ref.ref = ref.newReference()
+	ref.eventClient = nil
+	if prevClient != nil {
+		ref.draining[prevClient] = struct{}{}
+	}
+	ref.lock.Unlock()
+
+	prevRef.Close()
+
+	if prevClient != nil {
+		logger.Debugf("Draining previous event client with timeout %s", drainTimeout)
+		go ref.drain(prevClient, drainTimeout)
+	}
 }
 
 // Closed returns true if the event client is closed
@@ -68,6 +102,23 @@ func (ref *EventClientRef) Closed() bool {
 	return atomic.LoadInt32(&ref.closed) == 1
 }
 
+// ConnectionState returns the connection state of the event client. False is returned if the
+// event client has not been created (event clients are created on demand and closed when idle).
+func (ref *EventClientRef) ConnectionState() (client.ConnectionState, bool) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
+	c, ok := ref.eventClient.(connectionStateProvider)
+	if !ok {
+		return client.Disconnected, false
+	}
+	return c.ConnectionState(), true
+}
+
+type connectionStateProvider interface {
+	ConnectionState() client.ConnectionState
+}
+
 // RegisterBlockEvent registers for block events.
 func (ref *EventClientRef) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
 	service, err := ref.get()
@@ -105,7 +156,12 @@ func (ref *EventClientRef) RegisterTxStatusEvent(txID string) (fab.Registration,
 }
 
 // Unregister removes the given registration and closes the event channel.
+// The registration may belong to an event client that is being drained after a reset.
 func (ref *EventClientRef) Unregister(reg fab.Registration) {
+	for _, eventClient := range ref.drainingClients() {
+		eventClient.Unregister(reg)
+	}
+
 	if service, err := ref.get(); err != nil {
 		logger.Warnf("Error unregistering event registration: %s", err)
 	} else {
@@ -118,7 +174,7 @@ func (ref *EventClientRef) get() (fab.EventService, error) {
 		return nil, errors.New("event client is closed")
 	}
 
-	service, err := ref.ref.Get()
+	service, err := ref.reference().Get()
 	if err != nil {
 		return nil, err
 	}
@@ -141,30 +197,114 @@ func (ref *EventClientRef) initializer() lazyref.Initializer {
 		if err := eventClient.Connect(); err != nil {
 			return nil, err
 		}
-		ref.eventClient = eventClient
+		ref.setEventClient(eventClient)
 		logger.Debug("...event client successfully connected.")
 		return eventClient, nil
 	}
 }
 
 func (ref *EventClientRef) finalizer() lazyref.Finalizer {
-	return func(interface{}) {
+	return func(value interface{}) {
 		logger.Debug("Finalizer called")
-		if ref.eventClient != nil {
-			if ref.Closed() {
-				logger.Debug("Forcing close the event client")
-				ref.eventClient.Close()
-			} else {
-				logger.Debug("Closing the event client if no outstanding connections...")
-
-				// Only close the client if there are not outstanding registrations
-				if ref.eventClient.CloseIfIdle() {
-					logger.Debug("... closed event client.")
-					ref.eventClient = nil
-				} else {
-					logger.Debug("... event client was not closed since there are outstanding registrations.")
-				}
-			}
+		eventClient, ok := value.(fab.EventClient)
+		if !ok || eventClient == nil {
+			return
+		}
+
+		if ref.Closed() {
+			logger.Debug("Forcing close the event client")
+			eventClient.Close()
+			return
+		}
+
+		if ref.isDraining(eventClient) {
+			logger.Debug("Event client is being drained and will be closed when idle")
+			return
 		}
+
+		logger.Debug("Closing the event client if no outstanding connections...")
+
+		// Only close the client if there are not outstanding registrations
+		if eventClient.CloseIfIdle() {
+			logger.Debug("... closed event client.")
+			ref.clearEventClient(eventClient)
+		} else {
+			logger.Debug("... event client was not closed since there are outstanding registrations.")
+		}
+	}
+}
+
+// drain closes the given event client once it has no outstanding registrations. The
+// event client is closed forcefully if it is still in use after the timeout.
+func (ref *EventClientRef) drain(eventClient fab.EventClient, timeout time.Duration) {
+	defer ref.removeDraining(eventClient)
+
+	deadline := time.Now().Add(timeout)
+	for !eventClient.CloseIfIdle() {
+		if ref.Closed() || !time.Now().Before(deadline) {
+			logger.Debug("Forcing close of draining event client")
+			eventClient.Close()
+			return
+		}
+		time.Sleep(drainPollInterval)
 	}
+	logger.Debug("Closed drained event client")
+}
+
+func (ref *EventClientRef) newReference() *lazyref.Reference {
+	return lazyref.New(
+		ref.initializer(),
+		lazyref.WithFinalizer(ref.finalizer()),
+		lazyref.WithIdleExpiration(ref.idleTimeout),
+	)
+}
+
+func (ref *EventClientRef) reference() *lazyref.Reference {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
+	return ref.ref
+}
+
+func (ref *EventClientRef) setEventClient(eventClient fab.EventClient) {
+	ref.lock.Lock()
+	defer ref.lock.Unlock()
+
+	ref.eventClient = eventClient
+}
+
+// clearEventClient clears the event client only if it's the current event client
+func (ref *EventClientRef) clearEventClient(eventClient fab.EventClient) {
+	ref.lock.Lock()
+	defer ref.lock.Unlock()
+
+	if ref.eventClient == eventClient {
+		ref.eventClient = nil
+	}
+}
+
+func (ref *EventClientRef) isDraining(eventClient fab.EventClient) bool {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
+	_, ok := ref.draining[eventClient]
+	return ok
+}
+
+func (ref *EventClientRef) drainingClients() []fab.EventClient {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
+	var clients []fab.EventClient
+	for eventClient := range ref.draining {
+		clients = append(clients, eventClient)
+	}
+	return clients
+}
+
+func (ref *EventClientRef) removeDraining(eventClient fab.EventClient) {
+	ref.lock.Lock()
+	defer ref.lock.Unlock()
+
+	delete(ref.draining, eventClient)
 }
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventClientRefReset(t *testing.T) {
+	var clients []*drainableEventClient
+	ref := NewEventClientRef(time.Minute, func() (fab.EventClient, error) {
+		c := &drainableEventClient{}
+		clients = append(clients, c)
+		return c, nil
+	})
+	defer ref.Close()
+
+	reg, _, err := ref.RegisterBlockEvent()
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+
+	ref.Reset(time.Minute)
+
+	_, _, err = ref.RegisterBlockEvent()
+	require.NoError(t, err)
+	require.Len(t, clients, 2, "a new event client should be created after a reset")
+	assert.False(t, clients[0].isClosed(), "previous event client should not be closed while it has registrations")
+
+	ref.Unregister(reg)
+	assert.True(t, waitFor(clients[0].isClosed), "previous event client should be closed once idle")
+	assert.False(t, clients[1].isClosed())
+	assert.True(t, waitFor(func() bool { return len(ref.drainingClients()) == 0 }))
+}
+
+func TestEventClientRefResetTimeout(t *testing.T) {
+	eventClient := &drainableEventClient{}
+	ref := NewEventClientRef(time.Minute, func() (fab.EventClient, error) {
+		return eventClient, nil
+	})
+	defer ref.Close()
+
+	_, _, err := ref.RegisterBlockEvent()
+	require.NoError(t, err)
+
+	ref.Reset(100 * time.Millisecond)
+	assert.True(t, waitFor(eventClient.isClosed), "event client should be closed after the drain timeout")
+}
+
+// waitFor returns true if the condition is satisfied within a couple of seconds
+func waitFor(condition func() bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+type drainableEventClient struct {
+	fab.EventClient
+	lock          sync.Mutex
+	registrations int
+	closed        bool
+}
+
+func (c *drainableEventClient) Connect() error {
+	return nil
+}
+
+func (c *drainableEventClient) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.registrations++
+	return c, make(chan *fab.BlockEvent), nil
+}
+
+func (c *drainableEventClient) Unregister(reg fab.Registration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if reg == c && c.registrations > 0 {
+		c.registrations--
+	}
+}
+
+func (c *drainableEventClient) CloseIfIdle() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.registrations > 0 {
+		return false
+	}
+	c.closed = true
+	return true
+}
+
+func (c *drainableEventClient) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closed = true
+}
+
+func (c *drainableEventClient) isClosed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.closed
+}
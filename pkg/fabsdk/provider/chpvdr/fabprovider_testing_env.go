@@ -58,6 +58,14 @@ func (m *chCfgCache) Get(k lazycache.Key, data ...interface{}) (interface{}, err
 	return cfg, nil
 }
 
+// Delete not implemented
+func (m *chCfgCache) Delete(k lazycache.Key) {
+}
+
+// DeleteAll not implemented
+func (m *chCfgCache) DeleteAll() {
+}
+
 // Close not implemented
 func (m *chCfgCache) Close() {
 }
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/pkg/errors"
+)
+
+// refreshReporter is implemented by discovery services that periodically refresh the peers of a channel
+type refreshReporter interface {
+	LastRefresh() (time.Time, error)
+	Closed() bool
+}
+
+// serviceTracker keeps track of the event clients and discovery services created by
+// the channel provider so that their health may be checked
+type serviceTracker struct {
+	lock              sync.Mutex
+	eventClients      map[*EventClientRef]string
+	discoveryServices map[refreshReporter]string
+}
+
+func newServiceTracker() *serviceTracker {
+	return &serviceTracker{
+		eventClients:      make(map[*EventClientRef]string),
+		discoveryServices: make(map[refreshReporter]string),
+	}
+}
+
+func (t *serviceTracker) addEventClient(channelID string, ref *EventClientRef) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.eventClients[ref] = channelID
+}
+
+func (t *serviceTracker) addDiscoveryService(channelID string, service fab.DiscoveryService) {
+	reporter, ok := service.(refreshReporter)
+	if !ok {
+		// Static discovery doesn't refresh
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.discoveryServices[reporter] = channelID
+}
+
+// resetEventClients resets the event clients of the given channels (or of all channels if
+// no channel is specified). Closed event clients are no longer tracked.
+func (t *serviceTracker) resetEventClients(drainTimeout time.Duration, channelIDs ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for ref, channelID := range t.eventClients {
+		if ref.Closed() {
+			delete(t.eventClients, ref)
+			continue
+		}
+
+		if len(channelIDs) == 0 || containsString(channelIDs, channelID) {
+			logger.Debugf("Resetting event client of channel [%s]", channelID)
+			ref.Reset(drainTimeout)
+		}
+	}
+}
+
+// checkEventClients returns an error if any of the event clients is not connected. Closed
+// event clients are no longer tracked.
+func (t *serviceTracker) checkEventClients() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var problems []string
+	for ref, channelID := range t.eventClients {
+		if ref.Closed() {
+			delete(t.eventClients, ref)
+			continue
+		}
+
+		state, ok := ref.ConnectionState()
+		if ok && state != client.Connected {
+			problems = append(problems, fmt.Sprintf("event client of channel [%s] is %s", channelID, state))
+		}
+	}
+
+	return toError(problems)
+}
+
+// checkDiscovery returns an error if the most recent refresh of any discovery service failed or if
+// the peers haven't been refreshed within maxAge. Closed discovery services are no longer tracked.
+func (t *serviceTracker) checkDiscovery(maxAge time.Duration) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var problems []string
+	for service, channelID := range t.discoveryServices {
+		if service.Closed() {
+			delete(t.discoveryServices, service)
+			continue
+		}
+
+		lastRefresh, err := service.LastRefresh()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("discovery of channel [%s] failed: %s", channelID, err))
+		} else if !lastRefresh.IsZero() && time.Since(lastRefresh) > maxAge {
+			problems = append(problems, fmt.Sprintf("peers of channel [%s] were last refreshed at %s", channelID, lastRefresh.Format(time.RFC3339)))
+		}
+	}
+
+	return toError(problems)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// CheckEventClients returns an error if the event client of any channel is not connected.
+// Event clients are created on demand and closed when idle, so channels without an event
+// client are not considered unhealthy.
+func (cp *ChannelProvider) CheckEventClients() error {
+	return cp.tracker.checkEventClients()
+}
+
+// CheckDiscovery returns an error if the most recent refresh of the peers of any channel (by the
+// dynamic discovery service) failed or if the peers haven't been refreshed within maxAge
+func (cp *ChannelProvider) CheckDiscovery(maxAge time.Duration) error {
+	return cp.tracker.checkDiscovery(maxAge)
+}
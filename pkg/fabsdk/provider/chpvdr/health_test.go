@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEventClients(t *testing.T) {
+	tracker := newServiceTracker()
+
+	ref := NewEventClientRef(time.Minute, func() (fab.EventClient, error) { return nil, errors.New("not used") })
+	tracker.addEventClient("orgchannel", ref)
+
+	assert.NoError(t, tracker.checkEventClients(), "event client that was not created should not be reported")
+
+	eventClient := &stateEventClient{state: client.Connected}
+	ref.setEventClient(eventClient)
+	assert.NoError(t, tracker.checkEventClients())
+
+	eventClient.state = client.Connecting
+	err := tracker.checkEventClients()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "orgchannel")
+
+	ref.Close()
+	assert.NoError(t, tracker.checkEventClients(), "closed event client should not be reported")
+	assert.Empty(t, tracker.eventClients)
+}
+
+func TestCheckDiscovery(t *testing.T) {
+	tracker := newServiceTracker()
+
+	service := &refreshingDiscoveryService{}
+	tracker.addDiscoveryService("orgchannel", service)
+
+	assert.NoError(t, tracker.checkDiscovery(time.Minute), "discovery service that has not refreshed should not be reported")
+
+	service.lastRefresh = time.Now()
+	assert.NoError(t, tracker.checkDiscovery(time.Minute))
+
+	service.lastRefresh = time.Now().Add(-2 * time.Minute)
+	assert.Error(t, tracker.checkDiscovery(time.Minute), "stale peers should be reported")
+
+	service.lastRefresh = time.Now()
+	service.err = errors.New("discovery failed")
+	assert.Error(t, tracker.checkDiscovery(time.Minute), "failed refresh should be reported")
+
+	service.closed = true
+	assert.NoError(t, tracker.checkDiscovery(time.Minute), "closed discovery service should not be reported")
+	assert.Empty(t, tracker.discoveryServices)
+}
+
+type stateEventClient struct {
+	fab.EventClient
+	state client.ConnectionState
+}
+
+func (c *stateEventClient) ConnectionState() client.ConnectionState {
+	return c.state
+}
+
+func (c *stateEventClient) Close() {
+}
+
+type refreshingDiscoveryService struct {
+	fab.DiscoveryService
+	lastRefresh time.Time
+	err         error
+	closed      bool
+}
+
+func (s *refreshingDiscoveryService) LastRefresh() (time.Time, error) {
+	return s.lastRefresh, s.err
+}
+
+func (s *refreshingDiscoveryService) Closed() bool {
+	return s.closed
+}
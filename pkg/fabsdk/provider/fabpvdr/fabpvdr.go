@@ -22,6 +22,7 @@ var logger = logging.NewLogger("fabsdk")
 type InfraProvider struct {
 	providerContext context.Providers
 	commManager     *comm.CachingConnector
+	breakers        *comm.Breakers
 }
 
 // New creates a InfraProvider enabling access to core Fabric objects and functionality.
@@ -37,9 +38,21 @@ func New(config fab.EndpointConfig) *InfraProvider {
 // Initialize sets the provider context
 func (f *InfraProvider) Initialize(providers context.Providers) error {
 	f.providerContext = providers
+	f.commManager.SetMetrics(providers.GetMetrics().Connections())
+	f.breakers.SetMetrics(providers.GetMetrics().Connections())
 	return nil
 }
 
+// SetCircuitBreakers enables the circuit breakers of the peers and orderers
+func (f *InfraProvider) SetCircuitBreakers(breakers *comm.Breakers) {
+	f.breakers = breakers
+}
+
+// CircuitBreakers returns the circuit breakers of the peers and orderers (or nil if circuit breakers are not enabled)
+func (f *InfraProvider) CircuitBreakers() *comm.Breakers {
+	return f.breakers
+}
+
 // Close frees resources and caches.
 func (f *InfraProvider) Close() {
 	logger.Debug("Closing comm manager...")
@@ -23,13 +23,13 @@ type MSPProvider struct {
 	identityManager map[string]msp.IdentityManager
 }
 
-// New creates a MSP context provider
-func New(endpointConfig fab.EndpointConfig, cryptoSuite core.CryptoSuite, userStore msp.UserStore) (*MSPProvider, error) {
+// New creates a MSP context provider. The options are passed to the identity manager of each organization.
+func New(endpointConfig fab.EndpointConfig, cryptoSuite core.CryptoSuite, userStore msp.UserStore, opts ...mspimpl.IdentityManagerOption) (*MSPProvider, error) {
 
 	identityManager := make(map[string]msp.IdentityManager)
 	netConfig := endpointConfig.NetworkConfig()
 	for orgName := range netConfig.Organizations {
-		mgr, err := mspimpl.NewIdentityManager(orgName, userStore, cryptoSuite, endpointConfig)
+		mgr, err := mspimpl.NewIdentityManager(orgName, userStore, cryptoSuite, endpointConfig, opts...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to initialize identity manager for organization: %s", orgName)
 		}
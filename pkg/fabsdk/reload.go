@@ -0,0 +1,375 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/health"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDrainTimeout = 30 * time.Second
+	defaultPollInterval = 10 * time.Second
+)
+
+// ConfigChangeEvent is emitted by a ConfigWatcher after an attempt to reload the configuration
+type ConfigChangeEvent struct {
+	// Changes contains the changes that were applied. Changes is nil if the reload failed.
+	Changes *fabImpl.EndpointConfigChanges
+	// Err is set if the new configuration could not be loaded or is invalid, in which
+	// case the previous configuration remains in effect
+	Err error
+}
+
+// ConfigChangeHandler is invoked by a ConfigWatcher with the result of each reload
+type ConfigChangeHandler func(event *ConfigChangeEvent)
+
+type reloadOptions struct {
+	drainTimeout time.Duration
+	pollInterval time.Duration
+	handler      ConfigChangeHandler
+}
+
+// ReloadOption configures the reloading of the SDK configuration
+type ReloadOption func(opts *reloadOptions)
+
+// WithDrainTimeout sets the maximum time that the event clients of the previous configuration
+// are kept open (while they still have registrations) after a reload
+func WithDrainTimeout(timeout time.Duration) ReloadOption {
+	return func(opts *reloadOptions) {
+		opts.drainTimeout = timeout
+	}
+}
+
+// WithPollInterval sets the interval at which a ConfigWatcher checks for configuration changes
+func WithPollInterval(interval time.Duration) ReloadOption {
+	return func(opts *reloadOptions) {
+		opts.pollInterval = interval
+	}
+}
+
+// WithChangeHandler sets the handler that is invoked by a ConfigWatcher after each reload
+func WithChangeHandler(handler ConfigChangeHandler) ReloadOption {
+	return func(opts *reloadOptions) {
+		opts.handler = handler
+	}
+}
+
+type channelInvalidator interface {
+	InvalidateChannels(drainTimeout time.Duration, channelIDs ...string)
+	InvalidateAll(drainTimeout time.Duration)
+}
+
+type localDiscoveryInvalidator interface {
+	Invalidate()
+}
+
+type connectionDrainer interface {
+	Drain(targets ...string)
+}
+
+// ReloadConfig loads the endpoint configuration from the given config provider and, if it is valid
+// and differs from the current configuration, replaces the current endpoint configuration. The
+// services of the affected channels are re-created with the new configuration and the connections
+// to changed peers and orderers are drained. If the new configuration can't be loaded or is invalid
+// then an error is returned and the current configuration remains in effect.
+//
+// Configuration that was passed to New using WithEndpointConfig takes precedence over the
+// reloaded configuration. The crypto suite, identity and metrics configurations are not reloaded.
+func (sdk *FabricSDK) ReloadConfig(configProvider core.ConfigProvider, opts ...ReloadOption) (*fabImpl.EndpointConfigChanges, error) {
+	options := newReloadOptions(opts)
+
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	if atomic.LoadInt32(&sdk.closed) == 1 {
+		return nil, errors.New("SDK is closed")
+	}
+
+	configBackend, err := configProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load config backend")
+	}
+
+	endpointConfig, err := sdk.loadEndpointConfig(configBackend...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load endpoint config")
+	}
+
+	if err := validateEndpointConfig(endpointConfig); err != nil {
+		return nil, errors.WithMessage(err, "invalid endpoint config")
+	}
+
+	changes := fabImpl.DiffEndpointConfig(sdk.endpointConfig.Current(), endpointConfig)
+	if changes.IsEmpty() {
+		logger.Debug("Endpoint config has not changed")
+		return changes, nil
+	}
+
+	previous := sdk.endpointConfig.Swap(endpointConfig)
+	sdk.opts.ConfigBackend = configBackend
+
+	sdk.applyChanges(changes, previous, endpointConfig, options.drainTimeout)
+
+	logger.Infof("Endpoint config reloaded - added peers: %v, removed peers: %v, updated peers: %v, added orderers: %v, removed orderers: %v, updated orderers: %v, affected channels: %v, all channels: %t",
+		changes.AddedPeers, changes.RemovedPeers, changes.UpdatedPeers,
+		changes.AddedOrderers, changes.RemovedOrderers, changes.UpdatedOrderers,
+		changes.AffectedChannels, changes.AllChannels)
+
+	return changes, nil
+}
+
+func (sdk *FabricSDK) applyChanges(changes *fabImpl.EndpointConfigChanges, previous, current fab.EndpointConfig, drainTimeout time.Duration) {
+	if invalidator, ok := sdk.provider.ChannelProvider().(channelInvalidator); ok {
+		if changes.AllChannels {
+			invalidator.InvalidateAll(drainTimeout)
+		} else if len(changes.AffectedChannels) > 0 {
+			invalidator.InvalidateChannels(drainTimeout, changes.AffectedChannels...)
+		}
+	}
+
+	peersChanged := len(changes.AddedPeers) > 0 || len(changes.RemovedPeers) > 0 || len(changes.UpdatedPeers) > 0
+	if changes.AllChannels || peersChanged {
+		if invalidator, ok := sdk.provider.LocalDiscoveryProvider().(localDiscoveryInvalidator); ok {
+			invalidator.Invalidate()
+		}
+	}
+
+	if drainer, ok := sdk.provider.InfraProvider().CommManager().(connectionDrainer); ok {
+		if changes.TLSClientCertsChanged || changes.TimeoutsChanged {
+			drainer.Drain()
+		} else if urls := changes.ChangedEndpoints(previous, current); len(urls) > 0 {
+			targets := make([]string, len(urls))
+			for i, url := range urls {
+				targets[i] = endpoint.ToAddress(url)
+			}
+			drainer.Drain(targets...)
+		}
+	}
+
+	sdk.updateEndpointHealth(changes, current)
+}
+
+// updateEndpointHealth re-registers the connectivity checks of the peers and orderers that changed
+func (sdk *FabricSDK) updateEndpointHealth(changes *fabImpl.EndpointConfigChanges, current fab.EndpointConfig) {
+	if sdk.health == nil {
+		return
+	}
+
+	var components []string
+	for _, name := range append(append(append([]string{}, changes.AddedPeers...), changes.RemovedPeers...), changes.UpdatedPeers...) {
+		components = append(components, peerComponentPrefix+name)
+	}
+	for _, name := range append(append(append([]string{}, changes.AddedOrderers...), changes.RemovedOrderers...), changes.UpdatedOrderers...) {
+		components = append(components, ordererComponentPrefix+name)
+	}
+
+	netConfig := current.NetworkConfig()
+	if netConfig == nil {
+		netConfig = &fab.NetworkConfig{}
+	}
+	checkers := sdk.endpointCheckers(netConfig)
+
+	for _, component := range components {
+		sdk.health.Deregister(component)
+		if checker, ok := checkers[component]; ok {
+			if err := sdk.health.Register(component, health.Readiness, checker); err != nil {
+				logger.Warnf("Failed to register health checker for [%s]: %s", component, err)
+			}
+		}
+	}
+}
+
+// validateEndpointConfig ensures that the endpoint config is usable before it replaces the current config
+func validateEndpointConfig(endpointConfig fab.EndpointConfig) error {
+	netConfig := endpointConfig.NetworkConfig()
+	if netConfig == nil {
+		return errors.New("network config is missing")
+	}
+
+	for name, peerCfg := range netConfig.Peers {
+		if peerCfg.URL == "" {
+			return errors.Errorf("URL of peer [%s] is missing", name)
+		}
+	}
+	for name, ordererCfg := range netConfig.Orderers {
+		if ordererCfg.URL == "" {
+			return errors.Errorf("URL of orderer [%s] is missing", name)
+		}
+	}
+
+	for channelID, chConfig := range netConfig.Channels {
+		for name := range chConfig.Peers {
+			if _, ok := endpointConfig.PeerConfig(name); !ok {
+				return errors.Errorf("peer [%s] of channel [%s] is not configured", name, channelID)
+			}
+		}
+		for _, name := range chConfig.Orderers {
+			if _, ok := endpointConfig.OrdererConfig(name); !ok {
+				return errors.Errorf("orderer [%s] of channel [%s] is not configured", name, channelID)
+			}
+		}
+	}
+
+	if _, err := endpointConfig.TLSCACertPool().Get(); err != nil {
+		return errors.WithMessage(err, "failed to load TLS CA certs")
+	}
+
+	return nil
+}
+
+// ConfigWatcher periodically checks the configuration for changes and reloads
+// the configuration of the SDK when a change is detected
+type ConfigWatcher struct {
+	sdk            *FabricSDK
+	configProvider core.ConfigProvider
+	modified       func() (func(), error)
+	opts           *reloadOptions
+	done           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+}
+
+// WatchConfig starts a watcher which loads the configuration from the given config provider at
+// each poll interval and reloads the SDK configuration if the endpoint configuration changed.
+func (sdk *FabricSDK) WatchConfig(configProvider core.ConfigProvider, opts ...ReloadOption) *ConfigWatcher {
+	return sdk.startWatcher(configProvider, nil, opts)
+}
+
+// WatchConfigFile starts a watcher which checks the given configuration file at each poll
+// interval and reloads the SDK configuration when the content of the file changes.
+func (sdk *FabricSDK) WatchConfigFile(path string, opts ...ReloadOption) *ConfigWatcher {
+	var lastHash []byte
+	if content, err := ioutil.ReadFile(path); err == nil {
+		lastHash = hashOf(content)
+	}
+
+	// the hash is only recorded once the content was reloaded successfully so that
+	// a failed reload is retried at the next poll interval
+	modified := func() (func(), error) {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read config file [%s]", path)
+		}
+		hash := hashOf(content)
+		if bytes.Equal(hash, lastHash) {
+			return nil, nil
+		}
+		return func() { lastHash = hash }, nil
+	}
+
+	return sdk.startWatcher(config.FromFile(path), modified, opts)
+}
+
+func (sdk *FabricSDK) startWatcher(configProvider core.ConfigProvider, modified func() (func(), error), opts []ReloadOption) *ConfigWatcher {
+	w := &ConfigWatcher{
+		sdk:            sdk,
+		configProvider: configProvider,
+		modified:       modified,
+		opts:           newReloadOptions(opts),
+		done:           make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// Stop stops the watcher
+func (w *ConfigWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *ConfigWatcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			logger.Debug("Config watcher stopped")
+			return
+		case <-ticker.C:
+			if atomic.LoadInt32(&w.sdk.closed) == 1 {
+				logger.Debug("SDK is closed - stopping config watcher")
+				return
+			}
+			w.check()
+		}
+	}
+}
+
+func (w *ConfigWatcher) check() {
+	var reloaded func()
+	if w.modified != nil {
+		var err error
+		reloaded, err = w.modified()
+		if err != nil {
+			logger.Warnf("Error checking config for changes: %s", err)
+			w.notify(&ConfigChangeEvent{Err: err})
+			return
+		}
+		if reloaded == nil {
+			return
+		}
+	}
+
+	changes, err := w.sdk.ReloadConfig(w.configProvider, WithDrainTimeout(w.opts.drainTimeout))
+	if err != nil {
+		logger.Warnf("Error reloading config: %s", err)
+		w.notify(&ConfigChangeEvent{Err: err})
+		return
+	}
+
+	if reloaded != nil {
+		reloaded()
+	}
+
+	if !changes.IsEmpty() {
+		w.notify(&ConfigChangeEvent{Changes: changes})
+	}
+}
+
+func (w *ConfigWatcher) notify(event *ConfigChangeEvent) {
+	if w.opts.handler != nil {
+		w.opts.handler(event)
+	}
+}
+
+func newReloadOptions(opts []ReloadOption) *reloadOptions {
+	options := &reloadOptions{
+		drainTimeout: defaultDrainTimeout,
+		pollInterval: defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func hashOf(content []byte) []byte {
+	hash := sha256.Sum256(content)
+	return hash[:]
+}
@@ -0,0 +1,167 @@
+// +build testing
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadTestConfig = `
+version: 1.0.0
+client:
+  organization: org1
+  credentialStore:
+    path: "/tmp/state-store"
+    cryptoStore:
+      path: /tmp/msp
+channels:
+  mychannel:
+    peers:
+      peer0.org1.example.com:
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: /tmp/msp
+    peers:
+      - peer0.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com:7050
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+`
+
+func reloadTestConfigProvider(replacements ...string) core.ConfigProvider {
+	raw := strings.NewReplacer(replacements...).Replace(reloadTestConfig)
+	return configImpl.FromRaw([]byte(raw), "yaml")
+}
+
+func TestReloadConfig(t *testing.T) {
+	sdk, err := New(reloadTestConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	endpointConfig := sdk.provider.EndpointConfig()
+
+	changes, err := sdk.ReloadConfig(reloadTestConfigProvider())
+	require.NoError(t, err)
+	assert.True(t, changes.IsEmpty())
+
+	changes, err = sdk.ReloadConfig(reloadTestConfigProvider("peer0.org1.example.com:7051", "peer0.org1.example.com:8051"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"peer0.org1.example.com"}, changes.UpdatedPeers)
+	assert.Equal(t, []string{"mychannel"}, changes.AffectedChannels)
+
+	peerConfig, ok := endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:8051", peerConfig.URL, "existing references to the endpoint config should see the new config")
+
+	_, err = sdk.ReloadConfig(reloadTestConfigProvider("      peer0.org1.example.com:\norganizations", "      peer2.org1.example.com:\norganizations"))
+	require.Error(t, err, "channel peer that isn't configured should be rejected")
+
+	peerConfig, ok = endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:8051", peerConfig.URL, "invalid config should not be applied")
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reloadtest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(reloadTestConfig), 0600))
+
+	sdk, err := New(configImpl.FromFile(path))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	events := make(chan *ConfigChangeEvent, 10)
+	watcher := sdk.WatchConfigFile(path,
+		WithPollInterval(10*time.Millisecond),
+		WithChangeHandler(func(event *ConfigChangeEvent) { events <- event }),
+	)
+	defer watcher.Stop()
+
+	newConfig := strings.Replace(reloadTestConfig, "orderer.example.com:7050", "orderer.example.com:8050", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(newConfig), 0600))
+
+	select {
+	case event := <-events:
+		require.NoError(t, event.Err)
+		assert.Equal(t, []string{"orderer.example.com"}, event.Changes.UpdatedOrderers)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config change event")
+	}
+
+	ordererConfig, ok := sdk.provider.EndpointConfig().OrdererConfig("orderer.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://orderer.example.com:8050", ordererConfig.URL)
+}
+
+func TestWatchConfigFileRetriesFailedReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reloadtest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(reloadTestConfig), 0600))
+
+	sdk, err := New(configImpl.FromFile(path))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	var failures int32
+	events := make(chan *ConfigChangeEvent, 10)
+	watcher := sdk.WatchConfigFile(path,
+		WithPollInterval(10*time.Millisecond),
+		WithChangeHandler(func(event *ConfigChangeEvent) {
+			if event.Err != nil {
+				atomic.AddInt32(&failures, 1)
+				return
+			}
+			events <- event
+		}),
+	)
+	defer watcher.Stop()
+
+	invalidConfig := strings.Replace(reloadTestConfig, "grpc://orderer.example.com:7050", "", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(invalidConfig), 0600))
+
+	// the unchanged invalid file is reloaded again at each poll interval
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&failures) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the failed reload to be retried")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	newConfig := strings.Replace(reloadTestConfig, "orderer.example.com:7050", "orderer.example.com:8050", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(newConfig), 0600))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, []string{"orderer.example.com"}, event.Changes.UpdatedOrderers)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config change event")
+	}
+}
@@ -8,6 +8,7 @@ package api
 
 import (
 	"errors"
+	"time"
 )
 
 var (
@@ -32,6 +33,8 @@ type CAClient interface {
 	AddAffiliation(request *AffiliationRequest) (*AffiliationResponse, error)
 	ModifyAffiliation(request *ModifyAffiliationRequest) (*AffiliationResponse, error)
 	RemoveAffiliation(request *AffiliationRequest) (*AffiliationResponse, error)
+	GetCertificates(request *GetCertificatesRequest) (*GetCertificatesResponse, error)
+	GenCRL(request *GenCRLRequest) (*GenCRLResponse, error)
 }
 
 // AttributeRequest is a request for an attribute.
@@ -79,6 +82,17 @@ type EnrollmentRequest struct {
 	// The type of the enrollment request: x509 or idemix
 	// The default is a request for an X509 enrollment certificate
 	Type string
+	// KeyRequest is the key pair to generate for the identity.
+	// The default is an ECDSA P-256 key pair.
+	KeyRequest *KeyRequest
+}
+
+// KeyRequest describes the key pair that's generated for an enrollment
+type KeyRequest struct {
+	// Algorithm of the key: "ecdsa" or "ed25519"
+	Algorithm string
+	// Size of the key in bits: 256 or 384 for ECDSA, ignored for Ed25519
+	Size int
 }
 
 // ReenrollmentRequest is a request to reenroll an identity.
@@ -95,6 +109,12 @@ type ReenrollmentRequest struct {
 	// AttrReqs are requests for attributes to add to the certificate.
 	// Each attribute is added only if the requestor owns the attribute.
 	AttrReqs []*AttributeRequest
+	// ReuseKey requests a certificate for the existing private key.
+	// The default is to generate a new key pair.
+	ReuseKey bool
+	// KeyRequest is the new key pair to generate (ignored if ReuseKey is set).
+	// The default is a key pair of the same type as the existing key.
+	KeyRequest *KeyRequest
 }
 
 // Attribute defines additional attributes that may be passed along during registration
@@ -137,6 +157,61 @@ type RevokedCert struct {
 	AKI string
 }
 
+// GetCertificatesRequest is a request to list the certificates issued by the CA.
+// All criteria are optional; certificates must match all criteria that are specified.
+type GetCertificatesRequest struct {
+	// ID returns the certificates of the given enrollment ID
+	ID string
+	// AKI returns the certificates with the given AKI (Authority Key Identifier)
+	AKI string
+	// Serial returns the certificate with the given serial number
+	Serial string
+	// RevokedStart and RevokedEnd return the certificates that were revoked within the time window
+	RevokedStart time.Time
+	RevokedEnd   time.Time
+	// ExpiredStart and ExpiredEnd return the certificates that expire within the time window
+	ExpiredStart time.Time
+	ExpiredEnd   time.Time
+	// NotRevoked excludes revoked certificates
+	NotRevoked bool
+	// NotExpired excludes expired certificates
+	NotExpired bool
+	// CAName is the name of the CA to connect to
+	CAName string
+	// Offset is the number of matching certificates to skip
+	Offset int
+	// Limit is the maximum number of certificates to return (zero means no limit)
+	Limit int
+}
+
+// GetCertificatesResponse contains the certificates that match a GetCertificatesRequest
+type GetCertificatesResponse struct {
+	// Certificates are the PEM-encoded certificates
+	Certificates [][]byte
+	// More is true if more certificates match the request than were returned (see Limit).
+	// The next page starts at Offset + len(Certificates).
+	More bool
+}
+
+// GenCRLRequest is a request to generate a CRL (certificate revocation list).
+// All time criteria are optional.
+type GenCRLRequest struct {
+	// CAName is the name of the CA to connect to
+	CAName string
+	// RevokedAfter and RevokedBefore include the certificates that were revoked within the time window
+	RevokedAfter  time.Time
+	RevokedBefore time.Time
+	// ExpireAfter and ExpireBefore include the certificates that expire within the time window
+	ExpireAfter  time.Time
+	ExpireBefore time.Time
+}
+
+// GenCRLResponse is the response to a GenCRLRequest
+type GenCRLResponse struct {
+	// CRL is the PEM-encoded certificate revocation list (CRL) that contains the requested revoked certificates
+	CRL []byte
+}
+
 // IdentityRequest represents the request to add/update identity to the fabric-ca-server
 type IdentityRequest struct {
 
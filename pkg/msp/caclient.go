@@ -9,11 +9,14 @@ package msp
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
@@ -30,6 +33,11 @@ type CAClientImpl struct {
 	userStore       msp.UserStore
 	adapter         *fabricCAAdapter
 	registrar       msp.EnrollCredentials
+	caID            string
+	metrics         *metrics.OperationMetrics
+	endpointConfig  fab.EndpointConfig
+	infraProvider   fab.InfraProvider
+	tlsStorePath    string
 }
 
 // CAClientOption describes a functional parameter for NewCAClient
@@ -81,7 +89,7 @@ func NewCAClient(orgName string, ctx contextApi.Client, opts ...CAClientOption)
 	if !ok {
 		return nil, errors.Errorf("error initializing CA [%s]", caID)
 	}
-	adapter, err := newFabricCAAdapter(caID, ctx.CryptoSuite(), ctx.IdentityConfig())
+	adapter, err := newFabricCAAdapter(caID, ctx.CryptoSuite(), ctx.IdentityConfig(), enrolledTLSClientCert(ctx.EndpointConfig()))
 	if err != nil {
 		return nil, errors.Wrapf(err, "error initializing CA [%s]", caID)
 	}
@@ -100,6 +108,11 @@ func NewCAClient(orgName string, ctx contextApi.Client, opts ...CAClientOption)
 		userStore:       ctx.UserStore(),
 		adapter:         adapter,
 		registrar:       caConfig.Registrar,
+		caID:            caID,
+		metrics:         ctx.GetMetrics().CA(),
+		endpointConfig:  ctx.EndpointConfig(),
+		infraProvider:   ctx.InfraProvider(),
+		tlsStorePath:    tlsCredentialStorePath(ctx.IdentityConfig()),
 	}
 	return mgr, nil
 }
@@ -123,8 +136,8 @@ func processCAClientOptions(opts ...CAClientOption) (*caClientOption, error) {
 //
 // enrollmentID The registered ID to use for enrollment
 // enrollmentSecret The secret associated with the enrollment ID
-func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
-
+func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) (err error) {
+	defer c.observe("Enroll", time.Now(), &err)
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -158,8 +171,8 @@ func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
 //
 //  Returns:
 //  Return identity info including secret
-func (c *CAClientImpl) CreateIdentity(request *api.IdentityRequest) (*api.IdentityResponse, error) {
-
+func (c *CAClientImpl) CreateIdentity(request *api.IdentityRequest) (resp *api.IdentityResponse, err error) {
+	defer c.observe("CreateIdentity", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -187,8 +200,8 @@ func (c *CAClientImpl) CreateIdentity(request *api.IdentityRequest) (*api.Identi
 //
 //  Returns:
 //  Return modified identity info
-func (c *CAClientImpl) ModifyIdentity(request *api.IdentityRequest) (*api.IdentityResponse, error) {
-
+func (c *CAClientImpl) ModifyIdentity(request *api.IdentityRequest) (resp *api.IdentityResponse, err error) {
+	defer c.observe("ModifyIdentity", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -216,8 +229,8 @@ func (c *CAClientImpl) ModifyIdentity(request *api.IdentityRequest) (*api.Identi
 //
 //  Returns:
 //  Return removed identity info
-func (c *CAClientImpl) RemoveIdentity(request *api.RemoveIdentityRequest) (*api.IdentityResponse, error) {
-
+func (c *CAClientImpl) RemoveIdentity(request *api.RemoveIdentityRequest) (resp *api.IdentityResponse, err error) {
+	defer c.observe("RemoveIdentity", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -246,8 +259,8 @@ func (c *CAClientImpl) RemoveIdentity(request *api.RemoveIdentityRequest) (*api.
 //
 //  Returns:
 //  Returns identity information
-func (c *CAClientImpl) GetIdentity(id, caname string) (*api.IdentityResponse, error) {
-
+func (c *CAClientImpl) GetIdentity(id, caname string) (resp *api.IdentityResponse, err error) {
+	defer c.observe("GetIdentity", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -269,8 +282,8 @@ func (c *CAClientImpl) GetIdentity(id, caname string) (*api.IdentityResponse, er
 //
 //  Returns:
 //  Response containing identities
-func (c *CAClientImpl) GetAllIdentities(caname string) ([]*api.IdentityResponse, error) {
-
+func (c *CAClientImpl) GetAllIdentities(caname string) (resp []*api.IdentityResponse, err error) {
+	defer c.observe("GetAllIdentities", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -284,8 +297,8 @@ func (c *CAClientImpl) GetAllIdentities(caname string) ([]*api.IdentityResponse,
 }
 
 // Reenroll an enrolled user in order to obtain a new signed X509 certificate
-func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) error {
-
+func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) (err error) {
+	defer c.observe("Reenroll", time.Now(), &err)
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -294,32 +307,41 @@ func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) error {
 		return errors.New("user name missing")
 	}
 
+	userData, err := c.reenroll(request)
+	if err != nil {
+		return err
+	}
+	err = c.userStore.Store(userData)
+	if err != nil {
+		return errors.Wrap(err, "reenroll failed")
+	}
+
+	return nil
+}
+
+// reenroll obtains a new enrollment certificate for the given user without storing it
+func (c *CAClientImpl) reenroll(request *api.ReenrollmentRequest) (*msp.UserData, error) {
 	user, err := c.identityManager.GetSigningIdentity(request.Name)
 	if err != nil {
-		return errors.Wrapf(err, "failed to retrieve user: %s", request.Name)
+		return nil, errors.Wrapf(err, "failed to retrieve user: %s", request.Name)
 	}
 
 	cert, err := c.adapter.Reenroll(user.PrivateKey(), user.EnrollmentCertificate(), request)
 	if err != nil {
-		return errors.Wrap(err, "reenroll failed")
+		return nil, errors.Wrap(err, "reenroll failed")
 	}
-	userData := &msp.UserData{
+	return &msp.UserData{
 		MSPID:                 c.orgMSPID,
 		ID:                    user.Identifier().ID,
 		EnrollmentCertificate: cert,
-	}
-	err = c.userStore.Store(userData)
-	if err != nil {
-		return errors.Wrap(err, "reenroll failed")
-	}
-
-	return nil
+	}, nil
 }
 
 // Register a User with the Fabric CA
 // request: Registration Request
 // Returns Enrolment Secret
-func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error) {
+func (c *CAClientImpl) Register(request *api.RegistrationRequest) (secret string, err error) {
+	defer c.observe("Register", time.Now(), &err)
 	if c.adapter == nil {
 		return "", fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -339,7 +361,7 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 		return "", err
 	}
 
-	secret, err := c.adapter.Register(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	secret, err = c.adapter.Register(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to register user")
 	}
@@ -350,7 +372,8 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 // Revoke a User with the Fabric CA
 // registrar: The User that is initiating the revocation
 // request: Revocation Request
-func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationResponse, error) {
+func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (resp *api.RevocationResponse, err error) {
+	defer c.observe("Revoke", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -367,7 +390,7 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 		return nil, err
 	}
 
-	resp, err := c.adapter.Revoke(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	resp, err = c.adapter.Revoke(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to revoke")
 	}
@@ -375,7 +398,8 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 }
 
 // GetCAInfo returns generic CA information
-func (c *CAClientImpl) GetCAInfo() (*api.GetCAInfoResponse, error) {
+func (c *CAClientImpl) GetCAInfo() (resp *api.GetCAInfoResponse, err error) {
+	defer c.observe("GetCAInfo", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -384,7 +408,8 @@ func (c *CAClientImpl) GetCAInfo() (*api.GetCAInfoResponse, error) {
 }
 
 // GetAffiliation returns information about the requested affiliation
-func (c *CAClientImpl) GetAffiliation(affiliation, caname string) (*api.AffiliationResponse, error) {
+func (c *CAClientImpl) GetAffiliation(affiliation, caname string) (resp *api.AffiliationResponse, err error) {
+	defer c.observe("GetAffiliation", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -403,7 +428,8 @@ func (c *CAClientImpl) GetAffiliation(affiliation, caname string) (*api.Affiliat
 }
 
 // GetAllAffiliations returns all affiliations that the caller is authorized to see
-func (c *CAClientImpl) GetAllAffiliations(caname string) (*api.AffiliationResponse, error) {
+func (c *CAClientImpl) GetAllAffiliations(caname string) (resp *api.AffiliationResponse, err error) {
+	defer c.observe("GetAllAffiliations", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization %s", c.orgName)
 	}
@@ -416,8 +442,50 @@ func (c *CAClientImpl) GetAllAffiliations(caname string) (*api.AffiliationRespon
 	return c.adapter.GetAllAffiliations(registrar.PrivateKey(), registrar.EnrollmentCertificate(), caname)
 }
 
+// GetCertificates returns the certificates issued by the CA that match the request
+func (c *CAClientImpl) GetCertificates(request *api.GetCertificatesRequest) (resp *api.GetCertificatesResponse, err error) {
+	defer c.observe("GetCertificates", time.Now(), &err)
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+
+	if request == nil {
+		return nil, errors.New("must provide certificates request")
+	}
+	if request.Offset < 0 || request.Limit < 0 {
+		return nil, errors.New("offset and limit may not be negative")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.adapter.GetCertificates(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+}
+
+// GenCRL generates a CRL that contains the revoked certificates that match the request
+func (c *CAClientImpl) GenCRL(request *api.GenCRLRequest) (resp *api.GenCRLResponse, err error) {
+	defer c.observe("GenCRL", time.Now(), &err)
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+
+	if request == nil {
+		return nil, errors.New("must provide CRL request")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.adapter.GenCRL(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+}
+
 // AddAffiliation adds a new affiliation to the server
-func (c *CAClientImpl) AddAffiliation(request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+func (c *CAClientImpl) AddAffiliation(request *api.AffiliationRequest) (resp *api.AffiliationResponse, err error) {
+	defer c.observe("AddAffiliation", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -440,7 +508,8 @@ func (c *CAClientImpl) AddAffiliation(request *api.AffiliationRequest) (*api.Aff
 }
 
 // ModifyAffiliation renames an existing affiliation on the server
-func (c *CAClientImpl) ModifyAffiliation(request *api.ModifyAffiliationRequest) (*api.AffiliationResponse, error) {
+func (c *CAClientImpl) ModifyAffiliation(request *api.ModifyAffiliationRequest) (resp *api.AffiliationResponse, err error) {
+	defer c.observe("ModifyAffiliation", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -463,7 +532,8 @@ func (c *CAClientImpl) ModifyAffiliation(request *api.ModifyAffiliationRequest)
 }
 
 // RemoveAffiliation removes an existing affiliation from the server
-func (c *CAClientImpl) RemoveAffiliation(request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+func (c *CAClientImpl) RemoveAffiliation(request *api.AffiliationRequest) (resp *api.AffiliationResponse, err error) {
+	defer c.observe("RemoveAffiliation", time.Now(), &err)
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -512,3 +582,9 @@ func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.S
 	}
 	return registrar, nil
 }
+
+// observe records the metrics of a CA operation. It is deferred by the operation
+// with a pointer to its error result.
+func (c *CAClientImpl) observe(operation string, startTime time.Time, err *error) {
+	c.metrics.Observe(c.caID, operation, time.Since(startTime).Seconds(), *err)
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -23,6 +24,7 @@ import (
 	bccspwrapper "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 )
@@ -48,6 +50,18 @@ func TestEnrollAndReenroll(t *testing.T) {
 		t.Fatal("Enroll didn't return error")
 	}
 
+	// Unsupported key algorithm
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: "enrolledUsername", Secret: "user1", KeyRequest: &api.KeyRequest{Algorithm: "dsa"}})
+	if err == nil || !strings.Contains(err.Error(), "Invalid algorithm: dsa") {
+		t.Fatalf("Expected enrollment with unsupported key algorithm to fail. Got: %v", err)
+	}
+
+	// Enrollment with an Ed25519 key
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: createRandomName(), Secret: "enrollmentSecret", KeyRequest: &api.KeyRequest{Algorithm: "ed25519"}})
+	if err != nil {
+		t.Fatalf("Enroll with Ed25519 key return error %s", err)
+	}
+
 	// Successful enrollment
 	enrollUsername := createRandomName()
 	_, err = f.userStore.Load(msp.IdentityIdentifier{MSPID: orgMSPID, ID: enrollUsername})
@@ -126,6 +140,7 @@ func TestWrongURL(t *testing.T) {
 	mockContext.EXPECT().CryptoSuite().Return(f.cryptoSuite).AnyTimes()
 	mockContext.EXPECT().UserStore().Return(f.userStore).AnyTimes()
 	mockContext.EXPECT().IdentityManager("Org1").Return(iManager, true).AnyTimes()
+	mockContext.EXPECT().GetMetrics().Return(&metrics.ClientMetrics{}).AnyTimes()
 
 	//f.caClient, err = NewCAClient(org1, f.identityManager, f.userStore, f.cryptoSuite, wrongURLConfigConfig)
 	f.caClient, err = NewCAClient(org1, mockContext)
@@ -346,6 +361,98 @@ func TestGetAllIdentities(t *testing.T) {
 
 }
 
+// TestGetCertificates tests listing the certificates issued by the CA
+func TestGetCertificates(t *testing.T) {
+
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	_, err := f.caClient.GetCertificates(nil)
+	if err == nil {
+		t.Fatal("Expected error with nil request")
+	}
+
+	_, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{Limit: -1})
+	if err == nil {
+		t.Fatal("Expected error with negative limit")
+	}
+
+	resp, err := f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: "123", NotExpired: true, ExpiredEnd: time.Now().Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("get certificates return error %s", err)
+	}
+	if len(resp.Certificates) != 3 || resp.More {
+		t.Fatalf("expecting %d certificates, got %d (more: %t)", 3, len(resp.Certificates), resp.More)
+	}
+
+	resp, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("get certificates return error %s", err)
+	}
+	if len(resp.Certificates) != 1 || !resp.More {
+		t.Fatalf("expecting %d certificate with more, got %d (more: %t)", 1, len(resp.Certificates), resp.More)
+	}
+}
+
+// TestGenCRL tests generating a CRL
+func TestGenCRL(t *testing.T) {
+
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	_, err := f.caClient.GenCRL(nil)
+	if err == nil {
+		t.Fatal("Expected error with nil request")
+	}
+
+	resp, err := f.caClient.GenCRL(&api.GenCRLRequest{RevokedAfter: time.Now().Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("gencrl return error %s", err)
+	}
+	if string(resp.CRL) != "MockCRL" {
+		t.Fatalf("unexpected CRL: %s", resp.CRL)
+	}
+}
+
+// TestCertificatePage tests paging of the certificates that are streamed by the CA
+func TestCertificatePage(t *testing.T) {
+	page := newCertificatePage(2, 2)
+	var err error
+	for _, cert := range []string{"c1", "c2", "c3", "c4", "c5"} {
+		if err = page.add([]byte(cert)); err != nil {
+			break
+		}
+	}
+	if err != errCertificatePageFull {
+		t.Fatalf("Expected page to be full. Got: %v", err)
+	}
+	if len(page.response.Certificates) != 2 || string(page.response.Certificates[0]) != "c3" || string(page.response.Certificates[1]) != "c4" {
+		t.Fatalf("unexpected certificates in page: %s", page.response.Certificates)
+	}
+	if !page.response.More {
+		t.Fatal("Expected more certificates")
+	}
+
+	page = newCertificatePage(0, 0)
+	for _, cert := range []string{"c1", "c2"} {
+		if err := page.add([]byte(cert)); err != nil {
+			t.Fatalf("add returned error %s", err)
+		}
+	}
+	if len(page.response.Certificates) != 2 || page.response.More {
+		t.Fatalf("expecting %d certificates, got %d (more: %t)", 2, len(page.response.Certificates), page.response.More)
+	}
+
+	if formatTime(time.Time{}) != "" {
+		t.Fatal("Expected empty string for zero time")
+	}
+	if s := formatTime(time.Date(2019, 8, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))); s != "2019-08-01T10:00:00Z" {
+		t.Fatalf("unexpected time format: %s", s)
+	}
+}
+
 // TestEmbeddedRegistar tests registration with embedded registrar identity
 func TestEmbeddedRegistar(t *testing.T) {
 
@@ -10,6 +10,8 @@ import (
 	"github.com/pkg/errors"
 
 	"encoding/json"
+	"strings"
+	"time"
 
 	caapi "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
@@ -18,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 )
 
@@ -28,9 +31,9 @@ type fabricCAAdapter struct {
 	caClient    *calib.Client
 }
 
-func newFabricCAAdapter(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*fabricCAAdapter, error) {
+func newFabricCAAdapter(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig, tlsClientCert []byte) (*fabricCAAdapter, error) {
 
-	caClient, err := createFabricCAClient(caID, cryptoSuite, config)
+	caClient, err := createFabricCAClient(caID, cryptoSuite, config, tlsClientCert)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +68,9 @@ func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error)
 		}
 		careq.AttrReqs = attrs
 	}
+	if request.KeyRequest != nil {
+		careq.CSR = &caapi.CSRInfo{KeyRequest: newBasicKeyRequest(request.KeyRequest)}
+	}
 
 	caresp, err := c.caClient.Enroll(careq)
 	if err != nil {
@@ -90,6 +96,14 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.Reenr
 		}
 		careq.AttrReqs = attrs
 	}
+	if request.ReuseKey {
+		careq.CSR = &caapi.CSRInfo{KeyRequest: &caapi.BasicKeyRequest{ReuseKey: true}}
+	} else if request.KeyRequest != nil {
+		careq.CSR = &caapi.CSRInfo{KeyRequest: newBasicKeyRequest(request.KeyRequest)}
+	} else if kr := keyRequestFromKey(key); kr != nil {
+		// Keep the key type of the identity
+		careq.CSR = &caapi.CSRInfo{KeyRequest: newBasicKeyRequest(kr)}
+	}
 
 	caidentity, err := c.newIdentity(key, cert)
 	if err != nil {
@@ -104,6 +118,23 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.Reenr
 	return caresp.Identity.GetECert().Cert(), nil
 }
 
+func newBasicKeyRequest(kr *api.KeyRequest) *caapi.BasicKeyRequest {
+	return &caapi.BasicKeyRequest{Algo: strings.ToLower(kr.Algorithm), Size: kr.Size}
+}
+
+// keyRequestFromKey returns the request for a key of the same type as the given key,
+// or nil if the CA client's default key type may be used
+func keyRequestFromKey(key core.Key) *api.KeyRequest {
+	switch cryptosuite.KeyAlgorithm(key) {
+	case cryptosuite.KeyAlgorithmECDSAP384:
+		return &api.KeyRequest{Algorithm: "ecdsa", Size: 384}
+	case cryptosuite.KeyAlgorithmEd25519:
+		return &api.KeyRequest{Algorithm: "ed25519"}
+	default:
+		return nil
+	}
+}
+
 // Register handles user registration
 // key: registrar private key
 // cert: registrar enrollment certificate
@@ -503,6 +534,111 @@ func (c *fabricCAAdapter) RemoveAffiliation(key core.Key, cert []byte, request *
 	return resp, err
 }
 
+// errCertificatePageFull stops streaming certificates from the CA once the requested page is complete
+var errCertificatePageFull = errors.New("certificate page is full")
+
+// certificatePEM is a certificate that's streamed by the CA
+type certificatePEM struct {
+	PEM string
+}
+
+// GetCertificates returns the certificates that match the request
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetCertificates(key core.Key, cert []byte, request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	logger.Debugf("Retrieving certificates: %+v", request)
+
+	registrar, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	req := &caapi.GetCertificatesRequest{
+		ID:         request.ID,
+		AKI:        request.AKI,
+		Serial:     request.Serial,
+		Revoked:    caapi.TimeRange{StartTime: formatTime(request.RevokedStart), EndTime: formatTime(request.RevokedEnd)},
+		Expired:    caapi.TimeRange{StartTime: formatTime(request.ExpiredStart), EndTime: formatTime(request.ExpiredEnd)},
+		NotRevoked: request.NotRevoked,
+		NotExpired: request.NotExpired,
+		CAName:     request.CAName,
+	}
+
+	page := newCertificatePage(request.Offset, request.Limit)
+	err = registrar.GetCertificates(req, func(decoder *json.Decoder) error {
+		var cert certificatePEM
+		if err := decoder.Decode(&cert); err != nil {
+			return err
+		}
+		return page.add([]byte(cert.PEM))
+	})
+	if err != nil && err != errCertificatePageFull {
+		return nil, errors.Wrap(err, "failed to get certificates")
+	}
+
+	return page.response, nil
+}
+
+// GenCRL generates a CRL
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GenCRL(key core.Key, cert []byte, request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	logger.Debugf("Generating CRL: %+v", request)
+
+	registrar, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GenCRL(&caapi.GenCRLRequest{
+		CAName:        request.CAName,
+		RevokedAfter:  request.RevokedAfter,
+		RevokedBefore: request.RevokedBefore,
+		ExpireAfter:   request.ExpireAfter,
+		ExpireBefore:  request.ExpireBefore,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CRL")
+	}
+
+	return &api.GenCRLResponse{CRL: resp.CRL}, nil
+}
+
+// certificatePage collects the certificates of the requested page
+type certificatePage struct {
+	offset   int
+	limit    int
+	skipped  int
+	response *api.GetCertificatesResponse
+}
+
+func newCertificatePage(offset, limit int) *certificatePage {
+	return &certificatePage{offset: offset, limit: limit, response: &api.GetCertificatesResponse{}}
+}
+
+// add adds the certificate to the page (if it isn't before the offset) and returns
+// errCertificatePageFull once a certificate beyond the end of the page is encountered
+func (p *certificatePage) add(cert []byte) error {
+	if p.skipped < p.offset {
+		p.skipped++
+		return nil
+	}
+	if p.limit > 0 && len(p.response.Certificates) == p.limit {
+		p.response.More = true
+		return errCertificatePageFull
+	}
+	p.response.Certificates = append(p.response.Certificates, cert)
+	return nil
+}
+
+// formatTime formats the time as expected by the CA (an empty string for the zero time)
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func fillAffiliationInfo(info *api.AffiliationInfo, name string, affiliations []caapi.AffiliationInfo, identities []caapi.IdentityInfo) error {
 	info.Name = name
 
@@ -577,7 +713,10 @@ func getIdentityResponses(ca string, responses []caapi.IdentityInfo) []*api.Iden
 	return ret
 }
 
-func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*calib.Client, error) {
+// createFabricCAClient creates a fabric-ca client for the given CA. If tlsClientCert is provided
+// then it's used as the client cert for mutual TLS (instead of the configured client cert and key)
+// and its private key is retrieved from the crypto suite.
+func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig, tlsClientCert []byte) (*calib.Client, error) {
 
 	// Create new Fabric-ca client without configs
 	c := &calib.Client{
@@ -612,6 +751,11 @@ func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.
 		return nil, errors.Errorf("CA '%s' has no corresponding client keys in the configs", caID)
 	}
 
+	if len(tlsClientCert) > 0 {
+		c.Config.TLS.Client.CertFile = tlsClientCert
+		c.Config.TLS.Client.KeyFile = nil
+	}
+
 	//TLS flag enabled/disabled
 	c.Config.TLS.Enabled = endpoint.IsTLSEnabled(conf.URL)
 	c.Config.MSPDir = config.CAKeyStorePath()
@@ -13,29 +13,101 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 	"github.com/pkg/errors"
 )
 
+// FileKeyStoreOption describes a functional parameter for NewFileKeyStore
+type FileKeyStoreOption func(*fileKeyStoreOptions)
+
+type fileKeyStoreOptions struct {
+	kekProvider sw.KEKProvider
+}
+
+// WithKeyStoreKEKProvider decrypts the keys of the keystore directories that are encrypted under the
+// key-encryption key of the given provider (see sw.NewEncryptedFileKeyStore). Keys that are stored
+// in plaintext are loaded as-is.
+func WithKeyStoreKEKProvider(provider sw.KEKProvider) FileKeyStoreOption {
+	return func(o *fileKeyStoreOptions) {
+		o.kekProvider = provider
+	}
+}
+
 // NewFileKeyStore ...
-func NewFileKeyStore(cryptoConfigMSPPath string) (core.KVStore, error) {
-	opts := &keyvaluestore.FileKeyValueStoreOptions{
-		Path: cryptoConfigMSPPath,
-		KeySerializer: func(key interface{}) (string, error) {
-			pkk, ok := key.(*msp.PrivKeyKey)
-			if !ok {
-				return "", errors.New("converting key to PrivKeyKey failed")
-			}
-			if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
-				return "", errors.New("invalid key")
-			}
-
-			// TODO: refactor to case insensitive or remove eventually.
-			r := strings.NewReplacer("{userName}", pkk.ID, "{username}", pkk.ID)
-			keyDir := filepath.Join(r.Replace(cryptoConfigMSPPath), "keystore")
-
-			return filepath.Join(keyDir, hex.EncodeToString(pkk.SKI)+"_sk"), nil
-		},
-	}
-	return keyvaluestore.New(opts)
+func NewFileKeyStore(cryptoConfigMSPPath string, opts ...FileKeyStoreOption) (core.KVStore, error) {
+	options := fileKeyStoreOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	keySerializer := func(key interface{}) (string, error) {
+		pkk, ok := key.(*msp.PrivKeyKey)
+		if !ok {
+			return "", errors.New("converting key to PrivKeyKey failed")
+		}
+		if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
+			return "", errors.New("invalid key")
+		}
+
+		// TODO: refactor to case insensitive or remove eventually.
+		r := strings.NewReplacer("{userName}", pkk.ID, "{username}", pkk.ID)
+		keyDir := filepath.Join(r.Replace(cryptoConfigMSPPath), "keystore")
+
+		return filepath.Join(keyDir, hex.EncodeToString(pkk.SKI)+"_sk"), nil
+	}
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{
+		Path:          cryptoConfigMSPPath,
+		KeySerializer: keySerializer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if options.kekProvider == nil {
+		return store, nil
+	}
+
+	files, err := sw.NewEncryptedKeyFiles(options.kekProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedFileKeyStore{FileKeyValueStore: store, keySerializer: keySerializer, files: files}, nil
+}
+
+// encryptedFileKeyStore decrypts the keys that are loaded from encrypted keystore directories
+type encryptedFileKeyStore struct {
+	*keyvaluestore.FileKeyValueStore
+	keySerializer keyvaluestore.KeySerializer
+	files         *sw.EncryptedKeyFiles
+}
+
+// Load returns the (decrypted) private key for the given key
+func (s *encryptedFileKeyStore) Load(key interface{}) (interface{}, error) {
+	value, err := s.FileKeyValueStore.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	path, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.files.Decrypt(path, value.([]byte))
+}
+
+// Store stores the private key for the given key. The key is encrypted if its keystore directory is encrypted.
+func (s *encryptedFileKeyStore) Store(key interface{}, value interface{}) error {
+	path, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	plaintext, ok := value.([]byte)
+	if !ok || plaintext == nil {
+		return errors.New("value is not a byte array")
+	}
+	raw, err := s.files.Encrypt(path, plaintext)
+	if err != nil {
+		return err
+	}
+	return s.FileKeyValueStore.Store(key, raw)
 }
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyStoreEncrypted(t *testing.T) {
+	path, err := ioutil.TempDir("", "filekeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	provider, err := sw.NewPassphraseKEKProvider([]byte("passphrase"))
+	require.NoError(t, err)
+
+	// Generate a key in the user's encrypted keystore directory
+	ks, err := sw.NewEncryptedFileKeyStore(filepath.Join(path, "user1", "keystore"), provider)
+	require.NoError(t, err)
+	suite, err := sw.GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+	key, err := suite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	pkk := &msp.PrivKeyKey{MSPID: "Org1MSP", ID: "user1", SKI: key.SKI()}
+	cryptoPath := filepath.Join(path, "{userName}")
+
+	store, err := NewFileKeyStore(cryptoPath)
+	require.NoError(t, err)
+	raw, err := store.Load(pkk)
+	require.NoError(t, err)
+	_, err = utils.PEMtoPrivateKey(raw.([]byte), nil)
+	assert.Error(t, err, "key should be encrypted on disk")
+
+	store, err = NewFileKeyStore(cryptoPath, WithKeyStoreKEKProvider(provider))
+	require.NoError(t, err)
+	plaintext, err := store.Load(pkk)
+	require.NoError(t, err)
+	_, err = utils.PEMtoPrivateKey(plaintext.([]byte), nil)
+	require.NoError(t, err, "loaded key should be decrypted")
+
+	// Stored keys are encrypted again
+	err = store.Store(pkk, plaintext)
+	require.NoError(t, err)
+	raw, err = ioutil.ReadFile(filepath.Join(path, "user1", "keystore", hex.EncodeToString(key.SKI())+"_sk"))
+	require.NoError(t, err)
+	assert.NotEqual(t, plaintext, raw, "stored key should be encrypted")
+	loaded, err := store.Load(pkk)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, loaded)
+
+	wrongProvider, err := sw.NewPassphraseKEKProvider([]byte("wrong"))
+	require.NoError(t, err)
+	store, err = NewFileKeyStore(cryptoPath, WithKeyStoreKEKProvider(wrongProvider))
+	require.NoError(t, err)
+	_, err = store.Load(pkk)
+	assert.Error(t, err, "loading with the wrong passphrase should fail")
+}
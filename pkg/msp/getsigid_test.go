@@ -253,6 +253,48 @@ func TestGetSigningIdentityFromMSPDir(t *testing.T) {
 	checkSigningIdentityFromMSPDir(mgr, t)
 }
 
+func TestGetSigningIdentityFromSQLKeyStore(t *testing.T) {
+
+	configPath := filepath.Join(getConfigPath(), configMSPOnly)
+	configBackend, err := config.FromFile(configPath)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpointConfig, err := fab.ConfigFromBackend(configBackend...)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read config: %s", err))
+	}
+
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+	keyStore, err := NewSQLKeyStore(&SQLStoreOptions{DB: db})
+	if err != nil {
+		t.Fatalf("Failed to create SQL key store: %s", err)
+	}
+
+	// The crypto suite doesn't hold the keys of the users
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	if err != nil {
+		t.Fatalf("Failed to create crypto suite: %s", err)
+	}
+
+	mgr, err := NewIdentityManager(orgName, nil, cryptoSuite, endpointConfig, WithPrivKeyStore(keyStore))
+	if err != nil {
+		t.Fatalf("Failed to setup credential manager: %s", err)
+	}
+
+	cryptoPath := endpointConfig.NetworkConfig().Organizations[strings.ToLower(orgName)].CryptoPath
+	for _, user := range []string{"Admin", "User1"} {
+		mspDir := filepath.Join(endpointConfig.CryptoConfigPath(), strings.Replace(cryptoPath, "{username}", user, -1))
+		if _, err := ImportKeyStoreDir(filepath.Join(mspDir, "keystore"), mgr.orgMSPID, user, keyStore); err != nil {
+			t.Fatalf("Failed to import keys of %s: %s", user, err)
+		}
+	}
+
+	checkSigningIdentityFromMSPDir(mgr, t)
+}
+
 func checkSigningIdentityFromMSPDir(mgr *IdentityManager, t *testing.T) {
 	_, err := mgr.GetSigningIdentity("")
 	if err == nil {
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/secret"
 	logApi "github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
@@ -47,6 +48,7 @@ func ConfigFromBackend(coreBackend ...core.ConfigBackend) (msp.IdentityConfig, e
 type IdentityConfig struct {
 	client              *msp.ClientConfig
 	caConfigs           map[string]*msp.CAConfig
+	caClientKeyRefs     map[string]string
 	backend             *lookup.ConfigLookup
 	caKeyStorePath      string
 	credentialStorePath string
@@ -118,10 +120,20 @@ func (c *IdentityConfig) Client() *msp.ClientConfig {
 	return c.client
 }
 
-// CAConfig returns the CA configuration.
+// CAConfig returns the CA configuration. Secret references in the registrar's enrollment secret
+// and the client key are resolved when the configuration is requested.
 func (c *IdentityConfig) CAConfig(caID string) (*msp.CAConfig, bool) {
 	cfg, ok := c.caConfigs[strings.ToLower(caID)]
-	return cfg, ok
+	if !ok {
+		return nil, false
+	}
+
+	cfg, err := c.resolveCASecrets(strings.ToLower(caID), cfg)
+	if err != nil {
+		logger.Errorf("Failed to resolve secrets of CA [%s]: %s", caID, err)
+		return nil, false
+	}
+	return cfg, true
 }
 
 //CAClientCert read configuration for the fabric CA client cert bytes for given org
@@ -136,7 +148,7 @@ func (c *IdentityConfig) CAClientCert(caID string) ([]byte, bool) {
 
 //CAClientKey read configuration for the fabric CA client key bytes for given org
 func (c *IdentityConfig) CAClientKey(caID string) ([]byte, bool) {
-	cfg, ok := c.caConfigs[strings.ToLower(caID)]
+	cfg, ok := c.CAConfig(caID)
 	if ok {
 		//for now, we're only loading the first Cert Authority by default.
 		return cfg.TLSCAClientKey, true
@@ -261,13 +273,15 @@ func (c *IdentityConfig) loadCATLSConfig(configEntity *identityConfigEntity) err
 		caConfig.TLSCACerts.Path = pathvar.Subst(caConfig.TLSCACerts.Path)
 		caConfig.TLSCACerts.Client.Key.Path = pathvar.Subst(caConfig.TLSCACerts.Client.Key.Path)
 		caConfig.TLSCACerts.Client.Cert.Path = pathvar.Subst(caConfig.TLSCACerts.Client.Cert.Path)
-		//pre load key and cert bytes
-		err := caConfig.TLSCACerts.Client.Key.LoadBytes()
-		if err != nil {
-			return errors.WithMessage(err, "failed to load ca key")
+		//pre load key and cert bytes (keys stored as secrets are resolved when requested)
+		if caKeyRef(caConfig) == "" {
+			err := caConfig.TLSCACerts.Client.Key.LoadBytes()
+			if err != nil {
+				return errors.WithMessage(err, "failed to load ca key")
+			}
 		}
 
-		err = caConfig.TLSCACerts.Client.Cert.LoadBytes()
+		err := caConfig.TLSCACerts.Client.Cert.LoadBytes()
 		if err != nil {
 			return errors.WithMessage(err, "failed to load ca cert")
 		}
@@ -280,6 +294,7 @@ func (c *IdentityConfig) loadCATLSConfig(configEntity *identityConfigEntity) err
 func (c *IdentityConfig) loadAllCAConfigs(configEntity *identityConfigEntity) error {
 
 	configs := make(map[string]*msp.CAConfig)
+	keyRefs := make(map[string]string)
 
 	for caID := range configEntity.CertificateAuthorities {
 
@@ -294,9 +309,13 @@ func (c *IdentityConfig) loadAllCAConfigs(configEntity *identityConfigEntity) er
 			return err
 		}
 		configs[strings.ToLower(caID)] = mspCAConfig
+		if ref := caKeyRef(*matchedCaConfig); ref != "" {
+			keyRefs[strings.ToLower(caID)] = ref
+		}
 	}
 
 	c.caConfigs = configs
+	c.caClientKeyRefs = keyRefs
 	return nil
 }
 
@@ -443,3 +462,40 @@ func (c *IdentityConfig) findMatchingCAConfig(configEntity *identityConfigEntity
 
 	return &caConfig, true
 }
+
+// resolveCASecrets returns a copy of the given CA config in which the secret references are resolved
+func (c *IdentityConfig) resolveCASecrets(caID string, cfg *msp.CAConfig) (*msp.CAConfig, error) {
+	keyRef, hasKeyRef := c.caClientKeyRefs[caID]
+	if !hasKeyRef && !secret.IsReference(cfg.Registrar.EnrollSecret) {
+		return cfg, nil
+	}
+
+	resolved := *cfg
+
+	enrollSecret, err := secret.ResolveString(cfg.Registrar.EnrollSecret)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to resolve registrar enrollment secret")
+	}
+	resolved.Registrar.EnrollSecret = enrollSecret
+
+	if hasKeyRef {
+		resolved.TLSCAClientKey, err = secret.Resolve(keyRef)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to resolve client key")
+		}
+	}
+
+	return &resolved, nil
+}
+
+// caKeyRef returns the secret reference of the CA's client key, if the key is stored as a secret
+func caKeyRef(caConfig CAConfig) string {
+	key := caConfig.TLSCACerts.Client.Key
+	if secret.IsReference(key.Pem) {
+		return key.Pem
+	}
+	if key.Pem == "" && secret.IsReference(key.Path) {
+		return key.Path
+	}
+	return ""
+}
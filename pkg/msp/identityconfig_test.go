@@ -415,6 +415,41 @@ func TestCACertAndKeys(t *testing.T) {
 
 }
 
+func TestCAConfigSecrets(t *testing.T) {
+	configPath := filepath.Join(getConfigPath(), configTestFile)
+	backend, err := config.FromFile(configPath)()
+	if err != nil {
+		t.Fatal("Failed to get config backend")
+	}
+
+	config, err := ConfigFromBackend(backend...)
+	if err != nil {
+		t.Fatal("Failed to get identity config")
+	}
+	identityConfig := config.(*IdentityConfig)
+
+	caID := strings.ToLower(org1CA)
+	identityConfig.caConfigs[caID].Registrar.EnrollSecret = "env://CA_TEST_ENROLL_SECRET"
+	identityConfig.caClientKeyRefs[caID] = "env://CA_TEST_CLIENT_KEY"
+
+	_, ok := identityConfig.CAConfig(org1CA)
+	assert.False(t, ok, "CAConfig should fail if the secret can't be resolved")
+
+	os.Setenv("CA_TEST_ENROLL_SECRET", "adminpw")
+	defer os.Unsetenv("CA_TEST_ENROLL_SECRET")
+	os.Setenv("CA_TEST_CLIENT_KEY", "key")
+	defer os.Unsetenv("CA_TEST_CLIENT_KEY")
+
+	caConfig, ok := identityConfig.CAConfig(org1CA)
+	assert.True(t, ok, "Get CA Config failed")
+	assert.Equal(t, "adminpw", caConfig.Registrar.EnrollSecret)
+	assert.Equal(t, "env://CA_TEST_ENROLL_SECRET", identityConfig.caConfigs[caID].Registrar.EnrollSecret, "loaded config should keep the reference")
+
+	key, ok := identityConfig.CAClientKey(org1CA)
+	assert.True(t, ok, "identityConfig.CAClientKey not supposed to return failure")
+	assert.Equal(t, "key", string(key))
+}
+
 func TestIdentityConfigWithMultipleBackends(t *testing.T) {
 
 	configPath := filepath.Join(getConfigPath(), configTestEntityMatchersFile)
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 )
 
 // IdentityManager implements fab/IdentityManager
@@ -29,8 +30,37 @@ type IdentityManager struct {
 	userStore       msp.UserStore
 }
 
+// IdentityManagerOption describes a functional parameter for NewIdentityManager
+type IdentityManagerOption func(*identityManagerOptions)
+
+type identityManagerOptions struct {
+	privKeyStore core.KVStore
+	kekProvider  sw.KEKProvider
+}
+
+// WithPrivKeyStore sets the store (keyed by *msp.PrivKeyKey) from which the private keys of the users
+// are loaded, such as a SQL key store (see NewSQLKeyStore). By default the keys are loaded from the
+// keystore directories of the organization's crypto path.
+func WithPrivKeyStore(store core.KVStore) IdentityManagerOption {
+	return func(o *identityManagerOptions) {
+		o.privKeyStore = store
+	}
+}
+
+// WithKEKProvider sets the provider of the key-encryption key of the keystore directories of the
+// organization's crypto path whose keys are encrypted (see NewFileKeyStore)
+func WithKEKProvider(provider sw.KEKProvider) IdentityManagerOption {
+	return func(o *identityManagerOptions) {
+		o.kekProvider = provider
+	}
+}
+
 // NewIdentityManager creates a new instance of IdentityManager
-func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig) (*IdentityManager, error) {
+func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig, opts ...IdentityManagerOption) (*IdentityManager, error) {
+	options := identityManagerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	netConfig := endpointConfig.NetworkConfig()
 	// viper keys are case insensitive
@@ -43,7 +73,7 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 		return nil, errors.New("Either a cryptopath or an embedded list of users is required")
 	}
 
-	var mspPrivKeyStore core.KVStore
+	mspPrivKeyStore := options.privKeyStore
 	var mspCertStore core.KVStore
 
 	orgCryptoPathTemplate := orgConfig.CryptoPath
@@ -52,9 +82,15 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 		if !filepath.IsAbs(orgCryptoPathTemplate) {
 			orgCryptoPathTemplate = filepath.Join(endpointConfig.CryptoConfigPath(), orgCryptoPathTemplate)
 		}
-		mspPrivKeyStore, err = NewFileKeyStore(orgCryptoPathTemplate)
-		if err != nil {
-			return nil, errors.Wrap(err, "creating a private key store failed")
+		if mspPrivKeyStore == nil {
+			var keyStoreOpts []FileKeyStoreOption
+			if options.kekProvider != nil {
+				keyStoreOpts = append(keyStoreOpts, WithKeyStoreKEKProvider(options.kekProvider))
+			}
+			mspPrivKeyStore, err = NewFileKeyStore(orgCryptoPathTemplate, keyStoreOpts...)
+			if err != nil {
+				return nil, errors.Wrap(err, "creating a private key store failed")
+			}
 		}
 		mspCertStore, err = NewFileCertStore(orgCryptoPathTemplate)
 		if err != nil {
@@ -0,0 +1,309 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRenewalThreshold     = 7 * 24 * time.Hour
+	defaultRenewalCheckInterval = time.Hour
+)
+
+// RenewalEvent describes the renewal (or failed renewal) of the enrollment certificate of a user
+type RenewalEvent struct {
+	// ID is the enrollment ID of the user
+	ID string
+	// Previous is the user data prior to renewal
+	Previous *msp.UserData
+	// Current is the renewed user data (nil if the renewal failed)
+	Current *msp.UserData
+	// Err is the reason the renewal failed (nil if the renewal succeeded)
+	Err error
+}
+
+// RenewalHandler is invoked after the enrollment certificate of a user is renewed or fails to be renewed
+type RenewalHandler func(event *RenewalEvent)
+
+// RenewalOption describes a functional parameter for NewRenewalManager
+type RenewalOption func(*renewalOptions)
+
+type renewalOptions struct {
+	threshold     time.Duration
+	checkInterval time.Duration
+	reuseKey      bool
+	handlers      []RenewalHandler
+}
+
+// WithRenewalThreshold sets how long before expiry an enrollment certificate is renewed (default 7 days)
+func WithRenewalThreshold(threshold time.Duration) RenewalOption {
+	return func(o *renewalOptions) {
+		o.threshold = threshold
+	}
+}
+
+// WithRenewalCheckInterval sets the interval at which the enrollment certificates are checked (default 1 hour)
+func WithRenewalCheckInterval(interval time.Duration) RenewalOption {
+	return func(o *renewalOptions) {
+		o.checkInterval = interval
+	}
+}
+
+// WithKeyRotation sets whether a new key pair is generated when a certificate is renewed (default true).
+// If false then the new certificate is issued for the existing private key.
+func WithKeyRotation(rotate bool) RenewalOption {
+	return func(o *renewalOptions) {
+		o.reuseKey = !rotate
+	}
+}
+
+// WithRenewalHandler registers a handler that's invoked after each renewal attempt
+func WithRenewalHandler(handler RenewalHandler) RenewalOption {
+	return func(o *renewalOptions) {
+		o.handlers = append(o.handlers, handler)
+	}
+}
+
+type reenrollFunc func(request *api.ReenrollmentRequest) (*msp.UserData, error)
+
+// RenewalManager renews the enrollment certificates of the watched users before they expire. A certificate
+// is re-enrolled with the CA once it expires within the renewal threshold and the user data in the user
+// store is replaced with the renewed certificate, so identities that are subsequently loaded from the
+// identity manager use the new certificate. Renewal handlers are notified of each renewal.
+type RenewalManager struct {
+	mspID     string
+	userStore msp.UserStore
+	reenroll  reenrollFunc
+	opts      renewalOptions
+
+	lock     sync.RWMutex
+	ids      map[string]struct{}
+	handlers []RenewalHandler
+	stop     chan struct{}
+	done     chan struct{}
+
+	renewLock sync.Mutex
+}
+
+// NewRenewalManager returns a renewal manager that re-enrolls users with the CA of the given CA client
+func NewRenewalManager(caClient *CAClientImpl, opts ...RenewalOption) (*RenewalManager, error) {
+	if caClient.adapter == nil {
+		return nil, errors.Errorf("no CAs configured for organization: %s", caClient.orgName)
+	}
+	if caClient.userStore == nil {
+		return nil, errors.New("user store is required for certificate renewal")
+	}
+	return newRenewalManager(caClient.orgMSPID, caClient.userStore, caClient.reenroll, opts...), nil
+}
+
+func newRenewalManager(mspID string, userStore msp.UserStore, reenroll reenrollFunc, opts ...RenewalOption) *RenewalManager {
+	options := renewalOptions{
+		threshold:     defaultRenewalThreshold,
+		checkInterval: defaultRenewalCheckInterval,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &RenewalManager{
+		mspID:     mspID,
+		userStore: userStore,
+		reenroll:  reenroll,
+		opts:      options,
+		ids:       make(map[string]struct{}),
+		handlers:  options.handlers,
+	}
+}
+
+// Watch adds the given enrolled users to the set of users whose certificates are renewed
+func (m *RenewalManager) Watch(ids ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, id := range ids {
+		m.ids[id] = struct{}{}
+	}
+}
+
+// Unwatch removes the given users from the set of users whose certificates are renewed
+func (m *RenewalManager) Unwatch(ids ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, id := range ids {
+		delete(m.ids, id)
+	}
+}
+
+// Watched returns the (sorted) IDs of the watched users
+func (m *RenewalManager) Watched() []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	ids := make([]string, 0, len(m.ids))
+	for id := range m.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// OnRenew registers a handler that's invoked after each renewal attempt
+func (m *RenewalManager) OnRenew(handler RenewalHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// Start checks the certificates of the watched users immediately and then periodically (at the
+// check interval) until Stop is called. Calling Start on a manager that was already started has no effect.
+func (m *RenewalManager) Start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.run(m.stop, m.done)
+}
+
+// Stop stops the periodic certificate checks and waits for a check that's in progress to complete
+func (m *RenewalManager) Stop() {
+	m.lock.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.lock.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (m *RenewalManager) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.opts.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(); err != nil {
+			logger.Warnf("Certificate renewal check failed: %s", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Check renews the certificates of the watched users that expire within the renewal threshold
+func (m *RenewalManager) Check() error {
+	var errs error
+	for _, id := range m.Watched() {
+		userData, err := m.load(id)
+		if err != nil {
+			errs = multi.Append(errs, err)
+			continue
+		}
+
+		notAfter, err := certificateExpiry(userData.EnrollmentCertificate)
+		if err != nil {
+			errs = multi.Append(errs, errors.WithMessagef(err, "invalid enrollment certificate for user [%s]", id))
+			continue
+		}
+
+		if remaining := time.Until(notAfter); remaining > m.opts.threshold {
+			logger.Debugf("Enrollment certificate of user [%s] expires in %s - not renewing", id, remaining)
+			continue
+		}
+
+		if err := m.renew(userData); err != nil {
+			errs = multi.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Renew renews the certificate of the given user, regardless of its expiry
+func (m *RenewalManager) Renew(id string) error {
+	userData, err := m.load(id)
+	if err != nil {
+		return err
+	}
+	return m.renew(userData)
+}
+
+func (m *RenewalManager) load(id string) (*msp.UserData, error) {
+	userData, err := m.userStore.Load(msp.IdentityIdentifier{MSPID: m.mspID, ID: id})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load user [%s]", id)
+	}
+	return userData, nil
+}
+
+func (m *RenewalManager) renew(previous *msp.UserData) error {
+	m.renewLock.Lock()
+	defer m.renewLock.Unlock()
+
+	logger.Infof("Renewing enrollment certificate of user [%s]", previous.ID)
+
+	current, err := m.reenroll(&api.ReenrollmentRequest{Name: previous.ID, ReuseKey: m.opts.reuseKey})
+	if err == nil {
+		err = m.userStore.Store(current)
+	}
+	if err != nil {
+		err = errors.WithMessagef(err, "failed to renew enrollment certificate of user [%s]", previous.ID)
+		m.notify(&RenewalEvent{ID: previous.ID, Previous: previous, Err: err})
+		return err
+	}
+
+	logger.Infof("Renewed enrollment certificate of user [%s]", previous.ID)
+	m.notify(&RenewalEvent{ID: previous.ID, Previous: previous, Current: current})
+	return nil
+}
+
+func (m *RenewalManager) notify(event *RenewalEvent) {
+	m.lock.RLock()
+	handlers := make([]RenewalHandler, len(m.handlers))
+	copy(handlers, m.handlers)
+	m.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// certificateExpiry returns the NotAfter time of the given PEM-encoded certificate
+func certificateExpiry(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("failed to decode PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse certificate")
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks/testcert"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const renewalTestMSPID = "Org1MSP"
+
+func TestRenewalCheck(t *testing.T) {
+	userStore := NewMemoryUserStore()
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "expiring", MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, time.Hour)}))
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "valid", MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, 30*24*time.Hour)}))
+
+	renewedCert := newTestCert(t, 90*24*time.Hour)
+	var requests []*api.ReenrollmentRequest
+	reenroll := func(request *api.ReenrollmentRequest) (*msp.UserData, error) {
+		requests = append(requests, request)
+		return &msp.UserData{ID: request.Name, MSPID: renewalTestMSPID, EnrollmentCertificate: renewedCert}, nil
+	}
+
+	var events []*RenewalEvent
+	mgr := newRenewalManager(renewalTestMSPID, userStore, reenroll,
+		WithRenewalThreshold(24*time.Hour),
+		WithRenewalHandler(func(event *RenewalEvent) { events = append(events, event) }),
+	)
+	mgr.Watch("expiring", "valid")
+	assert.Equal(t, []string{"expiring", "valid"}, mgr.Watched())
+
+	require.NoError(t, mgr.Check())
+
+	require.Len(t, requests, 1, "only the expiring certificate should be renewed")
+	assert.Equal(t, "expiring", requests[0].Name)
+	assert.False(t, requests[0].ReuseKey, "a new key should be generated by default")
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "expiring", events[0].ID)
+	assert.NoError(t, events[0].Err)
+	assert.Equal(t, renewedCert, events[0].Current.EnrollmentCertificate)
+	assert.NotEqual(t, renewedCert, events[0].Previous.EnrollmentCertificate)
+
+	userData, err := userStore.Load(msp.IdentityIdentifier{ID: "expiring", MSPID: renewalTestMSPID})
+	require.NoError(t, err)
+	assert.Equal(t, renewedCert, userData.EnrollmentCertificate, "user store should contain the renewed certificate")
+
+	require.NoError(t, mgr.Check())
+	assert.Len(t, requests, 1, "renewed certificate should not be renewed again")
+
+	mgr.Unwatch("expiring")
+	assert.Equal(t, []string{"valid"}, mgr.Watched())
+}
+
+func TestRenewalWithoutKeyRotation(t *testing.T) {
+	userStore := NewMemoryUserStore()
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user1", MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, 30*24*time.Hour)}))
+
+	var request *api.ReenrollmentRequest
+	mgr := newRenewalManager(renewalTestMSPID, userStore, func(r *api.ReenrollmentRequest) (*msp.UserData, error) {
+		request = r
+		return &msp.UserData{ID: r.Name, MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, 90*24*time.Hour)}, nil
+	}, WithKeyRotation(false))
+
+	require.NoError(t, mgr.Renew("user1"))
+	require.NotNil(t, request)
+	assert.True(t, request.ReuseKey)
+
+	assert.Error(t, mgr.Renew("unknown"))
+}
+
+func TestRenewalFailure(t *testing.T) {
+	cert := newTestCert(t, time.Hour)
+	userStore := NewMemoryUserStore()
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user1", MSPID: renewalTestMSPID, EnrollmentCertificate: cert}))
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "invalid", MSPID: renewalTestMSPID, EnrollmentCertificate: []byte("invalid")}))
+
+	mgr := newRenewalManager(renewalTestMSPID, userStore, func(r *api.ReenrollmentRequest) (*msp.UserData, error) {
+		return nil, errors.New("CA unavailable")
+	})
+
+	var events []*RenewalEvent
+	mgr.OnRenew(func(event *RenewalEvent) { events = append(events, event) })
+	mgr.Watch("user1", "invalid", "unknown")
+
+	err := mgr.Check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CA unavailable")
+	assert.Contains(t, err.Error(), "invalid enrollment certificate for user [invalid]")
+	assert.Contains(t, err.Error(), "failed to load user [unknown]")
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "user1", events[0].ID)
+	assert.Error(t, events[0].Err)
+	assert.Nil(t, events[0].Current)
+
+	userData, err := userStore.Load(msp.IdentityIdentifier{ID: "user1", MSPID: renewalTestMSPID})
+	require.NoError(t, err)
+	assert.Equal(t, cert, userData.EnrollmentCertificate, "user data should not be replaced if renewal fails")
+}
+
+func TestRenewalStartStop(t *testing.T) {
+	userStore := NewMemoryUserStore()
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user1", MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, time.Hour)}))
+
+	renewed := make(chan *RenewalEvent, 1)
+	mgr := newRenewalManager(renewalTestMSPID, userStore, func(r *api.ReenrollmentRequest) (*msp.UserData, error) {
+		return &msp.UserData{ID: r.Name, MSPID: renewalTestMSPID, EnrollmentCertificate: newTestCert(t, 90*24*time.Hour)}, nil
+	}, WithRenewalCheckInterval(time.Hour), WithRenewalHandler(func(event *RenewalEvent) { renewed <- event }))
+	mgr.Watch("user1")
+
+	mgr.Start()
+	mgr.Start()
+	defer mgr.Stop()
+
+	select {
+	case event := <-renewed:
+		assert.Equal(t, "user1", event.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the certificate to be renewed")
+	}
+
+	mgr.Stop()
+	mgr.Stop()
+}
+
+func newTestCert(t *testing.T, validity time.Duration) []byte {
+	now := time.Now()
+	cert, err := testcert.New(testcert.WithCommonName("user"), testcert.WithValidity(now.Add(-time.Hour), now.Add(validity)))
+	require.NoError(t, err)
+	return cert.PEM()
+}
@@ -0,0 +1,222 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultUserTable = "users"
+	defaultKeyTable  = "private_keys"
+	certKeySuffix    = "-cert.pem"
+	privKeySuffix    = "_sk"
+)
+
+// SQLStoreOptions allow overriding SQL user and key store defaults
+type SQLStoreOptions struct {
+	// Database, mandatory
+	DB *sql.DB
+	// Optional. Name of the table. If not provided, "users" (user store) or "private_keys" (key store) is used.
+	Table string
+	// Optional. If not provided, keyvaluestore.SQLiteDialect is used.
+	Dialect *keyvaluestore.SQLDialect
+	// Optional. If provided, the stored values are envelope encrypted (recommended for private keys).
+	KeyWrapper keyvaluestore.KeyWrapper
+}
+
+// SQLUserStore stores the enrollment certificates of the users in a table of a SQL database.
+// The rows are keyed the same way as the files of CertFileUserStore (<user>@<org>-cert.pem).
+type SQLUserStore struct {
+	store *keyvaluestore.SQLKeyValueStore
+}
+
+// NewSQLUserStore creates a new instance of SQLUserStore
+func NewSQLUserStore(opts *SQLStoreOptions) (*SQLUserStore, error) {
+	store, err := newSQLStore(opts, defaultUserTable, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "user store creation failed")
+	}
+	return &SQLUserStore{store: store}, nil
+}
+
+// Load returns the User stored in the store for a key.
+func (s *SQLUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, error) {
+	cert, err := s.store.Load(storeKeyFromUserIdentifier(key))
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, msp.ErrUserNotFound
+		}
+		return nil, err
+	}
+	certBytes, ok := cert.([]byte)
+	if !ok {
+		return nil, errors.New("user is not of proper type")
+	}
+	return &msp.UserData{
+		MSPID:                 key.MSPID,
+		ID:                    key.ID,
+		EnrollmentCertificate: certBytes,
+	}, nil
+}
+
+// Store stores a User into store
+func (s *SQLUserStore) Store(user *msp.UserData) error {
+	key := storeKeyFromUserIdentifier(msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID})
+	return s.store.Store(key, user.EnrollmentCertificate)
+}
+
+// Delete deletes a User from store
+func (s *SQLUserStore) Delete(key msp.IdentityIdentifier) error {
+	return s.store.Delete(storeKeyFromUserIdentifier(key))
+}
+
+// List returns the identifiers of the stored users
+func (s *SQLUserStore) List() ([]msp.IdentityIdentifier, error) {
+	keys, err := s.store.Keys("")
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list users")
+	}
+
+	var ids []msp.IdentityIdentifier
+	for _, key := range keys {
+		if id, ok := userIdentifierFromStoreKey(key); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// userIdentifierFromStoreKey is the inverse of storeKeyFromUserIdentifier.
+// The MSP ID is separated at the last '@' since user IDs are often e-mail addresses.
+func userIdentifierFromStoreKey(key string) (msp.IdentityIdentifier, bool) {
+	if !strings.HasSuffix(key, certKeySuffix) {
+		return msp.IdentityIdentifier{}, false
+	}
+	key = strings.TrimSuffix(key, certKeySuffix)
+
+	i := strings.LastIndex(key, "@")
+	if i <= 0 || i == len(key)-1 {
+		return msp.IdentityIdentifier{}, false
+	}
+	return msp.IdentityIdentifier{ID: key[:i], MSPID: key[i+1:]}, true
+}
+
+// ImportCertFileUserStore copies the users of the CertFileUserStore at the given path into the given store.
+// Returns the number of imported users.
+func ImportCertFileUserStore(path string, store msp.UserStore) (int, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read user store [%s]", path)
+	}
+
+	count := 0
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		id, ok := userIdentifierFromStoreKey(file.Name())
+		if !ok {
+			continue
+		}
+		cert, err := ioutil.ReadFile(filepath.Join(path, file.Name())) // nolint: gas
+		if err != nil {
+			return count, errors.Wrapf(err, "failed to read user [%s]", file.Name())
+		}
+		err = store.Store(&msp.UserData{ID: id.ID, MSPID: id.MSPID, EnrollmentCertificate: cert})
+		if err != nil {
+			return count, errors.WithMessagef(err, "failed to import user [%s]", file.Name())
+		}
+		count++
+	}
+	return count, nil
+}
+
+// NewSQLKeyStore returns a key store that stores the private keys in a table of a SQL database.
+// The keys of the users are keyed by *msp.PrivKeyKey (see WithPrivKeyStore); the keys of the
+// crypto suite are keyed by their name, <ski>_<type> (see the NewKVKeyStore of the SW crypto suite).
+// Providing a KeyWrapper is strongly recommended so that the private keys are encrypted at rest.
+func NewSQLKeyStore(opts *SQLStoreOptions) (core.KVStore, error) {
+	store, err := newSQLStore(opts, defaultKeyTable, sqlPrivKeyKeySerializer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "key store creation failed")
+	}
+	return store, nil
+}
+
+func sqlPrivKeyKeySerializer(key interface{}) (string, error) {
+	if name, ok := key.(string); ok {
+		if name == "" || strings.Contains(name, "/") {
+			return "", errors.New("invalid key")
+		}
+		return name, nil
+	}
+	pkk, ok := key.(*msp.PrivKeyKey)
+	if !ok {
+		return "", errors.New("converting key to PrivKeyKey failed")
+	}
+	if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
+		return "", errors.New("invalid key")
+	}
+	return pkk.MSPID + "/" + pkk.ID + "/" + hex.EncodeToString(pkk.SKI) + privKeySuffix, nil
+}
+
+// ImportKeyStoreDir copies the private keys (<ski>_sk files) of the given user from a keystore
+// directory (such as the keystore of the user's MSP directory) into the given key store.
+// Returns the number of imported keys.
+func ImportKeyStoreDir(path string, mspID string, id string, store core.KVStore) (int, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read key store [%s]", path)
+	}
+
+	count := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), privKeySuffix) {
+			continue
+		}
+		ski, err := hex.DecodeString(strings.TrimSuffix(file.Name(), privKeySuffix))
+		if err != nil {
+			continue
+		}
+		key, err := ioutil.ReadFile(filepath.Join(path, file.Name())) // nolint: gas
+		if err != nil {
+			return count, errors.Wrapf(err, "failed to read key [%s]", file.Name())
+		}
+		if err := store.Store(&msp.PrivKeyKey{MSPID: mspID, ID: id, SKI: ski}, key); err != nil {
+			return count, errors.WithMessagef(err, "failed to import key [%s]", file.Name())
+		}
+		count++
+	}
+	return count, nil
+}
+
+func newSQLStore(opts *SQLStoreOptions, defaultTable string, keySerializer keyvaluestore.KeySerializer) (*keyvaluestore.SQLKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("SQLStoreOptions is nil")
+	}
+	table := opts.Table
+	if table == "" {
+		table = defaultTable
+	}
+	return keyvaluestore.NewSQLKeyValueStore(&keyvaluestore.SQLKeyValueStoreOptions{
+		DB:            opts.DB,
+		Table:         table,
+		Dialect:       opts.Dialect,
+		KeySerializer: keySerializer,
+		KeyWrapper:    opts.KeyWrapper,
+	})
+}
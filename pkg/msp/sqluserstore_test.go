@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	cryptosuiteimpl "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSQLDB opens a SQLite database in a temporary file. The returned function removes it.
+func newTestSQLDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "sqlstore")
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "store.db")+"?_busy_timeout=5000")
+	require.NoError(t, err)
+	return db, func() {
+		db.Close() // nolint: errcheck
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSQLUserStore(t *testing.T) {
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+
+	store, err := NewSQLUserStore(&SQLStoreOptions{DB: db})
+	require.NoError(t, err)
+
+	user1 := &msp.UserData{MSPID: "Org1MSP", ID: "user1@example.com", EnrollmentCertificate: []byte(testCert1)}
+	user2 := &msp.UserData{MSPID: "Org2MSP", ID: "user2", EnrollmentCertificate: []byte(testCert2)}
+
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: user1.MSPID, ID: user1.ID})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	require.NoError(t, store.Store(user1))
+	require.NoError(t, store.Store(user2))
+
+	loaded, err := store.Load(msp.IdentityIdentifier{MSPID: user1.MSPID, ID: user1.ID})
+	require.NoError(t, err)
+	assert.Equal(t, user1, loaded)
+
+	ids, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []msp.IdentityIdentifier{{MSPID: "Org1MSP", ID: "user1@example.com"}, {MSPID: "Org2MSP", ID: "user2"}}, ids)
+
+	require.NoError(t, store.Delete(msp.IdentityIdentifier{MSPID: user1.MSPID, ID: user1.ID}))
+	_, err = store.Load(msp.IdentityIdentifier{MSPID: user1.MSPID, ID: user1.ID})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	ids, err = store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []msp.IdentityIdentifier{{MSPID: "Org2MSP", ID: "user2"}}, ids)
+}
+
+func TestImportCertFileUserStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqluserstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileStore, err := NewCertFileUserStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, fileStore.Store(&msp.UserData{MSPID: "Org1MSP", ID: "user1", EnrollmentCertificate: []byte(testCert1)}))
+	require.NoError(t, fileStore.Store(&msp.UserData{MSPID: "Org1MSP", ID: "user2", EnrollmentCertificate: []byte(testCert2)}))
+
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+	store, err := NewSQLUserStore(&SQLStoreOptions{DB: db})
+	require.NoError(t, err)
+
+	n, err := ImportCertFileUserStore(dir, store)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	loaded, err := store.Load(msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "user2"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(testCert2), loaded.EnrollmentCertificate)
+}
+
+func TestSQLKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ski := []byte{0xab, 0xcd}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "abcd_sk"), []byte("private key"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other"), []byte("ignored"), 0600))
+
+	wrapper, err := keyvaluestore.NewAESKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+	store, err := NewSQLKeyStore(&SQLStoreOptions{DB: db, KeyWrapper: wrapper})
+	require.NoError(t, err)
+
+	n, err := ImportKeyStoreDir(dir, "Org1MSP", "user1", store)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	key, err := store.Load(&msp.PrivKeyKey{MSPID: "Org1MSP", ID: "user1", SKI: ski})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("private key"), key)
+
+	_, err = store.Load(&msp.PrivKeyKey{MSPID: "Org1MSP", ID: "user1"})
+	assert.Error(t, err)
+
+	// The private key is encrypted at rest
+	rawStore, err := keyvaluestore.NewSQLKeyValueStore(&keyvaluestore.SQLKeyValueStoreOptions{DB: db, Table: defaultKeyTable})
+	require.NoError(t, err)
+	raw, err := rawStore.Load("Org1MSP/user1/abcd_sk")
+	require.NoError(t, err)
+	assert.True(t, keyvaluestore.IsEnvelope(raw.([]byte)))
+}
+
+func TestSQLKeyStoreDialects(t *testing.T) {
+	for name, dialect := range map[string]*keyvaluestore.SQLDialect{
+		"SQLite":   &keyvaluestore.SQLiteDialect,
+		"MySQL":    &keyvaluestore.MySQLDialect,
+		"Postgres": &keyvaluestore.PostgresDialect,
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, cleanup := newTestSQLDB(t)
+			defer cleanup()
+
+			store, err := NewSQLKeyStore(&SQLStoreOptions{DB: db, Dialect: dialect})
+			require.NoError(t, err)
+
+			key := &msp.PrivKeyKey{MSPID: "Org1MSP", ID: "user1", SKI: []byte{0xab, 0xcd}}
+			require.NoError(t, store.Store(key, []byte("private key")))
+			value, err := store.Load(key)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("private key"), value)
+
+			var table string
+			require.NoError(t, db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table'").Scan(&table))
+			assert.Equal(t, "private_keys", table)
+		})
+	}
+}
+
+func TestSQLKeyStoreWithCryptoSuite(t *testing.T) {
+	db, cleanup := newTestSQLDB(t)
+	defer cleanup()
+
+	wrapper, err := keyvaluestore.NewAESKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	store, err := NewSQLKeyStore(&SQLStoreOptions{DB: db, KeyWrapper: wrapper})
+	require.NoError(t, err)
+
+	keyStore, err := cryptosuiteimpl.NewKVKeyStore(store)
+	require.NoError(t, err)
+	suite, err := cryptosuiteimpl.GetSuite(256, "SHA2", keyStore)
+	require.NoError(t, err)
+
+	ecKey, err := suite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	edKey, err := suite.KeyGen(&cryptosuiteimpl.Ed25519KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+
+	// The keys of the crypto suite are stored alongside the keys of the users
+	rawStore, err := keyvaluestore.NewSQLKeyValueStore(&keyvaluestore.SQLKeyValueStoreOptions{DB: db, Table: defaultKeyTable})
+	require.NoError(t, err)
+	keys, err := rawStore.Keys("")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	for _, k := range keys {
+		assert.True(t, strings.HasPrefix(k, hex.EncodeToString(ecKey.SKI())) || strings.HasPrefix(k, hex.EncodeToString(edKey.SKI())), k)
+	}
+
+	// A new crypto suite loads the keys from the database
+	keyStore, err = cryptosuiteimpl.NewKVKeyStore(store)
+	require.NoError(t, err)
+	suite, err = cryptosuiteimpl.GetSuite(256, "SHA2", keyStore)
+	require.NoError(t, err)
+	for _, key := range []interface{ SKI() []byte }{ecKey, edKey} {
+		loaded, err := suite.GetKey(key.SKI())
+		require.NoError(t, err)
+		assert.True(t, loaded.Private())
+	}
+
+	_, err = suite.GetKey([]byte{0x01})
+	assert.Error(t, err)
+
+	_, err = store.Load("invalid/name")
+	assert.Error(t, err)
+}
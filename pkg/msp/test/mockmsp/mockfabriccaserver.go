@@ -90,6 +90,8 @@ func (s *MockFabricCAServer) Start(lis net.Listener, cryptoSuite core.CryptoSuit
 	http.HandleFunc("/affiliations", s.affiliations)
 	http.HandleFunc("/affiliations/123", s.affiliation)
 	http.HandleFunc("/cainfo", s.cainfo)
+	http.HandleFunc("/certificates", s.certificates)
+	http.HandleFunc("/gencrl", s.gencrl)
 
 	server := &http.Server{
 		Addr:      addr,
@@ -265,3 +267,38 @@ func (s *MockFabricCAServer) cainfo(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 }
+
+// The certificates that are streamed by the GET /certificates request
+type certificatesResponseNet struct {
+	Certs []certificatePEM `json:"certs"`
+}
+
+type certificatePEM struct {
+	PEM string
+}
+
+func (s *MockFabricCAServer) certificates(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		resp := &certificatesResponseNet{Certs: []certificatePEM{{PEM: ecert}, {PEM: ecert}, {PEM: ecert}}}
+		if err := cfsslapi.SendResponse(w, resp); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// The response to the POST /gencrl request
+type genCRLResponseNet struct {
+	// Base64 encoding of PEM-encoded CRL
+	CRL string
+}
+
+func (s *MockFabricCAServer) gencrl(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		resp := &genCRLResponseNet{CRL: util.B64Encode([]byte("MockCRL"))}
+		if err := cfsslapi.SendResponse(w, resp); err != nil {
+			logger.Error(err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+)
+
+// TLSProfile is the name of the CA signing profile that issues TLS certificates
+const TLSProfile = "tls"
+
+// tlsCredentialStoreDir is the directory (within the credential store) that holds the enrolled TLS certificates
+const tlsCredentialStoreDir = "tls"
+
+// tlsClientCertProvider is implemented by endpoint configs whose client certs for mutual TLS may be overridden at runtime
+type tlsClientCertProvider interface {
+	SetTLSClientCerts(certs []tls.Certificate)
+	TLSClientCertsOverride() ([]tls.Certificate, bool)
+}
+
+// connectionDrainer is implemented by comm managers that are able to close their cached connections
+type connectionDrainer interface {
+	Drain(targets ...string)
+}
+
+// EnrollTLS enrolls a registered user with the TLS profile of the CA (unless another profile is requested).
+// The TLS certificate is stored in the TLS credential store, separately from the enrollment certificate,
+// and is subsequently used as the client certificate for mutual TLS connections to peers, orderers and CAs.
+func (c *CAClientImpl) EnrollTLS(request *api.EnrollmentRequest) (err error) {
+	defer c.observe("EnrollTLS", time.Now(), &err)
+	if c.adapter == nil {
+		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if request.Name == "" {
+		return errors.New("enrollmentID is required")
+	}
+	if request.Secret == "" {
+		return errors.New("enrollmentSecret is required")
+	}
+
+	store, err := c.tlsStore()
+	if err != nil {
+		return errors.WithMessage(err, "TLS enroll failed")
+	}
+
+	tlsRequest := *request
+	if tlsRequest.Profile == "" {
+		tlsRequest.Profile = TLSProfile
+	}
+
+	cert, err := c.adapter.Enroll(&tlsRequest)
+	if err != nil {
+		return errors.Wrap(err, "TLS enroll failed")
+	}
+
+	err = store.Store(&msp.UserData{
+		MSPID:                 c.orgMSPID,
+		ID:                    request.Name,
+		EnrollmentCertificate: cert,
+	})
+	if err != nil {
+		return errors.Wrap(err, "TLS enroll failed")
+	}
+
+	return c.applyTLSClientCert(cert)
+}
+
+// UseTLSIdentity uses the TLS certificate of the given user (which was previously enrolled with EnrollTLS)
+// as the client certificate for mutual TLS connections
+func (c *CAClientImpl) UseTLSIdentity(id string) error {
+	store, err := c.tlsStore()
+	if err != nil {
+		return err
+	}
+
+	userData, err := store.Load(msp.IdentityIdentifier{MSPID: c.orgMSPID, ID: id})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load TLS certificate of user [%s]", id)
+	}
+
+	return c.applyTLSClientCert(userData.EnrollmentCertificate)
+}
+
+// NewTLSRenewalManager returns a renewal manager that renews the TLS certificates that were enrolled with
+// EnrollTLS. If a renewed certificate is in use for mutual TLS then it's replaced by the renewed certificate.
+func NewTLSRenewalManager(caClient *CAClientImpl, opts ...RenewalOption) (*RenewalManager, error) {
+	if caClient.adapter == nil {
+		return nil, errors.Errorf("no CAs configured for organization: %s", caClient.orgName)
+	}
+
+	store, err := caClient.tlsStore()
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := newRenewalManager(caClient.orgMSPID, store, caClient.reenrollTLS, opts...)
+	mgr.OnRenew(caClient.applyRenewedTLSClientCert)
+
+	return mgr, nil
+}
+
+func (c *CAClientImpl) reenrollTLS(request *api.ReenrollmentRequest) (*msp.UserData, error) {
+	store, err := c.tlsStore()
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := store.Load(msp.IdentityIdentifier{MSPID: c.orgMSPID, ID: request.Name})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load TLS certificate of user [%s]", request.Name)
+	}
+
+	key, err := cryptoutil.GetPrivateKeyFromCert(userData.EnrollmentCertificate, c.cryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to retrieve private key of TLS certificate of user [%s]", request.Name)
+	}
+
+	tlsRequest := *request
+	if tlsRequest.Profile == "" {
+		tlsRequest.Profile = TLSProfile
+	}
+
+	cert, err := c.adapter.Reenroll(key, userData.EnrollmentCertificate, &tlsRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "TLS reenroll failed")
+	}
+
+	return &msp.UserData{
+		MSPID:                 c.orgMSPID,
+		ID:                    request.Name,
+		EnrollmentCertificate: cert,
+	}, nil
+}
+
+func (c *CAClientImpl) tlsStore() (msp.UserStore, error) {
+	if c.tlsStorePath == "" {
+		return nil, errors.New("credential store path is required to store TLS certificates")
+	}
+	return NewCertFileUserStore(c.tlsStorePath)
+}
+
+// applyTLSClientCert sets the given certificate as the client certificate for mutual TLS and closes the
+// cached connections, which were established with the previous client certificate
+func (c *CAClientImpl) applyTLSClientCert(certPEM []byte) error {
+	provider, ok := c.endpointConfig.(tlsClientCertProvider)
+	if !ok {
+		return errors.New("endpoint config does not support enrolled TLS client certificates")
+	}
+
+	key, err := cryptoutil.GetPrivateKeyFromCert(certPEM, c.cryptoSuite)
+	if err != nil {
+		return errors.WithMessage(err, "failed to retrieve private key of TLS certificate")
+	}
+
+	cert, err := cryptoutil.X509KeyPair(certPEM, key, c.cryptoSuite)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load TLS certificate")
+	}
+
+	provider.SetTLSClientCerts([]tls.Certificate{cert})
+
+	if c.infraProvider != nil {
+		if drainer, ok := c.infraProvider.CommManager().(connectionDrainer); ok {
+			drainer.Drain()
+		}
+	}
+
+	return nil
+}
+
+// applyRenewedTLSClientCert replaces the client certificate for mutual TLS if it was renewed
+func (c *CAClientImpl) applyRenewedTLSClientCert(event *RenewalEvent) {
+	if event.Err != nil || !isTLSClientCert(c.endpointConfig, event.Previous.EnrollmentCertificate) {
+		return
+	}
+
+	if err := c.applyTLSClientCert(event.Current.EnrollmentCertificate); err != nil {
+		logger.Warnf("Unable to use renewed TLS certificate of user [%s]: %s", event.ID, err)
+	}
+}
+
+// isTLSClientCert returns true if the given certificate is the enrolled client certificate for mutual TLS
+func isTLSClientCert(config fab.EndpointConfig, certPEM []byte) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false
+	}
+
+	provider, ok := config.(tlsClientCertProvider)
+	if !ok {
+		return false
+	}
+
+	certs, ok := provider.TLSClientCertsOverride()
+	return ok && len(certs[0].Certificate) > 0 && bytes.Equal(certs[0].Certificate[0], block.Bytes)
+}
+
+// enrolledTLSClientCert returns the PEM-encoded client certificate for mutual TLS
+// that was enrolled with EnrollTLS (or nil if the configured client certificate is used)
+func enrolledTLSClientCert(config fab.EndpointConfig) []byte {
+	provider, ok := config.(tlsClientCertProvider)
+	if !ok {
+		return nil
+	}
+
+	certs, ok := provider.TLSClientCertsOverride()
+	if !ok {
+		return nil
+	}
+
+	var certPEM []byte
+	for _, der := range certs[0].Certificate {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	return certPEM
+}
+
+// tlsCredentialStorePath returns the path of the store that holds the enrolled TLS certificates
+func tlsCredentialStorePath(config msp.IdentityConfig) string {
+	if config.CredentialStorePath() == "" {
+		return ""
+	}
+	return filepath.Join(config.CredentialStorePath(), tlsCredentialStoreDir)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollTLS(t *testing.T) {
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	// The endpoint config of the fixture doesn't support enrolled TLS certificates
+	err := f.caClient.(*CAClientImpl).EnrollTLS(&api.EnrollmentRequest{Name: createRandomName(), Secret: "enrollmentSecret"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "endpoint config does not support enrolled TLS client certificates")
+
+	endpointConfig := fab.NewReloadableEndpointConfig(f.endpointConfig)
+	ctxProvider := context.NewProvider(context.WithIdentityManagerProvider(f.identityManagerProvider),
+		context.WithUserStore(f.userStore), context.WithCryptoSuite(f.cryptoSuite),
+		context.WithCryptoSuiteConfig(f.cryptSuiteConfig), context.WithEndpointConfig(endpointConfig),
+		context.WithIdentityConfig(f.identityConfig))
+	caClient, err := NewCAClient(org1, &context.Client{Providers: ctxProvider})
+	require.NoError(t, err)
+
+	err = caClient.EnrollTLS(&api.EnrollmentRequest{Name: "", Secret: "enrollmentSecret"})
+	assert.Error(t, err)
+
+	enrollUsername := createRandomName()
+	require.NoError(t, caClient.EnrollTLS(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"}))
+
+	store, err := caClient.tlsStore()
+	require.NoError(t, err)
+	userData, err := store.Load(msp.IdentityIdentifier{MSPID: caClient.orgMSPID, ID: enrollUsername})
+	require.NoError(t, err)
+	assert.True(t, isTLSClientCert(endpointConfig, userData.EnrollmentCertificate), "enrolled TLS certificate should be used for mutual TLS")
+
+	_, err = f.userStore.Load(msp.IdentityIdentifier{MSPID: caClient.orgMSPID, ID: enrollUsername})
+	assert.Equal(t, msp.ErrUserNotFound, err, "TLS certificate should not be stored with the enrollment certificates")
+
+	// The renewed TLS certificate replaces the certificate in use
+	mgr, err := NewTLSRenewalManager(caClient)
+	require.NoError(t, err)
+	require.NoError(t, mgr.Renew(enrollUsername))
+
+	renewed, err := store.Load(msp.IdentityIdentifier{MSPID: caClient.orgMSPID, ID: enrollUsername})
+	require.NoError(t, err)
+	assert.True(t, isTLSClientCert(endpointConfig, renewed.EnrollmentCertificate), "renewed TLS certificate should be used for mutual TLS")
+
+	endpointConfig.SetTLSClientCerts(nil)
+	require.NoError(t, caClient.UseTLSIdentity(enrollUsername))
+	assert.True(t, isTLSClientCert(endpointConfig, renewed.EnrollmentCertificate))
+
+	assert.Error(t, caClient.UseTLSIdentity("unknown"))
+}
+
+func TestEnrolledTLSClientCert(t *testing.T) {
+	certPEM := newTestCert(t, time.Hour)
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+
+	config := fab.NewReloadableEndpointConfig(nil)
+	assert.Nil(t, enrolledTLSClientCert(config))
+	assert.False(t, isTLSClientCert(config, certPEM))
+
+	config.SetTLSClientCerts([]tls.Certificate{{Certificate: [][]byte{block.Bytes}}})
+	assert.Equal(t, certPEM, enrolledTLSClientCert(config))
+	assert.True(t, isTLSClientCert(config, certPEM))
+	assert.False(t, isTLSClientCert(config, newTestCert(t, time.Hour)))
+	assert.False(t, isTLSClientCert(config, []byte("invalid")))
+
+	assert.Equal(t, "", tlsCredentialStorePath(&mockIdentityConfig{}))
+	assert.Equal(t, "/tmp/msp/tls", tlsCredentialStorePath(&mockIdentityConfig{credentialStorePath: "/tmp/msp"}))
+}
+
+type mockIdentityConfig struct {
+	msp.IdentityConfig
+	credentialStorePath string
+}
+
+func (c *mockIdentityConfig) CredentialStorePath() string {
+	return c.credentialStorePath
+}
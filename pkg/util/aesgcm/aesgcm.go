@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aesgcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Cipher encrypts and authenticates data with AES-GCM. A random nonce is generated
+// for each encryption and is prepended to the ciphertext.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New returns a cipher for the given AES key (which must be 16, 24 or 32 bytes long)
+func New(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCM")
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts the plaintext and authenticates it along with the additional data (which may be nil)
+func (c *Cipher) Seal(plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Open decrypts a ciphertext that was encrypted with Seal using the same additional data
+func (c *Cipher) Open(ciphertext []byte, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce := ciphertext[:c.aead.NonceSize()]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext[c.aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "decryption failed")
+	}
+	return plaintext, nil
+}
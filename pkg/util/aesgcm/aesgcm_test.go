@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aesgcm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	c, err := New(bytes.Repeat([]byte("k"), 32))
+	require.NoError(t, err)
+
+	sealed, err := c.Seal([]byte("secret"), []byte("name"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	plaintext, err := c.Open(sealed, []byte("name"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	_, err = c.Open(sealed, []byte("other"))
+	assert.Error(t, err, "additional data must match")
+
+	_, err = c.Open(sealed[:4], nil)
+	assert.EqualError(t, err, "ciphertext is too short")
+
+	_, err = New([]byte("short"))
+	assert.Error(t, err)
+}
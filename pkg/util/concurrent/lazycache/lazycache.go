@@ -190,6 +190,24 @@ func (c *Cache) DeleteAll() {
 	}
 }
 
+// Range calls f for each entry in the cache that has been successfully initialized.
+// Entries that are still being initialized are skipped. If the cache uses lazy
+// references then the value passed to f is the reference. Iteration stops if f
+// returns false.
+func (c *Cache) Range(f func(key string, value interface{}) bool) {
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		fv := value.(future)
+		if !fv.IsSet() {
+			return true
+		}
+		v, err := fv.Get()
+		if err != nil || v == nil {
+			return true
+		}
+		return f(key.(string), v)
+	})
+}
+
 // Delete does the following:
 // - calls Close on all values that implement a Close() function
 // - deletes key from the cache
@@ -185,6 +185,37 @@ func TestDelete(t *testing.T) {
 
 }
 
+func TestRange(t *testing.T) {
+	cache := New("Example_Cache", func(key Key) (interface{}, error) {
+		if key.String() == "error" {
+			return nil, fmt.Errorf("some error")
+		}
+		return fmt.Sprintf("Value_for_key_%s", key), nil
+	})
+	defer cache.Close()
+
+	_, err := cache.Get(NewStringKey("Key1"))
+	require.NoError(t, err)
+	_, err = cache.Get(NewStringKey("Key2"))
+	require.NoError(t, err)
+	_, err = cache.Get(NewStringKey("error"))
+	require.Error(t, err)
+
+	values := make(map[string]interface{})
+	cache.Range(func(key string, value interface{}) bool {
+		values[key] = value
+		return true
+	})
+	assert.Equal(t, map[string]interface{}{"Key1": "Value_for_key_Key1", "Key2": "Value_for_key_Key2"}, values)
+
+	count := 0
+	cache.Range(func(key string, value interface{}) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
 func TestMustGetPanic(t *testing.T) {
 	cache := New("Example_Cache", func(key Key) (interface{}, error) {
 		if key.String() == "error" {
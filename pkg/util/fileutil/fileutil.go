@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fileutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFileAtomic writes the data to the file with the given permissions. The data is written to a
+// unique temporary file in the same directory which is then renamed, so that readers (and concurrent
+// writers) never see a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to write [%s]", path)
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+
+	if err := writeAndClose(tmp, data, perm); err != nil {
+		return errors.Wrapf(err, "failed to write [%s]", path)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrapf(err, "failed to write [%s]", path)
+	}
+	return nil
+}
+
+func writeAndClose(f *os.File, data []byte, perm os.FileMode) error {
+	if _, err := f.Write(data); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	return f.Close()
+}
@@ -128,6 +128,7 @@ declare -a FILES=(
     "bccsp/sw/new.go"
     "bccsp/sw/rsa.go"
     "bccsp/sw/rsakey.go"
+    "bccsp/sw/storageks.go"
 
     "bccsp/utils/errs.go"
     "bccsp/utils/io.go"